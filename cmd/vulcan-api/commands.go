@@ -5,6 +5,7 @@ Copyright 2021 Adevinta
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,7 +13,9 @@ import (
 	"os/user"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	goaclient "github.com/goadesign/goa/client"
@@ -24,6 +27,7 @@ import (
 	"github.com/adevinta/vulcan-api/pkg/api/middleware"
 	"github.com/adevinta/vulcan-api/pkg/api/service"
 	globalmiddleware "github.com/adevinta/vulcan-api/pkg/api/service/middleware/global"
+	"github.com/adevinta/vulcan-api/pkg/api/service/middleware/scanqueue"
 	"github.com/adevinta/vulcan-api/pkg/api/store"
 	"github.com/adevinta/vulcan-api/pkg/api/store/cdc"
 	"github.com/adevinta/vulcan-api/pkg/api/store/global"
@@ -33,12 +37,20 @@ import (
 	"github.com/adevinta/vulcan-api/pkg/awscatalogue"
 	awscatalogueclient "github.com/adevinta/vulcan-api/pkg/awscatalogue/client"
 	"github.com/adevinta/vulcan-api/pkg/checktypes"
+	"github.com/adevinta/vulcan-api/pkg/findingsexports"
 	"github.com/adevinta/vulcan-api/pkg/jwt"
+	"github.com/adevinta/vulcan-api/pkg/notifications"
 	"github.com/adevinta/vulcan-api/pkg/reports"
+	"github.com/adevinta/vulcan-api/pkg/riskacceptances"
 	saml "github.com/adevinta/vulcan-api/pkg/saml"
 	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	"github.com/adevinta/vulcan-api/pkg/scanevents"
 	"github.com/adevinta/vulcan-api/pkg/schedule"
+	"github.com/adevinta/vulcan-api/pkg/slabreaches"
+	"github.com/adevinta/vulcan-api/pkg/statssnapshots"
+	"github.com/adevinta/vulcan-api/pkg/subscriptions"
 	"github.com/adevinta/vulcan-api/pkg/tickets"
+	"github.com/adevinta/vulcan-api/pkg/ticketsync"
 	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
 	vulcancore "github.com/adevinta/vulcan-core-cli/vulcan-core/client"
 	metrics "github.com/adevinta/vulcan-metrics-client"
@@ -115,19 +127,27 @@ type samlConfig struct {
 }
 
 type vulcanCoreConfig struct {
-	Schema string
-	Host   string
+	Schema       string
+	Host         string
+	CatalogueTTL int    `mapstructure:"catalogue_ttl"`
+	SchemasDir   string `mapstructure:"schemas_dir"`
 }
 
 type vulnerabilityDBConfig struct {
+	URL         string                      `mapstructure:"url"`
+	InsecureTLS bool                        `mapstructure:"insecure_tls"`
+	Cache       vulnerabilitydb.CacheConfig `mapstructure:"cache"`
+}
+
+type vulcantrackerConfig struct {
 	URL         string `mapstructure:"url"`
 	InsecureTLS bool   `mapstructure:"insecure_tls"`
 }
 
-type vulcantrackerConfig struct {
-	URL            string `mapstructure:"url"`
-	InsecureTLS    bool   `mapstructure:"insecure_tls"`
-	OnboardedTeams string `mapstructure:"onboarded_teams"`
+type ticketsConfig struct {
+	// ProvidersEnabled enables the ticket providers configured per team, even
+	// if the vulcan-tracker component is not configured.
+	ProvidersEnabled bool `mapstructure:"providers_enabled"`
 }
 
 type metricsConfig struct {
@@ -157,16 +177,26 @@ type config struct {
 	SAML               samlConfig
 	Defaults           store.DefaultEntities
 	ScanEngine         scanengine.Config
+	ScanLimits         scanqueue.Config  `mapstructure:"scanlimits"`
+	ScanEvents         scanevents.Config `mapstructure:"scanevents"`
 	Scheduler          schedule.Config
 	Reports            reports.Config
+	Subscriptions      subscriptions.Config `mapstructure:"reportsubscriptions"`
 	VulcanCore         vulcanCoreConfig
 	VulnerabilityDB    vulnerabilityDBConfig
 	VulcanTracker      vulcantrackerConfig
+	Tickets            ticketsConfig
 	Metrics            metricsConfig
 	AWSCatalogue       awsCatalogueConfig
 	Kafka              kafkaConfig               `mapstructure:"kafka"`
 	GlobalPolicyConfig global.GlobalPolicyConfig `mapstructure:"globalpolicy"`
+	GlobalEntities     global.Config             `mapstructure:"globalentities"`
 	AssetsConfig       assetsConfig              `mapstructure:"assets"`
+	RiskAcceptances    riskacceptances.Config    `mapstructure:"riskacceptances"`
+	SLABreaches        slabreaches.Config        `mapstructure:"slabreaches"`
+	TicketSync         ticketsync.Config         `mapstructure:"ticketsync"`
+	StatsSnapshots     statssnapshots.Config     `mapstructure:"statssnapshots"`
+	FindingsExports    findingsexports.Config    `mapstructure:"findingsexports"`
 }
 
 func initConfig() {
@@ -211,11 +241,13 @@ func startServer() error {
 
 	// Build vulndb client.
 	vulnerabilityDBClient := vulnerabilitydb.NewClient(nil, cfg.VulnerabilityDB.URL, cfg.VulnerabilityDB.InsecureTLS)
-
-	// Build tickets client.
-	var vulcantrackerClient tickets.Client
-	if cfg.VulcanTracker.URL != "" { // This is an optional component.
-		vulcantrackerClient = tickets.NewClient(nil, cfg.VulcanTracker.URL, cfg.VulcanTracker.InsecureTLS)
+	if cfg.VulnerabilityDB.Cache.Enabled {
+		cacheStore, err := vulnerabilitydb.NewCacheStore(cfg.VulnerabilityDB.Cache)
+		if err != nil {
+			fmt.Printf("error creating vulndb cache: %v", err)
+			return err
+		}
+		vulnerabilityDBClient = vulnerabilitydb.NewCachedClient(vulnerabilityDBClient, cacheStore, cfg.VulnerabilityDB.Cache, logger)
 	}
 
 	// Build reports client.
@@ -230,6 +262,9 @@ func startServer() error {
 		return err
 	}
 
+	// Build the scan engine client shared by all the components.
+	scanEngineClient := scanengine.NewClient(nil, cfg.ScanEngine, metricsClient)
+
 	// Build the AWS accounts names component.
 	catalogueCfg := awscatalogueclient.AWSCatalogueAPIConfig{
 		URL: cfg.AWSCatalogue.URL, Key: cfg.AWSCatalogue.Key,
@@ -263,25 +298,99 @@ func startServer() error {
 		return err
 	}
 
+	// Build tickets client. The requests of each team are sent to its ticket
+	// provider, or to vulcan-tracker if the team has none.
+	var vulcantrackerClient tickets.Client
+	if cfg.VulcanTracker.URL != "" { // This is an optional component.
+		vulcantrackerClient = tickets.NewClient(nil, cfg.VulcanTracker.URL, cfg.VulcanTracker.InsecureTLS)
+	}
+	if vulcantrackerClient != nil || cfg.Tickets.ProvidersEnabled {
+		vulcantrackerClient = tickets.NewRouter(nil, db, vulcantrackerClient, tickets.EnvCredentials)
+	}
+
+	// Build the checktypes catalogue.
+	coreclient := newVulcanCoreAPIClient(cfg.VulcanCore)
+	checktypesCatalogue := checktypes.NewCatalogue(coreclient, checktypes.CatalogueConfig{
+		TTL: cfg.VulcanCore.CatalogueTTL, SchemasDir: cfg.VulcanCore.SchemasDir,
+	})
+	// The checktypes for each assettype are read from the catalogue and
+	// stored in the database, so the global entities keep working when
+	// vulcan-core is not available.
+	checktypesInformer := checktypes.NewCachedInformer(checktypesCatalogue, db)
+
 	// Build service layer.
-	onBoardedTeamsVT := strings.Split(cfg.VulcanTracker.OnboardedTeams, ",")
-	vulcanitoService := service.New(logger, db, jwtConfig, cfg.ScanEngine, schedulerClient, cfg.Reports,
-		vulnerabilityDBClient, vulcantrackerClient, reportsClient, metricsClient, awsAccounts, onBoardedTeamsVT,
+	vulcanitoService := service.New(logger, db, jwtConfig, scanEngineClient, schedulerClient, cfg.Reports,
+		vulnerabilityDBClient, vulcantrackerClient, reportsClient, metricsClient, awsAccounts, checktypesCatalogue, checktypesInformer,
 		cfg.AssetsConfig.DNSHostnameValidation)
 
 	// Second, inject the service layer to the CDC parser JobsRunner.
 	jobsRunner.Client = vulcanitoService
 
 	// Create the global entities service middleware dependencies.
-	coreclient := newVulcanCoreAPIClient(cfg.VulcanCore)
-	globalEntities, err := global.NewEntities(db, checktypes.New(coreclient))
+	cfg.GlobalEntities.Definitions.PolicyConfig = cfg.GlobalPolicyConfig
+	globalEntities, err := global.NewEntities(db, checktypesInformer, cfg.GlobalEntities.Definitions)
 	if err != nil {
-		fmt.Printf("error creating checktypesinformer: %v", err)
+		fmt.Printf("error creating global entities: %v", err)
 		return err
 	}
-	globalMiddleware := globalmiddleware.NewEntities(logger, globalEntities, db, schedulerClient, schedulerClient, cfg.ScanEngine, metricsClient, cfg.GlobalPolicyConfig)
+	go reloadGlobalEntities(logger, globalEntities, cfg.GlobalEntities)
+	globalMiddleware := globalmiddleware.NewEntities(logger, globalEntities, db, schedulerClient, schedulerClient, scanEngineClient, metricsClient, cfg.GlobalPolicyConfig)
 	// Add global middleware to the vulcanito service.
 	vulcanitoService = globalMiddleware(vulcanitoService)
+	// Add the scan limits middleware on top of the global one so the limits
+	// apply also to the scans of global programs.
+	scanQueue := scanqueue.New(logger, db, scanEngineClient, cfg.ScanLimits)
+	vulcanitoService = scanQueue.Middleware()(vulcanitoService)
+
+	// Release the slots of the scan queue and send the notifications of the
+	// programs when their scans finish. This is optional as it requires a
+	// queue subscribed to the scan engine events; without it the scan queue
+	// checks the status of the running scans periodically.
+	if cfg.ScanEvents.QueueArn != "" {
+		var emailSender notifications.EmailSender
+		if reportsClient != nil {
+			emailSender = reportsClient
+		}
+		notifier := notifications.New(logger, db, vulcanitoService, emailSender, scanEngineClient)
+		scanEventsConsumer, err := scanevents.NewConsumer(cfg.ScanEvents, scanevents.Handlers{scanQueue, notifier}, logger)
+		if err != nil {
+			fmt.Printf("error creating scan events consumer: %v", err)
+			return err
+		}
+		go scanEventsConsumer.Run(context.Background())
+	}
+
+	// Send the reports of the report subscriptions of the teams when they are
+	// due.
+	if reportsClient != nil {
+		subscriptionsRunner := subscriptions.New(logger, db, vulcanitoService, cfg.Subscriptions)
+		go subscriptionsRunner.Run(context.Background())
+	}
+
+	// Reopen the findings whose risk acceptance expired.
+	riskAcceptancesRunner := riskacceptances.New(logger, db, vulcanitoService, cfg.RiskAcceptances)
+	go riskAcceptancesRunner.Run(context.Background())
+
+	// Notify the breaches of the SLA of the findings of the teams.
+	if reportsClient != nil {
+		slaBreachesRunner := slabreaches.New(logger, db, vulcanitoService, cfg.SLABreaches)
+		go slaBreachesRunner.Run(context.Background())
+	}
+
+	// Synchronize the tickets of the findings with the status of the
+	// findings.
+	if vulcantrackerClient != nil {
+		ticketSyncRunner := ticketsync.New(logger, db, vulcanitoService, cfg.TicketSync)
+		go ticketSyncRunner.Run(context.Background())
+	}
+
+	// Store the daily stats of the findings of the teams.
+	statsSnapshotsRunner := statssnapshots.New(logger, db, vulcanitoService, cfg.StatsSnapshots)
+	go statsSnapshotsRunner.Run(context.Background())
+
+	// Generate the findings exports requested asynchronously.
+	findingsExportsRunner := findingsexports.New(logger, db, vulcanitoService, cfg.FindingsExports)
+	go findingsExportsRunner.Run(context.Background())
 
 	endpoints := endpoint.MakeEndpoints(vulcanitoService, vulcantrackerClient != nil, logger)
 
@@ -381,6 +490,95 @@ func startServer() error {
 	return logger.Log("exit", <-errs)
 }
 
+// reloadGlobalEntities reloads the global entities and the global policy
+// config every time the process receives a SIGHUP or, if enabled, the config
+// file changes, reading again the definitions from the config file, and
+// periodically, if a reload interval is configured, to load the changes in
+// the definitions stored in the database. After every reload the global
+// policies are evaluated to log the changes in their checktypes.
+func reloadGlobalEntities(logger log.Logger, entities *global.Entities, globalCfg global.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	changed := make(chan struct{}, 1)
+	if globalCfg.WatchConfig {
+		viper.OnConfigChange(func(fsnotify.Event) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		viper.WatchConfig()
+	}
+	var tick <-chan time.Time
+	if globalCfg.ReloadInterval > 0 {
+		ticker := time.NewTicker(time.Duration(globalCfg.ReloadInterval) * time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	evaluatePolicies(logger, entities)
+	for {
+		select {
+		case <-hup:
+			if err := viper.ReadInConfig(); err != nil {
+				_ = level.Error(logger).Log("GlobalEntities", "error reading config", "err", err)
+				continue
+			}
+		case <-changed:
+			// Viper has already read the config file when notifying the change.
+		case <-tick:
+		}
+		// The definitions are read again from the config loaded by viper, that
+		// only changes when the config file is read again.
+		defs, err := readGlobalDefinitions()
+		if err != nil {
+			_ = level.Error(logger).Log("GlobalEntities", "error reading config", "err", err)
+			continue
+		}
+		if err := entities.Reload(defs); err != nil {
+			_ = level.Error(logger).Log("GlobalEntities", "error reloading global entities", "err", err)
+			continue
+		}
+		_ = level.Debug(logger).Log("GlobalEntities", "global entities reloaded")
+		evaluatePolicies(logger, entities)
+	}
+}
+
+// readGlobalDefinitions returns the global entities definitions and the
+// global policy config in the config currently loaded by viper.
+func readGlobalDefinitions() (global.Definitions, error) {
+	cfg := global.Config{}
+	if err := viper.UnmarshalKey("globalentities", &cfg); err != nil {
+		return global.Definitions{}, err
+	}
+	gpc := global.GlobalPolicyConfig{}
+	if err := viper.UnmarshalKey("globalpolicy", &gpc); err != nil {
+		return global.Definitions{}, err
+	}
+	cfg.Definitions.PolicyConfig = gpc
+	return cfg.Definitions, nil
+}
+
+// evaluatePolicies evaluates the global policies and logs the ones whose
+// checktypes changed.
+func evaluatePolicies(logger log.Logger, entities *global.Entities) {
+	evaluations, err := entities.EvaluatePolicies(context.Background())
+	if err != nil {
+		_ = level.Error(logger).Log("GlobalEntities", "error evaluating global policies", "err", err)
+		return
+	}
+	for _, e := range evaluations {
+		if e.Error != "" {
+			_ = level.Error(logger).Log("GlobalEntities", "error evaluating global policy", "policy", e.Policy, "err", e.Error)
+			continue
+		}
+		if e.ChangedAt == nil || !e.ChangedAt.Equal(e.EvaluatedAt) {
+			continue
+		}
+		_ = level.Info(logger).Log("GlobalEntities", "global policy checktypes changed", "policy", e.Policy,
+			"added", strings.Join(e.Added, ","), "removed", strings.Join(e.Removed, ","))
+	}
+}
+
 func addMetricsMiddleware(endpoints endpoint.Endpoints, metricsClient metrics.Client) endpoint.Endpoints {
 	metricsMiddleware := middleware.NewMetricsMiddleware(metricsClient)
 
@@ -429,6 +627,10 @@ func addValidateUUIDsMiddleware(endpoints endpoint.Endpoints, db api.VulcanitoSt
 func addAuthenticationMiddleware(endpoints endpoint.Endpoints, logger log.Logger, jwtSignKey string, db api.VulcanitoStore) endpoint.Endpoints {
 	exceptions := map[string]bool{
 		endpoint.Healthcheck: true,
+		// The recipients are identified by the token sent to them.
+		endpoint.VerifyRecipient:          true,
+		endpoint.UnsubscribeRecipient:     true,
+		endpoint.UnsubscribeRecipientPage: true,
 	}
 
 	for name := range endpoints {
@@ -465,6 +667,11 @@ func addAuthorizationMiddleware(endpoints endpoint.Endpoints, db api.VulcanitoSt
 		endpoint.GlobalStatsOpen:            true,
 		endpoint.GlobalStatsFixed:           true,
 		endpoint.GlobalStatsAssets:          true,
+		// Recipients endpoints not scoped to a team.
+		endpoint.VerifyRecipient:             true,
+		endpoint.UnsubscribeRecipient:        true,
+		endpoint.UnsubscribeRecipientPage:    true,
+		endpoint.DeactivateBouncedRecipients: true,
 	}
 
 	for name := range endpoints {
@@ -504,8 +711,17 @@ func addWhitelistingMiddleware(endpoints endpoint.Endpoints, logger log.Logger)
 		endpoint.UpdateTeamMember: true,
 		endpoint.DeleteTeamMember: true,
 		// Recipients management.
-		endpoint.ListRecipients:   true,
-		endpoint.UpdateRecipients: true,
+		endpoint.ListRecipients:            true,
+		endpoint.UpdateRecipients:          true,
+		endpoint.CreateRecipient:           true,
+		endpoint.UpdateRecipient:           true,
+		endpoint.DeleteRecipient:           true,
+		endpoint.SendRecipientVerification: true,
+		// Recipients verification and unsubscription, they do not require
+		// login.
+		endpoint.VerifyRecipient:          true,
+		endpoint.UnsubscribeRecipient:     true,
+		endpoint.UnsubscribeRecipientPage: true,
 		// Assets management.
 		endpoint.ListAssets:             true,
 		endpoint.CreateAsset:            true,
@@ -520,6 +736,11 @@ func addWhitelistingMiddleware(endpoints endpoint.Endpoints, logger log.Logger)
 		endpoint.UpdateAssetAnnotations: true,
 		endpoint.PutAssetAnnotations:    true,
 		endpoint.DeleteAssetAnnotations: true,
+		// Asset checktype settings management.
+		endpoint.ListAssetChecktypeSettings:  true,
+		endpoint.CreateAssetChecktypeSetting: true,
+		endpoint.UpdateAssetChecktypeSetting: true,
+		endpoint.DeleteAssetChecktypeSetting: true,
 		// Group management.
 		endpoint.CreateGroup:    true,
 		endpoint.ListGroups:     true,
@@ -531,28 +752,67 @@ func addWhitelistingMiddleware(endpoints endpoint.Endpoints, logger log.Logger)
 		endpoint.ListAssetGroup: true,
 
 		// List scans.
-		endpoint.ListProgramScans: true,
+		endpoint.ListProgramScans:     true,
+		endpoint.ListScanQueue:        true,
+		endpoint.CancelScanQueueEntry: true,
+		endpoint.DiffScans:            true,
 		// List programs.
 		endpoint.ListPrograms: true,
+		endpoint.ProgramPlan:  true,
+		// Program notifications management.
+		endpoint.ListNotificationRules:  true,
+		endpoint.CreateNotificationRule: true,
+		endpoint.UpdateNotificationRule: true,
+		endpoint.DeleteNotificationRule: true,
+		// Report subscriptions management.
+		endpoint.ListReportSubscriptions:  true,
+		endpoint.FindReportSubscription:   true,
+		endpoint.CreateReportSubscription: true,
+		endpoint.UpdateReportSubscription: true,
+		endpoint.DeleteReportSubscription: true,
 		// Issues.
 		endpoint.ListIssues: true,
 		// Findings access.
-		endpoint.ListFindings:           true,
-		endpoint.ListFindingsIssues:     true,
-		endpoint.ListFindingsByIssue:    true,
-		endpoint.ListFindingsTargets:    true,
-		endpoint.ListFindingsByTarget:   true,
-		endpoint.FindFinding:            true,
-		endpoint.CreateFindingOverwrite: true,
-		endpoint.ListFindingOverwrites:  true,
-		endpoint.ListFindingsLabels:     true,
-		endpoint.CreateFindingTicket:    true,
+		endpoint.ListFindings:             true,
+		endpoint.ListFindingsIssues:       true,
+		endpoint.ListFindingsByIssue:      true,
+		endpoint.ListFindingsTargets:      true,
+		endpoint.ListFindingsByTarget:     true,
+		endpoint.FindFinding:              true,
+		endpoint.CreateFindingOverwrite:   true,
+		endpoint.ListFindingOverwrites:    true,
+		endpoint.CreateFindingOverwrites:  true,
+		endpoint.ListFindingComments:      true,
+		endpoint.CreateFindingComment:     true,
+		endpoint.UpdateFindingComment:     true,
+		endpoint.DeleteFindingComment:     true,
+		endpoint.ListRiskAcceptances:      true,
+		endpoint.FindRiskAcceptance:       true,
+		endpoint.RequestRiskAcceptance:    true,
+		endpoint.ApproveRiskAcceptance:    true,
+		endpoint.RejectRiskAcceptance:     true,
+		endpoint.VerifyFinding:            true,
+		endpoint.ListFindingVerifications: true,
+		endpoint.ListFindingsLabels:       true,
+		endpoint.CreateFindingTicket:      true,
+		endpoint.CreateIssueTickets:       true,
+		endpoint.ListFindingTickets:       true,
+		endpoint.FindTeamTicketProvider:   true,
+		endpoint.ListFindingsSLA:          true,
+		endpoint.ListTeamSLAPolicies:      true,
+		endpoint.CreateTeamSLAPolicy:      true,
+		endpoint.UpdateTeamSLAPolicy:      true,
+		endpoint.DeleteTeamSLAPolicy:      true,
+		endpoint.ExportFindings:           true,
+		endpoint.CreateFindingsExport:     true,
+		endpoint.DownloadFindingsExport:   true,
 		// Metrics access.
 		endpoint.StatsMTTR:                  true,
 		endpoint.StatsExposure:              true,
 		endpoint.StatsCurrentExposure:       true,
 		endpoint.StatsOpen:                  true,
 		endpoint.StatsFixed:                 true,
+		endpoint.StatsTrend:                 true,
 		endpoint.GlobalStatsMTTR:            true,
 		endpoint.GlobalStatsExposure:        true,
 		endpoint.GlobalStatsCurrentExposure: true,
@@ -95,3 +95,43 @@ func TestInitConfigGlobalPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestInitConfigGlobalEntities(t *testing.T) {
+	want := global.Config{
+		ReloadInterval: 300,
+		Definitions: global.Definitions{
+			Groups: []global.GroupDefinition{
+				{
+					Name:        "prod-web-global",
+					Description: "Production web assets",
+					Selector:    `type in (Hostname, WebAddress) and annotations["env"] == "prod"`,
+				},
+			},
+			Policies: []global.PolicyDefinition{
+				{
+					Name:        "web-global",
+					Description: "Web checks",
+					GlobalPolicyConfigEntry: global.GlobalPolicyConfigEntry{
+						AllowedChecks:     []string{"vulcan-zap"},
+						ExcludingSuffixes: []string{"-experimental"},
+					},
+				},
+			},
+			Programs: []global.ProgramDefinition{
+				{
+					ID:       "prod-web-scan",
+					Name:     "Production Web Scan",
+					Cron:     "0 4 * * 1",
+					Autosend: true,
+					Policies: []global.PolicyGroup{{Group: "prod-web-global", Policy: "web-global"}},
+				},
+			},
+		},
+	}
+	cfgFile = "testdata/globalentities/definitions.toml"
+	cfg = config{}
+	initConfig()
+	if !reflect.DeepEqual(cfg.GlobalEntities, want) {
+		t.Errorf("unexpeced global entities config parsing:\ngot=\n%#v\n, want=\n%#v", cfg.GlobalEntities, want)
+	}
+}
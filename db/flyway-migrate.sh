@@ -7,8 +7,8 @@ docker exec vulcanito_db psql -c "CREATE DATABASE vulcanito_test;" -U vulcanito
 
 docker run -q --net=host --rm -v "$PWD":/scripts flyway/flyway:"${FLYWAY_VERSION:-10}-alpine" \
     -user=vulcanito -password=vulcanito -url=jdbc:postgresql://localhost:5432/vulcanito \
-    -locations=filesystem:/scripts/sql -baselineOnMigrate=true migrate
+    -locations=filesystem:/scripts/sql -placeholders.vulcantracker_teams= -baselineOnMigrate=true migrate
 
 docker run -q --net=host --rm -v "$PWD":/scripts flyway/flyway:"${FLYWAY_VERSION:-10}-alpine" \
     -user=vulcanito_test -password=vulcanito_test -url=jdbc:postgresql://localhost:5432/vulcanito_test \
-    -locations=filesystem:/scripts/sql,filesystem:/scripts/test-sql -baselineOnMigrate=true migrate
+    -locations=filesystem:/scripts/sql,filesystem:/scripts/test-sql -placeholders.vulcantracker_teams= -baselineOnMigrate=true migrate
@@ -0,0 +1,18 @@
+CREATE TABLE finding_verifications (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    finding_id  UUID NOT NULL,
+    team_id     UUID NOT NULL,
+    scan_id     UUID NOT NULL,
+    checktype   TEXT NOT NULL,
+    target      TEXT NOT NULL,
+    user_id     UUID NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_finding_verifications_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_finding_verifications_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id)
+);
+
+CREATE INDEX idx_finding_verifications_finding_id ON finding_verifications (finding_id);
@@ -0,0 +1,21 @@
+CREATE TABLE scan_queue_entries (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id          UUID NOT NULL,
+    program_id       TEXT NOT NULL,
+    scheduled_time   TIMESTAMP WITH TIME ZONE,
+    requested_by     TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    scan_id          UUID,
+    check_count      INTEGER NOT NULL DEFAULT 0,
+    checks_in_flight INTEGER NOT NULL DEFAULT 0,
+    error            TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at       TIMESTAMP WITH TIME ZONE,
+    started_at       TIMESTAMP WITH TIME ZONE,
+    finished_at      TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_scan_queue_entries_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scan_queue_entries_status ON scan_queue_entries (status, created_at);
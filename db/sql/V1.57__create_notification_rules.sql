@@ -0,0 +1,17 @@
+CREATE TABLE notification_rules (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id      UUID NOT NULL,
+    program_id   TEXT NOT NULL,
+    trigger      TEXT NOT NULL,
+    min_severity TEXT NOT NULL DEFAULT '',
+    channel      TEXT NOT NULL,
+    url          TEXT NOT NULL DEFAULT '',
+    disabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_notification_rules_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_rules_program ON notification_rules (program_id, team_id);
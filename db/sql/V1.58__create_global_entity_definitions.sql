@@ -0,0 +1,10 @@
+CREATE TABLE global_entity_definitions (
+    kind       TEXT NOT NULL,
+    name       TEXT NOT NULL,
+    definition JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (kind, name),
+    CONSTRAINT chk_global_entity_definitions_kind
+        CHECK (kind IN ('group', 'policy', 'program'))
+);
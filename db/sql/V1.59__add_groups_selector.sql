@@ -0,0 +1 @@
+ALTER TABLE groups ADD COLUMN selector TEXT;
@@ -0,0 +1,27 @@
+CREATE TABLE policy_templates (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        TEXT NOT NULL UNIQUE,
+    description TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE policy_template_settings (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    policy_template_id UUID NOT NULL,
+    check_type_name    TEXT NOT NULL,
+    options            TEXT,
+    created_at         TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_policy_template_settings_template
+        FOREIGN KEY(policy_template_id)
+        REFERENCES policy_templates(id) ON DELETE CASCADE,
+    CONSTRAINT uq_policy_template_settings_checktype
+        UNIQUE (policy_template_id, check_type_name)
+);
+
+ALTER TABLE policies ADD COLUMN template_id UUID;
+ALTER TABLE policies ADD CONSTRAINT fk_policies_template
+    FOREIGN KEY (template_id) REFERENCES policy_templates(id);
+
+ALTER TABLE checktype_settings ADD COLUMN excluded BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE global_entity_definitions DROP CONSTRAINT chk_global_entity_definitions_kind;
+ALTER TABLE global_entity_definitions ADD CONSTRAINT chk_global_entity_definitions_kind
+    CHECK (kind IN ('group', 'policy', 'program', 'policy_config'));
@@ -0,0 +1,22 @@
+CREATE TABLE asset_checktype_settings (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id        UUID NOT NULL,
+    asset_id       UUID NOT NULL,
+    checktype_name TEXT NOT NULL,
+    excluded       BOOLEAN NOT NULL DEFAULT FALSE,
+    options        TEXT,
+    justification  TEXT NOT NULL,
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_asset_checktype_settings_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_asset_checktype_settings_asset
+        FOREIGN KEY(asset_id)
+        REFERENCES assets(id) ON DELETE CASCADE,
+    CONSTRAINT uq_asset_checktype_settings_checktype
+        UNIQUE (asset_id, checktype_name)
+);
+
+CREATE INDEX idx_asset_checktype_settings_team_id ON asset_checktype_settings (team_id);
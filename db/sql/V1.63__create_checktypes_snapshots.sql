@@ -0,0 +1,5 @@
+CREATE TABLE checktypes_snapshots (
+    name       TEXT PRIMARY KEY,
+    content    TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,35 @@
+CREATE TABLE report_subscriptions (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id      UUID NOT NULL,
+    name         TEXT NOT NULL,
+    report_id    TEXT NOT NULL,
+    cron         TEXT NOT NULL,
+    min_severity TEXT,
+    group_id     UUID,
+    disabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    next_run_at  TIMESTAMP WITH TIME ZONE,
+    last_run_at  TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at   TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_report_subscriptions_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_report_subscriptions_group
+        FOREIGN KEY(group_id)
+        REFERENCES groups(id) ON DELETE CASCADE,
+    CONSTRAINT uq_report_subscriptions_name
+        UNIQUE (team_id, name)
+);
+
+CREATE INDEX idx_report_subscriptions_next_run_at ON report_subscriptions (next_run_at) WHERE NOT disabled;
+
+CREATE TABLE report_subscription_recipients (
+    subscription_id UUID NOT NULL,
+    email           TEXT NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (subscription_id, email),
+    CONSTRAINT fk_report_subscription_recipients_subscription
+        FOREIGN KEY(subscription_id)
+        REFERENCES report_subscriptions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+ALTER TABLE recipients ADD COLUMN report_types TEXT NOT NULL DEFAULT '';
+ALTER TABLE recipients ADD COLUMN verified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE recipients ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE recipients ADD COLUMN deactivated_reason TEXT;
+
+-- The existing recipients were added before their addresses had to be
+-- verified, so they keep receiving the reports.
+UPDATE recipients SET verified_at = COALESCE(created_at, NOW());
+
+CREATE INDEX idx_recipients_email ON recipients (lower(email));
@@ -0,0 +1,33 @@
+CREATE TABLE risk_acceptances (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id         UUID NOT NULL,
+    finding_id      TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    status_previous TEXT NOT NULL,
+    justification   TEXT NOT NULL,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    requested_by    UUID NOT NULL,
+    reviewed_by     UUID,
+    review_notes    TEXT,
+    reviewed_at     TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_risk_acceptances_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_risk_acceptances_requested_by
+        FOREIGN KEY(requested_by)
+        REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_risk_acceptances_reviewed_by
+        FOREIGN KEY(reviewed_by)
+        REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_risk_acceptances_finding ON risk_acceptances (team_id, finding_id);
+
+-- A finding can only have one acceptance waiting for review or in force.
+CREATE UNIQUE INDEX uq_risk_acceptances_active ON risk_acceptances (team_id, finding_id)
+    WHERE status IN ('PENDING', 'APPROVED');
+
+CREATE INDEX idx_risk_acceptances_expires_at ON risk_acceptances (expires_at)
+    WHERE status IN ('PENDING', 'APPROVED');
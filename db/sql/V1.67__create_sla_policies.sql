@@ -0,0 +1,32 @@
+CREATE TABLE sla_policies (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID,
+    severity    TEXT NOT NULL,
+    rolfp_level SMALLINT,
+    days        INTEGER NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_sla_policies_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- The team_id and rolfp_level columns are nullable, so the uniqueness of the
+-- policies is enforced over expressions that give a value to the nulls.
+CREATE UNIQUE INDEX uq_sla_policies ON sla_policies (
+    COALESCE(team_id, '00000000-0000-0000-0000-000000000000'),
+    severity,
+    COALESCE(rolfp_level, -1)
+);
+
+-- Breaches already notified. A breach is only notified once per finding.
+CREATE TABLE sla_breaches (
+    team_id     UUID NOT NULL,
+    finding_id  TEXT NOT NULL,
+    due_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    notified_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (team_id, finding_id),
+    CONSTRAINT fk_sla_breaches_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
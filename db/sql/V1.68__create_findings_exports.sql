@@ -0,0 +1,14 @@
+CREATE TABLE findings_exports (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id    UUID NOT NULL,
+    job_id     UUID,
+    format     TEXT NOT NULL,
+    findings   INTEGER NOT NULL DEFAULT 0,
+    content    BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_findings_exports_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_findings_exports_team_id ON findings_exports (team_id);
@@ -0,0 +1,47 @@
+CREATE TABLE finding_comments (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id    UUID NOT NULL,
+    finding_id TEXT NOT NULL,
+    author_id  UUID NOT NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_finding_comments_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_finding_comments_author
+        FOREIGN KEY(author_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_finding_comments_finding ON finding_comments (team_id, finding_id);
+
+CREATE TABLE finding_comment_mentions (
+    comment_id UUID NOT NULL,
+    user_id    UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id),
+    CONSTRAINT fk_finding_comment_mentions_comment
+        FOREIGN KEY(comment_id)
+        REFERENCES finding_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_finding_comment_mentions_user
+        FOREIGN KEY(user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Each row stores the body a comment had before one of its edits.
+CREATE TABLE finding_comment_edits (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL,
+    body       TEXT NOT NULL,
+    edited_by  UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_finding_comment_edits_comment
+        FOREIGN KEY(comment_id)
+        REFERENCES finding_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_finding_comment_edits_user
+        FOREIGN KEY(edited_by)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_finding_comment_edits_comment ON finding_comment_edits (comment_id);
@@ -0,0 +1,30 @@
+-- The integration with the ticket tracker is enabled per team instead of
+-- using the list of onboarded teams of the configuration.
+ALTER TABLE teams ADD COLUMN using_tracker BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE finding_tickets (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID NOT NULL,
+    finding_id  TEXT NOT NULL,
+    ticket_id   TEXT NOT NULL,
+    ticket_key  TEXT,
+    url_tracker TEXT,
+    status      TEXT,
+    resolution  TEXT,
+    created_by  UUID,
+    closed_at   TIMESTAMP WITH TIME ZONE,
+    synced_at   TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_finding_tickets_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE,
+    CONSTRAINT fk_finding_tickets_created_by
+        FOREIGN KEY(created_by)
+        REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX uq_finding_tickets_finding ON finding_tickets (team_id, finding_id);
+
+CREATE INDEX idx_finding_tickets_open ON finding_tickets (team_id)
+    WHERE closed_at IS NULL;
@@ -0,0 +1,15 @@
+-- The ticket provider of a team. The teams without a ticket provider create
+-- their tickets through vulcan-tracker. The credentials of the providers are
+-- not stored, only the name they are referenced by.
+CREATE TABLE team_ticket_providers (
+    team_id         UUID PRIMARY KEY,
+    type            TEXT NOT NULL,
+    url             TEXT,
+    project         TEXT,
+    credentials_ref TEXT,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_team_ticket_providers_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
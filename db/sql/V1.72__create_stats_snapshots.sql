@@ -0,0 +1,20 @@
+-- The daily snapshots of the stats of the findings of the teams. Each row
+-- contains the stats of the findings of a severity of a team, or of a group
+-- of a team, at the end of a day. The snapshots of the whole team have an
+-- empty group_id. The snapshots of a group are kept after the group is
+-- deleted, as they are part of the history of the team.
+CREATE TABLE stats_snapshots (
+    team_id       UUID NOT NULL,
+    group_id      TEXT NOT NULL DEFAULT '',
+    date          DATE NOT NULL,
+    severity      TEXT NOT NULL,
+    open_count    INTEGER NOT NULL DEFAULT 0,
+    new_count     INTEGER NOT NULL DEFAULT 0,
+    fixed_count   INTEGER NOT NULL DEFAULT 0,
+    exposure_mean REAL NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (team_id, group_id, date, severity),
+    CONSTRAINT fk_stats_snapshots_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
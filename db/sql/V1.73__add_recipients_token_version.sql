@@ -0,0 +1,3 @@
+-- The version is included in the tokens sent to the recipients and increased
+-- when a recipient is deactivated, which revokes the tokens sent before.
+ALTER TABLE recipients ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
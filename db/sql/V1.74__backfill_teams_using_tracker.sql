@@ -0,0 +1,7 @@
+-- Enable the integration with the ticket tracker for the teams onboarded
+-- through the former VULCANTRACKER_TEAMS setting, a comma separated list of
+-- team ids or * for all the teams, which is passed to this migration as a
+-- placeholder.
+UPDATE teams SET using_tracker = TRUE
+WHERE '${vulcantracker_teams}' = '*'
+   OR id::text = ANY(string_to_array(replace('${vulcantracker_teams}', ' ', ''), ','));
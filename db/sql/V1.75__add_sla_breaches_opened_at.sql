@@ -0,0 +1,12 @@
+-- A finding that is fixed and opened again is notified again when it breaches
+-- its SLA, so the breaches are recorded per open cycle of the findings.
+ALTER TABLE sla_breaches ADD COLUMN opened_at TIMESTAMP WITH TIME ZONE;
+
+-- The open time of the findings of the existing breaches was not recorded.
+-- Their notification time identifies their open cycle as well, as a breach is
+-- notified only once per cycle.
+UPDATE sla_breaches SET opened_at = notified_at;
+
+ALTER TABLE sla_breaches ALTER COLUMN opened_at SET NOT NULL;
+ALTER TABLE sla_breaches DROP CONSTRAINT sla_breaches_pkey;
+ALTER TABLE sla_breaches ADD PRIMARY KEY (team_id, finding_id, opened_at);
@@ -0,0 +1,30 @@
+-- The content of the exports is stored in parts, so they are generated and
+-- downloaded without keeping the whole export in memory.
+CREATE TABLE findings_export_parts (
+    export_id UUID NOT NULL,
+    part      INTEGER NOT NULL,
+    content   BYTEA NOT NULL,
+    PRIMARY KEY (export_id, part),
+    CONSTRAINT fk_findings_export_parts_export
+        FOREIGN KEY(export_id)
+        REFERENCES findings_exports(id) ON DELETE CASCADE
+);
+
+INSERT INTO findings_export_parts (export_id, part, content)
+    SELECT id, 0, content FROM findings_exports;
+
+-- The exports are generated by the API instances claiming the pending ones,
+-- so the params of the export are stored with it.
+ALTER TABLE findings_exports
+    ADD COLUMN params     JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN status     TEXT NOT NULL DEFAULT 'DONE',
+    ADD COLUMN size       BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE findings_exports SET size = octet_length(content);
+
+ALTER TABLE findings_exports
+    DROP COLUMN content,
+    ALTER COLUMN status SET DEFAULT 'PENDING';
+
+CREATE INDEX idx_findings_exports_status ON findings_exports (status, created_at);
@@ -0,0 +1,15 @@
+-- The claims of the stats snapshots of the teams. A row is inserted by the
+-- instance of the API that snapshots the stats of a team of a day, and it is
+-- marked as done once the snapshots are stored, so no other instance, or the
+-- same one after a restart, computes them again. A claim not done can be
+-- taken again after some time, in case the instance that took it stopped.
+CREATE TABLE stats_snapshot_claims (
+    team_id    UUID NOT NULL,
+    date       DATE NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    done       BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (team_id, date),
+    CONSTRAINT fk_stats_snapshot_claims_team
+        FOREIGN KEY(team_id)
+        REFERENCES teams(id) ON DELETE CASCADE
+);
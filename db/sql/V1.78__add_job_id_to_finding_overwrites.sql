@@ -0,0 +1,3 @@
+ALTER TABLE finding_overwrites ADD COLUMN job_id UUID;
+
+CREATE UNIQUE INDEX idx_finding_overwrites_job_id ON finding_overwrites (job_id, finding_id) WHERE job_id IS NOT NULL;
@@ -0,0 +1,5 @@
+CREATE TABLE global_policy_evaluations (
+    policy     TEXT PRIMARY KEY,
+    evaluation JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
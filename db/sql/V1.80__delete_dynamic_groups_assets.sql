@@ -0,0 +1 @@
+DELETE FROM asset_group WHERE group_id IN (SELECT id FROM groups WHERE selector IS NOT NULL AND selector <> '');
@@ -42,6 +42,9 @@ type Asset struct {
 	CreatedAt         time.Time          `json:"-"`
 	UpdatedAt         time.Time          `json:"-"`
 	ClassifiedAt      *time.Time         `json:"classified_at"`
+	// ChecktypeSettings are not loaded with the asset. They are set before
+	// building the scans of the programs the asset belongs to.
+	ChecktypeSettings []*AssetChecktypeSetting `gorm:"-" json:"-"`
 }
 
 func validateAWSARN(arn string) bool {
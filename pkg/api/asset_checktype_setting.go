@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+// AssetChecktypeSetting excludes a checktype from the scans of an asset, or
+// overrides the options the policies define for the checktype when it runs
+// against the asset. Every setting must be justified and expires at a given
+// date, after which it is ignored, so exclusions do not become permanent
+// blind spots.
+type AssetChecktypeSetting struct {
+	ID            string     `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID        string     `json:"team_id"`
+	AssetID       string     `json:"asset_id"`
+	ChecktypeName string     `json:"checktype_name"`
+	Excluded      *bool      `json:"excluded" sql:"DEFAULT:false"`
+	Options       *string    `json:"options"`
+	Justification string     `json:"justification"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	CreatedAt     time.Time  `json:"-"`
+	UpdatedAt     time.Time  `json:"-"`
+}
+
+// Validate checks the setting is justified, has not expired at the given time
+// and either excludes the checktype or overrides its options.
+func (s AssetChecktypeSetting) Validate(now time.Time) error {
+	if s.ChecktypeName == "" {
+		return errors.Validation("checktype name is required")
+	}
+	if s.Justification == "" {
+		return errors.Validation("justification is required")
+	}
+	if s.ExpiresAt == nil {
+		return errors.Validation("expiration date is required")
+	}
+	if !s.ExpiresAt.After(now) {
+		return errors.Validation("expiration date must be in the future")
+	}
+	if s.IsExcluded() && s.Options != nil {
+		return errors.Validation("an excluded checktype can not override options")
+	}
+	if !s.IsExcluded() && common.IsStringEmpty(s.Options) {
+		return errors.Validation("the setting must either exclude the checktype or override its options")
+	}
+	if !common.IsStringEmpty(s.Options) && !common.IsValidJSON(s.Options) {
+		return errors.Validation("options field has invalid json")
+	}
+	return nil
+}
+
+// IsExcluded returns true if the checktype must not be run against the
+// asset.
+func (s AssetChecktypeSetting) IsExcluded() bool {
+	return s.Excluded != nil && *s.Excluded
+}
+
+// IsActive returns true if the setting has not expired at the given time.
+func (s AssetChecktypeSetting) IsActive(now time.Time) bool {
+	return s.ExpiresAt != nil && s.ExpiresAt.After(now)
+}
+
+type AssetChecktypeSettingResponse struct {
+	ID            string    `json:"id"`
+	AssetID       string    `json:"asset_id"`
+	ChecktypeName string    `json:"checktype_name"`
+	Excluded      bool      `json:"excluded"`
+	Options       string    `json:"options,omitempty"`
+	Justification string    `json:"justification"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Expired       bool      `json:"expired"`
+}
+
+func (s AssetChecktypeSetting) ToResponse() AssetChecktypeSettingResponse {
+	response := AssetChecktypeSettingResponse{
+		ID:            s.ID,
+		AssetID:       s.AssetID,
+		ChecktypeName: s.ChecktypeName,
+		Excluded:      s.IsExcluded(),
+		Options:       common.StringValue(s.Options),
+		Justification: s.Justification,
+		Expired:       !s.IsActive(time.Now()),
+	}
+	if s.ExpiresAt != nil {
+		response.ExpiresAt = *s.ExpiresAt
+	}
+	return response
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+func TestAssetChecktypeSettingValidate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := now.Add(24 * time.Hour)
+	past := now.Add(-24 * time.Hour)
+	tests := []struct {
+		name    string
+		setting AssetChecktypeSetting
+		wantErr bool
+	}{
+		{
+			name:    "Exclusion",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-nessus", Excluded: common.Bool(true), Justification: "fragile host", ExpiresAt: &future},
+		},
+		{
+			name:    "Override",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-zap", Options: common.String(`{"depth":1}`), Justification: "slow app", ExpiresAt: &future},
+		},
+		{
+			name:    "NoJustification",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-nessus", Excluded: common.Bool(true), ExpiresAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "NoExpiration",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-nessus", Excluded: common.Bool(true), Justification: "fragile host"},
+			wantErr: true,
+		},
+		{
+			name:    "Expired",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-nessus", Excluded: common.Bool(true), Justification: "fragile host", ExpiresAt: &past},
+			wantErr: true,
+		},
+		{
+			name:    "NothingToApply",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-zap", Justification: "slow app", ExpiresAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "ExcludedWithOptions",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-zap", Excluded: common.Bool(true), Options: common.String(`{}`), Justification: "slow app", ExpiresAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "InvalidOptions",
+			setting: AssetChecktypeSetting{ChecktypeName: "vulcan-zap", Options: common.String(`{`), Justification: "slow app", ExpiresAt: &future},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.setting.Validate(now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -38,8 +38,13 @@ type Group struct {
 	Options     string        `json:"options"`
 	AssetGroup  []*AssetGroup `json:"asset_group"` // This line is infered from other tables.
 	Description *string       `json:"description,omitempty"`
-	CreatedAt   time.Time     `json:"-"`
-	UpdatedAt   time.Time     `json:"-"`
+	// Selector, when set, makes the group dynamic: its assets are the assets
+	// of the team matching the expression, evaluated every time the group
+	// is read, instead of the ones stored in the asset_group table. See
+	// AssetSelector for the syntax of the expressions.
+	Selector  *string   `json:"selector,omitempty"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
 }
 
 // Overwrite gorm default pluralized table name convention
@@ -52,6 +57,7 @@ type GroupResponse struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description"`
 	Options     string  `json:"options"`
+	Selector    *string `json:"selector,omitempty"`
 	AssetsCount *int    `json:"assets_count,omitempty"`
 }
 
@@ -61,6 +67,7 @@ func (g Group) ToResponse() *GroupResponse {
 		Name:        g.Name,
 		Options:     g.Options,
 		Description: g.Description,
+		Selector:    g.Selector,
 	}
 
 	if g.AssetGroup != nil {
@@ -110,5 +117,41 @@ func (g Group) Validate() error {
 	if !common.IsStringEmpty(&g.Options) && !common.IsValidJSON(&g.Options) {
 		return errors.Validation("group.options field identified by has invalid json")
 	}
+	if g.IsDynamic() {
+		if _, err := ParseAssetSelector(*g.Selector); err != nil {
+			return errors.Validation(err)
+		}
+	}
+	return nil
+}
+
+// IsDynamic returns true if the assets of the group are defined by a
+// selector expression.
+func (g Group) IsDynamic() bool {
+	return g.Selector != nil && *g.Selector != ""
+}
+
+// SelectAssets sets the assets of a dynamic group to the given assets that
+// match its selector. The assets must have their type, annotations and
+// static groups loaded. It does nothing if the group is not dynamic.
+func (g *Group) SelectAssets(assets []*Asset) error {
+	if !g.IsDynamic() {
+		return nil
+	}
+	selector, err := ParseAssetSelector(*g.Selector)
+	if err != nil {
+		return errors.Validation(err)
+	}
+	g.AssetGroup = []*AssetGroup{}
+	for _, a := range assets {
+		if !selector.Matches(a) {
+			continue
+		}
+		g.AssetGroup = append(g.AssetGroup, &AssetGroup{
+			AssetID: a.ID,
+			Asset:   a,
+			GroupID: g.ID,
+		})
+	}
 	return nil
 }
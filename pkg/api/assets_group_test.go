@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroup_SelectAssets(t *testing.T) {
+	assets := []*Asset{
+		{
+			ID:               "a1",
+			AssetType:        &AssetType{Name: "Hostname"},
+			AssetAnnotations: []*AssetAnnotation{{Key: "env", Value: "prod"}},
+		},
+		{ID: "a2", AssetType: &AssetType{Name: "Hostname"}},
+		{
+			ID:               "a3",
+			AssetType:        &AssetType{Name: "IP"},
+			AssetAnnotations: []*AssetAnnotation{{Key: "env", Value: "prod"}},
+		},
+	}
+	selector := `type in (Hostname, WebAddress) and annotations["env"] == "prod"`
+	empty := ""
+	tests := []struct {
+		name    string
+		group   Group
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Dynamic",
+			group: Group{ID: "g1", Selector: &selector},
+			want:  []string{"a1"},
+		},
+		{
+			name:  "Static",
+			group: Group{ID: "g1", AssetGroup: []*AssetGroup{{AssetID: "a2", GroupID: "g1"}}},
+			want:  []string{"a2"},
+		},
+		{
+			name:  "EmptySelector",
+			group: Group{ID: "g1", Selector: &empty, AssetGroup: []*AssetGroup{{AssetID: "a3", GroupID: "g1"}}},
+			want:  []string{"a3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.group
+			err := g.SelectAssets(assets)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Group.SelectAssets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := []string{}
+			for _, ag := range g.AssetGroup {
+				if ag.GroupID != g.ID {
+					t.Errorf("asset %s has group id %s, want %s", ag.AssetID, ag.GroupID, g.ID)
+				}
+				got = append(got, ag.AssetID)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("assets mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestGroup_Validate(t *testing.T) {
+	valid := `type == Hostname and rolfp.level >= 2`
+	invalid := `type >= Hostname`
+	tests := []struct {
+		name    string
+		group   Group
+		wantErr bool
+	}{
+		{name: "Static", group: Group{Name: "web"}},
+		{name: "ValidSelector", group: Group{Name: "web", Selector: &valid}},
+		{name: "InvalidSelector", group: Group{Name: "web", Selector: &invalid}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.group.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Group.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
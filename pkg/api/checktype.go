@@ -0,0 +1,53 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Checktype describes a checktype that can be run in a scan.
+type Checktype struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version"`
+	Image       string   `json:"image"`
+	AssetTypes  []string `json:"assettypes"`
+	// Options contains the default options of the checktype.
+	Options *string `json:"options,omitempty"`
+	// OptionsSchema is the JSON schema the options of the checktype settings
+	// of the policies must conform to.
+	OptionsSchema json.RawMessage `json:"options_schema"`
+}
+
+// ChecktypesSnapshot stores the last checktypes information successfully
+// read from vulcan-core, so it can be used when vulcan-core is not
+// available.
+type ChecktypesSnapshot struct {
+	Name string `gorm:"primary_key"`
+	// Content is the JSON encoded information.
+	Content   string
+	UpdatedAt time.Time
+}
+
+// Checktypes cache sources.
+const (
+	ChecktypesSourceVulcanCore = "vulcan-core"
+	ChecktypesSourceDatabase   = "database"
+)
+
+// ChecktypesCacheStatus describes how fresh the cached checktypes
+// information is.
+type ChecktypesCacheStatus struct {
+	// Source is where the cached information was loaded from.
+	Source     string     `json:"source,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	AgeSeconds int64      `json:"age_seconds"`
+	// Stale is true when the information is older than the TTL of the
+	// cache, usually because vulcan-core is not available.
+	Stale     bool   `json:"stale"`
+	LastError string `json:"last_error,omitempty"`
+}
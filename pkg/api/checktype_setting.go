@@ -12,19 +12,23 @@ import (
 )
 
 type ChecktypeSetting struct {
-	ID            string     `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
-	PolicyID      string     `json:"policy_id"`
-	Policy        *Policy    `json:"policy"` // This line is infered from column name "policy_id".
-	CheckTypeName string     `json:"checktype_name"`
-	Options       *string    `json:"options"`
-	CreatedAt     *time.Time `json:"-"`
-	UpdatedAt     *time.Time `json:"-"`
+	ID            string  `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	PolicyID      string  `json:"policy_id"`
+	Policy        *Policy `json:"policy"` // This line is infered from column name "policy_id".
+	CheckTypeName string  `json:"checktype_name"`
+	Options       *string `json:"options"`
+	// Excluded removes the checktype from the settings the policy inherits
+	// from its template.
+	Excluded  *bool      `json:"excluded" sql:"DEFAULT:false"`
+	CreatedAt *time.Time `json:"-"`
+	UpdatedAt *time.Time `json:"-"`
 }
 
 type ChecktypeSettingResponse struct {
 	ID            string `json:"id"`
 	CheckTypeName string `json:"checktype_name"`
 	Options       string `json:"options"`
+	Excluded      bool   `json:"excluded,omitempty"`
 }
 
 func (c ChecktypeSetting) ToResponse() *ChecktypeSettingResponse {
@@ -32,10 +36,17 @@ func (c ChecktypeSetting) ToResponse() *ChecktypeSettingResponse {
 		ID:            c.ID,
 		CheckTypeName: c.CheckTypeName,
 		Options:       common.StringValue(c.Options),
+		Excluded:      c.IsExcluded(),
 	}
 	return &response
 }
 
+// IsExcluded returns true if the setting excludes its checktype from the
+// ones inherited from the template of the policy.
+func (c ChecktypeSetting) IsExcluded() bool {
+	return c.Excluded != nil && *c.Excluded
+}
+
 func (c ChecktypeSetting) Validate() error {
 	if common.IsStringEmpty(&c.CheckTypeName) ||
 		(!common.IsStringEmpty(c.Options) && !common.IsValidJSON(c.Options)) {
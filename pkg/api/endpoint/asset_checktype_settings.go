@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type AssetChecktypeSettingRequest struct {
+	ID            string     `json:"id" urlvar:"setting_id"`
+	TeamID        string     `json:"team_id" urlvar:"team_id"`
+	AssetID       string     `json:"asset_id" urlvar:"asset_id"`
+	ChecktypeName string     `json:"checktype_name"`
+	Excluded      *bool      `json:"excluded"`
+	Options       *string    `json:"options"`
+	Justification string     `json:"justification"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+func (r AssetChecktypeSettingRequest) setting() api.AssetChecktypeSetting {
+	return api.AssetChecktypeSetting{
+		ID:            r.ID,
+		TeamID:        r.TeamID,
+		AssetID:       r.AssetID,
+		ChecktypeName: r.ChecktypeName,
+		Excluded:      r.Excluded,
+		Options:       r.Options,
+		Justification: r.Justification,
+		ExpiresAt:     r.ExpiresAt,
+	}
+}
+
+func makeListAssetChecktypeSettingsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*AssetChecktypeSettingRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if r.AssetID == "" {
+			return nil, errors.NotFound(`Asset ID is empty`)
+		}
+		settings, err := s.ListAssetChecktypeSettings(ctx, r.TeamID, r.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.AssetChecktypeSettingResponse{}
+		for _, setting := range settings {
+			response = append(response, setting.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeCreateAssetChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*AssetChecktypeSettingRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		setting, err := s.CreateAssetChecktypeSetting(ctx, r.setting())
+		if err != nil {
+			return nil, err
+		}
+		return Created{setting.ToResponse()}, nil
+	}
+}
+
+func makeUpdateAssetChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*AssetChecktypeSettingRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		setting, err := s.UpdateAssetChecktypeSetting(ctx, r.setting())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{setting.ToResponse()}, nil
+	}
+}
+
+func makeDeleteAssetChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*AssetChecktypeSettingRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeleteAssetChecktypeSetting(ctx, r.setting()); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
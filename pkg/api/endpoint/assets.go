@@ -310,10 +310,11 @@ func makeListGroupsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoi
 }
 
 type AssetsGroupRequest struct {
-	ID      string `json:"id" urlvar:"group_id"`
-	TeamID  string `json:"team_id" urlvar:"team_id"`
-	Name    string `json:"name"`
-	Options string `json:"options"`
+	ID       string  `json:"id" urlvar:"group_id"`
+	TeamID   string  `json:"team_id" urlvar:"team_id"`
+	Name     string  `json:"name"`
+	Options  string  `json:"options"`
+	Selector *string `json:"selector"`
 }
 
 func makeCreateGroupEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
@@ -322,7 +323,12 @@ func makeCreateGroupEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpo
 		if !ok {
 			return nil, errors.Assertion("Type assertion failed")
 		}
-		assetGroup := api.Group{TeamID: requestBody.TeamID, Name: requestBody.Name, Options: requestBody.Options}
+		assetGroup := api.Group{
+			TeamID:   requestBody.TeamID,
+			Name:     requestBody.Name,
+			Options:  requestBody.Options,
+			Selector: requestBody.Selector,
+		}
 		assetsGroup, err := s.CreateGroup(ctx, assetGroup)
 		if err != nil {
 			return nil, err
@@ -338,10 +344,11 @@ func makeUpdateGroupEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpo
 			return nil, errors.Assertion("Type assertion failed")
 		}
 		group := api.Group{
-			ID:      requestBody.ID,
-			TeamID:  requestBody.TeamID,
-			Name:    requestBody.Name,
-			Options: requestBody.Options,
+			ID:       requestBody.ID,
+			TeamID:   requestBody.TeamID,
+			Name:     requestBody.Name,
+			Options:  requestBody.Options,
+			Selector: requestBody.Selector,
 		}
 		updated, err := s.UpdateGroup(ctx, group)
 		if err != nil {
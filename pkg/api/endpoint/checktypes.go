@@ -0,0 +1,24 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func makeListChecktypesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		checktypes, err := s.ListChecktypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{checktypes}, nil
+	}
+}
@@ -6,6 +6,7 @@ package endpoint
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/go-kit/kit/endpoint"
@@ -44,6 +45,15 @@ const (
 	ListRecipients   = "ListRecipients"
 	UpdateRecipients = "UpdateRecipients"
 
+	CreateRecipient             = "CreateRecipient"
+	UpdateRecipient             = "UpdateRecipient"
+	DeleteRecipient             = "DeleteRecipient"
+	SendRecipientVerification   = "SendRecipientVerification"
+	VerifyRecipient             = "VerifyRecipient"
+	UnsubscribeRecipient        = "UnsubscribeRecipient"
+	UnsubscribeRecipientPage    = "UnsubscribeRecipientPage"
+	DeactivateBouncedRecipients = "DeactivateBouncedRecipients"
+
 	ListAssets             = "ListAssets"
 	CreateAsset            = "CreateAsset"
 	CreateAssetMultiStatus = "CreateAssetMultiStatus"
@@ -58,6 +68,11 @@ const (
 	PutAssetAnnotations    = "PutAssetAnnotations"
 	DeleteAssetAnnotations = "DeleteAssetAnnotations"
 
+	ListAssetChecktypeSettings  = "ListAssetChecktypeSettings"
+	CreateAssetChecktypeSetting = "CreateAssetChecktypeSetting"
+	UpdateAssetChecktypeSetting = "UpdateAssetChecktypeSetting"
+	DeleteAssetChecktypeSetting = "DeleteAssetChecktypeSetting"
+
 	CreateGroup = "CreateGroup"
 	ListGroups  = "ListGroups"
 	UpdateGroup = "UpdateGroup"
@@ -73,6 +88,7 @@ const (
 	FindProgram   = "FindProgram"
 	UpdateProgram = "UpdateProgram"
 	DeleteProgram = "DeleteProgram"
+	ProgramPlan   = "ProgramPlan"
 
 	CreateSchedule        = "CreateSchedule"
 	DeleteSchedule        = "DeleteSchedule"
@@ -90,12 +106,38 @@ const (
 	UpdateChecktypeSetting = "UpdateChecktypeSetting"
 	DeleteChecktypeSetting = "DeleteChecktypeSetting"
 
-	ListProgramScans = "ListProgramScans"
-	CreateScan       = "CreateScan"
-	FindScan         = "FindScan"
-	AbortScan        = "AbortScan"
+	ListChecktypes = "ListChecktypes"
+
+	ListPolicyTemplates  = "ListPolicyTemplates"
+	CreatePolicyTemplate = "CreatePolicyTemplate"
+	FindPolicyTemplate   = "FindPolicyTemplate"
+	UpdatePolicyTemplate = "UpdatePolicyTemplate"
+	DeletePolicyTemplate = "DeletePolicyTemplate"
+	PolicyDivergence     = "PolicyDivergence"
+
+	ListGlobalPolicyEvaluations = "ListGlobalPolicyEvaluations"
+
+	ListProgramScans     = "ListProgramScans"
+	CreateScan           = "CreateScan"
+	FindScan             = "FindScan"
+	AbortScan            = "AbortScan"
+	ListScanQueue        = "ListScanQueue"
+	CancelScanQueueEntry = "CancelScanQueueEntry"
+	DiffScans            = "DiffScans"
+
+	ListNotificationRules  = "ListNotificationRules"
+	CreateNotificationRule = "CreateNotificationRule"
+	UpdateNotificationRule = "UpdateNotificationRule"
+	DeleteNotificationRule = "DeleteNotificationRule"
 
-	SendDigestReport = "SendDigestReport"
+	ListReportSubscriptions  = "ListReportSubscriptions"
+	FindReportSubscription   = "FindReportSubscription"
+	CreateReportSubscription = "CreateReportSubscription"
+	UpdateReportSubscription = "UpdateReportSubscription"
+	DeleteReportSubscription = "DeleteReportSubscription"
+
+	SendDigestReport       = "SendDigestReport"
+	SendReportSubscription = "SendReportSubscription"
 
 	StatsCoverage              = "StatsCoverage"
 	ListIssues                 = "ListIssues"
@@ -107,13 +149,44 @@ const (
 	FindFinding                = "FindFinding"
 	CreateFindingOverwrite     = "CreateFindingOverwrite"
 	ListFindingOverwrites      = "ListFindingOverwrites"
+	CreateFindingOverwrites    = "CreateFindingOverwrites"
+	ListFindingComments        = "ListFindingComments"
+	CreateFindingComment       = "CreateFindingComment"
+	UpdateFindingComment       = "UpdateFindingComment"
+	DeleteFindingComment       = "DeleteFindingComment"
+	ListRiskAcceptances        = "ListRiskAcceptances"
+	FindRiskAcceptance         = "FindRiskAcceptance"
+	RequestRiskAcceptance      = "RequestRiskAcceptance"
+	ApproveRiskAcceptance      = "ApproveRiskAcceptance"
+	RejectRiskAcceptance       = "RejectRiskAcceptance"
+	VerifyFinding              = "VerifyFinding"
+	ListFindingVerifications   = "ListFindingVerifications"
 	ListFindingsLabels         = "ListFindingsLabels"
 	CreateFindingTicket        = "CreateFindingTicket"
+	CreateIssueTickets         = "CreateIssueTickets"
+	ListFindingTickets         = "ListFindingTickets"
+	UpdateTeamTracker          = "UpdateTeamTracker"
+	FindTeamTicketProvider     = "FindTeamTicketProvider"
+	UpdateTeamTicketProvider   = "UpdateTeamTicketProvider"
+	DeleteTeamTicketProvider   = "DeleteTeamTicketProvider"
+	ExportFindings             = "ExportFindings"
+	CreateFindingsExport       = "CreateFindingsExport"
+	DownloadFindingsExport     = "DownloadFindingsExport"
+	ListFindingsSLA            = "ListFindingsSLA"
+	ListTeamSLAPolicies        = "ListTeamSLAPolicies"
+	CreateTeamSLAPolicy        = "CreateTeamSLAPolicy"
+	UpdateTeamSLAPolicy        = "UpdateTeamSLAPolicy"
+	DeleteTeamSLAPolicy        = "DeleteTeamSLAPolicy"
+	ListSLAPolicies            = "ListSLAPolicies"
+	CreateSLAPolicy            = "CreateSLAPolicy"
+	UpdateSLAPolicy            = "UpdateSLAPolicy"
+	DeleteSLAPolicy            = "DeleteSLAPolicy"
 	StatsMTTR                  = "StatsMTTR"
 	StatsExposure              = "StatsExposure"
 	StatsCurrentExposure       = "StatsCurrentExposure"
 	StatsOpen                  = "StatsOpen"
 	StatsFixed                 = "StatsFixed"
+	StatsTrend                 = "StatsTrend"
 	GlobalStatsMTTR            = "GlobalStatsMTTR"
 	GlobalStatsExposure        = "GlobalStatsExposure"
 	GlobalStatsCurrentExposure = "GlobalStatsCurrentExposure"
@@ -156,6 +229,14 @@ func MakeEndpoints(s api.VulcanitoService, isJiraIntEnabled bool, logger log.Log
 
 	endpoints[ListRecipients] = makeListRecipientsEndpoint(s, logger)
 	endpoints[UpdateRecipients] = makeUpdateRecipientsEndpoint(s, logger)
+	endpoints[CreateRecipient] = makeCreateRecipientEndpoint(s, logger)
+	endpoints[UpdateRecipient] = makeUpdateRecipientEndpoint(s, logger)
+	endpoints[DeleteRecipient] = makeDeleteRecipientEndpoint(s, logger)
+	endpoints[SendRecipientVerification] = makeSendRecipientVerificationEndpoint(s, logger)
+	endpoints[VerifyRecipient] = makeVerifyRecipientEndpoint(s, logger)
+	endpoints[UnsubscribeRecipient] = makeUnsubscribeRecipientEndpoint(s, logger)
+	endpoints[UnsubscribeRecipientPage] = makeUnsubscribeRecipientPageEndpoint(s, logger)
+	endpoints[DeactivateBouncedRecipients] = makeDeactivateBouncedRecipientsEndpoint(s, logger)
 
 	endpoints[ListAssets] = makeListAssetsEndpoint(s, logger)
 	endpoints[CreateAsset] = makeCreateAssetEndpoint(s, logger)
@@ -171,6 +252,11 @@ func MakeEndpoints(s api.VulcanitoService, isJiraIntEnabled bool, logger log.Log
 	endpoints[PutAssetAnnotations] = makePutAssetAnnotationsEndpoint(s, logger)
 	endpoints[DeleteAssetAnnotations] = makeDeleteAssetAnnotationsEndpoint(s, logger)
 
+	endpoints[ListAssetChecktypeSettings] = makeListAssetChecktypeSettingsEndpoint(s, logger)
+	endpoints[CreateAssetChecktypeSetting] = makeCreateAssetChecktypeSettingEndpoint(s, logger)
+	endpoints[UpdateAssetChecktypeSetting] = makeUpdateAssetChecktypeSettingEndpoint(s, logger)
+	endpoints[DeleteAssetChecktypeSetting] = makeDeleteAssetChecktypeSettingEndpoint(s, logger)
+
 	endpoints[CreateGroup] = makeCreateGroupEndpoint(s, logger)
 	endpoints[ListGroups] = makeListGroupsEndpoint(s, logger)
 	endpoints[UpdateGroup] = makeUpdateGroupEndpoint(s, logger)
@@ -186,6 +272,7 @@ func MakeEndpoints(s api.VulcanitoService, isJiraIntEnabled bool, logger log.Log
 	endpoints[FindProgram] = makeFindProgramEndpoint(s, logger)
 	endpoints[UpdateProgram] = makeUpdateProgramEndpoint(s, logger)
 	endpoints[DeleteProgram] = makeDeleteProgramEndpoint(s, logger)
+	endpoints[ProgramPlan] = makeProgramPlanEndpoint(s, logger)
 
 	endpoints[CreateSchedule] = makeCreateScheduleEndpoint(s, logger)
 	endpoints[DeleteSchedule] = makeDeleteScheduleEndpoint(s, logger)
@@ -203,12 +290,38 @@ func MakeEndpoints(s api.VulcanitoService, isJiraIntEnabled bool, logger log.Log
 	endpoints[UpdateChecktypeSetting] = makeUpdateChecktypeSettingEndpoint(s, logger)
 	endpoints[DeleteChecktypeSetting] = makeDeleteChecktypeSettingEndpoint(s, logger)
 
+	endpoints[ListChecktypes] = makeListChecktypesEndpoint(s, logger)
+
+	endpoints[ListPolicyTemplates] = makeListPolicyTemplatesEndpoint(s, logger)
+	endpoints[CreatePolicyTemplate] = makeCreatePolicyTemplateEndpoint(s, logger)
+	endpoints[FindPolicyTemplate] = makeFindPolicyTemplateEndpoint(s, logger)
+	endpoints[UpdatePolicyTemplate] = makeUpdatePolicyTemplateEndpoint(s, logger)
+	endpoints[DeletePolicyTemplate] = makeDeletePolicyTemplateEndpoint(s, logger)
+	endpoints[PolicyDivergence] = makePolicyDivergenceEndpoint(s, logger)
+
+	endpoints[ListGlobalPolicyEvaluations] = makeListGlobalPolicyEvaluationsEndpoint(s, logger)
+
 	endpoints[ListProgramScans] = makeListProgramScansEndpoint(s, logger)
 	endpoints[CreateScan] = makeCreateScanEndpoint(s, logger)
 	endpoints[FindScan] = makeFindScanEndpoint(s, logger)
 	endpoints[AbortScan] = makeAbortScanEndpoint(s, logger)
+	endpoints[ListScanQueue] = makeListScanQueueEndpoint(s, logger)
+	endpoints[CancelScanQueueEntry] = makeCancelScanQueueEntryEndpoint(s, logger)
+	endpoints[DiffScans] = makeDiffScansEndpoint(s, logger)
+
+	endpoints[ListNotificationRules] = makeListNotificationRulesEndpoint(s, logger)
+	endpoints[CreateNotificationRule] = makeCreateNotificationRuleEndpoint(s, logger)
+	endpoints[UpdateNotificationRule] = makeUpdateNotificationRuleEndpoint(s, logger)
+	endpoints[DeleteNotificationRule] = makeDeleteNotificationRuleEndpoint(s, logger)
+
+	endpoints[ListReportSubscriptions] = makeListReportSubscriptionsEndpoint(s, logger)
+	endpoints[FindReportSubscription] = makeFindReportSubscriptionEndpoint(s, logger)
+	endpoints[CreateReportSubscription] = makeCreateReportSubscriptionEndpoint(s, logger)
+	endpoints[UpdateReportSubscription] = makeUpdateReportSubscriptionEndpoint(s, logger)
+	endpoints[DeleteReportSubscription] = makeDeleteReportSubscriptionEndpoint(s, logger)
 
 	endpoints[SendDigestReport] = makeSendDigestReportEndpoint(s, logger)
+	endpoints[SendReportSubscription] = makeSendReportSubscriptionEndpoint(s, logger)
 
 	endpoints[StatsCoverage] = makeStatsCoverageEndpoint(s, logger)
 	endpoints[ListIssues] = makeListIssuesEndpoint(s, logger)
@@ -220,15 +333,46 @@ func MakeEndpoints(s api.VulcanitoService, isJiraIntEnabled bool, logger log.Log
 	endpoints[FindFinding] = makeFindFindingEndpoint(s, logger)
 	endpoints[CreateFindingOverwrite] = makeCreateFindingOverwriteEndpoint(s, logger)
 	endpoints[ListFindingOverwrites] = makeListFindingOverwritesEndpoint(s, logger)
+	endpoints[CreateFindingOverwrites] = makeCreateFindingOverwritesEndpoint(s, logger)
+	endpoints[ListFindingComments] = makeListFindingCommentsEndpoint(s, logger)
+	endpoints[CreateFindingComment] = makeCreateFindingCommentEndpoint(s, logger)
+	endpoints[UpdateFindingComment] = makeUpdateFindingCommentEndpoint(s, logger)
+	endpoints[DeleteFindingComment] = makeDeleteFindingCommentEndpoint(s, logger)
+	endpoints[ListRiskAcceptances] = makeListRiskAcceptancesEndpoint(s, logger)
+	endpoints[FindRiskAcceptance] = makeFindRiskAcceptanceEndpoint(s, logger)
+	endpoints[RequestRiskAcceptance] = makeRequestRiskAcceptanceEndpoint(s, logger)
+	endpoints[ApproveRiskAcceptance] = makeApproveRiskAcceptanceEndpoint(s, logger)
+	endpoints[RejectRiskAcceptance] = makeRejectRiskAcceptanceEndpoint(s, logger)
+	endpoints[VerifyFinding] = makeVerifyFindingEndpoint(s, logger)
+	endpoints[ListFindingVerifications] = makeListFindingVerificationsEndpoint(s, logger)
 	if isJiraIntEnabled {
 		endpoints[CreateFindingTicket] = makeCreateFindingTicketEndpoint(s, logger)
+		endpoints[CreateIssueTickets] = makeCreateIssueTicketsEndpoint(s, logger)
+		endpoints[ListFindingTickets] = makeListFindingTicketsEndpoint(s, logger)
+		endpoints[UpdateTeamTracker] = makeUpdateTeamTrackerEndpoint(s, logger)
+		endpoints[FindTeamTicketProvider] = makeFindTeamTicketProviderEndpoint(s, logger)
+		endpoints[UpdateTeamTicketProvider] = makeUpdateTeamTicketProviderEndpoint(s, logger)
+		endpoints[DeleteTeamTicketProvider] = makeDeleteTeamTicketProviderEndpoint(s, logger)
 	}
 	endpoints[ListFindingsLabels] = makeListFindingsLabelsEndpoint(s, logger)
+	endpoints[ExportFindings] = makeExportFindingsEndpoint(s, logger)
+	endpoints[CreateFindingsExport] = makeCreateFindingsExportEndpoint(s, logger)
+	endpoints[DownloadFindingsExport] = makeDownloadFindingsExportEndpoint(s, logger)
+	endpoints[ListFindingsSLA] = makeListFindingsSLAEndpoint(s, logger)
+	endpoints[ListTeamSLAPolicies] = makeListSLAPoliciesEndpoint(s, logger)
+	endpoints[CreateTeamSLAPolicy] = makeCreateSLAPolicyEndpoint(s, logger)
+	endpoints[UpdateTeamSLAPolicy] = makeUpdateSLAPolicyEndpoint(s, logger)
+	endpoints[DeleteTeamSLAPolicy] = makeDeleteSLAPolicyEndpoint(s, logger)
+	endpoints[ListSLAPolicies] = makeListSLAPoliciesEndpoint(s, logger)
+	endpoints[CreateSLAPolicy] = makeCreateSLAPolicyEndpoint(s, logger)
+	endpoints[UpdateSLAPolicy] = makeUpdateSLAPolicyEndpoint(s, logger)
+	endpoints[DeleteSLAPolicy] = makeDeleteSLAPolicyEndpoint(s, logger)
 	endpoints[StatsMTTR] = makeStatsMTTREndpoint(s, logger)
 	endpoints[StatsExposure] = makeStatsExposureEndpoint(s, logger)
 	endpoints[StatsCurrentExposure] = makeStatsCurrentExposureEndpoint(s, logger)
 	endpoints[StatsOpen] = makeStatsOpenEndpoint(s, logger)
 	endpoints[StatsFixed] = makeStatsFixedEndpoint(s, logger)
+	endpoints[StatsTrend] = makeStatsTrendEndpoint(s, logger)
 	endpoints[GlobalStatsMTTR] = makeGlobalStatsMTTREndpoint(s, logger)
 	endpoints[GlobalStatsExposure] = makeGlobalStatsExposureEndpoint(s, logger)
 	endpoints[GlobalStatsCurrentExposure] = makeGlobalStatsCurrentExposureEndpoint(s, logger)
@@ -317,6 +461,29 @@ func (m MultiStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.Data)
 }
 
+// HTML is the response of the public endpoints opened in a browser from the
+// links sent by email.
+type HTML struct {
+	Body []byte
+}
+
+func (h HTML) StatusCode() int {
+	return http.StatusOK
+}
+
+// File is the response of the endpoints that return a file instead of a JSON
+// document. WriteTo writes the content of the file, which allows the
+// endpoints to stream it.
+type File struct {
+	Name        string
+	ContentType string
+	WriteTo     func(w io.Writer) error
+}
+
+func (f File) StatusCode() int {
+	return http.StatusOK
+}
+
 type Forbidden struct {
 	Data interface{}
 }
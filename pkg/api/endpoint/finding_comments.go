@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type FindingCommentRequest struct {
+	ID        string `json:"id" urlvar:"comment_id"`
+	TeamID    string `json:"team_id" urlvar:"team_id"`
+	FindingID string `json:"finding_id" urlvar:"finding_id"`
+	Body      string `json:"body"`
+	// Mentions contains the emails of the mentioned members of the team.
+	Mentions []string `json:"mentions"`
+}
+
+func (r FindingCommentRequest) comment() api.FindingComment {
+	comment := api.FindingComment{
+		ID:        r.ID,
+		TeamID:    r.TeamID,
+		FindingID: r.FindingID,
+		Body:      r.Body,
+	}
+	for _, email := range r.Mentions {
+		comment.Mentions = append(comment.Mentions, &api.FindingCommentMention{User: &api.User{Email: email}})
+	}
+	return comment
+}
+
+func makeListFindingCommentsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingCommentRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		comments, err := s.ListFindingComments(ctx, r.TeamID, r.FindingID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{findingCommentsResponse(comments)}, nil
+	}
+}
+
+func makeCreateFindingCommentEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingCommentRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		user, err := api.UserFromContext(ctx)
+		if err != nil {
+			return nil, errors.Default(err)
+		}
+		comment := r.comment()
+		comment.AuthorID = user.ID
+		created, err := s.CreateFindingComment(ctx, comment)
+		if err != nil {
+			return nil, err
+		}
+		return Created{created.ToResponse()}, nil
+	}
+}
+
+func makeUpdateFindingCommentEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingCommentRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		comment, err := s.UpdateFindingComment(ctx, r.comment())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{comment.ToResponse()}, nil
+	}
+}
+
+func makeDeleteFindingCommentEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingCommentRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeleteFindingComment(ctx, r.comment()); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+func findingCommentsResponse(comments []*api.FindingComment) []api.FindingCommentResponse {
+	response := []api.FindingCommentResponse{}
+	for _, comment := range comments {
+		response = append(response, comment.ToResponse())
+	}
+	return response
+}
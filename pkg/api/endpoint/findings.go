@@ -174,7 +174,7 @@ func makeFindFindingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpo
 			return nil, errors.Assertion("Type assertion failed")
 		}
 
-		finding, err := s.FindFinding(ctx, r.ID)
+		finding, err := s.FindFinding(ctx, r.TeamID, r.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -188,6 +188,11 @@ func makeFindFindingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpo
 		}
 
 		if authorizedFindFindingRequest(finding.Finding.Target.Teams, r.TeamID) {
+			comments, err := s.ListFindingComments(ctx, r.TeamID, finding.Finding.ID)
+			if err != nil {
+				return nil, err
+			}
+			finding.Finding.Comments = findingCommentsResponse(comments)
 			return Ok{finding.Finding}, nil
 		}
 
@@ -209,7 +214,7 @@ func makeCreateFindingOverwriteEndpoint(s api.VulcanitoService, logger kitlog.Lo
 			return nil, errors.Assertion("Type assertion failed")
 		}
 
-		finding, err := s.FindFinding(ctx, r.FindingID)
+		finding, err := s.FindFinding(ctx, "", r.FindingID)
 		if err != nil {
 			return nil, err
 		}
@@ -241,6 +246,67 @@ func makeCreateFindingOverwriteEndpoint(s api.VulcanitoService, logger kitlog.Lo
 	}
 }
 
+type BulkFindingOverwriteRequest struct {
+	TeamID     string                      `json:"team_id" urlvar:"team_id"`
+	FindingIDs []string                    `json:"finding_ids"`
+	Filter     *BulkFindingOverwriteFilter `json:"filter"`
+	Status     string                      `json:"status"`
+	Notes      string                      `json:"notes"`
+}
+
+// BulkFindingOverwriteFilter selects the findings of a team a bulk overwrite
+// is applied to.
+type BulkFindingOverwriteFilter struct {
+	Status      string  `json:"status"`
+	MinScore    float64 `json:"min_score"`
+	MaxScore    float64 `json:"max_score"`
+	IssueID     string  `json:"issue_id"`
+	TargetID    string  `json:"target_id"`
+	Identifiers string  `json:"identifiers"`
+	Labels      string  `json:"labels"`
+}
+
+func makeCreateFindingOverwritesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*BulkFindingOverwriteRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		user, err := api.UserFromContext(ctx)
+		if err != nil {
+			return nil, errors.Default(err)
+		}
+
+		bulk := api.BulkFindingOverwrite{
+			TeamID:     r.TeamID,
+			UserID:     user.ID,
+			FindingIDs: r.FindingIDs,
+			Status:     r.Status,
+			Notes:      r.Notes,
+		}
+		if r.Filter != nil {
+			bulk.Params = &api.FindingsParams{
+				Team:        r.TeamID,
+				Status:      r.Filter.Status,
+				MinScore:    r.Filter.MinScore,
+				MaxScore:    r.Filter.MaxScore,
+				IssueID:     r.Filter.IssueID,
+				TargetID:    r.Filter.TargetID,
+				Identifiers: r.Filter.Identifiers,
+				Labels:      r.Filter.Labels,
+			}
+		}
+
+		job, err := s.CreateFindingOverwritesAsync(ctx, bulk)
+		if err != nil {
+			return nil, err
+		}
+
+		return Accepted{job.ToResponse()}, nil
+	}
+}
+
 func makeListFindingOverwritesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		r, ok := request.(*FindingsRequest)
@@ -262,6 +328,48 @@ func makeListFindingOverwritesEndpoint(s api.VulcanitoService, logger kitlog.Log
 	}
 }
 
+func makeVerifyFindingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*FindingsRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		user, err := api.UserFromContext(ctx)
+		if err != nil {
+			return nil, errors.Default(err)
+		}
+
+		verification, err := s.VerifyFinding(ctx, r.TeamID, r.ID, user)
+		if err != nil {
+			return nil, err
+		}
+
+		return Created{verification.ToResponse()}, nil
+	}
+}
+
+func makeListFindingVerificationsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*FindingsRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		verifications, err := s.ListFindingVerifications(ctx, r.TeamID, r.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		output := []api.FindingVerificationResponse{}
+		for _, v := range verifications {
+			output = append(output, v.ToResponse())
+		}
+
+		return Ok{output}, nil
+	}
+}
+
 func makeListFindingsLabelsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		r, ok := request.(*FindingsRequest)
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// FindingsExportRequest selects the findings of a team to export using the
+// same query parameters as the list findings request.
+type FindingsExportRequest struct {
+	ExportID string `json:"export_id" urlvar:"export_id"`
+	Format   string `json:"format" urlquery:"format"`
+	FindingsRequest
+}
+
+func (r FindingsExportRequest) export() (api.FindingsExport, error) {
+	if !isValidListFindingsRequest(&r.FindingsRequest) {
+		return api.FindingsExport{}, errors.Validation("Invalid date format")
+	}
+	export := api.FindingsExport{
+		TeamID: r.TeamID,
+		Format: r.Format,
+		Params: api.FindingsExportParams(buildFindingsParams(&r.FindingsRequest)),
+	}
+	if err := export.Validate(); err != nil {
+		return api.FindingsExport{}, errors.Validation(err)
+	}
+	return export, nil
+}
+
+// makeExportFindingsEndpoint returns an endpoint that streams the export of
+// the findings of a team while they are read from the vulnerability db. The
+// export is validated and its first page read before the response is
+// written, so those errors are returned with the right status code.
+func makeExportFindingsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingsExportRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		export, err := r.export()
+		if err != nil {
+			return nil, err
+		}
+		write, err := s.ExportFindings(ctx, export)
+		if err != nil {
+			return nil, err
+		}
+		return File{
+			Name:        export.FileName(),
+			ContentType: export.ContentType(),
+			WriteTo: func(w io.Writer) error {
+				_, err := write(w)
+				if err != nil {
+					_ = level.Error(logger).Log("ExportFindings", "error streaming findings export", "TeamID", export.TeamID, "err", err)
+				}
+				return err
+			},
+		}, nil
+	}
+}
+
+func makeCreateFindingsExportEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingsExportRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		export, err := r.export()
+		if err != nil {
+			return nil, err
+		}
+		job, err := s.CreateFindingsExportAsync(ctx, export)
+		if err != nil {
+			return nil, err
+		}
+		return Accepted{job.ToResponse()}, nil
+	}
+}
+
+func makeDownloadFindingsExportEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingsExportRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		export, err := s.FindFindingsExport(ctx, r.TeamID, r.ExportID)
+		if err != nil {
+			return nil, err
+		}
+		return File{
+			Name:        export.FileName(),
+			ContentType: export.ContentType(),
+			WriteTo: func(w io.Writer) error {
+				err := s.WriteFindingsExport(ctx, *export, w)
+				if err != nil {
+					_ = level.Error(logger).Log("DownloadFindingsExport", "error streaming findings export", "TeamID", export.TeamID, "ExportID", export.ID, "err", err)
+				}
+				return err
+			},
+		}, nil
+	}
+}
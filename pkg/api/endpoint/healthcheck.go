@@ -26,6 +26,9 @@ func makeHealthcheckEndpoint(svc api.VulcanitoService, logger kitlog.Logger) end
 		}
 
 		hc.Status = "OK"
+		// The API keeps working with stale checktypes, so they do not make
+		// the healthcheck fail.
+		hc.Checktypes = svc.ChecktypesCacheStatus(ctx)
 		return Ok{hc.ToResponse()}, nil
 	}
 }
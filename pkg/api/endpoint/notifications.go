@@ -0,0 +1,103 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type NotificationRuleRequest struct {
+	ID          string `json:"id" urlvar:"notification_id"`
+	TeamID      string `json:"team_id" urlvar:"team_id"`
+	ProgramID   string `json:"program_id" urlvar:"program_id"`
+	Trigger     string `json:"trigger"`
+	MinSeverity string `json:"min_severity"`
+	Channel     string `json:"channel"`
+	URL         string `json:"url"`
+	Disabled    *bool  `json:"disabled"`
+}
+
+func (r NotificationRuleRequest) rule() api.NotificationRule {
+	return api.NotificationRule{
+		ID:          r.ID,
+		TeamID:      r.TeamID,
+		ProgramID:   r.ProgramID,
+		Trigger:     api.NotificationTrigger(r.Trigger),
+		MinSeverity: r.MinSeverity,
+		Channel:     api.NotificationChannel(r.Channel),
+		URL:         r.URL,
+		Disabled:    r.Disabled,
+	}
+}
+
+func makeListNotificationRulesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*NotificationRuleRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		rules, err := s.ListNotificationRules(ctx, r.TeamID, r.ProgramID)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.NotificationRuleResponse{}
+		for _, rule := range rules {
+			response = append(response, rule.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeCreateNotificationRuleEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*NotificationRuleRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		// Ensure the program exists and belongs to the team. This is done
+		// here so global programs are resolved by the service middlewares.
+		if _, err := s.FindProgram(ctx, r.ProgramID, r.TeamID); err != nil {
+			return nil, err
+		}
+		rule, err := s.CreateNotificationRule(ctx, r.rule())
+		if err != nil {
+			return nil, err
+		}
+		return Created{rule.ToResponse()}, nil
+	}
+}
+
+func makeUpdateNotificationRuleEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*NotificationRuleRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		rule, err := s.UpdateNotificationRule(ctx, r.rule())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{rule.ToResponse()}, nil
+	}
+}
+
+func makeDeleteNotificationRuleEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*NotificationRuleRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeleteNotificationRule(ctx, r.rule()); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
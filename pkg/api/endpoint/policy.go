@@ -15,10 +15,11 @@ import (
 )
 
 type PolicyRequest struct {
-	ID     string `json:"id" urlvar:"policy_id"`
-	TeamID string `json:"team_id" urlvar:"team_id"`
-	Name   string `json:"name"`
-	Global *bool  `json:"global"`
+	ID         string  `json:"id" urlvar:"policy_id"`
+	TeamID     string  `json:"team_id" urlvar:"team_id"`
+	Name       string  `json:"name"`
+	Global     *bool   `json:"global"`
+	TemplateID *string `json:"template_id"`
 }
 
 func makeListPoliciesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
@@ -46,8 +47,9 @@ func makeCreatePolicyEndpoint(s api.VulcanitoService, logger kitlog.Logger) endp
 			return nil, errors.Assertion("Type assertion failed")
 		}
 		policy := api.Policy{
-			Name:   policyRequest.Name,
-			TeamID: policyRequest.TeamID,
+			Name:       policyRequest.Name,
+			TeamID:     policyRequest.TeamID,
+			TemplateID: policyRequest.TemplateID,
 		}
 		createdPolicy, err := s.CreatePolicy(ctx, policy)
 		if err != nil {
@@ -81,9 +83,10 @@ func makeUpdatePolicyEndpoint(s api.VulcanitoService, logger kitlog.Logger) endp
 		}
 
 		policy := api.Policy{
-			ID:     policyRequest.ID,
-			Name:   policyRequest.Name,
-			TeamID: policyRequest.TeamID,
+			ID:         policyRequest.ID,
+			Name:       policyRequest.Name,
+			TeamID:     policyRequest.TeamID,
+			TemplateID: policyRequest.TemplateID,
 		}
 		updated, err := s.UpdatePolicy(ctx, policy)
 		if err != nil {
@@ -117,6 +120,7 @@ type ChecktypeSettingRequest struct {
 	PolicyID      string  `json:"policy_id" urlvar:"policy_id"`
 	CheckTypeName string  `json:"checktype_name"`
 	Options       *string `json:"options"`
+	Excluded      *bool   `json:"excluded"`
 }
 
 func makeListChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
@@ -148,6 +152,7 @@ func makeCreateChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Lo
 			CheckTypeName: checktypeSettingRequest.CheckTypeName,
 			PolicyID:      checktypeSettingRequest.PolicyID,
 			Options:       checktypeSettingRequest.Options,
+			Excluded:      checktypeSettingRequest.Excluded,
 		}
 		createdChecktypeSetting, err := s.CreateChecktypeSetting(ctx, checktypeSetting)
 		if err != nil {
@@ -185,6 +190,7 @@ func makeUpdateChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Lo
 			ID:            requestBody.ID,
 			CheckTypeName: requestBody.CheckTypeName,
 			Options:       requestBody.Options,
+			Excluded:      requestBody.Excluded,
 			PolicyID:      requestBody.PolicyID,
 		}
 		checktypeSettingr, err := s.UpdateChecktypeSetting(ctx, checktypeSetting)
@@ -209,3 +215,13 @@ func makeDeleteChecktypeSettingEndpoint(s api.VulcanitoService, logger kitlog.Lo
 		return NoContent{nil}, nil
 	}
 }
+
+func makeListGlobalPolicyEvaluationsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		evaluations, err := s.ListGlobalPolicyEvaluations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{evaluations}, nil
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type PolicyTemplateRequest struct {
+	ID          string                         `json:"id" urlvar:"template_id"`
+	Name        string                         `json:"name"`
+	Description *string                        `json:"description"`
+	Settings    []PolicyTemplateSettingRequest `json:"settings"`
+}
+
+type PolicyTemplateSettingRequest struct {
+	CheckTypeName string  `json:"checktype_name"`
+	Options       *string `json:"options"`
+}
+
+func (r PolicyTemplateRequest) toPolicyTemplate() api.PolicyTemplate {
+	template := api.PolicyTemplate{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+	}
+	if r.Settings != nil {
+		template.Settings = []*api.PolicyTemplateSetting{}
+		for _, s := range r.Settings {
+			template.Settings = append(template.Settings, &api.PolicyTemplateSetting{
+				CheckTypeName: s.CheckTypeName,
+				Options:       s.Options,
+			})
+		}
+	}
+	return template
+}
+
+func makeListPolicyTemplatesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		templates, err := s.ListPolicyTemplates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.PolicyTemplateResponse{}
+		for _, t := range templates {
+			response = append(response, *t.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeCreatePolicyTemplateEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		requestBody, ok := request.(*PolicyTemplateRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		template := requestBody.toPolicyTemplate()
+		template.ID = ""
+		created, err := s.CreatePolicyTemplate(ctx, template)
+		if err != nil {
+			return nil, err
+		}
+		return Created{created.ToResponse()}, nil
+	}
+}
+
+func makeFindPolicyTemplateEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		requestBody, ok := request.(*PolicyTemplateRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		template, err := s.FindPolicyTemplate(ctx, requestBody.ID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{template.ToResponse()}, nil
+	}
+}
+
+func makeUpdatePolicyTemplateEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		requestBody, ok := request.(*PolicyTemplateRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		updated, err := s.UpdatePolicyTemplate(ctx, requestBody.toPolicyTemplate())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{updated.ToResponse()}, nil
+	}
+}
+
+func makeDeletePolicyTemplateEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		requestBody, ok := request.(*PolicyTemplateRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeletePolicyTemplate(ctx, requestBody.ID); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+func makePolicyDivergenceEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		requestBody, ok := request.(*PolicyRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		divergence, err := s.PolicyDivergence(ctx, requestBody.TeamID, requestBody.ID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{divergence}, nil
+	}
+}
@@ -131,6 +131,21 @@ func makeFindProgramEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpo
 	}
 }
 
+func makeProgramPlanEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		programRequest, ok := request.(*ProgramRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		plan, err := s.ProgramPlan(ctx, programRequest.ID, programRequest.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{plan}, nil
+	}
+}
+
 // TODO: We are using the same struct ProgramRequest for creating a program
 // and for updating a program. That is a problem because if the user does not set
 // a field of the request after the update the program will have all those fields
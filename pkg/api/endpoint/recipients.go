@@ -5,7 +5,9 @@ Copyright 2021 Adevinta
 package endpoint
 
 import (
+	"bytes"
 	"context"
+	"html/template"
 
 	"github.com/go-kit/kit/endpoint"
 	kitlog "github.com/go-kit/kit/log"
@@ -64,3 +66,170 @@ func makeUpdateRecipientsEndpoint(s api.VulcanitoService, logger kitlog.Logger)
 		return Ok{response}, nil
 	}
 }
+
+type RecipientRequest struct {
+	TeamID      string    `json:"team_id" urlvar:"team_id"`
+	Email       string    `json:"email" urlvar:"email"`
+	ReportTypes *[]string `json:"report_types"`
+	Active      *bool     `json:"active"`
+}
+
+func (r RecipientRequest) recipient() api.Recipient {
+	recipient := api.Recipient{
+		TeamID: r.TeamID,
+		Email:  r.Email,
+		Active: r.Active,
+	}
+	if r.ReportTypes != nil {
+		recipient.SetReportTypes(*r.ReportTypes)
+	}
+	return recipient
+}
+
+// RecipientTokenRequest contains the token sent to a recipient to verify its
+// address or to unsubscribe.
+type RecipientTokenRequest struct {
+	Token string `json:"token" urlquery:"token"`
+}
+
+type BouncedRecipientsRequest struct {
+	Emails []string `json:"emails"`
+}
+
+type BouncedRecipientsResponse struct {
+	Deactivated int64 `json:"deactivated"`
+}
+
+func makeCreateRecipientEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		recipient, err := s.CreateRecipient(ctx, r.recipient())
+		if err != nil {
+			return nil, err
+		}
+		return Created{recipient.ToResponse()}, nil
+	}
+}
+
+func makeUpdateRecipientEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		recipient, err := s.UpdateRecipient(ctx, r.recipient(), r.ReportTypes)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{recipient.ToResponse()}, nil
+	}
+}
+
+func makeDeleteRecipientEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeleteRecipient(ctx, r.TeamID, r.Email); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+func makeSendRecipientVerificationEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.SendRecipientVerification(ctx, r.TeamID, r.Email); err != nil {
+			return nil, err
+		}
+		return Accepted{}, nil
+	}
+}
+
+func makeVerifyRecipientEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientTokenRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		recipient, err := s.VerifyRecipient(ctx, r.Token)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{recipient.ToResponse()}, nil
+	}
+}
+
+func makeUnsubscribeRecipientEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientTokenRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		recipient, err := s.UnsubscribeRecipient(ctx, r.Token)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{recipient.ToResponse()}, nil
+	}
+}
+
+// unsubscribePage asks the recipient to confirm it wants to stop receiving
+// the reports. The unsubscribe links of the emails open this page instead of
+// unsubscribing directly, so the mail scanners and the link previews that
+// follow the links do not unsubscribe the recipients. The form posts to the
+// same URL as the one-click unsubscription defined in RFC 8058.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Unsubscribe from Vulcan reports</title>
+</head>
+<body>
+<p>Do you want to stop receiving the Vulcan reports?</p>
+<form method="post" action="?token={{.}}">
+<button type="submit">Unsubscribe</button>
+</form>
+</body>
+</html>
+`))
+
+func makeUnsubscribeRecipientPageEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RecipientTokenRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if r.Token == "" {
+			return nil, errors.Validation("token is required")
+		}
+		var b bytes.Buffer
+		if err := unsubscribePage.Execute(&b, r.Token); err != nil {
+			return nil, errors.Default(err)
+		}
+		return HTML{Body: b.Bytes()}, nil
+	}
+}
+
+func makeDeactivateBouncedRecipientsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*BouncedRecipientsRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		n, err := s.DeactivateBouncedRecipients(ctx, r.Emails)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{BouncedRecipientsResponse{Deactivated: n}}, nil
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kitlog "github.com/go-kit/kit/log"
+)
+
+func TestMakeUnsubscribeRecipientPageEndpoint(t *testing.T) {
+	// The page must not call the service, so it does not need one.
+	e := makeUnsubscribeRecipientPageEndpoint(nil, kitlog.NewNopLogger())
+	resp, err := e(context.Background(), &RecipientTokenRequest{Token: "a.b+c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, ok := resp.(HTML)
+	if !ok {
+		t.Fatalf("unexpected response type %T", resp)
+	}
+	body := string(page.Body)
+	if !strings.Contains(body, `<form method="post" action="?token=a.b%2bc">`) {
+		t.Errorf("the page does not post the escaped token:\n%s", body)
+	}
+
+	if _, err := e(context.Background(), &RecipientTokenRequest{}); err == nil {
+		t.Errorf("expected error for a request without token")
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type ReportSubscriptionRequest struct {
+	ID          string    `json:"id" urlvar:"subscription_id"`
+	TeamID      string    `json:"team_id" urlvar:"team_id"`
+	Name        string    `json:"name"`
+	ReportID    string    `json:"report_id"`
+	Cron        string    `json:"cron"`
+	Recipients  *[]string `json:"recipients"`
+	MinSeverity string    `json:"min_severity"`
+	GroupID     *string   `json:"group_id"`
+	Disabled    *bool     `json:"disabled"`
+}
+
+func (r ReportSubscriptionRequest) subscription() api.ReportSubscription {
+	subscription := api.ReportSubscription{
+		ID:          r.ID,
+		TeamID:      r.TeamID,
+		Name:        r.Name,
+		ReportID:    r.ReportID,
+		Cron:        r.Cron,
+		MinSeverity: r.MinSeverity,
+		GroupID:     r.GroupID,
+		Disabled:    r.Disabled,
+	}
+	// A nil list of recipients means the recipients are not modified.
+	if r.Recipients != nil {
+		subscription.Recipients = []*api.ReportSubscriptionRecipient{}
+		for _, email := range *r.Recipients {
+			subscription.Recipients = append(subscription.Recipients, &api.ReportSubscriptionRecipient{Email: email})
+		}
+	}
+	return subscription
+}
+
+func makeListReportSubscriptionsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		subscriptions, err := s.ListReportSubscriptions(ctx, r.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.ReportSubscriptionResponse{}
+		for _, subscription := range subscriptions {
+			response = append(response, subscription.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeFindReportSubscriptionEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		subscription, err := s.FindReportSubscription(ctx, r.TeamID, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{subscription.ToResponse()}, nil
+	}
+}
+
+func makeCreateReportSubscriptionEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		subscription, err := s.CreateReportSubscription(ctx, r.subscription())
+		if err != nil {
+			return nil, err
+		}
+		return Created{subscription.ToResponse()}, nil
+	}
+}
+
+func makeUpdateReportSubscriptionEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		subscription, err := s.UpdateReportSubscription(ctx, r.subscription())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{subscription.ToResponse()}, nil
+	}
+}
+
+func makeDeleteReportSubscriptionEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.DeleteReportSubscription(ctx, r.subscription()); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+func makeSendReportSubscriptionEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*ReportSubscriptionRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		if err := s.SendReportSubscription(ctx, r.TeamID, r.ID); err != nil {
+			return nil, err
+		}
+		return Created{}, nil
+	}
+}
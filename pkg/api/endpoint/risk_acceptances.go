@@ -0,0 +1,110 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type RiskAcceptanceRequest struct {
+	ID            string     `json:"id" urlvar:"risk_acceptance_id"`
+	TeamID        string     `json:"team_id" urlvar:"team_id"`
+	FindingID     string     `json:"finding_id" urlvar:"finding_id" urlquery:"finding_id"`
+	Status        string     `json:"status" urlquery:"status"`
+	Justification string     `json:"justification"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	Notes         string     `json:"notes"`
+}
+
+func makeListRiskAcceptancesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RiskAcceptanceRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		acceptances, err := s.ListRiskAcceptances(ctx, r.TeamID, r.FindingID, r.Status)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.RiskAcceptanceResponse{}
+		for _, acceptance := range acceptances {
+			response = append(response, acceptance.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeFindRiskAcceptanceEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RiskAcceptanceRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		acceptance, err := s.FindRiskAcceptance(ctx, r.TeamID, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{acceptance.ToResponse()}, nil
+	}
+}
+
+func makeRequestRiskAcceptanceEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RiskAcceptanceRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		user, err := api.UserFromContext(ctx)
+		if err != nil {
+			return nil, errors.Default(err)
+		}
+		acceptance, err := s.RequestRiskAcceptance(ctx, api.RiskAcceptance{
+			TeamID:        r.TeamID,
+			FindingID:     r.FindingID,
+			Justification: r.Justification,
+			ExpiresAt:     r.ExpiresAt,
+			RequestedBy:   user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return Created{acceptance.ToResponse()}, nil
+	}
+}
+
+func makeApproveRiskAcceptanceEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RiskAcceptanceRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		acceptance, err := s.ApproveRiskAcceptance(ctx, r.TeamID, r.ID, r.Notes)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{acceptance.ToResponse()}, nil
+	}
+}
+
+func makeRejectRiskAcceptanceEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*RiskAcceptanceRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		acceptance, err := s.RejectRiskAcceptance(ctx, r.TeamID, r.ID, r.Notes)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{acceptance.ToResponse()}, nil
+	}
+}
@@ -56,6 +56,9 @@ func makeCreateScanEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoi
 		if err != nil {
 			return nil, err
 		}
+		if createdScan.Status == api.ScanStatusQueued {
+			return Accepted{createdScan.ToResponse()}, nil
+		}
 		return Created{createdScan.ToResponse()}, nil
 	}
 }
@@ -89,3 +92,70 @@ func makeAbortScanEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoin
 		return Ok{scan.ToResponse()}, nil
 	}
 }
+
+// ScanQueueRequest holds the information passed to the ListScanQueue and
+// CancelScanQueueEntry endpoints.
+type ScanQueueRequest struct {
+	TeamID  string `json:"team_id" urlvar:"team_id"`
+	EntryID string `json:"entry_id" urlvar:"entry_id"`
+}
+
+func makeListScanQueueEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*ScanQueueRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		entries, err := s.ListScanQueue(ctx, r.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		output := []api.ScanQueueEntryResponse{}
+		for _, e := range entries {
+			output = append(output, e.ToResponse())
+		}
+
+		return Ok{output}, nil
+	}
+}
+
+func makeCancelScanQueueEntryEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*ScanQueueRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		if err := s.CancelScanQueueEntry(ctx, r.TeamID, r.EntryID); err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+// DiffScansRequest holds the information passed to the DiffScans endpoint.
+// If From and To are empty the latest finished scan of the program is
+// compared with the previous one.
+type DiffScansRequest struct {
+	TeamID    string `urlvar:"team_id"`
+	ProgramID string `urlvar:"program_id"`
+	From      string `urlquery:"from"`
+	To        string `urlquery:"to"`
+}
+
+func makeDiffScansEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*DiffScansRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		diff, err := s.DiffProgramScans(ctx, r.TeamID, r.ProgramID, r.From, r.To)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{diff.ToResponse()}, nil
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 	"github.com/adevinta/vulcan-api/pkg/awscatalogue"
 	"github.com/adevinta/vulcan-api/pkg/reports"
-	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
 	"github.com/adevinta/vulcan-api/pkg/schedule"
 	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
 
@@ -61,9 +61,9 @@ func buildTestService(testStore api.VulcanitoStore) api.VulcanitoService {
 	s := &localScheduler{
 		schedules: make(map[string]string),
 	}
-	return service.New(svcLogger, testStore, jwt.Config{}, scanengine.Config{Url: ""},
+	return service.New(svcLogger, testStore, jwt.Config{}, &scanenginetest.Fake{},
 		s, reports.Config{}, vulnerabilitydb.NewClient(nil, "", true),
-		nil, nil, nil, awscatalogue.NewAWSAccounts(nil, nil), []string{},
+		nil, nil, nil, awscatalogue.NewAWSAccounts(nil, nil), nil, nil,
 		false)
 }
 
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package endpoint
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitlog "github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type SLAPolicyRequest struct {
+	ID         string `json:"id" urlvar:"policy_id"`
+	TeamID     string `json:"team_id" urlvar:"team_id"`
+	Severity   string `json:"severity"`
+	ROLFPLevel *int   `json:"rolfp_level"`
+	Days       int    `json:"days"`
+}
+
+func (r SLAPolicyRequest) policy() api.SLAPolicy {
+	policy := api.SLAPolicy{
+		ID:         r.ID,
+		Severity:   r.Severity,
+		ROLFPLevel: r.ROLFPLevel,
+		Days:       r.Days,
+	}
+	if r.TeamID != "" {
+		teamID := r.TeamID
+		policy.TeamID = &teamID
+	}
+	return policy
+}
+
+type FindingsSLARequest struct {
+	TeamID string `json:"team_id" urlvar:"team_id"`
+	Status string `json:"status" urlquery:"status"`
+}
+
+func makeListSLAPoliciesEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*SLAPolicyRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		policies, err := s.ListSLAPolicies(ctx, r.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		response := []api.SLAPolicyResponse{}
+		for _, policy := range policies {
+			response = append(response, policy.ToResponse())
+		}
+		return Ok{response}, nil
+	}
+}
+
+func makeCreateSLAPolicyEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*SLAPolicyRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		policy, err := s.CreateSLAPolicy(ctx, r.policy())
+		if err != nil {
+			return nil, err
+		}
+		return Created{policy.ToResponse()}, nil
+	}
+}
+
+func makeUpdateSLAPolicyEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*SLAPolicyRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		policy, err := s.UpdateSLAPolicy(ctx, r.policy())
+		if err != nil {
+			return nil, err
+		}
+		return Ok{policy.ToResponse()}, nil
+	}
+}
+
+func makeDeleteSLAPolicyEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*SLAPolicyRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		err := s.DeleteSLAPolicy(ctx, r.TeamID, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		return NoContent{nil}, nil
+	}
+}
+
+func makeListFindingsSLAEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, ok := request.(*FindingsSLARequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+		findings, err := s.ListFindingsSLA(ctx, r.TeamID, r.Status)
+		if err != nil {
+			return nil, err
+		}
+		return Ok{findings}, nil
+	}
+}
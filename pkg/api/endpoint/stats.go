@@ -21,15 +21,24 @@ const (
 )
 
 type StatsRequest struct {
-	TeamID      string  `json:"team_id" urlvar:"team_id"`
-	Teams       string  `urlquery:"teams"`
-	MinDate     string  `urlquery:"minDate"`
-	MaxDate     string  `urlquery:"maxDate"`
-	AtDate      string  `urlquery:"atDate"`
-	MinScore    float64 `urlquery:"minScore"`
-	MaxScore    float64 `urlquery:"maxScore"`
-	Identifiers string  `urlquery:"identifiers"`
-	Labels      string  `urlquery:"labels"`
+	TeamID       string  `json:"team_id" urlvar:"team_id"`
+	Teams        string  `urlquery:"teams"`
+	MinDate      string  `urlquery:"minDate"`
+	MaxDate      string  `urlquery:"maxDate"`
+	AtDate       string  `urlquery:"atDate"`
+	MinScore     float64 `urlquery:"minScore"`
+	MaxScore     float64 `urlquery:"maxScore"`
+	MinRiskScore float64 `urlquery:"minRiskScore"`
+	MaxRiskScore float64 `urlquery:"maxRiskScore"`
+	Identifiers  string  `urlquery:"identifiers"`
+	Labels       string  `urlquery:"labels"`
+}
+
+type StatsTrendRequest struct {
+	TeamID  string `json:"team_id" urlvar:"team_id"`
+	GroupID string `urlquery:"groupId"`
+	MinDate string `urlquery:"minDate"`
+	MaxDate string `urlquery:"maxDate"`
 }
 
 type GlobalStatsRequest struct {
@@ -138,6 +147,30 @@ func makeStatsFixedEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoi
 	}
 }
 
+func makeStatsTrendEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*StatsTrendRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		if (r.MinDate != "" && !isValidDate(r.MinDate)) || (r.MaxDate != "" && !isValidDate(r.MaxDate)) {
+			return nil, errors.Validation("Invalid query params")
+		}
+
+		response, err = s.StatsTrend(ctx, api.StatsTrendParams{
+			TeamID:  r.TeamID,
+			GroupID: r.GroupID,
+			MinDate: r.MinDate,
+			MaxDate: r.MaxDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return Ok{response}, nil
+	}
+}
+
 func makeGlobalStatsMTTREndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		r, ok := request.(*GlobalStatsRequest)
@@ -418,27 +451,31 @@ func isValidExposureRequest(r *StatsRequest) bool {
 
 func buildStatsParams(r *StatsRequest) api.StatsParams {
 	return api.StatsParams{
-		Team:        r.TeamID,
-		MinDate:     r.MinDate,
-		MaxDate:     r.MaxDate,
-		AtDate:      r.AtDate,
-		MinScore:    r.MinScore,
-		MaxScore:    r.MaxScore,
-		Identifiers: r.Identifiers,
-		Labels:      r.Labels,
+		Team:         r.TeamID,
+		MinDate:      r.MinDate,
+		MaxDate:      r.MaxDate,
+		AtDate:       r.AtDate,
+		MinScore:     r.MinScore,
+		MaxScore:     r.MaxScore,
+		MinRiskScore: r.MinRiskScore,
+		MaxRiskScore: r.MaxRiskScore,
+		Identifiers:  r.Identifiers,
+		Labels:       r.Labels,
 	}
 }
 
 func buildGlobalStatsParams(teams string, r *GlobalStatsRequest) api.StatsParams {
 	return api.StatsParams{
-		Teams:       teams,
-		MinDate:     r.MinDate,
-		MaxDate:     r.MaxDate,
-		AtDate:      r.AtDate,
-		MinScore:    r.MinScore,
-		MaxScore:    r.MaxScore,
-		Identifiers: r.Identifiers,
-		Labels:      r.Labels,
+		Teams:        teams,
+		MinDate:      r.MinDate,
+		MaxDate:      r.MaxDate,
+		AtDate:       r.AtDate,
+		MinScore:     r.MinScore,
+		MaxScore:     r.MaxScore,
+		MinRiskScore: r.MinRiskScore,
+		MaxRiskScore: r.MaxRiskScore,
+		Identifiers:  r.Identifiers,
+		Labels:       r.Labels,
 	}
 }
 
@@ -33,7 +33,7 @@ func makeCreateFindingTicketEndpoint(s api.VulcanitoService,
 			return nil, errors.Assertion("Type assertion failed")
 		}
 
-		finding, err := s.FindFinding(ctx, r.FindingID)
+		finding, err := s.FindFinding(ctx, "", r.FindingID)
 		if err != nil {
 			return nil, err
 		}
@@ -60,3 +60,145 @@ func makeCreateFindingTicketEndpoint(s api.VulcanitoService,
 		return Forbidden{}, nil
 	}
 }
+
+// IssueTicketsRequest represents a request to create a ticket for each open
+// finding of an issue of a team.
+type IssueTicketsRequest struct {
+	TeamID  string   `json:"team_id" urlvar:"team_id"`
+	IssueID string   `json:"issue_id" urlvar:"issue_id"`
+	Labels  []string `json:"labels"`
+}
+
+func makeCreateIssueTicketsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*IssueTicketsRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		user, err := api.UserFromContext(ctx)
+		if err != nil {
+			return nil, errors.Default(err)
+		}
+
+		job, err := s.CreateIssueTicketsAsync(ctx, api.BulkFindingTicketCreate{
+			TeamID:  r.TeamID,
+			IssueID: r.IssueID,
+			UserID:  user.ID,
+			Labels:  r.Labels,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return Accepted{job.ToResponse()}, nil
+	}
+}
+
+// FindingTicketsRequest represents a request to list the tickets of the
+// findings of a team.
+type FindingTicketsRequest struct {
+	TeamID string `json:"team_id" urlvar:"team_id"`
+}
+
+func makeListFindingTicketsEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*FindingTicketsRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		tickets, err := s.ListFindingTickets(ctx, r.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		return Ok{tickets}, nil
+	}
+}
+
+// TeamTrackerRequest represents a request to enable or disable the
+// integration of a team with the ticket tracker.
+type TeamTrackerRequest struct {
+	TeamID  string `json:"team_id" urlvar:"team_id"`
+	Enabled bool   `json:"enabled"`
+}
+
+func makeUpdateTeamTrackerEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*TeamTrackerRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		team, err := s.UpdateTeamTracker(ctx, r.TeamID, r.Enabled)
+		if err != nil {
+			return nil, err
+		}
+
+		return Ok{team.ToResponse()}, nil
+	}
+}
+
+// TicketProviderRequest represents a request to set the ticket provider of a
+// team.
+type TicketProviderRequest struct {
+	TeamID         string `json:"team_id" urlvar:"team_id"`
+	Type           string `json:"type"`
+	URL            string `json:"url"`
+	Project        string `json:"project"`
+	CredentialsRef string `json:"credentials_ref"`
+}
+
+func makeFindTeamTicketProviderEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*TicketProviderRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		provider, err := s.FindTeamTicketProvider(ctx, r.TeamID)
+		if err != nil {
+			return nil, err
+		}
+
+		return Ok{provider}, nil
+	}
+}
+
+func makeUpdateTeamTicketProviderEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*TicketProviderRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		provider, err := s.UpdateTeamTicketProvider(ctx, api.TicketProvider{
+			TeamID:         r.TeamID,
+			Type:           r.Type,
+			URL:            r.URL,
+			Project:        r.Project,
+			CredentialsRef: r.CredentialsRef,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return Ok{provider}, nil
+	}
+}
+
+func makeDeleteTeamTicketProviderEndpoint(s api.VulcanitoService, logger kitlog.Logger) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		r, ok := request.(*TicketProviderRequest)
+		if !ok {
+			return nil, errors.Assertion("Type assertion failed")
+		}
+
+		if err := s.DeleteTeamTicketProvider(ctx, r.TeamID); err != nil {
+			return nil, err
+		}
+
+		return NoContent{nil}, nil
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxFindingCommentLength is the maximum number of characters of the body of
+// a finding comment.
+const MaxFindingCommentLength = 10000
+
+var (
+	// ErrFindingCommentBodyRequired is returned when a finding comment has no
+	// body.
+	ErrFindingCommentBodyRequired = errors.New("finding comment body is required")
+	// ErrFindingCommentTooLong is returned when the body of a finding comment
+	// is longer than MaxFindingCommentLength.
+	ErrFindingCommentTooLong = errors.New("finding comment body is too long")
+)
+
+// FindingComment is a message of the discussion thread of a finding of a
+// team. The comments of a finding are only visible to the team that wrote
+// them.
+type FindingComment struct {
+	ID        string `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID    string `json:"team_id" validate:"required"`
+	FindingID string `json:"finding_id" validate:"required"`
+	AuthorID  string `json:"author_id" validate:"required"`
+	Author    *User  `json:"author,omitempty" gorm:"foreignkey:AuthorID"`
+	Body      string `json:"body"`
+	// Mentions are the members of the team mentioned in the comment.
+	Mentions []*FindingCommentMention `json:"mentions" gorm:"foreignkey:CommentID"`
+	// Edits contains the previous versions of the body of the comment, from
+	// the oldest to the newest.
+	Edits     []*FindingCommentEdit `json:"edits" gorm:"foreignkey:CommentID"`
+	CreatedAt time.Time             `json:"-"`
+	UpdatedAt time.Time             `json:"-"`
+}
+
+// FindingCommentMention is a member of a team mentioned in a finding comment.
+type FindingCommentMention struct {
+	CommentID string    `json:"comment_id" gorm:"primary_key"`
+	UserID    string    `json:"user_id" gorm:"primary_key"`
+	User      *User     `json:"user,omitempty"` // This line is infered from column name "user_id".
+	CreatedAt time.Time `json:"-"`
+}
+
+// FindingCommentEdit stores the body a finding comment had before it was
+// edited.
+type FindingCommentEdit struct {
+	ID        string    `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	CommentID string    `json:"comment_id"`
+	Body      string    `json:"body"`
+	EditedBy  string    `json:"edited_by"`
+	Editor    *User     `json:"editor,omitempty" gorm:"foreignkey:EditedBy"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// FindingCommentMentionNotification contains the information sent to the
+// members of a team mentioned in a comment of a finding.
+type FindingCommentMentionNotification struct {
+	TeamID    string `json:"team_id"`
+	TeamName  string `json:"team_name"`
+	FindingID string `json:"finding_id"`
+	CommentID string `json:"comment_id"`
+	Author    string `json:"author"`
+	Body      string `json:"body"`
+}
+
+// Validate checks the body of the comment.
+func (c FindingComment) Validate() error {
+	body := strings.TrimSpace(c.Body)
+	if body == "" {
+		return ErrFindingCommentBodyRequired
+	}
+	if len([]rune(body)) > MaxFindingCommentLength {
+		return ErrFindingCommentTooLong
+	}
+	return nil
+}
+
+type FindingCommentResponse struct {
+	ID        string                       `json:"id"`
+	TeamID    string                       `json:"team_id"`
+	FindingID string                       `json:"finding_id"`
+	Author    string                       `json:"author"`
+	Body      string                       `json:"body"`
+	Mentions  []string                     `json:"mentions"`
+	Edits     []FindingCommentEditResponse `json:"edits"`
+	CreatedAt time.Time                    `json:"created_at"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+type FindingCommentEditResponse struct {
+	Body      string    `json:"body"`
+	EditedBy  string    `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c FindingComment) ToResponse() FindingCommentResponse {
+	output := FindingCommentResponse{
+		ID:        c.ID,
+		TeamID:    c.TeamID,
+		FindingID: c.FindingID,
+		Body:      c.Body,
+		Mentions:  []string{},
+		Edits:     []FindingCommentEditResponse{},
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.Author != nil {
+		output.Author = c.Author.Email
+	}
+	for _, m := range c.Mentions {
+		if m.User != nil {
+			output.Mentions = append(output.Mentions, m.User.Email)
+		}
+	}
+	for _, e := range c.Edits {
+		edit := FindingCommentEditResponse{Body: e.Body, CreatedAt: e.CreatedAt}
+		if e.Editor != nil {
+			edit.EditedBy = e.Editor.Email
+		}
+		output.Edits = append(output.Edits, edit)
+	}
+	return output
+}
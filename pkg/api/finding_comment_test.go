@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindingCommentValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment FindingComment
+		wantErr error
+	}{
+		{
+			name:    "Valid",
+			comment: FindingComment{Body: "fixed in the next release"},
+		},
+		{
+			name:    "NoBody",
+			comment: FindingComment{Body: " \n "},
+			wantErr: ErrFindingCommentBodyRequired,
+		},
+		{
+			name:    "MaxLength",
+			comment: FindingComment{Body: strings.Repeat("ñ", MaxFindingCommentLength)},
+		},
+		{
+			name:    "TooLong",
+			comment: FindingComment{Body: strings.Repeat("a", MaxFindingCommentLength+1)},
+			wantErr: ErrFindingCommentTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.comment.Validate()
+			if err != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindingCommentToResponse(t *testing.T) {
+	createdAt := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	tests := []struct {
+		name    string
+		comment FindingComment
+		want    FindingCommentResponse
+	}{
+		{
+			name: "WithMentionsAndEdits",
+			comment: FindingComment{
+				ID:        "c1",
+				TeamID:    "t1",
+				FindingID: "f1",
+				AuthorID:  "u1",
+				Author:    &User{ID: "u1", Email: "alice@example.com"},
+				Body:      "@bob can you check it?",
+				Mentions: []*FindingCommentMention{
+					{CommentID: "c1", UserID: "u2", User: &User{ID: "u2", Email: "bob@example.com"}},
+				},
+				Edits: []*FindingCommentEdit{
+					{ID: "e1", CommentID: "c1", Body: "can you check it?", EditedBy: "u1",
+						Editor: &User{ID: "u1", Email: "alice@example.com"}, CreatedAt: updatedAt},
+				},
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
+			},
+			want: FindingCommentResponse{
+				ID:        "c1",
+				TeamID:    "t1",
+				FindingID: "f1",
+				Author:    "alice@example.com",
+				Body:      "@bob can you check it?",
+				Mentions:  []string{"bob@example.com"},
+				Edits: []FindingCommentEditResponse{
+					{Body: "can you check it?", EditedBy: "alice@example.com", CreatedAt: updatedAt},
+				},
+				CreatedAt: createdAt,
+				UpdatedAt: updatedAt,
+			},
+		},
+		{
+			name:    "NotEdited",
+			comment: FindingComment{ID: "c1", TeamID: "t1", FindingID: "f1", Body: "ok", CreatedAt: createdAt, UpdatedAt: createdAt},
+			want: FindingCommentResponse{
+				ID:        "c1",
+				TeamID:    "t1",
+				FindingID: "f1",
+				Body:      "ok",
+				Mentions:  []string{},
+				Edits:     []FindingCommentEditResponse{},
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.comment.ToResponse()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("want!=got, diff: %s", diff)
+			}
+		})
+	}
+}
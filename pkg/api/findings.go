@@ -17,6 +17,7 @@ type FindingOverwrite struct {
 	Status         string    `json:"status" validate:"required"`
 	Notes          string    `json:"notes" validate:"required"`
 	TeamID         string    `json:"team_id" validate:"required"`
+	JobID          *string   `json:"job_id,omitempty"` // The job of the bulk overwrite that created it, if any.
 	CreatedAt      time.Time `json:"-"`
 }
 
@@ -45,3 +46,71 @@ func (fr FindingOverwrite) ToResponse() FindingOverwriteResponse {
 
 	return output
 }
+
+// BulkFindingOverwrite applies the same overwrite to a set of findings of a
+// team. The findings are given either as a list of IDs or as a filter.
+type BulkFindingOverwrite struct {
+	TeamID     string          `json:"team_id"`
+	UserID     string          `json:"user_id"`
+	FindingIDs []string        `json:"finding_ids"`
+	Params     *FindingsParams `json:"params"`
+	Status     string          `json:"status"`
+	Notes      string          `json:"notes"`
+	// JobID is the job applying the overwrite asynchronously. The findings
+	// already overwritten by the job are not overwritten again.
+	JobID string `json:"job_id,omitempty"`
+}
+
+// BulkFindingOverwriteResult contains the result of applying a bulk
+// overwrite to one finding. Error is empty if the overwrite was applied.
+type BulkFindingOverwriteResult struct {
+	FindingID      string `json:"finding_id"`
+	StatusPrevious string `json:"status_previous,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// FindingVerification links a finding with the scan launched to verify
+// whether the finding is still present in its target.
+type FindingVerification struct {
+	ID        string    `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	FindingID string    `json:"finding_id" validate:"required"`
+	TeamID    string    `json:"team_id" validate:"required"`
+	ScanID    string    `json:"scan_id" validate:"required"`
+	Checktype string    `json:"checktype" validate:"required"`
+	Target    string    `json:"target" validate:"required"`
+	UserID    string    `json:"user_id" validate:"required"`
+	User      *User     `json:"user,omitempty"` // This line is infered from column name "user_id".
+	CreatedAt time.Time `json:"-"`
+	// Status holds the status of the verification scan as reported by the
+	// scan engine. It is not stored in the database.
+	Status string `json:"status" sql:"-"`
+}
+
+type FindingVerificationResponse struct {
+	ID        string    `json:"id"`
+	FindingID string    `json:"finding_id"`
+	TeamID    string    `json:"team_id"`
+	ScanID    string    `json:"scan_id"`
+	Checktype string    `json:"checktype"`
+	Target    string    `json:"target"`
+	User      string    `json:"user"`
+	Status    string    `json:"status,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (fv FindingVerification) ToResponse() FindingVerificationResponse {
+	output := FindingVerificationResponse{
+		ID:        fv.ID,
+		FindingID: fv.FindingID,
+		TeamID:    fv.TeamID,
+		ScanID:    fv.ScanID,
+		Checktype: fv.Checktype,
+		Target:    fv.Target,
+		Status:    fv.Status,
+		CreatedAt: fv.CreatedAt,
+	}
+	if fv.User != nil {
+		output.User = fv.User.Email
+	}
+	return output
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Formats in which the findings of a team can be exported.
+const (
+	FindingsExportSARIF  = "sarif"
+	FindingsExportCSV    = "csv"
+	FindingsExportNDJSON = "ndjson"
+)
+
+// Status of the exports generated asynchronously.
+const (
+	FindingsExportPending = "PENDING"
+	FindingsExportRunning = "RUNNING"
+	FindingsExportDone    = "DONE"
+	FindingsExportFailed  = "FAILED"
+)
+
+// MaxFindingsExportSize is the maximum number of bytes of an export generated
+// asynchronously.
+const MaxFindingsExportSize = 256 << 20
+
+var (
+	// ErrInvalidFindingsExportFormat is returned when the format of an
+	// export is not one of the supported ones.
+	ErrInvalidFindingsExportFormat = errors.New("invalid findings export format")
+	// ErrFindingsExportTooLarge is returned when an export is larger than
+	// MaxFindingsExportSize.
+	ErrFindingsExportTooLarge = errors.New("findings export is too large, narrow the filter")
+)
+
+var findingsExportContentTypes = map[string]string{
+	FindingsExportSARIF:  "application/sarif+json",
+	FindingsExportCSV:    "text/csv",
+	FindingsExportNDJSON: "application/x-ndjson",
+}
+
+// FindingsExport contains the findings of a team, matching a filter, encoded
+// in one of the supported formats. The exports of the teams with many
+// findings are generated asynchronously and stored, so they can be
+// downloaded once the job generating them finishes.
+type FindingsExport struct {
+	ID       string               `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID   string               `json:"team_id"`
+	JobID    *string              `json:"job_id,omitempty"`
+	Format   string               `json:"format"`
+	Params   FindingsExportParams `json:"params"`
+	Status   string               `json:"status"`
+	Findings int                  `json:"findings"`
+	// Size is the number of bytes of the content of the export, which is
+	// stored in parts.
+	Size      int64      `json:"size"`
+	ClaimedAt *time.Time `json:"-"`
+	// DownloadPath is the path of the API the export can be downloaded from.
+	// It is not stored in the database.
+	DownloadPath string    `json:"download_path,omitempty" sql:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (FindingsExport) TableName() string {
+	return "findings_exports"
+}
+
+// FindingsExportParams are the params of the findings of an export. They are
+// stored as a JSON document.
+type FindingsExportParams FindingsParams
+
+// Scan scans value into the params, implements sql.Scanner interface.
+func (p *FindingsExportParams) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// Value returns json value, implements driver.Valuer interface.
+func (p FindingsExportParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// FindingsExportPart is a part of the content of an export.
+type FindingsExportPart struct {
+	ExportID string `gorm:"primary_key"`
+	// Part is the position of the part in the content of the export,
+	// starting at 0. It is not tagged as a primary key, as gorm doesn't
+	// insert blank primary keys.
+	Part    int
+	Content []byte
+}
+
+func (FindingsExportPart) TableName() string {
+	return "findings_export_parts"
+}
+
+// FindingsExportWriter writes the findings of an export to w and returns the
+// number of findings written.
+type FindingsExportWriter func(w io.Writer) (int, error)
+
+// Validate checks the format of the export.
+func (e FindingsExport) Validate() error {
+	if _, ok := findingsExportContentTypes[e.Format]; !ok {
+		return ErrInvalidFindingsExportFormat
+	}
+	return nil
+}
+
+// ContentType returns the media type of the format of the export.
+func (e FindingsExport) ContentType() string {
+	return findingsExportContentTypes[e.Format]
+}
+
+// FileName returns the name of the file the export is downloaded as.
+func (e FindingsExport) FileName() string {
+	ext := e.Format
+	if e.Format == FindingsExportSARIF {
+		ext = "sarif.json"
+	}
+	return fmt.Sprintf("findings-%s.%s", e.TeamID, ext)
+}
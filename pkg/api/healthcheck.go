@@ -7,6 +7,9 @@ package api
 // Healthcheck ....
 type Healthcheck struct {
 	Status string `json:"status" validate:"required"`
+	// Checktypes describes the freshness of the checktypes information
+	// cached from vulcan-core.
+	Checktypes *ChecktypesCacheStatus `json:"checktypes,omitempty"`
 }
 
 // ToResponse ...
@@ -16,5 +19,6 @@ func (h Healthcheck) ToResponse() HealthcheckResponse {
 
 // HealthcheckResponse ...
 type HealthcheckResponse struct {
-	Status string `json:"status"`
+	Status     string                 `json:"status"`
+	Checktypes *ChecktypesCacheStatus `json:"checktypes,omitempty"`
 }
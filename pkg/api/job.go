@@ -127,6 +127,8 @@ type JobsRunner struct {
 // JobsClient defines the API service layer methods exposd by the JobsRunner.
 type JobsClient interface {
 	MergeDiscoveredAssets(ctx context.Context, teamID string, assets []Asset, groupName string) error
+	CreateFindingOverwrites(ctx context.Context, bulk BulkFindingOverwrite) ([]BulkFindingOverwriteResult, error)
+	CreateIssueTickets(ctx context.Context, bulk BulkFindingTicketCreate) ([]BulkFindingTicketResult, error)
 	FindJob(ctx context.Context, jobID string) (*Job, error)
 	UpdateJob(ctx context.Context, job Job) (*Job, error)
 }
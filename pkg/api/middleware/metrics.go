@@ -79,6 +79,15 @@ var (
 		// Recipient
 		endpoint.ListRecipients:   entityRecipient,
 		endpoint.UpdateRecipients: entityRecipient,
+		// Recipient verification
+		endpoint.CreateRecipient:             entityRecipient,
+		endpoint.UpdateRecipient:             entityRecipient,
+		endpoint.DeleteRecipient:             entityRecipient,
+		endpoint.SendRecipientVerification:   entityRecipient,
+		endpoint.VerifyRecipient:             entityRecipient,
+		endpoint.UnsubscribeRecipient:        entityRecipient,
+		endpoint.UnsubscribeRecipientPage:    entityRecipient,
+		endpoint.DeactivateBouncedRecipients: entityRecipient,
 		// Asset
 		endpoint.ListAssets:             entityAsset,
 		endpoint.CreateAsset:            entityAsset,
@@ -95,12 +104,18 @@ var (
 		endpoint.GroupAsset:             entityAsset,
 		endpoint.UngroupAsset:           entityAsset,
 		endpoint.ListAssetGroup:         entityAsset,
+		// Asset checktype settings
+		endpoint.ListAssetChecktypeSettings:  entityAsset,
+		endpoint.CreateAssetChecktypeSetting: entityAsset,
+		endpoint.UpdateAssetChecktypeSetting: entityAsset,
+		endpoint.DeleteAssetChecktypeSetting: entityAsset,
 		// Program
 		endpoint.ListPrograms:          entityProgram,
 		endpoint.CreateProgram:         entityProgram,
 		endpoint.FindProgram:           entityProgram,
 		endpoint.UpdateProgram:         entityProgram,
 		endpoint.DeleteProgram:         entityProgram,
+		endpoint.ProgramPlan:           entityProgram,
 		endpoint.CreateSchedule:        entityProgram,
 		endpoint.DeleteSchedule:        entityProgram,
 		endpoint.ScheduleGlobalProgram: entityProgram,
@@ -110,12 +125,22 @@ var (
 		endpoint.FindPolicy:   entityPolicy,
 		endpoint.UpdatePolicy: entityPolicy,
 		endpoint.DeletePolicy: entityPolicy,
+		// Policy templates
+		endpoint.ListPolicyTemplates:  entityPolicy,
+		endpoint.CreatePolicyTemplate: entityPolicy,
+		endpoint.FindPolicyTemplate:   entityPolicy,
+		endpoint.UpdatePolicyTemplate: entityPolicy,
+		endpoint.DeletePolicyTemplate: entityPolicy,
+		endpoint.PolicyDivergence:     entityPolicy,
+		// Global policies
+		endpoint.ListGlobalPolicyEvaluations: entityPolicy,
 		// Check
 		endpoint.ListChecktypeSetting:   entityCheck,
 		endpoint.CreateChecktypeSetting: entityCheck,
 		endpoint.FindChecktypeSetting:   entityCheck,
 		endpoint.UpdateChecktypeSetting: entityCheck,
 		endpoint.DeleteChecktypeSetting: entityCheck,
+		endpoint.ListChecktypes:         entityCheck,
 		// Scan
 		endpoint.ListProgramScans: entityScan,
 		endpoint.CreateScan:       entityScan,
@@ -130,8 +155,40 @@ var (
 		endpoint.FindFinding:            entityFinding,
 		endpoint.CreateFindingOverwrite: entityFinding,
 		endpoint.ListFindingOverwrites:  entityFinding,
+		endpoint.ListFindingComments:    entityFinding,
+		endpoint.CreateFindingComment:   entityFinding,
+		endpoint.UpdateFindingComment:   entityFinding,
+		endpoint.DeleteFindingComment:   entityFinding,
+		endpoint.ListRiskAcceptances:    entityFinding,
+		endpoint.FindRiskAcceptance:     entityFinding,
+		endpoint.RequestRiskAcceptance:  entityFinding,
+		endpoint.ApproveRiskAcceptance:  entityFinding,
+		endpoint.RejectRiskAcceptance:   entityFinding,
 		endpoint.ListFindingsLabels:     entityFinding,
 		endpoint.CreateFindingTicket:    entityFinding,
+		endpoint.CreateIssueTickets:     entityFinding,
+		endpoint.ListFindingTickets:     entityFinding,
+		endpoint.UpdateTeamTracker:      entityTeam,
+		// Bulk finding overwrites
+		endpoint.CreateFindingOverwrites: entityFinding,
+		// SLA
+		endpoint.ListFindingsSLA:     entityFinding,
+		endpoint.ListTeamSLAPolicies: entityFinding,
+		endpoint.CreateTeamSLAPolicy: entityFinding,
+		endpoint.UpdateTeamSLAPolicy: entityFinding,
+		endpoint.DeleteTeamSLAPolicy: entityFinding,
+		endpoint.ListSLAPolicies:     entityFinding,
+		endpoint.CreateSLAPolicy:     entityFinding,
+		endpoint.UpdateSLAPolicy:     entityFinding,
+		endpoint.DeleteSLAPolicy:     entityFinding,
+		// Ticket providers
+		endpoint.FindTeamTicketProvider:   entityTeam,
+		endpoint.UpdateTeamTicketProvider: entityTeam,
+		endpoint.DeleteTeamTicketProvider: entityTeam,
+		// Findings exports
+		endpoint.ExportFindings:         entityFinding,
+		endpoint.CreateFindingsExport:   entityFinding,
+		endpoint.DownloadFindingsExport: entityFinding,
 		// Stats
 		endpoint.StatsCoverage:              entityStats,
 		endpoint.StatsMTTR:                  entityStats,
@@ -139,6 +196,7 @@ var (
 		endpoint.StatsCurrentExposure:       entityStats,
 		endpoint.StatsOpen:                  entityStats,
 		endpoint.StatsFixed:                 entityStats,
+		endpoint.StatsTrend:                 entityStats,
 		endpoint.GlobalStatsMTTR:            entityStats,
 		endpoint.GlobalStatsExposure:        entityStats,
 		endpoint.GlobalStatsCurrentExposure: entityStats,
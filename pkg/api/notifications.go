@@ -0,0 +1,212 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	vulcanreport "github.com/adevinta/vulcan-report"
+)
+
+const (
+	// NotificationTriggerCompleted defines a rule that notifies every time a
+	// scan of the program finishes.
+	NotificationTriggerCompleted NotificationTrigger = "COMPLETED"
+	// NotificationTriggerFailed defines a rule that notifies when a scan of
+	// the program finishes with, at least, one check that could not be run.
+	NotificationTriggerFailed NotificationTrigger = "FAILED"
+	// NotificationTriggerNewFindings defines a rule that notifies when a scan
+	// of the program detects new findings with a severity equal or higher
+	// than the MinSeverity of the rule.
+	NotificationTriggerNewFindings NotificationTrigger = "NEW_FINDINGS"
+
+	// NotificationChannelEmail sends the notifications to the recipients of
+	// the team using the reports publisher.
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+	// NotificationChannelWebhook posts the notifications, as JSON, to the
+	// URL of the rule.
+	NotificationChannelWebhook NotificationChannel = "WEBHOOK"
+	// NotificationChannelChat posts the notifications as a text message to
+	// the chat incoming webhook URL of the rule.
+	NotificationChannelChat NotificationChannel = "CHAT"
+)
+
+var (
+	// ErrInvalidNotificationTrigger is returned when the trigger of a
+	// notification rule is unknown.
+	ErrInvalidNotificationTrigger = errors.New("invalid notification trigger")
+	// ErrInvalidNotificationChannel is returned when the channel of a
+	// notification rule is unknown.
+	ErrInvalidNotificationChannel = errors.New("invalid notification channel")
+	// ErrInvalidNotificationSeverity is returned when the min severity of a
+	// notification rule is unknown.
+	ErrInvalidNotificationSeverity = errors.New("invalid notification min severity")
+	// ErrInvalidNotificationURL is returned when a notification rule for a
+	// webhook or chat channel does not have a valid http(s) URL.
+	ErrInvalidNotificationURL = errors.New("invalid notification url")
+	// ErrNotificationURLNotAllowed is returned when the host of the URL of a
+	// notification rule resolves to a loopback, link-local or private
+	// address.
+	ErrNotificationURLNotAllowed = errors.New("notification url does not point to a public address")
+
+	// sharedAddressSpace is the carrier-grade NAT range defined in RFC 6598,
+	// which is not covered by net.IP.IsPrivate.
+	sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+	// lookupIP resolves the hosts of the notification URLs. It is a variable
+	// so the tests do not depend on the DNS.
+	lookupIP = net.LookupIP
+
+	severityRanks = map[string]vulcanreport.SeverityRank{
+		"info":     vulcanreport.SeverityNone,
+		"low":      vulcanreport.SeverityLow,
+		"medium":   vulcanreport.SeverityMedium,
+		"high":     vulcanreport.SeverityHigh,
+		"critical": vulcanreport.SeverityCritical,
+	}
+)
+
+type NotificationTrigger string
+
+type NotificationChannel string
+
+// NotificationRule defines when and where to notify about the scans of a
+// program.
+type NotificationRule struct {
+	ID        string `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID    string `json:"team_id" validate:"required"`
+	ProgramID string `json:"program_id" validate:"required"`
+	// Trigger possible values are:
+	// - COMPLETED
+	// - FAILED
+	// - NEW_FINDINGS
+	Trigger NotificationTrigger `json:"trigger" validate:"required"`
+	// MinSeverity is only used by the NEW_FINDINGS trigger. Its possible
+	// values are: info, low, medium, high and critical.
+	MinSeverity string `json:"min_severity"`
+	// Channel possible values are:
+	// - EMAIL
+	// - WEBHOOK
+	// - CHAT
+	Channel   NotificationChannel `json:"channel" validate:"required"`
+	URL       string              `json:"url"`
+	Disabled  *bool               `json:"disabled"`
+	CreatedAt time.Time           `json:"-"`
+	UpdatedAt time.Time           `json:"-"`
+}
+
+// Validate checks the trigger, channel, severity and URL of the rule are
+// consistent.
+func (r NotificationRule) Validate() error {
+	switch r.Trigger {
+	case NotificationTriggerCompleted, NotificationTriggerFailed:
+	case NotificationTriggerNewFindings:
+		if _, ok := severityRanks[strings.ToLower(r.MinSeverity)]; !ok {
+			return ErrInvalidNotificationSeverity
+		}
+	default:
+		return ErrInvalidNotificationTrigger
+	}
+	switch r.Channel {
+	case NotificationChannelEmail:
+	case NotificationChannelWebhook, NotificationChannelChat:
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidNotificationURL
+		}
+		if err := checkNotificationHost(u.Hostname()); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidNotificationChannel
+	}
+	return nil
+}
+
+// checkNotificationHost returns an error if the host, or any of the addresses
+// it resolves to, is not a public address. The addresses are checked again
+// when the notification is sent, as the DNS records of the host can change
+// after the rule is validated.
+func checkNotificationHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return ErrNotificationURLNotAllowed
+		}
+		return nil
+	}
+	ips, err := lookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return ErrInvalidNotificationURL
+	}
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return ErrNotificationURLNotAllowed
+		}
+	}
+	return nil
+}
+
+// IsPublicIP returns false for the loopback, link-local, private, multicast
+// and unspecified addresses, which the notifications must never be sent to.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsPrivate() ||
+		ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// MatchesSeverity returns true if a finding with the given score reaches the
+// MinSeverity of the rule.
+func (r NotificationRule) MatchesSeverity(score float32) bool {
+	min, ok := severityRanks[strings.ToLower(r.MinSeverity)]
+	if !ok {
+		return false
+	}
+	return vulcanreport.RankSeverity(score) >= min
+}
+
+type NotificationRuleResponse struct {
+	ID          string              `json:"id"`
+	ProgramID   string              `json:"program_id"`
+	Trigger     NotificationTrigger `json:"trigger"`
+	MinSeverity string              `json:"min_severity,omitempty"`
+	Channel     NotificationChannel `json:"channel"`
+	URL         string              `json:"url,omitempty"`
+	Disabled    bool                `json:"disabled"`
+}
+
+// IsDisabled returns true if the rule must not be evaluated.
+func (r NotificationRule) IsDisabled() bool {
+	return r.Disabled != nil && *r.Disabled
+}
+
+func (r NotificationRule) ToResponse() NotificationRuleResponse {
+	return NotificationRuleResponse{
+		ID:          r.ID,
+		ProgramID:   r.ProgramID,
+		Trigger:     r.Trigger,
+		MinSeverity: r.MinSeverity,
+		Channel:     r.Channel,
+		URL:         maskURL(r.URL),
+		Disabled:    r.IsDisabled(),
+	}
+}
+
+// maskURL hides the path, query and credentials of a notification URL, as
+// the incoming webhooks of the chat systems carry their secret in them.
+func maskURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	masked := u.Scheme + "://" + u.Host
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.User != nil {
+		masked += "/***"
+	}
+	return masked
+}
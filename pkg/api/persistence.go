@@ -4,7 +4,11 @@ Copyright 2021 Adevinta
 
 package api
 
-import "github.com/adevinta/vulcan-api/pkg/saml"
+import (
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/saml"
+)
 
 type VulcanitoStore interface {
 	Close() error
@@ -34,6 +38,7 @@ type VulcanitoStore interface {
 	FindTeamByTag(tag string) (*Team, error)
 	FindTeamsByTags(tags []string) ([]*Team, error)
 	FindTeamByProgram(programID string) (*Team, error)
+	UpdateTeamTracker(teamID string, enabled bool) (*Team, error)
 	DeleteTeam(teamID string) error
 	ListTeams() ([]*Team, error)
 
@@ -44,8 +49,16 @@ type VulcanitoStore interface {
 
 	UpdateRecipients(teamID string, emails []string) error
 	ListRecipients(teamID string) ([]*Recipient, error)
+	FindRecipient(teamID, email string) (*Recipient, error)
+	CreateRecipient(recipient Recipient) (*Recipient, error)
+	UpdateRecipient(recipient Recipient) (*Recipient, error)
+	DeleteRecipient(teamID, email string) error
+	DeactivateRecipients(emails []string, reason string) (int64, error)
 
 	ListAssets(teamID string, asset Asset) ([]*Asset, error)
+	// ListAssetsByIdentifiers returns the assets of a team with the given
+	// identifiers, loading only their annotations.
+	ListAssetsByIdentifiers(teamID string, identifiers []string) ([]*Asset, error)
 	FindAsset(teamID, assetID string) (*Asset, error)
 	CreateAsset(asset Asset, groups []Group) (*Asset, error)
 	CreateAssets(assets []Asset, groups []Group) ([]Asset, error)
@@ -63,6 +76,12 @@ type VulcanitoStore interface {
 	PutAssetAnnotations(teamID string, assetID string, annotations []*AssetAnnotation) ([]*AssetAnnotation, error)
 	DeleteAssetAnnotations(teamID string, assetID string, annotations []*AssetAnnotation) error
 
+	ListAssetChecktypeSettings(teamID, assetID string) ([]*AssetChecktypeSetting, error)
+	FindAssetChecktypeSetting(teamID, assetID, settingID string) (*AssetChecktypeSetting, error)
+	CreateAssetChecktypeSetting(setting AssetChecktypeSetting) (*AssetChecktypeSetting, error)
+	UpdateAssetChecktypeSetting(setting AssetChecktypeSetting) (*AssetChecktypeSetting, error)
+	DeleteAssetChecktypeSetting(setting AssetChecktypeSetting) error
+
 	CreateGroup(group Group) (*Group, error)
 	ListGroups(teamID, groupName string) ([]*Group, error)
 	UpdateGroup(group Group) (*Group, error)
@@ -99,10 +118,86 @@ type VulcanitoStore interface {
 	UpdateChecktypeSetting(checktypeSetting ChecktypeSetting) (*ChecktypeSetting, error)
 	DeleteChecktypeSetting(checktypeSettingID string) error
 
+	ListPolicyTemplates() ([]*PolicyTemplate, error)
+	CreatePolicyTemplate(template PolicyTemplate) (*PolicyTemplate, error)
+	FindPolicyTemplate(templateID string) (*PolicyTemplate, error)
+	UpdatePolicyTemplate(template PolicyTemplate) (*PolicyTemplate, error)
+	DeletePolicyTemplate(templateID string) error
+
 	FindGlobalProgramMetadata(programID string, teamID string) (*GlobalProgramsMetadata, error)
 	UpsertGlobalProgramMetadata(teamID, program string, defaultAutosend bool, defaultDisabled bool, defaultCron string, autosend *bool, disabled *bool, cron *string) error
 	DeleteProgramMetadata(program string) error
+	ListGlobalEntityDefinitions() ([]*GlobalEntityDefinition, error)
+	ListGlobalPolicyEvaluations() ([]*GlobalPolicyEvaluation, error)
+	ReplaceGlobalPolicyEvaluations(evaluations []*GlobalPolicyEvaluation) error
+
+	FindChecktypesSnapshot(name string) (*ChecktypesSnapshot, error)
+	UpsertChecktypesSnapshot(snapshot ChecktypesSnapshot) error
 
 	CreateFindingOverwrite(findingOverwrite FindingOverwrite) error
 	ListFindingOverwrites(findingID string) ([]*FindingOverwrite, error)
+	CreateFindingOverwrites(findingOverwrites []FindingOverwrite) error
+	CreateFindingOverwritesAsync(bulk BulkFindingOverwrite) (*Job, error)
+	ListJobFindingOverwrites(jobID string) ([]*FindingOverwrite, error)
+	CreateFindingsExportAsync(export FindingsExport) (*Job, error)
+	ClaimFindingsExport(staleBefore time.Time) (*FindingsExport, error)
+	UpdateFindingsExport(export FindingsExport) (*FindingsExport, error)
+	FindFindingsExport(teamID, exportID string) (*FindingsExport, error)
+	DeleteFindingsExports(createdBefore time.Time) (int, error)
+	CreateFindingsExportPart(part FindingsExportPart) error
+	FindFindingsExportPart(exportID string, part int) (*FindingsExportPart, error)
+	DeleteFindingsExportParts(exportID string) error
+	ListFindingComments(teamID, findingID string) ([]*FindingComment, error)
+	FindFindingComment(teamID, findingID, commentID string) (*FindingComment, error)
+	CreateFindingComment(comment FindingComment) (*FindingComment, error)
+	UpdateFindingComment(comment FindingComment, edit *FindingCommentEdit) (*FindingComment, error)
+	DeleteFindingComment(comment FindingComment) error
+	UpsertFindingTicket(ticket FindingTicket) (*FindingTicket, error)
+	FindFindingTicket(teamID, findingID string) (*FindingTicket, error)
+	ListFindingTickets(teamID string, open bool) ([]*FindingTicket, error)
+	UpdateFindingTicket(ticket FindingTicket) (*FindingTicket, error)
+	CreateIssueTicketsAsync(bulk BulkFindingTicketCreate) (*Job, error)
+	FindTeamTicketProvider(teamID string) (*TicketProvider, error)
+	UpsertTeamTicketProvider(provider TicketProvider) (*TicketProvider, error)
+	DeleteTeamTicketProvider(teamID string) error
+	CreateRiskAcceptance(acceptance RiskAcceptance) (*RiskAcceptance, error)
+	ListRiskAcceptances(teamID, findingID, status string) ([]*RiskAcceptance, error)
+	ListExpiredRiskAcceptances(now time.Time) ([]*RiskAcceptance, error)
+	FindRiskAcceptance(teamID, acceptanceID string) (*RiskAcceptance, error)
+	TransitionRiskAcceptance(acceptance RiskAcceptance, fromStatus string, overwrite *FindingOverwrite) (bool, error)
+	ListSLAPolicies(teamID string) ([]*SLAPolicy, error)
+	FindSLAPolicy(teamID, policyID string) (*SLAPolicy, error)
+	CreateSLAPolicy(policy SLAPolicy) (*SLAPolicy, error)
+	UpdateSLAPolicy(policy SLAPolicy) (*SLAPolicy, error)
+	DeleteSLAPolicy(policy SLAPolicy) error
+	RecordSLABreaches(teamID string, breaches []SLABreach, notify func([]SLABreach) error) (int, error)
+
+	CreateFindingVerification(verification FindingVerification) (*FindingVerification, error)
+	ListFindingVerifications(teamID, findingID string) ([]*FindingVerification, error)
+
+	ListScanQueueEntries(teamID string, statuses []ScanQueueStatus) ([]*ScanQueueEntry, error)
+	AdmitScanQueueEntries(admit func(entries []*ScanQueueEntry) []*ScanQueueEntry) ([]*ScanQueueEntry, error)
+	StartScanQueueEntry(entryID, scanID string, checkCount int) (bool, error)
+	UpdateScanQueueEntryChecks(entryID string, checkCount, checksInFlight int) (bool, error)
+	FinishScanQueueEntry(entryID, reason string) (bool, error)
+	CancelScanQueueEntry(teamID, entryID string) (bool, error)
+
+	CreateNotificationRule(rule NotificationRule) (*NotificationRule, error)
+	ListNotificationRules(teamID, programID string) ([]*NotificationRule, error)
+	FindNotificationRule(teamID, programID, ruleID string) (*NotificationRule, error)
+	UpdateNotificationRule(rule NotificationRule) (*NotificationRule, error)
+	DeleteNotificationRule(rule NotificationRule) error
+
+	CreateReportSubscription(subscription ReportSubscription) (*ReportSubscription, error)
+	ListReportSubscriptions(teamID string) ([]*ReportSubscription, error)
+	ListDueReportSubscriptions(now time.Time) ([]*ReportSubscription, error)
+	FindReportSubscription(teamID, subscriptionID string) (*ReportSubscription, error)
+	UpdateReportSubscription(subscription ReportSubscription) (*ReportSubscription, error)
+	DeleteReportSubscription(subscription ReportSubscription) error
+	ClaimReportSubscription(subscription ReportSubscription, nextRunAt, lastRunAt time.Time) (bool, error)
+
+	UpsertStatsSnapshots(snapshots []StatsSnapshot) error
+	ListStatsSnapshots(teamID, groupID string, from, to time.Time) ([]*StatsSnapshot, error)
+	ClaimStatsSnapshots(teamID string, date, staleBefore time.Time) (bool, error)
+	ReleaseStatsSnapshots(teamID string, date time.Time, done bool) error
 }
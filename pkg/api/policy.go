@@ -14,6 +14,8 @@ type Policy struct {
 	ChecktypeSettings      []*ChecktypeSetting       `json:"checktype_settings"` // This line is infered from other tables.
 	ProgramsGroupsPolicies []*ProgramsGroupsPolicies `json:"program_policies"`   // This line is infered from other tables.
 	Description            *string                   `json:"description,omitempty"`
+	TemplateID             *string                   `json:"template_id,omitempty"`
+	Template               *PolicyTemplate           `json:"template,omitempty"` // This line is infered from column name "template_id".
 	CreatedAt              *time.Time                `json:"-"`
 	UpdatedAt              *time.Time                `json:"-"`
 }
@@ -23,10 +25,12 @@ func (Policy) TableName() string {
 }
 
 type PolicyResponse struct {
-	ID                     string  `json:"id"`
-	Name                   string  `json:"name"`
-	Description            *string `json:"description,omitempty"`
-	CheckTypeSettingsCount int     `json:"settings_count"`
+	ID                     string                     `json:"id"`
+	Name                   string                     `json:"name"`
+	Description            *string                    `json:"description,omitempty"`
+	CheckTypeSettingsCount int                        `json:"settings_count"`
+	TemplateID             *string                    `json:"template_id,omitempty"`
+	EffectiveSettings      []ChecktypeSettingResponse `json:"effective_settings,omitempty"`
 }
 
 func (p Policy) ToResponse() *PolicyResponse {
@@ -34,7 +38,15 @@ func (p Policy) ToResponse() *PolicyResponse {
 		ID:          p.ID,
 		Name:        p.Name,
 		Description: p.Description,
+		TemplateID:  p.TemplateID,
+	}
+	settings := p.EffectiveSettings()
+	response.CheckTypeSettingsCount = len(settings)
+	if p.Template != nil {
+		response.EffectiveSettings = []ChecktypeSettingResponse{}
+		for _, s := range settings {
+			response.EffectiveSettings = append(response.EffectiveSettings, *s.ToResponse())
+		}
 	}
-	response.CheckTypeSettingsCount = len(p.ChecktypeSettings)
 	return &response
 }
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+// PolicyTemplate is an organisation-level set of checktype settings that
+// the policies of the teams can extend. A policy extending a template
+// inherits all the settings of the template, and can override the options
+// of any of them, exclude them or add new ones using its own checktype
+// settings.
+type PolicyTemplate struct {
+	ID          string                   `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	Name        string                   `json:"name" validate:"required"`
+	Description *string                  `json:"description,omitempty"`
+	Settings    []*PolicyTemplateSetting `json:"settings"` // This line is infered from other tables.
+	CreatedAt   *time.Time               `json:"-"`
+	UpdatedAt   *time.Time               `json:"-"`
+}
+
+// PolicyTemplateSetting defines a checktype, and its options, included in a
+// policy template.
+type PolicyTemplateSetting struct {
+	ID               string     `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	PolicyTemplateID string     `json:"policy_template_id"`
+	CheckTypeName    string     `json:"checktype_name"`
+	Options          *string    `json:"options"`
+	CreatedAt        *time.Time `json:"-"`
+	UpdatedAt        *time.Time `json:"-"`
+}
+
+// Validate checks that the template has a name and that its settings are
+// valid and do not repeat checktypes.
+func (t PolicyTemplate) Validate() error {
+	if err := validator.New().Struct(t); err != nil {
+		return errors.Validation(err)
+	}
+	names := map[string]bool{}
+	for _, s := range t.Settings {
+		if common.IsStringEmpty(&s.CheckTypeName) {
+			return errors.Validation("policy template setting without checktype name")
+		}
+		if !common.IsStringEmpty(s.Options) && !common.IsValidJSON(s.Options) {
+			return errors.Validation(fmt.Sprintf("invalid options for checktype %s", s.CheckTypeName))
+		}
+		if names[s.CheckTypeName] {
+			return errors.Validation(fmt.Sprintf("checktype %s is repeated", s.CheckTypeName))
+		}
+		names[s.CheckTypeName] = true
+	}
+	return nil
+}
+
+type PolicyTemplateResponse struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Description *string                    `json:"description,omitempty"`
+	Settings    []ChecktypeSettingResponse `json:"settings"`
+}
+
+func (t PolicyTemplate) ToResponse() *PolicyTemplateResponse {
+	response := &PolicyTemplateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Settings:    []ChecktypeSettingResponse{},
+	}
+	for _, s := range t.Settings {
+		response.Settings = append(response.Settings, ChecktypeSettingResponse{
+			ID:            s.ID,
+			CheckTypeName: s.CheckTypeName,
+			Options:       common.StringValue(s.Options),
+		})
+	}
+	return response
+}
+
+// EffectiveSettings returns the checktype settings that apply to the policy.
+// For policies extending a template, they are the settings of the template
+// merged with the ones of the policy: the settings of the policy replace
+// the settings of the template for the same checktype, and excluded
+// checktypes are removed. The template must be loaded for the merge to
+// happen.
+func (p Policy) EffectiveSettings() []*ChecktypeSetting {
+	own := map[string]*ChecktypeSetting{}
+	for _, s := range p.ChecktypeSettings {
+		own[s.CheckTypeName] = s
+	}
+	settings := []*ChecktypeSetting{}
+	inherited := map[string]bool{}
+	if p.Template != nil {
+		for _, ts := range p.Template.Settings {
+			inherited[ts.CheckTypeName] = true
+			if s, ok := own[ts.CheckTypeName]; ok {
+				if !s.IsExcluded() {
+					settings = append(settings, s)
+				}
+				continue
+			}
+			settings = append(settings, &ChecktypeSetting{
+				ID:            ts.ID,
+				PolicyID:      p.ID,
+				CheckTypeName: ts.CheckTypeName,
+				Options:       ts.Options,
+			})
+		}
+	}
+	for _, s := range p.ChecktypeSettings {
+		if inherited[s.CheckTypeName] || s.IsExcluded() {
+			continue
+		}
+		settings = append(settings, s)
+	}
+	return settings
+}
+
+// PolicyDivergence describes the differences between a policy and the
+// template it extends.
+type PolicyDivergence struct {
+	PolicyID   string                   `json:"policy_id"`
+	TemplateID string                   `json:"template_id"`
+	Overridden []ChecktypeSettingChange `json:"overridden"`
+	Excluded   []string                 `json:"excluded"`
+	Added      []ChecktypeSettingChange `json:"added"`
+}
+
+// ChecktypeSettingChange contains the options of a checktype in a policy
+// and in the template the policy extends.
+type ChecktypeSettingChange struct {
+	CheckTypeName   string  `json:"checktype_name"`
+	TemplateOptions *string `json:"template_options,omitempty"`
+	PolicyOptions   *string `json:"policy_options,omitempty"`
+}
+
+// TemplateDivergence returns where the policy diverges from its template:
+// the inherited checktypes whose options are overridden with different
+// values, the inherited checktypes that are excluded and the checktypes
+// that are not in the template. It returns nil if the policy does not
+// extend a template or the template is not loaded.
+func (p Policy) TemplateDivergence() *PolicyDivergence {
+	if p.Template == nil {
+		return nil
+	}
+	d := &PolicyDivergence{
+		PolicyID:   p.ID,
+		TemplateID: p.Template.ID,
+		Overridden: []ChecktypeSettingChange{},
+		Excluded:   []string{},
+		Added:      []ChecktypeSettingChange{},
+	}
+	inherited := map[string]*PolicyTemplateSetting{}
+	for _, ts := range p.Template.Settings {
+		inherited[ts.CheckTypeName] = ts
+	}
+	for _, s := range p.ChecktypeSettings {
+		ts, ok := inherited[s.CheckTypeName]
+		switch {
+		case !ok && s.IsExcluded():
+			// Excluding a checktype not in the template has no effect.
+		case !ok:
+			d.Added = append(d.Added, ChecktypeSettingChange{
+				CheckTypeName: s.CheckTypeName,
+				PolicyOptions: s.Options,
+			})
+		case s.IsExcluded():
+			d.Excluded = append(d.Excluded, s.CheckTypeName)
+		case !sameOptions(ts.Options, s.Options):
+			d.Overridden = append(d.Overridden, ChecktypeSettingChange{
+				CheckTypeName:   s.CheckTypeName,
+				TemplateOptions: ts.Options,
+				PolicyOptions:   s.Options,
+			})
+		}
+	}
+	return d
+}
+
+// sameOptions returns true if the given checktype options are equal,
+// ignoring the formatting of the JSON documents.
+func sameOptions(a, b *string) bool {
+	sa, sb := common.StringValue(a), common.StringValue(b)
+	if sa == "" || sb == "" {
+		return sa == sb
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(sa), &va) != nil || json.Unmarshal([]byte(sb), &vb) != nil {
+		return sa == sb
+	}
+	return reflect.DeepEqual(va, vb)
+}
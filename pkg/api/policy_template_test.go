@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+var webTemplate = &PolicyTemplate{
+	ID:   "t1",
+	Name: "web",
+	Settings: []*PolicyTemplateSetting{
+		{ID: "ts1", CheckTypeName: "vulcan-zap", Options: common.String(`{"depth": 2}`)},
+		{ID: "ts2", CheckTypeName: "vulcan-tls"},
+		{ID: "ts3", CheckTypeName: "vulcan-nessus"},
+	},
+}
+
+func settingsSummary(settings []*ChecktypeSetting) map[string]string {
+	summary := map[string]string{}
+	for _, s := range settings {
+		summary[s.CheckTypeName] = common.StringValue(s.Options)
+	}
+	return summary
+}
+
+func TestPolicy_EffectiveSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   map[string]string
+	}{
+		{
+			name: "WithoutTemplate",
+			policy: Policy{
+				ChecktypeSettings: []*ChecktypeSetting{
+					{CheckTypeName: "vulcan-zap"},
+					{CheckTypeName: "vulcan-tls", Excluded: common.Bool(true)},
+				},
+			},
+			want: map[string]string{"vulcan-zap": ""},
+		},
+		{
+			name:   "InheritsTemplate",
+			policy: Policy{Template: webTemplate},
+			want: map[string]string{
+				"vulcan-zap":    `{"depth": 2}`,
+				"vulcan-tls":    "",
+				"vulcan-nessus": "",
+			},
+		},
+		{
+			name: "OverridesExclusionsAndAdditions",
+			policy: Policy{
+				Template: webTemplate,
+				ChecktypeSettings: []*ChecktypeSetting{
+					{CheckTypeName: "vulcan-zap", Options: common.String(`{"depth": 5}`)},
+					{CheckTypeName: "vulcan-nessus", Excluded: common.Bool(true)},
+					{CheckTypeName: "vulcan-retirejs"},
+				},
+			},
+			want: map[string]string{
+				"vulcan-zap":      `{"depth": 5}`,
+				"vulcan-tls":      "",
+				"vulcan-retirejs": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := settingsSummary(tt.policy.EffectiveSettings())
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("effective settings mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestPolicy_TemplateDivergence(t *testing.T) {
+	policy := Policy{
+		ID:       "p1",
+		Template: webTemplate,
+		ChecktypeSettings: []*ChecktypeSetting{
+			{CheckTypeName: "vulcan-zap", Options: common.String(`{"depth":2}`)},
+			{CheckTypeName: "vulcan-tls", Options: common.String(`{"port": 8443}`)},
+			{CheckTypeName: "vulcan-nessus", Excluded: common.Bool(true)},
+			{CheckTypeName: "vulcan-retirejs"},
+			{CheckTypeName: "vulcan-burp", Excluded: common.Bool(true)},
+		},
+	}
+	want := &PolicyDivergence{
+		PolicyID:   "p1",
+		TemplateID: "t1",
+		Overridden: []ChecktypeSettingChange{
+			{CheckTypeName: "vulcan-tls", PolicyOptions: common.String(`{"port": 8443}`)},
+		},
+		Excluded: []string{"vulcan-nessus"},
+		Added:    []ChecktypeSettingChange{{CheckTypeName: "vulcan-retirejs"}},
+	}
+	if diff := cmp.Diff(want, policy.TemplateDivergence()); diff != "" {
+		t.Errorf("divergence mismatch (-want +got):\n%v", diff)
+	}
+	if d := (Policy{}).TemplateDivergence(); d != nil {
+		t.Errorf("divergence of a policy without template = %v, want nil", d)
+	}
+}
+
+func TestPolicyTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template PolicyTemplate
+		wantErr  bool
+	}{
+		{name: "Valid", template: *webTemplate},
+		{name: "NoName", template: PolicyTemplate{}, wantErr: true},
+		{
+			name: "InvalidOptions",
+			template: PolicyTemplate{
+				Name:     "web",
+				Settings: []*PolicyTemplateSetting{{CheckTypeName: "vulcan-zap", Options: common.String("{")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "RepeatedChecktype",
+			template: PolicyTemplate{
+				Name: "web",
+				Settings: []*PolicyTemplateSetting{
+					{CheckTypeName: "vulcan-zap"},
+					{CheckTypeName: "vulcan-zap"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.template.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PolicyTemplate.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
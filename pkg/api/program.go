@@ -52,6 +52,26 @@ func (p Program) ValidateGroupsPolicies() error {
 	return err
 }
 
+// SetAssetChecktypeSettings assigns to each asset of the program the given
+// checktype settings that belong to it, replacing the ones it had.
+func (p Program) SetAssetChecktypeSettings(settings []*AssetChecktypeSetting) {
+	byAsset := map[string][]*AssetChecktypeSetting{}
+	for _, s := range settings {
+		byAsset[s.AssetID] = append(byAsset[s.AssetID], s)
+	}
+	for _, gp := range p.ProgramsGroupsPolicies {
+		if gp.Group == nil {
+			continue
+		}
+		for _, ag := range gp.Group.AssetGroup {
+			if ag.Asset == nil {
+				continue
+			}
+			ag.Asset.ChecktypeSettings = byAsset[ag.Asset.ID]
+		}
+	}
+}
+
 // ProgramsGroupsPolicies defines the association between a group and a policy in a
 // program.
 type ProgramsGroupsPolicies struct {
@@ -153,3 +173,39 @@ type GlobalProgramsMetadata struct {
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
 }
+
+const (
+	// GlobalEntityGroup is the kind of the definitions of global groups.
+	GlobalEntityGroup = "group"
+	// GlobalEntityPolicy is the kind of the definitions of global policies.
+	GlobalEntityPolicy = "policy"
+	// GlobalEntityProgram is the kind of the definitions of global programs.
+	GlobalEntityProgram = "program"
+	// GlobalEntityPolicyConfig is the kind of the definitions that replace
+	// the allowed and blocked checks and asset types of a global policy.
+	GlobalEntityPolicyConfig = "policy_config"
+)
+
+// GlobalPolicyEvaluation contains the checktypes of a global policy
+// resulting of evaluating it with the current global policy config and
+// checktypes. Added and Removed contain the changes from the previous
+// different evaluation of the policy.
+type GlobalPolicyEvaluation struct {
+	Policy      string     `json:"policy"`
+	Checktypes  []string   `json:"checktypes"`
+	Added       []string   `json:"added"`
+	Removed     []string   `json:"removed"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+	ChangedAt   *time.Time `json:"changed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// GlobalEntityDefinition stores, as JSON, the definition of a global group,
+// policy or program that is not built into the API.
+type GlobalEntityDefinition struct {
+	Kind       string `gorm:"primary_key"`
+	Name       string `gorm:"primary_key"`
+	Definition string
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+}
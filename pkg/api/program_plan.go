@@ -0,0 +1,47 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+// Reasons why an asset of a program is not scanned.
+const (
+	ExclusionNotScannable       = "the asset is not scannable"
+	ExclusionAssettypeMismatch  = "no checktype of the policy supports the asset type"
+	ExclusionAssetNotFound      = "the asset does not exist"
+	ExclusionPolicyWithoutCheck = "the policy has no checktypes"
+	ExclusionChecktypesExcluded = "all the checktypes of the policy are excluded for the asset"
+)
+
+// ProgramPlan describes the checks the scan engine would run if a scan of a
+// program was created, and the assets of the program that would not be
+// scanned.
+type ProgramPlan struct {
+	ProgramID      string          `json:"program_id"`
+	Checks         []PlannedCheck  `json:"checks"`
+	ExcludedAssets []ExcludedAsset `json:"excluded_assets"`
+	// Warnings contains the reasons why the plan may not be accurate, for
+	// instance the checktypes catalogue not being available.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PlannedCheck is a checktype that would be run against an asset.
+type PlannedCheck struct {
+	Group      string `json:"group"`
+	Policy     string `json:"policy"`
+	Identifier string `json:"identifier"`
+	AssetType  string `json:"assettype"`
+	Checktype  string `json:"checktype"`
+	Options    string `json:"options,omitempty"`
+}
+
+// ExcludedAsset is an asset of a group of a program that would not be
+// scanned with the policy associated to the group.
+type ExcludedAsset struct {
+	Group      string `json:"group"`
+	Policy     string `json:"policy"`
+	AssetID    string `json:"asset_id"`
+	Identifier string `json:"identifier,omitempty"`
+	AssetType  string `json:"assettype,omitempty"`
+	Reason     string `json:"reason"`
+}
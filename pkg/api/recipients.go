@@ -5,23 +5,153 @@ Copyright 2021 Adevinta
 package api
 
 import (
+	"errors"
+	"strings"
 	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// Types of the reports a recipient of a team can receive.
+const (
+	// RecipientReportDigest is the periodic digest report of the team.
+	RecipientReportDigest = "digest"
+	// RecipientReportSubscriptions are the reports of the report
+	// subscriptions of the team without recipients of their own.
+	RecipientReportSubscriptions = "subscriptions"
+	// RecipientReportScanNotifications are the notifications sent by email
+	// about the scans of the programs of the team.
+	RecipientReportScanNotifications = "scan-notifications"
+	// RecipientReportSLABreaches are the notifications sent by email about
+	// the findings of the team that breached their SLA.
+	RecipientReportSLABreaches = "sla-breaches"
+)
+
+var recipientReportTypes = map[string]bool{
+	RecipientReportDigest:            true,
+	RecipientReportSubscriptions:     true,
+	RecipientReportScanNotifications: true,
+	RecipientReportSLABreaches:       true,
+}
+
+// Reasons why a recipient stops receiving reports.
+const (
+	RecipientDeactivatedUnsubscribed = "unsubscribed"
+	RecipientDeactivatedBounced      = "bounced"
 )
 
+var (
+	// ErrInvalidRecipientEmail is returned when the email of a recipient is
+	// not valid.
+	ErrInvalidRecipientEmail = errors.New("invalid recipient email")
+	// ErrInvalidRecipientReportType is returned when a recipient is
+	// subscribed to an unknown type of report.
+	ErrInvalidRecipientReportType = errors.New("invalid recipient report type")
+)
+
+// Recipient is an email address the reports of a team are sent to. A
+// recipient only receives reports after verifying its address and while it is
+// active.
 type Recipient struct {
-	TeamID    string    `json:"team_id" gorm:"primary_key"`
-	Email     string    `json:"email" gorm:"primary_key"`
-	CreatedAt time.Time `json:"-"`
-	UpdatedAt time.Time `json:"-"`
+	TeamID string `json:"team_id" gorm:"primary_key"`
+	Email  string `json:"email" gorm:"primary_key"`
+	// ReportTypes is a comma separated list of the types of reports the
+	// recipient receives. An empty list means all the types.
+	ReportTypes string     `json:"report_types"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	// Active is false when the recipient unsubscribed or its address
+	// bounced.
+	Active            *bool   `json:"active" sql:"DEFAULT:true"`
+	DeactivatedReason *string `json:"deactivated_reason"`
+	// TokenVersion is included in the tokens sent to the recipient and
+	// increased every time the recipient is deactivated, which revokes the
+	// tokens sent before.
+	TokenVersion int       `json:"-"`
+	CreatedAt    time.Time `json:"-"`
+	UpdatedAt    time.Time `json:"-"`
+}
+
+// Validate checks the email and the report types of the recipient.
+func (r Recipient) Validate() error {
+	if err := validator.New().Var(r.Email, "required,email"); err != nil {
+		return ErrInvalidRecipientEmail
+	}
+	for _, t := range r.ReportTypesList() {
+		if !recipientReportTypes[t] {
+			return ErrInvalidRecipientReportType
+		}
+	}
+	return nil
+}
+
+// ReportTypesList returns the types of reports the recipient is subscribed
+// to.
+func (r Recipient) ReportTypesList() []string {
+	types := []string{}
+	for _, t := range strings.Split(r.ReportTypes, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// SetReportTypes sets the types of reports the recipient is subscribed to.
+func (r *Recipient) SetReportTypes(types []string) {
+	r.ReportTypes = strings.Join(types, ",")
+}
+
+func (r Recipient) IsActive() bool {
+	return r.Active == nil || *r.Active
+}
+
+func (r Recipient) IsVerified() bool {
+	return r.VerifiedAt != nil
+}
+
+// Receives returns true if the reports of the given type must be sent to the
+// recipient.
+func (r Recipient) Receives(reportType string) bool {
+	if !r.IsActive() || !r.IsVerified() {
+		return false
+	}
+	types := r.ReportTypesList()
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == reportType {
+			return true
+		}
+	}
+	return false
+}
+
+// ReportRecipient is a recipient a report is sent to, together with the link
+// it can use to stop receiving reports.
+type ReportRecipient struct {
+	Email          string
+	UnsubscribeURL string
 }
 
 type RecipientResponse struct {
-	Email string `json:"email"`
+	Email             string   `json:"email"`
+	ReportTypes       []string `json:"report_types"`
+	Verified          bool     `json:"verified"`
+	Active            bool     `json:"active"`
+	DeactivatedReason string   `json:"deactivated_reason,omitempty"`
 }
 
 func (r Recipient) ToResponse() *RecipientResponse {
 	response := RecipientResponse{
-		Email: r.Email,
+		Email:       r.Email,
+		ReportTypes: r.ReportTypesList(),
+		Verified:    r.IsVerified(),
+		Active:      r.IsActive(),
+	}
+	if r.DeactivatedReason != nil {
+		response.DeactivatedReason = *r.DeactivatedReason
 	}
 	return &response
 }
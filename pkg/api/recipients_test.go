@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+func TestRecipientValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient Recipient
+		wantErr   error
+	}{
+		{
+			name:      "Valid",
+			recipient: Recipient{Email: "security@example.com", ReportTypes: "digest,scan-notifications"},
+		},
+		{
+			name:      "InvalidEmail",
+			recipient: Recipient{Email: "security"},
+			wantErr:   ErrInvalidRecipientEmail,
+		},
+		{
+			name:      "InvalidReportType",
+			recipient: Recipient{Email: "security@example.com", ReportTypes: "digest,weekly"},
+			wantErr:   ErrInvalidRecipientReportType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.recipient.Validate(); err != tt.wantErr {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecipientReceives(t *testing.T) {
+	verified := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		recipient  Recipient
+		reportType string
+		want       bool
+	}{
+		{
+			name:       "AllReportTypes",
+			recipient:  Recipient{Email: "security@example.com", VerifiedAt: &verified},
+			reportType: RecipientReportDigest,
+			want:       true,
+		},
+		{
+			name:       "SubscribedReportType",
+			recipient:  Recipient{Email: "security@example.com", VerifiedAt: &verified, ReportTypes: "digest"},
+			reportType: RecipientReportDigest,
+			want:       true,
+		},
+		{
+			name:       "NotSubscribedReportType",
+			recipient:  Recipient{Email: "security@example.com", VerifiedAt: &verified, ReportTypes: "digest"},
+			reportType: RecipientReportScanNotifications,
+			want:       false,
+		},
+		{
+			name:       "NotVerified",
+			recipient:  Recipient{Email: "security@example.com"},
+			reportType: RecipientReportDigest,
+			want:       false,
+		},
+		{
+			name:       "Unsubscribed",
+			recipient:  Recipient{Email: "security@example.com", VerifiedAt: &verified, Active: common.Bool(false)},
+			reportType: RecipientReportDigest,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recipient.Receives(tt.reportType); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	vulcanreport "github.com/adevinta/vulcan-report"
+	"github.com/robfig/cron"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+var (
+	// ErrInvalidSubscriptionSchedule is returned when the cron expression of
+	// a report subscription is not valid.
+	ErrInvalidSubscriptionSchedule = errors.New("invalid report subscription schedule")
+	// ErrInvalidSubscriptionSeverity is returned when the min severity of a
+	// report subscription is not valid.
+	ErrInvalidSubscriptionSeverity = errors.New("invalid report subscription severity")
+	// ErrInvalidSubscriptionRecipient is returned when a recipient of a
+	// report subscription is not a valid email.
+	ErrInvalidSubscriptionRecipient = errors.New("invalid report subscription recipient")
+)
+
+// ReportSubscription defines a report of a team that is periodically sent to
+// a set of recipients.
+type ReportSubscription struct {
+	ID     string `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID string `json:"team_id" validate:"required"`
+	Name   string `json:"name" validate:"required"`
+	// ReportID is the ID of the global report sent by the subscription.
+	ReportID string `json:"report_id" validate:"required"`
+	// Cron is the schedule of the subscription in standard cron format.
+	Cron string `json:"cron" validate:"required"`
+	// Recipients of the report. They must be recipients of the team, and
+	// they only receive the report while they are verified and active. If
+	// there are no recipients, the report is sent to the recipients of the
+	// team.
+	Recipients []*ReportSubscriptionRecipient `json:"recipients" gorm:"foreignkey:SubscriptionID"`
+	// MinSeverity restricts the findings in the report to the ones with a
+	// severity equal or higher. Its possible values are: info, low, medium,
+	// high and critical.
+	MinSeverity string `json:"min_severity"`
+	// GroupID restricts the findings in the report to the ones of the assets
+	// of the group.
+	GroupID   *string    `json:"group_id"`
+	Disabled  *bool      `json:"disabled"`
+	NextRunAt *time.Time `json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	CreatedAt time.Time  `json:"-"`
+	UpdatedAt time.Time  `json:"-"`
+}
+
+// ReportSubscriptionRecipient is a recipient of the team a report
+// subscription is sent to.
+type ReportSubscriptionRecipient struct {
+	SubscriptionID string    `json:"subscription_id" gorm:"primary_key"`
+	Email          string    `json:"email" gorm:"primary_key"`
+	CreatedAt      time.Time `json:"-"`
+	UpdatedAt      time.Time `json:"-"`
+}
+
+// Validate checks the schedule, severity and recipients of the subscription.
+func (r ReportSubscription) Validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+	if _, err := r.Schedule(); err != nil {
+		return err
+	}
+	if r.MinSeverity != "" {
+		if _, ok := severityRanks[strings.ToLower(r.MinSeverity)]; !ok {
+			return ErrInvalidSubscriptionSeverity
+		}
+	}
+	for _, recipient := range r.Recipients {
+		if err := validator.New().Var(recipient.Email, "required,email"); err != nil {
+			return ErrInvalidSubscriptionRecipient
+		}
+	}
+	return nil
+}
+
+// Schedule returns the parsed cron expression of the subscription.
+func (r ReportSubscription) Schedule() (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(r.Cron)
+	if err != nil {
+		return nil, ErrInvalidSubscriptionSchedule
+	}
+	return schedule, nil
+}
+
+// ScheduleNextRun sets the next run time of the subscription to the first
+// activation of its schedule after the given time.
+func (r *ReportSubscription) ScheduleNextRun(now time.Time) error {
+	schedule, err := r.Schedule()
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(now)
+	r.NextRunAt = &next
+	return nil
+}
+
+// Period returns the time range covered by the reports of the subscription
+// sent at the given time, that is, the time between two consecutive runs of
+// its schedule.
+func (r ReportSubscription) Period(at time.Time) (from, to time.Time, err error) {
+	schedule, err := r.Schedule()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	next := schedule.Next(at)
+	return at.Add(-schedule.Next(next).Sub(next)), at, nil
+}
+
+// MinScore returns the minimum score of the findings included in the reports
+// of the subscription, or 0 if all the findings are included.
+func (r ReportSubscription) MinScore() float64 {
+	rank, ok := severityRanks[strings.ToLower(r.MinSeverity)]
+	if !ok || rank == vulcanreport.SeverityNone {
+		return 0
+	}
+	// The scores of a severity start right after the maximum score of the
+	// previous one.
+	return math.Round(float64(vulcanreport.ScoreSeverity(rank-1))*10+1) / 10
+}
+
+// IsDisabled returns true if the reports of the subscription must not be
+// sent.
+func (r ReportSubscription) IsDisabled() bool {
+	return r.Disabled != nil && *r.Disabled
+}
+
+type ReportSubscriptionResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	ReportID    string     `json:"report_id"`
+	Cron        string     `json:"cron"`
+	Recipients  []string   `json:"recipients"`
+	MinSeverity string     `json:"min_severity,omitempty"`
+	GroupID     string     `json:"group_id,omitempty"`
+	Disabled    bool       `json:"disabled"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+}
+
+func (r ReportSubscription) ToResponse() ReportSubscriptionResponse {
+	recipients := []string{}
+	for _, recipient := range r.Recipients {
+		recipients = append(recipients, recipient.Email)
+	}
+	response := ReportSubscriptionResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		ReportID:    r.ReportID,
+		Cron:        r.Cron,
+		Recipients:  recipients,
+		MinSeverity: r.MinSeverity,
+		Disabled:    r.IsDisabled(),
+		NextRunAt:   r.NextRunAt,
+		LastRunAt:   r.LastRunAt,
+	}
+	if r.GroupID != nil {
+		response.GroupID = *r.GroupID
+	}
+	return response
+}
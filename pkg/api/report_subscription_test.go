@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportSubscriptionValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription ReportSubscription
+		wantErr      bool
+	}{
+		{
+			name:         "Valid",
+			subscription: ReportSubscription{TeamID: "t1", Name: "weekly", ReportID: "critical-findings-report", Cron: "0 8 * * 1", MinSeverity: "high"},
+		},
+		{
+			name: "ValidRecipients",
+			subscription: ReportSubscription{TeamID: "t1", Name: "weekly", ReportID: "critical-findings-report", Cron: "0 8 * * 1",
+				Recipients: []*ReportSubscriptionRecipient{{Email: "security@example.com"}}},
+		},
+		{
+			name:         "NoName",
+			subscription: ReportSubscription{TeamID: "t1", ReportID: "critical-findings-report", Cron: "0 8 * * 1"},
+			wantErr:      true,
+		},
+		{
+			name:         "InvalidCron",
+			subscription: ReportSubscription{TeamID: "t1", Name: "weekly", ReportID: "critical-findings-report", Cron: "every monday"},
+			wantErr:      true,
+		},
+		{
+			name:         "InvalidSeverity",
+			subscription: ReportSubscription{TeamID: "t1", Name: "weekly", ReportID: "critical-findings-report", Cron: "0 8 * * 1", MinSeverity: "urgent"},
+			wantErr:      true,
+		},
+		{
+			name: "InvalidRecipient",
+			subscription: ReportSubscription{TeamID: "t1", Name: "weekly", ReportID: "critical-findings-report", Cron: "0 8 * * 1",
+				Recipients: []*ReportSubscriptionRecipient{{Email: "security"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.subscription.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReportSubscriptionPeriod(t *testing.T) {
+	at := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		cron     string
+		wantFrom time.Time
+	}{
+		{
+			name:     "Daily",
+			cron:     "0 8 * * *",
+			wantFrom: at.Add(-24 * time.Hour),
+		},
+		{
+			name:     "Weekly",
+			cron:     "0 8 * * 1",
+			wantFrom: at.Add(-7 * 24 * time.Hour),
+		},
+		{
+			// The length of the period is the time until the following run,
+			// from July 7 to August 7.
+			name:     "Monthly",
+			cron:     "0 8 7 * *",
+			wantFrom: at.Add(-31 * 24 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ReportSubscription{Cron: tt.cron}.Period(at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !from.Equal(tt.wantFrom) {
+				t.Errorf("got from %v, want %v", from, tt.wantFrom)
+			}
+			if !to.Equal(at) {
+				t.Errorf("got to %v, want %v", to, at)
+			}
+		})
+	}
+}
+
+func TestReportSubscriptionMinScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		minSeverity string
+		want        float64
+	}{
+		{name: "NoMinSeverity", want: 0},
+		{name: "Info", minSeverity: "info", want: 0},
+		{name: "Low", minSeverity: "low", want: 0.1},
+		{name: "Medium", minSeverity: "Medium", want: 4},
+		{name: "High", minSeverity: "high", want: 7},
+		{name: "Critical", minSeverity: "CRITICAL", want: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReportSubscription{MinSeverity: tt.minSeverity}.MinScore()
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// FindingStatusAcceptedRisk is the status of the findings whose risk has been
+// accepted by the team until a given date.
+const FindingStatusAcceptedRisk = "ACCEPTED_RISK"
+
+// Statuses of a risk acceptance.
+const (
+	// RiskAcceptancePending is the status of an acceptance waiting for the
+	// review of a team owner or of an admin user.
+	RiskAcceptancePending = "PENDING"
+	// RiskAcceptanceApproved is the status of an acceptance in force.
+	RiskAcceptanceApproved = "APPROVED"
+	// RiskAcceptanceRejected is the status of an acceptance that was not
+	// approved.
+	RiskAcceptanceRejected = "REJECTED"
+	// RiskAcceptanceExpired is the status of an acceptance whose expiry date
+	// passed.
+	RiskAcceptanceExpired = "EXPIRED"
+)
+
+// MaxRiskAcceptanceDuration is the maximum time the risk of a finding can be
+// accepted for.
+const MaxRiskAcceptanceDuration = 365 * 24 * time.Hour
+
+var (
+	// ErrRiskAcceptanceJustificationRequired is returned when a risk
+	// acceptance has no justification.
+	ErrRiskAcceptanceJustificationRequired = errors.New("risk acceptance justification is required")
+	// ErrRiskAcceptanceExpiryRequired is returned when a risk acceptance has
+	// no expiry date.
+	ErrRiskAcceptanceExpiryRequired = errors.New("risk acceptance expiry date is required")
+	// ErrRiskAcceptanceExpiryInvalid is returned when the expiry date of a
+	// risk acceptance is in the past or too far in the future.
+	ErrRiskAcceptanceExpiryInvalid = errors.New("risk acceptance expiry date must be in the future and within one year")
+)
+
+// RiskAcceptance is a request to accept the risk of a finding of a team until
+// a given date. The status of the finding only changes to ACCEPTED_RISK when
+// the acceptance is approved, and it is reopened when the acceptance expires.
+type RiskAcceptance struct {
+	ID        string `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID    string `json:"team_id" validate:"required"`
+	FindingID string `json:"finding_id" validate:"required"`
+	Status    string `json:"status"`
+	// StatusPrevious is the status of the finding when the acceptance was
+	// requested.
+	StatusPrevious string     `json:"status_previous"`
+	Justification  string     `json:"justification"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RequestedBy    string     `json:"requested_by" validate:"required"`
+	Requester      *User      `json:"requester,omitempty" gorm:"foreignkey:RequestedBy"`
+	ReviewedBy     *string    `json:"reviewed_by"`
+	Reviewer       *User      `json:"reviewer,omitempty" gorm:"foreignkey:ReviewedBy"`
+	ReviewNotes    *string    `json:"review_notes"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	CreatedAt      time.Time  `json:"-"`
+	UpdatedAt      time.Time  `json:"-"`
+}
+
+// Validate checks the justification and the expiry date of the acceptance
+// at the given time.
+func (r RiskAcceptance) Validate(now time.Time) error {
+	if strings.TrimSpace(r.Justification) == "" {
+		return ErrRiskAcceptanceJustificationRequired
+	}
+	if r.ExpiresAt == nil {
+		return ErrRiskAcceptanceExpiryRequired
+	}
+	if !r.ExpiresAt.After(now) || r.ExpiresAt.After(now.Add(MaxRiskAcceptanceDuration)) {
+		return ErrRiskAcceptanceExpiryInvalid
+	}
+	return nil
+}
+
+// IsPending returns true if the acceptance is waiting for review.
+func (r RiskAcceptance) IsPending() bool {
+	return r.Status == RiskAcceptancePending
+}
+
+// IsExpired returns true if the expiry date of the acceptance is not after
+// the given time.
+func (r RiskAcceptance) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !r.ExpiresAt.After(now)
+}
+
+type RiskAcceptanceResponse struct {
+	ID             string     `json:"id"`
+	TeamID         string     `json:"team_id"`
+	FindingID      string     `json:"finding_id"`
+	Status         string     `json:"status"`
+	StatusPrevious string     `json:"status_previous"`
+	Justification  string     `json:"justification"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RequestedBy    string     `json:"requested_by"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	ReviewNotes    string     `json:"review_notes,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (r RiskAcceptance) ToResponse() RiskAcceptanceResponse {
+	output := RiskAcceptanceResponse{
+		ID:             r.ID,
+		TeamID:         r.TeamID,
+		FindingID:      r.FindingID,
+		Status:         r.Status,
+		StatusPrevious: r.StatusPrevious,
+		Justification:  r.Justification,
+		ExpiresAt:      r.ExpiresAt,
+		ReviewedAt:     r.ReviewedAt,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.Requester != nil {
+		output.RequestedBy = r.Requester.Email
+	}
+	if r.Reviewer != nil {
+		output.ReviewedBy = r.Reviewer.Email
+	}
+	if r.ReviewNotes != nil {
+		output.ReviewNotes = *r.ReviewNotes
+	}
+	return output
+}
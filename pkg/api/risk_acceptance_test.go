@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRiskAcceptanceValidate(t *testing.T) {
+	now := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+	inAMonth := now.AddDate(0, 1, 0)
+	inTwoYears := now.AddDate(2, 0, 0)
+	yesterday := now.AddDate(0, 0, -1)
+	tests := []struct {
+		name       string
+		acceptance RiskAcceptance
+		wantErr    error
+	}{
+		{
+			name:       "Valid",
+			acceptance: RiskAcceptance{Justification: "compensating control in place", ExpiresAt: &inAMonth},
+		},
+		{
+			name:       "NoJustification",
+			acceptance: RiskAcceptance{Justification: "  ", ExpiresAt: &inAMonth},
+			wantErr:    ErrRiskAcceptanceJustificationRequired,
+		},
+		{
+			name:       "NoExpiry",
+			acceptance: RiskAcceptance{Justification: "compensating control in place"},
+			wantErr:    ErrRiskAcceptanceExpiryRequired,
+		},
+		{
+			name:       "ExpiryInThePast",
+			acceptance: RiskAcceptance{Justification: "compensating control in place", ExpiresAt: &yesterday},
+			wantErr:    ErrRiskAcceptanceExpiryInvalid,
+		},
+		{
+			name:       "ExpiryTooFar",
+			acceptance: RiskAcceptance{Justification: "compensating control in place", ExpiresAt: &inTwoYears},
+			wantErr:    ErrRiskAcceptanceExpiryInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.acceptance.Validate(now)
+			if err != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRiskAcceptanceIsExpired(t *testing.T) {
+	now := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{name: "NoExpiry", expiresAt: nil, want: false},
+		{name: "Future", expiresAt: timePtr(now.Add(time.Hour)), want: false},
+		{name: "Now", expiresAt: timePtr(now), want: true},
+		{name: "Past", expiresAt: timePtr(now.Add(-time.Hour)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RiskAcceptance{ExpiresAt: tt.expiresAt}.IsExpired(now)
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// ExposureAnnotation is the key of the asset annotation that defines how
+// exposed an asset is. Its possible values are ExposureInternet and
+// ExposureInternal.
+const ExposureAnnotation = "exposure"
+
+const (
+	// ExposureInternet is the exposure of the assets reachable from the
+	// internet.
+	ExposureInternet = "internet"
+	// ExposureInternal is the exposure of the assets only reachable from
+	// internal networks.
+	ExposureInternal = "internal"
+)
+
+// RiskScoreSortBy is the value of the sortBy parameter of the list findings
+// requests that sorts the findings by their risk score. Prefixed with "-" it
+// sorts them in descending order.
+const RiskScoreSortBy = "risk_score"
+
+var (
+	rolfpLevelRiskFactors = map[int]float64{0: 0.8, 1: 1, 2: 1.2}
+	exposureRiskFactors   = map[string]float64{ExposureInternet: 1.2, ExposureInternal: 0.8}
+)
+
+// AssetRisk contains the context of an asset used to compute the risk score
+// of its findings.
+type AssetRisk struct {
+	ROLFPLevel         int
+	EnvironmentalScore *float64
+	Exposure           string
+}
+
+// DefaultAssetRisk is the context of the findings whose target is not an
+// asset of the team. It uses the ROLFP level of the assets without ROLFP.
+var DefaultAssetRisk = AssetRisk{ROLFPLevel: 2}
+
+// NewAssetRisk returns the risk context of an asset. The environmental CVSS
+// of the asset is only taken into account if it is a score between 0 and 10,
+// and the exposure is read from the ExposureAnnotation of the asset.
+func NewAssetRisk(a Asset) AssetRisk {
+	risk := DefaultAssetRisk
+	if a.ROLFP != nil {
+		risk.ROLFPLevel = int(a.ROLFP.Level())
+	}
+	if a.EnvironmentalCVSS != nil {
+		score, err := strconv.ParseFloat(strings.TrimSpace(*a.EnvironmentalCVSS), 64)
+		if err == nil && score >= 0 && score <= 10 {
+			risk.EnvironmentalScore = &score
+		}
+	}
+	for _, an := range a.AssetAnnotations {
+		if an.Key == ExposureAnnotation {
+			risk.Exposure = strings.ToLower(an.Value)
+		}
+	}
+	return risk
+}
+
+// factor returns the number the base score of a finding is multiplied by to
+// get its risk score. The ROLFP level and the exposure of the asset weight
+// the score up to 20% up or down, and the environmental score of the asset
+// up to 50%, being 5 neutral.
+func (r AssetRisk) factor() float64 {
+	f, ok := rolfpLevelRiskFactors[r.ROLFPLevel]
+	if !ok {
+		f = 1
+	}
+	if r.EnvironmentalScore != nil {
+		f *= 0.5 + *r.EnvironmentalScore/10
+	}
+	if e, ok := exposureRiskFactors[r.Exposure]; ok {
+		f *= e
+	}
+	return f
+}
+
+// Score returns the risk score, between 0 and 10 and rounded to one decimal,
+// of a finding with the given base score in the asset.
+func (r AssetRisk) Score(score float32) float32 {
+	risk := math.Round(float64(score)*r.factor()*10) / 10
+	return float32(math.Max(0, math.Min(10, risk)))
+}
+
+// MaxAssetRisk returns the risk context that results in the highest risk
+// scores. It is used when several assets of a team, for instance of
+// different types, have the same identifier.
+func MaxAssetRisk(a, b AssetRisk) AssetRisk {
+	if b.factor() > a.factor() {
+		return b
+	}
+	return a
+}
+
+// ScoredFinding is a finding together with its risk score.
+type ScoredFinding struct {
+	vulndb.FindingExpanded
+	RiskScore float32 `json:"risk_score"`
+}
+
+// ScoredFindingsList is a list of findings together with their risk score.
+type ScoredFindingsList struct {
+	Findings   []ScoredFinding `json:"findings"`
+	Pagination PaginationInfo  `json:"pagination"`
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+func TestAssetRiskScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset Asset
+		score float32
+		want  float32
+	}{
+		{
+			name:  "DefaultROLFP",
+			asset: Asset{},
+			score: 5,
+			want:  6,
+		},
+		{
+			name:  "ROLFPLevel0",
+			asset: Asset{ROLFP: &ROLFP{}},
+			score: 5,
+			want:  4,
+		},
+		{
+			name:  "EnvironmentalCVSS",
+			asset: Asset{ROLFP: &ROLFP{Reputation: 1}, EnvironmentalCVSS: common.String("9")},
+			score: 5,
+			want:  7,
+		},
+		{
+			name:  "IgnoresInvalidEnvironmentalCVSS",
+			asset: Asset{ROLFP: &ROLFP{Reputation: 1}, EnvironmentalCVSS: common.String("a.b.c.d")},
+			score: 5,
+			want:  5,
+		},
+		{
+			name: "InternalExposure",
+			asset: Asset{ROLFP: &ROLFP{Reputation: 1}, AssetAnnotations: []*AssetAnnotation{
+				{Key: ExposureAnnotation, Value: "Internal"},
+			}},
+			score: 5,
+			want:  4,
+		},
+		{
+			name: "CappedAt10",
+			asset: Asset{EnvironmentalCVSS: common.String("10"), AssetAnnotations: []*AssetAnnotation{
+				{Key: ExposureAnnotation, Value: ExposureInternet},
+			}},
+			score: 9,
+			want:  10,
+		},
+		{
+			name:  "ZeroScore",
+			asset: Asset{EnvironmentalCVSS: common.String("10")},
+			score: 0,
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAssetRisk(tt.asset).Score(tt.score)
+			if got != tt.want {
+				t.Errorf("got risk score %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxAssetRisk(t *testing.T) {
+	low := AssetRisk{ROLFPLevel: 0}
+	high := AssetRisk{ROLFPLevel: 2, Exposure: ExposureInternet}
+	if got := MaxAssetRisk(low, high); got != high {
+		t.Errorf("got %+v, want %+v", got, high)
+	}
+	if got := MaxAssetRisk(high, low); got != high {
+		t.Errorf("got %+v, want %+v", got, high)
+	}
+}
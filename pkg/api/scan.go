@@ -4,7 +4,11 @@ Copyright 2021 Adevinta
 
 package api
 
-import "time"
+import (
+	"time"
+
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
 
 type Scan struct {
 	ID            string     `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
@@ -50,3 +54,50 @@ func (s Scan) ToResponse() *ScanResponse {
 	}
 	return &response
 }
+
+// ScanDiff contains the differences between two scans of the same program.
+type ScanDiff struct {
+	From *Scan
+	To   *Scan
+	// NewFindings are the findings open at the end of the To scan that were
+	// not open at the end of the From scan.
+	NewFindings []vulndb.FindingExpanded
+	// FixedFindings are the findings open at the end of the From scan that
+	// were not open at the end of the To scan.
+	FixedFindings []vulndb.FindingExpanded
+	// OpenFindings are the findings open at the end of both scans.
+	OpenFindings []vulndb.FindingExpanded
+	// AssetsEntered are the targets scanned by the To scan that were not
+	// scanned by the From scan.
+	AssetsEntered []string
+	// AssetsLeft are the targets scanned by the From scan that were not
+	// scanned by the To scan.
+	AssetsLeft []string
+}
+
+type ScanDiffResponse struct {
+	From          *ScanResponse            `json:"from"`
+	To            *ScanResponse            `json:"to"`
+	NewFindings   []vulndb.FindingExpanded `json:"new_findings"`
+	FixedFindings []vulndb.FindingExpanded `json:"fixed_findings"`
+	OpenFindings  []vulndb.FindingExpanded `json:"open_findings"`
+	AssetsEntered []string                 `json:"assets_entered"`
+	AssetsLeft    []string                 `json:"assets_left"`
+}
+
+func (d ScanDiff) ToResponse() *ScanDiffResponse {
+	response := ScanDiffResponse{
+		NewFindings:   d.NewFindings,
+		FixedFindings: d.FixedFindings,
+		OpenFindings:  d.OpenFindings,
+		AssetsEntered: d.AssetsEntered,
+		AssetsLeft:    d.AssetsLeft,
+	}
+	if d.From != nil {
+		response.From = d.From.ToResponse()
+	}
+	if d.To != nil {
+		response.To = d.To.ToResponse()
+	}
+	return &response
+}
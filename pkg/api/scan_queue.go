@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import "time"
+
+const (
+	// ScanQueueStatusQueued defines the status of a scan request waiting
+	// for the scan limits to allow it to be sent to the scan engine.
+	ScanQueueStatusQueued ScanQueueStatus = "QUEUED"
+	// ScanQueueStatusRunning defines the status of a scan request already
+	// sent to the scan engine whose scan has not finished yet.
+	ScanQueueStatusRunning ScanQueueStatus = "RUNNING"
+	// ScanQueueStatusDone defines the status of a scan request whose scan
+	// has finished or could not be created.
+	ScanQueueStatusDone ScanQueueStatus = "DONE"
+	// ScanQueueStatusCancelled defines the status of a scan request that was
+	// cancelled while it was waiting in the queue.
+	ScanQueueStatusCancelled ScanQueueStatus = "CANCELLED"
+
+	// ScanStatusQueued is the status returned for a scan that has been
+	// queued by the API and not yet sent to the scan engine.
+	ScanStatusQueued = "QUEUED"
+)
+
+type ScanQueueStatus string
+
+// ScanQueueEntry tracks a scan request from the moment it is received by the
+// API until the corresponding scan finishes in the scan engine.
+type ScanQueueEntry struct {
+	ID            string     `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID        string     `json:"team_id" validate:"required"`
+	ProgramID     string     `json:"program_id" validate:"required"`
+	ScheduledTime *time.Time `json:"scheduled_time"`
+	RequestedBy   string     `json:"requested_by"`
+	// Status possible values are:
+	// - QUEUED
+	// - RUNNING
+	// - DONE
+	// - CANCELLED
+	Status         ScanQueueStatus `json:"status" validate:"required"`
+	ScanID         *string         `json:"scan_id"`
+	CheckCount     int             `json:"check_count"`
+	ChecksInFlight int             `json:"checks_in_flight"`
+	Error          string          `json:"error"`
+	CreatedAt      time.Time       `json:"-"`
+	UpdatedAt      time.Time       `json:"-"`
+	StartedAt      *time.Time      `json:"-"`
+	FinishedAt     *time.Time      `json:"-"`
+}
+
+type ScanQueueEntryResponse struct {
+	ID             string          `json:"id"`
+	TeamID         string          `json:"team_id"`
+	ProgramID      string          `json:"program_id"`
+	ScheduledTime  *time.Time      `json:"scheduled_time"`
+	RequestedBy    string          `json:"requested_by"`
+	Status         ScanQueueStatus `json:"status"`
+	ScanID         string          `json:"scan_id,omitempty"`
+	CheckCount     int             `json:"check_count"`
+	ChecksInFlight int             `json:"checks_in_flight"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+}
+
+func (e ScanQueueEntry) ToResponse() ScanQueueEntryResponse {
+	output := ScanQueueEntryResponse{
+		ID:             e.ID,
+		TeamID:         e.TeamID,
+		ProgramID:      e.ProgramID,
+		ScheduledTime:  e.ScheduledTime,
+		RequestedBy:    e.RequestedBy,
+		Status:         e.Status,
+		CheckCount:     e.CheckCount,
+		ChecksInFlight: e.ChecksInFlight,
+		Error:          e.Error,
+		CreatedAt:      e.CreatedAt,
+		StartedAt:      e.StartedAt,
+	}
+	if e.ScanID != nil {
+		output.ScanID = *e.ScanID
+	}
+	return output
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidAssetSelector is returned when an asset selector expression can
+// not be parsed.
+var ErrInvalidAssetSelector = errors.New("invalid asset selector")
+
+// AssetSelector selects assets using an expression over their attributes.
+// The expressions are formed by comparisons joined with the "and", "or" and
+// "not" operators and parentheses, for instance:
+//
+//	type in (Hostname, WebAddress) and annotations["env"] == "prod" and rolfp.level >= 2
+//
+// The supported fields are:
+//   - type: the name of the asset type.
+//   - identifier: the identifier of the asset.
+//   - annotations["key"]: the value of the annotation, empty if not present.
+//   - group: the names of the team groups the asset belongs to. A comparison
+//     is true if it is true for any of the groups, except for != and "not in",
+//     that are true if they are true for all the groups.
+//   - scannable: true or false.
+//   - rolfp.level, rolfp.reputation, rolfp.operation, rolfp.legal,
+//     rolfp.financial, rolfp.personal and rolfp.scope: numeric values of the
+//     ROLFP of the asset.
+//
+// The operators ==, !=, in and "not in" can be used with all the fields, and
+// the operators <, <=, > and >= only with the numeric ones. The values can be
+// quoted strings, numbers or bare words.
+type AssetSelector struct {
+	expr   string
+	root   selectorNode
+	fields map[string]bool
+}
+
+// ParseAssetSelector parses an asset selector expression.
+func ParseAssetSelector(expr string) (*AssetSelector, error) {
+	tokens, err := tokenizeSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &selectorParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidAssetSelector, t.text, t.pos)
+	}
+	return &AssetSelector{expr: expr, root: root, fields: p.fields}, nil
+}
+
+// Matches returns true if the asset is selected by the expression.
+func (s *AssetSelector) Matches(a *Asset) bool {
+	return s.root.matches(a)
+}
+
+// UsesField returns true if the expression compares the given field. The
+// annotations of any key are reported as the field "annotations".
+func (s *AssetSelector) UsesField(name string) bool {
+	return s.fields[name]
+}
+
+// String returns the expression of the selector.
+func (s *AssetSelector) String() string {
+	return s.expr
+}
+
+type selectorNode interface {
+	matches(a *Asset) bool
+}
+
+type andNode struct{ left, right selectorNode }
+
+func (n andNode) matches(a *Asset) bool { return n.left.matches(a) && n.right.matches(a) }
+
+type orNode struct{ left, right selectorNode }
+
+func (n orNode) matches(a *Asset) bool { return n.left.matches(a) || n.right.matches(a) }
+
+type notNode struct{ node selectorNode }
+
+func (n notNode) matches(a *Asset) bool { return !n.node.matches(a) }
+
+type comparisonNode struct {
+	field  selectorField
+	op     string
+	values []string
+}
+
+func (n comparisonNode) matches(a *Asset) bool {
+	if n.field.numeric() {
+		v := float64(n.field.number(a))
+		want, _ := strconv.ParseFloat(n.values[0], 64)
+		switch n.op {
+		case "==":
+			return v == want
+		case "!=":
+			return v != want
+		case "<":
+			return v < want
+		case "<=":
+			return v <= want
+		case ">":
+			return v > want
+		case ">=":
+			return v >= want
+		case "in", "not in":
+			in := false
+			for _, s := range n.values {
+				want, _ := strconv.ParseFloat(s, 64)
+				if v == want {
+					in = true
+					break
+				}
+			}
+			return in == (n.op == "in")
+		}
+		return false
+	}
+	values := n.field.strings(a)
+	switch n.op {
+	case "==", "in":
+		for _, v := range values {
+			if inSelectorValues(v, n.values) {
+				return true
+			}
+		}
+		return false
+	case "!=", "not in":
+		for _, v := range values {
+			if inSelectorValues(v, n.values) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func inSelectorValues(v string, values []string) bool {
+	for _, s := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+type selectorField struct {
+	name string
+	key  string
+}
+
+var rolfpFields = map[string]func(r ROLFP) byte{
+	"rolfp.level":      func(r ROLFP) byte { return r.Level() },
+	"rolfp.reputation": func(r ROLFP) byte { return r.Reputation },
+	"rolfp.operation":  func(r ROLFP) byte { return r.Operation },
+	"rolfp.legal":      func(r ROLFP) byte { return r.Legal },
+	"rolfp.financial":  func(r ROLFP) byte { return r.Financial },
+	"rolfp.personal":   func(r ROLFP) byte { return r.Personal },
+	"rolfp.scope":      func(r ROLFP) byte { return r.Scope },
+}
+
+func (f selectorField) valid() bool {
+	switch f.name {
+	case "type", "identifier", "group", "scannable", "annotations":
+		return true
+	}
+	return f.numeric()
+}
+
+func (f selectorField) numeric() bool {
+	_, ok := rolfpFields[f.name]
+	return ok
+}
+
+func (f selectorField) number(a *Asset) byte {
+	rolfp := ROLFP{IsEmpty: true}
+	if a.ROLFP != nil {
+		rolfp = *a.ROLFP
+	}
+	return rolfpFields[f.name](rolfp)
+}
+
+func (f selectorField) strings(a *Asset) []string {
+	switch f.name {
+	case "type":
+		if a.AssetType == nil {
+			return []string{""}
+		}
+		return []string{a.AssetType.Name}
+	case "identifier":
+		return []string{a.Identifier}
+	case "scannable":
+		return []string{strconv.FormatBool(a.Scannable == nil || *a.Scannable)}
+	case "annotations":
+		for _, an := range a.AssetAnnotations {
+			if an.Key == f.key {
+				return []string{an.Value}
+			}
+		}
+		return []string{""}
+	case "group":
+		groups := []string{}
+		for _, ag := range a.AssetGroups {
+			if ag.Group != nil {
+				groups = append(groups, ag.Group.Name)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenPunct
+)
+
+type selectorToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeSelector(expr string) ([]selectorToken, error) {
+	tokens := []selectorToken{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == ',':
+			tokens = append(tokens, selectorToken{kind: tokenPunct, text: string(r), pos: i})
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			start := i
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("%w: unknown operator %q at position %d", ErrInvalidAssetSelector, op, start)
+			}
+			tokens = append(tokens, selectorToken{kind: tokenOperator, text: op, pos: start})
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidAssetSelector, start)
+			}
+			i++
+			s, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid string at position %d", ErrInvalidAssetSelector, start)
+			}
+			tokens = append(tokens, selectorToken{kind: tokenString, text: s, pos: start})
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, selectorToken{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidAssetSelector, r, i)
+		}
+	}
+	tokens = append(tokens, selectorToken{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == ':' || r == '/' || r == '*'
+}
+
+type selectorParser struct {
+	tokens []selectorToken
+	pos    int
+	fields map[string]bool
+}
+
+func (p *selectorParser) peek() selectorToken {
+	return p.tokens[p.pos]
+}
+
+func (p *selectorParser) next() selectorToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *selectorParser) keyword(t selectorToken, kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *selectorParser) expect(kind tokenKind, text string) error {
+	t := p.next()
+	if t.kind != kind || t.text != text {
+		return p.unexpected(t, text)
+	}
+	return nil
+}
+
+func (p *selectorParser) unexpected(t selectorToken, want string) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%w: expected %s at the end of the expression", ErrInvalidAssetSelector, want)
+	}
+	return fmt.Errorf("%w: expected %s at position %d, found %q", ErrInvalidAssetSelector, want, t.pos, t.text)
+}
+
+func (p *selectorParser) parseOr() (selectorNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseAnd() (selectorNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword(p.peek(), "and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseNot() (selectorNode, error) {
+	if p.keyword(p.peek(), "not") {
+		p.next()
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+	if t := p.peek(); t.kind == tokenPunct && t.text == "(" {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenPunct, ")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *selectorParser) parseComparison() (selectorNode, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		return nil, p.unexpected(t, "a field")
+	}
+	field := selectorField{name: strings.ToLower(t.text)}
+	if field.name == "groups" {
+		field.name = "group"
+	}
+	if !field.valid() {
+		return nil, fmt.Errorf("%w: unknown field %q at position %d", ErrInvalidAssetSelector, t.text, t.pos)
+	}
+	if p.fields == nil {
+		p.fields = map[string]bool{}
+	}
+	p.fields[field.name] = true
+	if field.name == "annotations" {
+		if err := p.expect(tokenPunct, "["); err != nil {
+			return nil, err
+		}
+		key := p.next()
+		if key.kind != tokenString && key.kind != tokenWord {
+			return nil, p.unexpected(key, "an annotation key")
+		}
+		field.key = key.text
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return nil, err
+		}
+	}
+
+	node := comparisonNode{field: field}
+	op := p.next()
+	switch {
+	case op.kind == tokenOperator:
+		node.op = op.text
+		if !field.numeric() && node.op != "==" && node.op != "!=" {
+			return nil, fmt.Errorf("%w: operator %q at position %d can only be used with numeric fields", ErrInvalidAssetSelector, op.text, op.pos)
+		}
+		v, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		node.values = []string{v}
+	case p.keyword(op, "in"):
+		node.op = "in"
+	case p.keyword(op, "not") && p.keyword(p.peek(), "in"):
+		p.next()
+		node.op = "not in"
+	default:
+		return nil, p.unexpected(op, "an operator")
+	}
+	if node.op == "in" || node.op == "not in" {
+		values, err := p.parseValueList(field)
+		if err != nil {
+			return nil, err
+		}
+		node.values = values
+	}
+	return node, nil
+}
+
+func (p *selectorParser) parseValueList(field selectorField) ([]string, error) {
+	if err := p.expect(tokenPunct, "("); err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for {
+		v, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		t := p.next()
+		if t.kind == tokenPunct && t.text == ")" {
+			return values, nil
+		}
+		if t.kind != tokenPunct || t.text != "," {
+			return nil, p.unexpected(t, `"," or ")"`)
+		}
+	}
+}
+
+func (p *selectorParser) parseValue(field selectorField) (string, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenString {
+		return "", p.unexpected(t, "a value")
+	}
+	if field.numeric() {
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			return "", fmt.Errorf("%w: expected a number at position %d, found %q", ErrInvalidAssetSelector, t.pos, t.text)
+		}
+	}
+	return t.text, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAssetSelector_Matches(t *testing.T) {
+	no := false
+	asset := &Asset{
+		Identifier: "www.example.com",
+		AssetType:  &AssetType{Name: "Hostname"},
+		ROLFP:      &ROLFP{Reputation: 1, Operation: 1, Legal: 1, Scope: 1},
+		AssetAnnotations: []*AssetAnnotation{
+			{Key: "env", Value: "prod"},
+			{Key: "autodiscovery/source", Value: "redcon"},
+		},
+		AssetGroups: []*AssetGroup{
+			{Group: &Group{Name: "Default"}},
+			{Group: &Group{Name: "web"}},
+		},
+	}
+	tests := []struct {
+		name  string
+		expr  string
+		asset *Asset
+		want  bool
+	}{
+		{name: "TypeIn", expr: "type in (Hostname, WebAddress)", asset: asset, want: true},
+		{name: "TypeNotIn", expr: "type not in (Hostname, WebAddress)", asset: asset, want: false},
+		{name: "Annotation", expr: `annotations["env"] == "prod"`, asset: asset, want: true},
+		{name: "AnnotationWithSlash", expr: `annotations["autodiscovery/source"] != redcon`, asset: asset, want: false},
+		{name: "MissingAnnotation", expr: `annotations["owner"] == ""`, asset: asset, want: true},
+		{name: "ROLFPLevel", expr: "rolfp.level >= 2", asset: asset, want: true},
+		{name: "ROLFPComponent", expr: "rolfp.financial > 0", asset: asset, want: false},
+		{name: "EmptyROLFPIsLevel2", expr: "rolfp.level == 2", asset: &Asset{}, want: true},
+		{name: "Group", expr: "group == web", asset: asset, want: true},
+		{name: "NotInGroup", expr: "group != Sensitive", asset: asset, want: true},
+		{name: "GroupNotIn", expr: "group not in (Sensitive, web)", asset: asset, want: false},
+		{name: "Scannable", expr: "scannable == false", asset: &Asset{Scannable: &no}, want: true},
+		{
+			name:  "Combined",
+			expr:  `type in (Hostname, WebAddress) and annotations["env"] == "prod" and rolfp.level >= 2`,
+			asset: asset,
+			want:  true,
+		},
+		{name: "Precedence", expr: "type == IP or type == Hostname and group == Sensitive", asset: asset, want: false},
+		{name: "Parentheses", expr: "(type == IP or type == Hostname) and not group == Sensitive", asset: asset, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseAssetSelector(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseAssetSelector() error = %v", err)
+			}
+			if got := s.Matches(tt.asset); got != tt.want {
+				t.Errorf("AssetSelector.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAssetSelector_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "Empty", expr: ""},
+		{name: "UnknownField", expr: "owner == team1"},
+		{name: "OrderingOnStrings", expr: "type >= Hostname"},
+		{name: "NotANumber", expr: "rolfp.level == high"},
+		{name: "MissingValue", expr: "type =="},
+		{name: "UnclosedList", expr: "type in (Hostname"},
+		{name: "UnclosedParentheses", expr: "(type == IP"},
+		{name: "UnterminatedString", expr: `annotations["env] == prod`},
+		{name: "SingleEquals", expr: "type = IP"},
+		{name: "TrailingTokens", expr: "type == IP IP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAssetSelector(tt.expr)
+			if !errors.Is(err, ErrInvalidAssetSelector) {
+				t.Errorf("ParseAssetSelector() error = %v, want %v", err, ErrInvalidAssetSelector)
+			}
+		})
+	}
+}
+
+func TestAssetSelector_UsesField(t *testing.T) {
+	s, err := ParseAssetSelector(`type == Hostname and (annotations["env"] == prod or not groups in (Default))`)
+	if err != nil {
+		t.Fatalf("ParseAssetSelector() error = %v", err)
+	}
+	for field, want := range map[string]bool{
+		"type":        true,
+		"annotations": true,
+		"group":       true,
+		"identifier":  false,
+		"rolfp.level": false,
+	} {
+		if got := s.UsesField(field); got != want {
+			t.Errorf("UsesField(%q) = %v, want %v", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// ListAssetChecktypeSettings returns the checktype settings of an asset. If
+// assetID is empty the settings of all the assets of the team are returned.
+func (s vulcanitoService) ListAssetChecktypeSettings(ctx context.Context, teamID, assetID string) ([]*api.AssetChecktypeSetting, error) {
+	if teamID == "" {
+		return nil, errors.NotFound(`Team ID is empty`)
+	}
+	return s.db.ListAssetChecktypeSettings(teamID, assetID)
+}
+
+func (s vulcanitoService) CreateAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	if setting.TeamID == "" {
+		return nil, errors.NotFound(`Team ID is empty`)
+	}
+	if setting.AssetID == "" {
+		return nil, errors.NotFound(`Asset ID is empty`)
+	}
+	if setting.Excluded == nil {
+		excluded := false
+		setting.Excluded = &excluded
+	}
+	if err := s.validateAssetChecktypeSetting(ctx, setting); err != nil {
+		return nil, err
+	}
+	return s.db.CreateAssetChecktypeSetting(setting)
+}
+
+// UpdateAssetChecktypeSetting modifies the fields of a setting that are set
+// in the given one. The checktype of a setting can not be changed.
+func (s vulcanitoService) UpdateAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	current, err := s.db.FindAssetChecktypeSetting(setting.TeamID, setting.AssetID, setting.ID)
+	if err != nil {
+		return nil, err
+	}
+	if setting.ChecktypeName != "" && setting.ChecktypeName != current.ChecktypeName {
+		return nil, errors.Validation("the checktype of a setting can not be modified")
+	}
+	if setting.Excluded != nil {
+		current.Excluded = setting.Excluded
+		if current.IsExcluded() {
+			current.Options = nil
+		}
+	}
+	if setting.Options != nil {
+		current.Options = setting.Options
+	}
+	if setting.Justification != "" {
+		current.Justification = setting.Justification
+	}
+	if setting.ExpiresAt != nil {
+		current.ExpiresAt = setting.ExpiresAt
+	}
+	if err := s.validateAssetChecktypeSetting(ctx, *current); err != nil {
+		return nil, err
+	}
+	return s.db.UpdateAssetChecktypeSetting(*current)
+}
+
+func (s vulcanitoService) DeleteAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) error {
+	return s.db.DeleteAssetChecktypeSetting(setting)
+}
+
+func (s vulcanitoService) validateAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) error {
+	if err := setting.Validate(time.Now()); err != nil {
+		return err
+	}
+	if setting.IsExcluded() {
+		return nil
+	}
+	return s.validateChecktypeOptions(ctx, setting.ChecktypeName, setting.Options)
+}
+
+// setAssetChecktypeSettings loads the checktype settings of the assets of the
+// team into the given program, so they are honoured when building the scans
+// of the program.
+func (s vulcanitoService) setAssetChecktypeSettings(ctx context.Context, program *api.Program, teamID string) error {
+	settings, err := s.db.ListAssetChecktypeSettings(teamID, "")
+	if err != nil {
+		return err
+	}
+	program.SetAssetChecktypeSettings(settings)
+	return nil
+}
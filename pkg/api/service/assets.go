@@ -625,6 +625,11 @@ func (s vulcanitoService) UpdateGroup(ctx context.Context, group api.Group) (*ap
 	if !common.IsStringEmpty(&group.Options) && !common.IsValidJSON(&group.Options) {
 		return nil, errors.Validation("group.options needs to be valid json")
 	}
+	if group.IsDynamic() {
+		if _, err := api.ParseAssetSelector(*group.Selector); err != nil {
+			return nil, errors.Validation(err)
+		}
+	}
 	foundGroup, err := s.db.FindGroup(api.Group{ID: group.ID})
 	if err != nil {
 		return nil, err
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"net/http"
 	"reflect"
+	"regexp"
 
 	"os"
 
@@ -24,6 +25,18 @@ var (
 	errMethodNotFoundInCtx  = errors.New("http method not found in context")
 )
 
+// memberRoutes are the routes of a team that, besides the get requests, can
+// also be used by the members of the team and not only by its owners.
+var memberRoutes = []struct {
+	method string
+	path   *regexp.Regexp
+}{
+	{http.MethodPost, regexp.MustCompile(`^/api/v1/teams/[^/]+/findings/[^/]+/comments$`)},
+	{http.MethodPatch, regexp.MustCompile(`^/api/v1/teams/[^/]+/findings/[^/]+/comments/[^/]+$`)},
+	{http.MethodDelete, regexp.MustCompile(`^/api/v1/teams/[^/]+/findings/[^/]+/comments/[^/]+$`)},
+	{http.MethodPost, regexp.MustCompile(`^/api/v1/teams/[^/]+/findings/[^/]+/risk-acceptances$`)},
+}
+
 type authorization struct {
 	db api.VulcanitoStore
 }
@@ -137,18 +150,38 @@ func (a *authorization) AuthRol(ctx context.Context, tenant interface{}) (bool,
 		return false, nil
 	}
 
-	// For non owner roles profiles we only allow to perform get methods.
+	// For non owner roles profiles we only allow to perform get methods,
+	// except for the routes the members of the team can also use.
 	if t.Role != api.Owner {
 		_ = logger.Log("authorization", "not owner")
-		if m != http.MethodGet {
+		if m != http.MethodGet && !isMemberRoute(ctx, t, m) {
 			return false, nil
 		}
 	}
 	// If we are here the user is authorized in the tenant (team) and:
 	// The user is owner and is authorized to do whatever he wants on the tenant
 	// or
-	// The user is a member and is performing a get
+	// The user is a member and is performing a get or using a member route
 	// in either case we must grant access.
 
 	return true, nil
 }
+
+// isMemberRoute returns true if the request is for one of the memberRoutes
+// and the user is a member of the team. The observers that are not members
+// of the team are only authorized with the member role to read.
+func isMemberRoute(ctx context.Context, t *api.UserTeam, method string) bool {
+	if t.Role != api.Member || t.UserID == "" {
+		return false
+	}
+	path, ok := ctx.Value(kithttp.ContextKeyRequestPath).(string)
+	if !ok {
+		return false
+	}
+	for _, r := range memberRoutes {
+		if r.method == method && r.path.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func TestAuthorization_AuthRol(t *testing.T) {
+	commentsPath := "/api/v1/teams/t1/findings/f1/comments"
+	tests := []struct {
+		name   string
+		tenant *api.UserTeam
+		method string
+		path   string
+		want   bool
+	}{
+		{
+			name:   "MemberGet",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodGet,
+			path:   "/api/v1/teams/t1/assets",
+			want:   true,
+		},
+		{
+			name:   "MemberPostAsset",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodPost,
+			path:   "/api/v1/teams/t1/assets",
+			want:   false,
+		},
+		{
+			name:   "MemberCreateComment",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodPost,
+			path:   commentsPath,
+			want:   true,
+		},
+		{
+			name:   "MemberUpdateComment",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodPatch,
+			path:   commentsPath + "/c1",
+			want:   true,
+		},
+		{
+			name:   "MemberDeleteComment",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodDelete,
+			path:   commentsPath + "/c1",
+			want:   true,
+		},
+		{
+			name:   "MemberDeleteComments",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodDelete,
+			path:   commentsPath,
+			want:   false,
+		},
+		{
+			name:   "ObserverNotInTeamCreateComment",
+			tenant: &api.UserTeam{TeamID: "t1", Role: api.Member},
+			method: http.MethodPost,
+			path:   commentsPath,
+			want:   false,
+		},
+		{
+			name:   "MemberRequestRiskAcceptance",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodPost,
+			path:   "/api/v1/teams/t1/findings/f1/risk-acceptances",
+			want:   true,
+		},
+		{
+			name:   "MemberApproveRiskAcceptance",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Member},
+			method: http.MethodPost,
+			path:   "/api/v1/teams/t1/risk-acceptances/r1/approve",
+			want:   false,
+		},
+		{
+			name:   "OwnerPostAsset",
+			tenant: &api.UserTeam{UserID: "u1", TeamID: "t1", Role: api.Owner},
+			method: http.MethodPost,
+			path:   "/api/v1/teams/t1/assets",
+			want:   true,
+		},
+	}
+	a := NewAuthorizationService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), kithttp.ContextKeyRequestMethod, tt.method)
+			ctx = context.WithValue(ctx, kithttp.ContextKeyRequestPath, tt.path)
+			got, err := a.AuthRol(ctx, tt.tenant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
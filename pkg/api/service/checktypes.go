@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	goerrors "errors"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/checktypes"
+)
+
+func (s vulcanitoService) ListChecktypes(ctx context.Context) ([]*api.Checktype, error) {
+	if s.checktypesCatalogue == nil {
+		return nil, errors.Default("checktypes catalogue not configured")
+	}
+	list, err := s.checktypesCatalogue.Checktypes(ctx)
+	if err != nil {
+		return nil, errors.Default(err)
+	}
+	return list, nil
+}
+
+// validateChecktypeOptions returns a validation error if the checktype is not
+// in the catalogue or the options do not conform to its schema. If the
+// catalogue is not available the options are accepted, so vulcan-core being
+// down does not prevent editing the policies.
+func (s vulcanitoService) validateChecktypeOptions(ctx context.Context, checktype string, options *string) error {
+	if s.checktypesCatalogue == nil {
+		return nil
+	}
+	opts := ""
+	if options != nil {
+		opts = *options
+	}
+	err := s.checktypesCatalogue.ValidateOptions(ctx, checktype, opts)
+	if err == nil {
+		return nil
+	}
+	if goerrors.Is(err, checktypes.ErrUnknownChecktype) || goerrors.Is(err, checktypes.ErrInvalidOptions) {
+		return errors.Validation(err)
+	}
+	_ = level.Warn(s.logger).Log("msg", "unable to validate checktype options", "checktype", checktype, "err", err)
+	return nil
+}
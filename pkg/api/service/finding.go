@@ -6,43 +6,104 @@ package service
 
 import (
 	"context"
+	errs "errors"
 	"fmt"
 	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
 
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
 	"gopkg.in/go-playground/validator.v9"
 )
 
-func (s vulcanitoService) ListFindings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
-	return s.vulndbClient.Findings(ctx, params, pagination)
+// ListFindings returns the findings matching the params together with their
+// risk score. The findings can be sorted by risk score using the
+// RiskScoreSortBy sort criteria.
+func (s vulcanitoService) ListFindings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+	if strings.TrimPrefix(params.SortBy, "-") == api.RiskScoreSortBy {
+		return s.listFindingsByRiskScore(ctx, params, pagination)
+	}
+	list, err := s.vulndbClient.Findings(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreFindingsList(params.Team, list)
 }
 
 func (s vulcanitoService) ListFindingsIssues(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsIssuesList, error) {
 	return s.vulndbClient.FindingsIssues(ctx, params, pagination)
 }
 
-func (s vulcanitoService) ListFindingsByIssue(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
-	return s.vulndbClient.FindingsByIssue(ctx, params, pagination)
+func (s vulcanitoService) ListFindingsByIssue(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+	list, err := s.vulndbClient.FindingsByIssue(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreFindingsList(params.Team, list)
 }
 
 func (s vulcanitoService) ListFindingsTargets(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsTargetsList, error) {
 	return s.vulndbClient.FindingsTargets(ctx, params, pagination)
 }
 
-func (s vulcanitoService) ListFindingsByTarget(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
-	return s.vulndbClient.FindingsByTarget(ctx, params, pagination)
+func (s vulcanitoService) ListFindingsByTarget(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+	list, err := s.vulndbClient.FindingsByTarget(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreFindingsList(params.Team, list)
 }
 
 func (s vulcanitoService) ListFindingsLabels(ctx context.Context, params api.FindingsParams) (*api.FindingsLabels, error) {
 	return s.vulndbClient.Labels(ctx, params)
 }
 
-func (s vulcanitoService) FindFinding(ctx context.Context, findingID string) (*api.Finding, error) {
-	return s.vulndbClient.Finding(ctx, findingID)
+// allFindingsPageSize is the page size used to retrieve all the findings
+// matching a filter from the vulnerability db.
+const allFindingsPageSize = 100
+
+// allFindings walks all the pages of findings returned by the vulnerability
+// db for the given params.
+func (s vulcanitoService) allFindings(ctx context.Context, params api.FindingsParams) ([]vulndb.FindingExpanded, error) {
+	findings := []vulndb.FindingExpanded{}
+	for page := 1; ; page++ {
+		list, err := s.vulndbClient.Findings(ctx, params, api.Pagination{Page: page, Size: allFindingsPageSize})
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, list.Findings...)
+		if !list.Pagination.More || len(list.Findings) == 0 {
+			return findings, nil
+		}
+	}
 }
 
+// CreateFindingOverwrite changes the status of a finding. The risk of a
+// finding can not be accepted directly, a risk acceptance must be requested
+// and approved instead. When a finding leaves the ACCEPTED_RISK status, its
+// approved risk acceptance is revoked.
 func (s vulcanitoService) CreateFindingOverwrite(ctx context.Context, findingOverwrite api.FindingOverwrite) error {
+	if err := validateFindingOverwrite(findingOverwrite); err != nil {
+		return err
+	}
+
+	if findingOverwrite.Status == api.FindingStatusAcceptedRisk {
+		return errors.Validation("The risk of a finding must be accepted by requesting a risk acceptance")
+	}
+
+	if findingOverwrite.StatusPrevious == api.FindingStatusAcceptedRisk {
+		return s.revokeRiskAcceptance(findingOverwrite)
+	}
+
+	return s.db.CreateFindingOverwrite(findingOverwrite)
+}
+
+func validateFindingOverwrite(findingOverwrite api.FindingOverwrite) error {
 	validationErr := validator.New().Struct(findingOverwrite)
 	if validationErr != nil {
 		return errors.Validation(validationErr)
@@ -56,13 +117,250 @@ func (s vulcanitoService) CreateFindingOverwrite(ctx context.Context, findingOve
 		return errors.Validation(fmt.Sprintf("Status transition not allowed: from '%s' to '%s'", findingOverwrite.StatusPrevious, findingOverwrite.Status))
 	}
 
-	return s.db.CreateFindingOverwrite(findingOverwrite)
+	return nil
 }
 
 func (s vulcanitoService) ListFindingOverwrites(ctx context.Context, findingID string) ([]*api.FindingOverwrite, error) {
 	return s.db.ListFindingOverwrites(findingID)
 }
 
+// CreateFindingOverwritesAsync validates a bulk finding overwrite and stores
+// the information necessary to apply it asynchronously.
+func (s vulcanitoService) CreateFindingOverwritesAsync(ctx context.Context, bulk api.BulkFindingOverwrite) (*api.Job, error) {
+	if (len(bulk.FindingIDs) == 0) == (bulk.Params == nil) {
+		return nil, errors.Validation("Either a list of findings or a filter must be specified")
+	}
+	if bulk.Params != nil && !hasFindingsCriteria(*bulk.Params) {
+		return nil, errors.Validation("The filter must specify at least one criterion")
+	}
+	if bulk.TeamID == "" || bulk.UserID == "" || bulk.Notes == "" {
+		return nil, errors.Validation("Team, user and notes are required")
+	}
+	if !isValidFindingStatus(bulk.Status) {
+		return nil, errors.Validation(fmt.Sprintf("Invalid status: '%s'", bulk.Status))
+	}
+	if bulk.Status == api.FindingStatusAcceptedRisk {
+		return nil, errors.Validation("The risk of a finding must be accepted by requesting a risk acceptance")
+	}
+	if bulk.Params != nil {
+		// The filter can only select findings of the team.
+		bulk.Params.Team = bulk.TeamID
+	}
+	return s.db.CreateFindingOverwritesAsync(bulk)
+}
+
+// hasFindingsCriteria returns true if the params select the findings of a
+// team by any criterion.
+func hasFindingsCriteria(params api.FindingsParams) bool {
+	return params.Status != "" || params.MinScore != 0 || params.MaxScore != 0 ||
+		params.IssueID != "" || params.TargetID != "" || params.Identifiers != "" ||
+		params.Labels != ""
+}
+
+// CreateFindingOverwrites applies a bulk finding overwrite. The overwrites of
+// all the findings that can be changed are created in one transaction, and
+// the result of each finding is returned. If the bulk overwrite is applied
+// by a job, the findings already overwritten by the job, for instance when
+// the job is run again, are returned as applied without overwriting them
+// again.
+func (s vulcanitoService) CreateFindingOverwrites(ctx context.Context, bulk api.BulkFindingOverwrite) ([]api.BulkFindingOverwriteResult, error) {
+	type candidate struct {
+		id     string
+		status string
+		err    error
+	}
+	results := []api.BulkFindingOverwriteResult{}
+	seen := map[string]bool{}
+	if bulk.JobID != "" {
+		done, err := s.db.ListJobFindingOverwrites(bulk.JobID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range done {
+			seen[o.FindingID] = true
+			results = append(results, api.BulkFindingOverwriteResult{FindingID: o.FindingID, StatusPrevious: o.StatusPrevious})
+		}
+	}
+	candidates := []candidate{}
+	if len(bulk.FindingIDs) > 0 {
+		for _, id := range bulk.FindingIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			finding, err := s.findTeamFinding(ctx, bulk.TeamID, id)
+			if err != nil {
+				candidates = append(candidates, candidate{id: id, err: err})
+				continue
+			}
+			candidates = append(candidates, candidate{id: id, status: finding.Finding.Status})
+		}
+	} else if bulk.Params != nil {
+		findings, err := s.allFindings(ctx, *bulk.Params)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range findings {
+			if seen[f.ID] {
+				continue
+			}
+			candidates = append(candidates, candidate{id: f.ID, status: f.Status})
+		}
+	}
+
+	var jobID *string
+	if bulk.JobID != "" {
+		jobID = &bulk.JobID
+	}
+	overwrites := []api.FindingOverwrite{}
+	for _, c := range candidates {
+		result := api.BulkFindingOverwriteResult{FindingID: c.id, StatusPrevious: c.status}
+		err := c.err
+		if err == nil && c.status == api.FindingStatusAcceptedRisk {
+			err = errors.Validation("Findings with an accepted risk must be overwritten individually")
+		}
+		overwrite := api.FindingOverwrite{
+			UserID:         bulk.UserID,
+			FindingID:      c.id,
+			StatusPrevious: c.status,
+			Status:         bulk.Status,
+			Notes:          bulk.Notes,
+			TeamID:         bulk.TeamID,
+			JobID:          jobID,
+		}
+		if err == nil {
+			err = validateFindingOverwrite(overwrite)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			overwrites = append(overwrites, overwrite)
+		}
+		results = append(results, result)
+	}
+
+	if len(overwrites) > 0 {
+		if err := s.db.CreateFindingOverwrites(overwrites); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// VerifyFinding launches a scan that runs only the checktype that reported
+// the finding against the target of the finding, and links the scan to the
+// finding so the status of the verification can be tracked.
+func (s vulcanitoService) VerifyFinding(ctx context.Context, teamID, findingID string, user api.User) (*api.FindingVerification, error) {
+	finding, err := s.vulndbClient.Finding(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(finding.Finding.Target.Teams, teamID) {
+		return nil, errors.Forbidden("Finding does not belong to given team")
+	}
+
+	checktype := finding.Finding.Source.Name
+	identifier := finding.Finding.Target.Identifier
+	if checktype == "" || identifier == "" {
+		return nil, errors.Validation("Finding has no checktype or target to verify")
+	}
+
+	asset, err := s.findScannableAsset(teamID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.db.ListAssetChecktypeSettings(teamID, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+	asset.ChecktypeSettings = settings
+
+	team, err := s.db.FindTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scanRequest, err := scanengine.CreateSingleCheckScanRequest(*asset, checktype,
+		finding.Finding.Source.Options, &now, findingID, user.Email, team.Tag)
+	if err != nil {
+		if errs.Is(err, scanengine.ErrNotFound) {
+			return nil, errors.NotFound(err)
+		}
+		if errs.Is(err, scanengine.ErrChecktypeExcluded) {
+			return nil, errors.Validation(err)
+		}
+		return nil, errors.Default(err)
+	}
+
+	scanResponse, err := s.scanEngineClient.Create(ctx, scanRequest)
+	if err != nil {
+		if errs.Is(err, scanengine.ErrUnprocessableEntity) {
+			return nil, errors.Validation(err)
+		}
+		return nil, errors.Default(err)
+	}
+	if scanResponse.ScanID == "" {
+		return nil, errors.Default("Scan engine did not return scan id")
+	}
+
+	verification, err := s.db.CreateFindingVerification(api.FindingVerification{
+		FindingID: findingID,
+		TeamID:    teamID,
+		ScanID:    scanResponse.ScanID,
+		Checktype: checktype,
+		Target:    identifier,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	verification.User = &user
+	s.setFindingVerificationStatus(ctx, verification)
+	return verification, nil
+}
+
+// ListFindingVerifications returns the verification scans launched for a
+// finding, with their status taken from the scan engine.
+func (s vulcanitoService) ListFindingVerifications(ctx context.Context, teamID, findingID string) ([]*api.FindingVerification, error) {
+	verifications, err := s.db.ListFindingVerifications(teamID, findingID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range verifications {
+		s.setFindingVerificationStatus(ctx, v)
+	}
+	return verifications, nil
+}
+
+// setFindingVerificationStatus sets the status of the scan of a verification.
+// The status is left empty if the scan engine fails to return it, as the
+// verification is already stored.
+func (s vulcanitoService) setFindingVerificationStatus(ctx context.Context, verification *api.FindingVerification) {
+	scan, err := s.scanEngineClient.Get(ctx, verification.ScanID)
+	if err != nil {
+		_ = level.Error(s.logger).Log("FindingVerifications", "error getting verification scan", "ScanID", verification.ScanID, "err", err)
+		return
+	}
+	verification.Status = scan.Status
+}
+
+// findScannableAsset returns the scannable asset of a team with the given
+// identifier.
+func (s vulcanitoService) findScannableAsset(teamID, identifier string) (*api.Asset, error) {
+	assets, err := s.db.ListAssets(teamID, api.Asset{Identifier: identifier})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assets {
+		if a.AssetType != nil && (a.Scannable == nil || *a.Scannable) {
+			return a, nil
+		}
+	}
+	return nil, errors.NotFound(fmt.Sprintf("No scannable asset found in team for target '%s'", identifier))
+}
+
 func isValidFindingStatus(status string) bool {
 	// Set of valid status type
 	validStatus := map[string]struct{}{
@@ -70,6 +368,7 @@ func isValidFindingStatus(status string) bool {
 		"FIXED":          {},
 		"EXPIRED":        {},
 		"FALSE_POSITIVE": {},
+		"ACCEPTED_RISK":  {},
 	}
 
 	_, existsInSet := validStatus[status]
@@ -84,14 +383,19 @@ func isValidFindingTransition(status, statusPrevious string) bool {
 	// FALSE_POSITIVE -> OPEN
 	// FALSE_POSITIVE -> FALSE_POSITIVE
 	// FIXED          -> FALSE_POSITIVE
+	// OPEN           -> ACCEPTED_RISK
+	// ACCEPTED_RISK  -> OPEN
+	// ACCEPTED_RISK  -> FALSE_POSITIVE
+	// ACCEPTED_RISK  -> ACCEPTED_RISK
 	if status == statusPrevious {
 		return true
 	}
 
 	validTransitions := map[string][]string{
-		"OPEN":           {"FALSE_POSITIVE"},
+		"OPEN":           {"FALSE_POSITIVE", "ACCEPTED_RISK"},
 		"FALSE_POSITIVE": {"OPEN"},
 		"FIXED":          {"FALSE_POSITIVE"},
+		"ACCEPTED_RISK":  {"OPEN", "FALSE_POSITIVE"},
 	}
 	return slices.Contains(validTransitions[statusPrevious], status)
 }
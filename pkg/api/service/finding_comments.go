@@ -0,0 +1,187 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
+)
+
+func (s vulcanitoService) ListFindingComments(ctx context.Context, teamID, findingID string) ([]*api.FindingComment, error) {
+	return s.db.ListFindingComments(teamID, findingID)
+}
+
+// CreateFindingComment adds a comment to the discussion thread of a finding
+// of a team. The mentioned users must be members of the team, and they are
+// notified about the comment.
+func (s vulcanitoService) CreateFindingComment(ctx context.Context, comment api.FindingComment) (*api.FindingComment, error) {
+	if err := comment.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	if _, err := s.findTeamFinding(ctx, comment.TeamID, comment.FindingID); err != nil {
+		return nil, err
+	}
+	mentions, err := s.findingCommentMentions(comment.TeamID, comment.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	comment.Mentions = mentions
+	created, err := s.db.CreateFindingComment(comment)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyFindingCommentMentions(ctx, *created, mentionRecipients(created.AuthorID, mentions, nil))
+	return created, nil
+}
+
+// UpdateFindingComment changes the body and the mentions of a comment. Only
+// the author of a comment can edit it, and the previous body is kept in the
+// edit history of the comment. Only the users that were not mentioned in the
+// previous version of the comment are notified.
+func (s vulcanitoService) UpdateFindingComment(ctx context.Context, comment api.FindingComment) (*api.FindingComment, error) {
+	if err := comment.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	user, err := api.UserFromContext(ctx)
+	if err != nil {
+		return nil, errors.Default(err)
+	}
+	current, err := s.db.FindFindingComment(comment.TeamID, comment.FindingID, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.AuthorID != user.ID {
+		return nil, errors.Forbidden("Only the author of a comment can edit it")
+	}
+	mentions, err := s.findingCommentMentions(comment.TeamID, comment.Mentions)
+	if err != nil {
+		return nil, err
+	}
+	recipients := mentionRecipients(current.AuthorID, mentions, current.Mentions)
+	current.Mentions = mentions
+	var edit *api.FindingCommentEdit
+	if current.Body != comment.Body {
+		edit = &api.FindingCommentEdit{Body: current.Body, EditedBy: user.ID}
+		current.Body = comment.Body
+	}
+	updated, err := s.db.UpdateFindingComment(*current, edit)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyFindingCommentMentions(ctx, *updated, recipients)
+	return updated, nil
+}
+
+// DeleteFindingComment deletes a comment of a finding. A comment can be
+// deleted by its author, by the owners of the team and by the admin users.
+func (s vulcanitoService) DeleteFindingComment(ctx context.Context, comment api.FindingComment) error {
+	user, err := api.UserFromContext(ctx)
+	if err != nil {
+		return errors.Default(err)
+	}
+	current, err := s.db.FindFindingComment(comment.TeamID, comment.FindingID, comment.ID)
+	if err != nil {
+		return err
+	}
+	admin := user.Admin != nil && *user.Admin
+	if current.AuthorID != user.ID && !admin {
+		member, err := s.db.FindTeamMember(comment.TeamID, user.ID)
+		if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
+			return err
+		}
+		if member == nil || member.Role != api.Owner {
+			return errors.Forbidden("Only the author of a comment and the owners of the team can delete it")
+		}
+	}
+	return s.db.DeleteFindingComment(*current)
+}
+
+// findingCommentMentions resolves the users mentioned in a comment by their
+// email, ignoring the duplicated ones, and checks they are members of the
+// team.
+func (s vulcanitoService) findingCommentMentions(teamID string, mentions []*api.FindingCommentMention) ([]*api.FindingCommentMention, error) {
+	resolved := []*api.FindingCommentMention{}
+	seen := map[string]bool{}
+	for _, m := range mentions {
+		if m.User == nil || strings.TrimSpace(m.User.Email) == "" {
+			return nil, errors.Validation("Mentioned user email is required")
+		}
+		email := strings.TrimSpace(m.User.Email)
+		user, err := s.db.FindUserByEmail(email)
+		if err != nil {
+			if errors.IsKind(err, errors.ErrNotFound) {
+				return nil, errors.Validation(fmt.Sprintf("Mentioned user '%s' is not a member of the team", email))
+			}
+			return nil, err
+		}
+		if seen[user.ID] {
+			continue
+		}
+		member, err := s.db.FindTeamMember(teamID, user.ID)
+		if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
+			return nil, err
+		}
+		if member == nil || member.Role == "" {
+			return nil, errors.Validation(fmt.Sprintf("Mentioned user '%s' is not a member of the team", email))
+		}
+		seen[user.ID] = true
+		resolved = append(resolved, &api.FindingCommentMention{UserID: user.ID, User: user})
+	}
+	return resolved, nil
+}
+
+// mentionRecipients returns the users mentioned in a comment that must be
+// notified: the ones that are not the author of the comment and were not
+// mentioned before.
+func mentionRecipients(authorID string, mentions, previous []*api.FindingCommentMention) []reports.Recipient {
+	notified := map[string]bool{authorID: true}
+	for _, m := range previous {
+		notified[m.UserID] = true
+	}
+	recipients := []reports.Recipient{}
+	for _, m := range mentions {
+		if notified[m.UserID] || m.User == nil {
+			continue
+		}
+		notified[m.UserID] = true
+		recipients = append(recipients, reports.Recipient{Email: m.User.Email})
+	}
+	return recipients
+}
+
+// notifyFindingCommentMentions notifies the given recipients that they were
+// mentioned in a comment. The comment is already stored, so an error sending
+// the notification is logged instead of returned.
+func (s vulcanitoService) notifyFindingCommentMentions(ctx context.Context, comment api.FindingComment, recipients []reports.Recipient) {
+	if s.reportsClient == nil || len(recipients) == 0 {
+		return
+	}
+	team, err := s.db.FindTeam(comment.TeamID)
+	if err != nil {
+		_ = level.Error(s.logger).Log("FindingComments", "error finding team", "TeamID", comment.TeamID, "err", err)
+		return
+	}
+	notification := api.FindingCommentMentionNotification{
+		TeamID:    comment.TeamID,
+		TeamName:  team.Name,
+		FindingID: comment.FindingID,
+		CommentID: comment.ID,
+		Body:      comment.Body,
+	}
+	if user, err := api.UserFromContext(ctx); err == nil {
+		notification.Author = user.Email
+	}
+	err = s.reportsClient.SendFindingCommentMention(comment.TeamID, team.Name, recipients, notification)
+	if err != nil {
+		_ = level.Error(s.logger).Log("FindingComments", "error notifying mentions", "TeamID", comment.TeamID, "CommentID", comment.ID, "err", err)
+	}
+}
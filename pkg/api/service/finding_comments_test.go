@@ -0,0 +1,243 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+	"github.com/adevinta/vulcan-api/pkg/reports"
+)
+
+type inMemoryCommentsStore struct {
+	api.VulcanitoStore
+	users    []*api.User
+	members  []api.UserTeam
+	comments []*api.FindingComment
+	edit     *api.FindingCommentEdit
+	deleted  []string
+}
+
+func (s *inMemoryCommentsStore) FindUserByEmail(email string) (*api.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.NotFound("User does not exists")
+}
+
+func (s *inMemoryCommentsStore) FindTeamMember(teamID, userID string) (*api.UserTeam, error) {
+	for _, m := range s.members {
+		if m.TeamID == teamID && m.UserID == userID {
+			member := m
+			return &member, nil
+		}
+	}
+	return &api.UserTeam{TeamID: teamID, UserID: userID}, nil
+}
+
+func (s *inMemoryCommentsStore) FindFindingComment(teamID, findingID, commentID string) (*api.FindingComment, error) {
+	for _, c := range s.comments {
+		if c.TeamID == teamID && c.FindingID == findingID && c.ID == commentID {
+			comment := *c
+			return &comment, nil
+		}
+	}
+	return nil, errors.NotFound("record not found")
+}
+
+func (s *inMemoryCommentsStore) UpdateFindingComment(comment api.FindingComment, edit *api.FindingCommentEdit) (*api.FindingComment, error) {
+	s.edit = edit
+	return &comment, nil
+}
+
+func (s *inMemoryCommentsStore) DeleteFindingComment(comment api.FindingComment) error {
+	s.deleted = append(s.deleted, comment.ID)
+	return nil
+}
+
+func newInMemoryCommentsStore() *inMemoryCommentsStore {
+	return &inMemoryCommentsStore{
+		users: []*api.User{
+			{ID: "author", Email: "author@example.com"},
+			{ID: "owner", Email: "owner@example.com"},
+			{ID: "outsider", Email: "outsider@example.com"},
+		},
+		members: []api.UserTeam{
+			{TeamID: "team1", UserID: "author", Role: api.Member},
+			{TeamID: "team1", UserID: "owner", Role: api.Owner},
+		},
+		comments: []*api.FindingComment{
+			{ID: "c1", TeamID: "team1", FindingID: "f1", AuthorID: "author", Body: "first version"},
+		},
+	}
+}
+
+func TestVulcanitoService_UpdateFindingComment(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         api.User
+		comment      api.FindingComment
+		wantMentions []string
+		wantEdit     *api.FindingCommentEdit
+		wantErr      error
+	}{
+		{
+			name: "KeepsThePreviousBody",
+			user: api.User{ID: "author"},
+			comment: api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1", Body: "second version", Mentions: []*api.FindingCommentMention{
+				{User: &api.User{Email: "owner@example.com"}},
+				{User: &api.User{Email: "owner@example.com"}},
+			}},
+			wantMentions: []string{"owner"},
+			wantEdit:     &api.FindingCommentEdit{Body: "first version", EditedBy: "author"},
+		},
+		{
+			name:         "OnlyMentionsChanged",
+			user:         api.User{ID: "author"},
+			comment:      api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1", Body: "first version"},
+			wantMentions: []string{},
+		},
+		{
+			name:    "NotTheAuthor",
+			user:    api.User{ID: "owner"},
+			comment: api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1", Body: "second version"},
+			wantErr: errors.Forbidden("Only the author of a comment can edit it"),
+		},
+		{
+			name: "MentionedUserNotInTheTeam",
+			user: api.User{ID: "author"},
+			comment: api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1", Body: "second version", Mentions: []*api.FindingCommentMention{
+				{User: &api.User{Email: "outsider@example.com"}},
+			}},
+			wantErr: errors.Validation("Mentioned user 'outsider@example.com' is not a member of the team"),
+		},
+		{
+			name:    "EmptyBody",
+			user:    api.User{ID: "author"},
+			comment: api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1", Body: " "},
+			wantErr: errors.Validation(api.ErrFindingCommentBodyRequired),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newInMemoryCommentsStore()
+			srv := vulcanitoService{db: db}
+			ctx := api.ContextWithUser(context.Background(), tt.user)
+			got, err := srv.UpdateFindingComment(ctx, tt.comment)
+			if errToStr(err) != errToStr(tt.wantErr) {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			mentions := []string{}
+			for _, m := range got.Mentions {
+				mentions = append(mentions, m.UserID)
+			}
+			if diff := cmp.Diff(tt.wantMentions, mentions); diff != "" {
+				t.Errorf("mentions, want!=got, diff: %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantEdit, db.edit); diff != "" {
+				t.Errorf("edit, want!=got, diff: %s", diff)
+			}
+			if got.Body != tt.comment.Body {
+				t.Errorf("got body %q, want %q", got.Body, tt.comment.Body)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_DeleteFindingComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    api.User
+		wantErr error
+	}{
+		{
+			name: "Author",
+			user: api.User{ID: "author"},
+		},
+		{
+			name: "TeamOwner",
+			user: api.User{ID: "owner"},
+		},
+		{
+			name: "Admin",
+			user: api.User{ID: "admin", Admin: common.Bool(true)},
+		},
+		{
+			name:    "OtherUser",
+			user:    api.User{ID: "outsider"},
+			wantErr: errors.Forbidden("Only the author of a comment and the owners of the team can delete it"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newInMemoryCommentsStore()
+			srv := vulcanitoService{db: db}
+			ctx := api.ContextWithUser(context.Background(), tt.user)
+			err := srv.DeleteFindingComment(ctx, api.FindingComment{ID: "c1", TeamID: "team1", FindingID: "f1"})
+			if errToStr(err) != errToStr(tt.wantErr) {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			wantDeleted := []string{"c1"}
+			if err != nil {
+				wantDeleted = nil
+			}
+			if diff := cmp.Diff(wantDeleted, db.deleted); diff != "" {
+				t.Errorf("deleted, want!=got, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestMentionRecipients(t *testing.T) {
+	author := &api.FindingCommentMention{UserID: "author", User: &api.User{ID: "author", Email: "author@example.com"}}
+	owner := &api.FindingCommentMention{UserID: "owner", User: &api.User{ID: "owner", Email: "owner@example.com"}}
+	member := &api.FindingCommentMention{UserID: "member", User: &api.User{ID: "member", Email: "member@example.com"}}
+	tests := []struct {
+		name     string
+		mentions []*api.FindingCommentMention
+		previous []*api.FindingCommentMention
+		want     []reports.Recipient
+	}{
+		{
+			name:     "NewComment",
+			mentions: []*api.FindingCommentMention{owner, member},
+			want:     []reports.Recipient{{Email: "owner@example.com"}, {Email: "member@example.com"}},
+		},
+		{
+			name:     "SkipsTheAuthor",
+			mentions: []*api.FindingCommentMention{author, owner},
+			want:     []reports.Recipient{{Email: "owner@example.com"}},
+		},
+		{
+			name:     "SkipsPreviousMentions",
+			mentions: []*api.FindingCommentMention{owner, member},
+			previous: []*api.FindingCommentMention{owner},
+			want:     []reports.Recipient{{Email: "member@example.com"}},
+		},
+		{
+			name:     "NoMentions",
+			previous: []*api.FindingCommentMention{owner},
+			want:     []reports.Recipient{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mentionRecipients("author", tt.mentions, tt.previous)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("recipients, want!=got, diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	errs "errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	scanengineData "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// inMemoryVerificationsStore stores the finding verifications of a team
+// with a single asset.
+type inMemoryVerificationsStore struct {
+	api.VulcanitoStore
+	asset         api.Asset
+	settings      []*api.AssetChecktypeSetting
+	verifications []*api.FindingVerification
+}
+
+func (s *inMemoryVerificationsStore) ListAssets(teamID string, asset api.Asset) ([]*api.Asset, error) {
+	if asset.Identifier != s.asset.Identifier {
+		return []*api.Asset{}, nil
+	}
+	a := s.asset
+	return []*api.Asset{&a}, nil
+}
+
+func (s *inMemoryVerificationsStore) ListAssetChecktypeSettings(teamID, assetID string) ([]*api.AssetChecktypeSetting, error) {
+	return s.settings, nil
+}
+
+func (s *inMemoryVerificationsStore) FindTeam(teamID string) (*api.Team, error) {
+	return &api.Team{ID: teamID}, nil
+}
+
+func (s *inMemoryVerificationsStore) CreateFindingVerification(verification api.FindingVerification) (*api.FindingVerification, error) {
+	verification.ID = "v1"
+	s.verifications = append(s.verifications, &verification)
+	v := verification
+	return &v, nil
+}
+
+func (s *inMemoryVerificationsStore) ListFindingVerifications(teamID, findingID string) ([]*api.FindingVerification, error) {
+	var verifications []*api.FindingVerification
+	for _, v := range s.verifications {
+		v := *v
+		verifications = append(verifications, &v)
+	}
+	return verifications, nil
+}
+
+type findingClient struct {
+	vulnerabilitydb.Client
+}
+
+func (c findingClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	f := vulndb.FindingExpanded{
+		Finding: vulndb.Finding{ID: findingID},
+		Target:  vulndb.Target{Identifier: "example.com", Teams: []string{"t1"}},
+		Source:  vulndb.Source{Name: "vulcan-tls", Options: `{"port":443}`},
+	}
+	return &api.Finding{Finding: api.FindingExpanded{ScoredFinding: api.ScoredFinding{FindingExpanded: f}}}, nil
+}
+
+// getFailingScanEngine creates scans but fails to return them.
+type getFailingScanEngine struct {
+	*scanenginetest.Fake
+}
+
+func (c getFailingScanEngine) Get(ctx context.Context, scanID string) (*scanengineData.GetScanResponse, error) {
+	return nil, errs.New("scan engine unavailable")
+}
+
+func TestVulcanitoService_VerifyFinding(t *testing.T) {
+	asset := api.Asset{ID: "a1", Identifier: "example.com", AssetType: &api.AssetType{Name: "Hostname"}}
+	future := time.Now().Add(time.Hour)
+	user := api.User{ID: "u1", Email: "user@example.com"}
+
+	t.Run("StoresTheVerification", func(t *testing.T) {
+		db := &inMemoryVerificationsStore{asset: asset}
+		scanEngine := &scanenginetest.Fake{}
+		srv := vulcanitoService{db: db, vulndbClient: findingClient{}, scanEngineClient: scanEngine, logger: log.NewNopLogger()}
+
+		got, err := srv.VerifyFinding(context.Background(), "t1", "f1", user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ScanID != "scan-1" || got.Status != "RUNNING" {
+			t.Errorf("got verification with scan %q and status %q", got.ScanID, got.Status)
+		}
+		checktypes := scanEngine.Requests[0].TargetGroups[0].ChecktypesGroup.Checktypes
+		if len(checktypes) != 1 || checktypes[0].Name != "vulcan-tls" || checktypes[0].Options != `{"port":443}` {
+			t.Errorf("got checktypes %+v", checktypes)
+		}
+	})
+
+	t.Run("OverriddenOptions", func(t *testing.T) {
+		db := &inMemoryVerificationsStore{asset: asset, settings: []*api.AssetChecktypeSetting{
+			{AssetID: "a1", ChecktypeName: "vulcan-tls", Options: common.String(`{"port":8443}`), ExpiresAt: &future},
+		}}
+		scanEngine := &scanenginetest.Fake{}
+		srv := vulcanitoService{db: db, vulndbClient: findingClient{}, scanEngineClient: scanEngine, logger: log.NewNopLogger()}
+
+		if _, err := srv.VerifyFinding(context.Background(), "t1", "f1", user); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		checktypes := scanEngine.Requests[0].TargetGroups[0].ChecktypesGroup.Checktypes
+		if diff := cmp.Diff(`{"port":8443}`, checktypes[0].Options); diff != "" {
+			t.Errorf("options mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ExcludedChecktype", func(t *testing.T) {
+		db := &inMemoryVerificationsStore{asset: asset, settings: []*api.AssetChecktypeSetting{
+			{AssetID: "a1", ChecktypeName: "vulcan-tls", Excluded: common.Bool(true), ExpiresAt: &future},
+		}}
+		scanEngine := &scanenginetest.Fake{}
+		srv := vulcanitoService{db: db, vulndbClient: findingClient{}, scanEngineClient: scanEngine, logger: log.NewNopLogger()}
+
+		_, err := srv.VerifyFinding(context.Background(), "t1", "f1", user)
+		if !errors.IsKind(err, errors.ErrValidation) {
+			t.Fatalf("got error %v, want validation error", err)
+		}
+		if len(scanEngine.Requests) != 0 {
+			t.Errorf("got %d scans created for an excluded checktype", len(scanEngine.Requests))
+		}
+	})
+
+	t.Run("ScanStatusNotAvailable", func(t *testing.T) {
+		db := &inMemoryVerificationsStore{asset: asset}
+		scanEngine := getFailingScanEngine{&scanenginetest.Fake{}}
+		srv := vulcanitoService{db: db, vulndbClient: findingClient{}, scanEngineClient: scanEngine, logger: log.NewNopLogger()}
+
+		// The verification is returned once stored, so the client does not
+		// retry it launching another scan.
+		got, err := srv.VerifyFinding(context.Background(), "t1", "f1", user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ScanID != "scan-1" || got.Status != "" {
+			t.Errorf("got verification with scan %q and status %q", got.ScanID, got.Status)
+		}
+
+		list, err := srv.ListFindingVerifications(context.Background(), "t1", "f1")
+		if err != nil {
+			t.Fatalf("unexpected error listing verifications: %v", err)
+		}
+		if len(list) != 1 || list[0].Status != "" {
+			t.Errorf("got verifications %+v", list)
+		}
+	})
+}
+
+// inMemoryOverwritesStore stores the finding overwrites of a team.
+type inMemoryOverwritesStore struct {
+	api.VulcanitoStore
+	overwrites []api.FindingOverwrite
+	jobs       int
+}
+
+func (s *inMemoryOverwritesStore) CreateFindingOverwrites(overwrites []api.FindingOverwrite) error {
+	s.overwrites = append(s.overwrites, overwrites...)
+	return nil
+}
+
+func (s *inMemoryOverwritesStore) ListJobFindingOverwrites(jobID string) ([]*api.FindingOverwrite, error) {
+	var overwrites []*api.FindingOverwrite
+	for i, o := range s.overwrites {
+		if o.JobID != nil && *o.JobID == jobID {
+			overwrites = append(overwrites, &s.overwrites[i])
+		}
+	}
+	return overwrites, nil
+}
+
+func (s *inMemoryOverwritesStore) CreateFindingOverwritesAsync(bulk api.BulkFindingOverwrite) (*api.Job, error) {
+	s.jobs++
+	return &api.Job{ID: "j1", TeamID: bulk.TeamID}, nil
+}
+
+// openFindingsClient returns open findings of the team t1. The status of the
+// findings overwritten in the store is the one of their last overwrite.
+type openFindingsClient struct {
+	vulnerabilitydb.Client
+	db *inMemoryOverwritesStore
+}
+
+func (c openFindingsClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	status := "OPEN"
+	for _, o := range c.db.overwrites {
+		if o.FindingID == findingID {
+			status = o.Status
+		}
+	}
+	f := vulndb.FindingExpanded{
+		Finding: vulndb.Finding{ID: findingID, Status: status},
+		Target:  vulndb.Target{Identifier: "example.com", Teams: []string{"t1"}},
+	}
+	return &api.Finding{Finding: api.FindingExpanded{ScoredFinding: api.ScoredFinding{FindingExpanded: f}}}, nil
+}
+
+func TestVulcanitoService_CreateFindingOverwritesAsync(t *testing.T) {
+	tests := []struct {
+		name    string
+		bulk    api.BulkFindingOverwrite
+		wantErr bool
+	}{
+		{
+			name: "FindingIDs",
+			bulk: api.BulkFindingOverwrite{FindingIDs: []string{"f1"}},
+		},
+		{
+			name: "Filter",
+			bulk: api.BulkFindingOverwrite{Params: &api.FindingsParams{IssueID: "i1"}},
+		},
+		{
+			name:    "EmptyFilter",
+			bulk:    api.BulkFindingOverwrite{Params: &api.FindingsParams{Team: "t1"}},
+			wantErr: true,
+		},
+		{
+			name:    "FindingIDsAndFilter",
+			bulk:    api.BulkFindingOverwrite{FindingIDs: []string{"f1"}, Params: &api.FindingsParams{IssueID: "i1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &inMemoryOverwritesStore{}
+			srv := vulcanitoService{db: db}
+			bulk := tt.bulk
+			bulk.TeamID, bulk.UserID, bulk.Status, bulk.Notes = "t1", "u1", "FALSE_POSITIVE", "notes"
+			_, err := srv.CreateFindingOverwritesAsync(context.Background(), bulk)
+			if tt.wantErr {
+				if !errors.IsKind(err, errors.ErrValidation) {
+					t.Errorf("got error %v, want a validation error", err)
+				}
+				if db.jobs != 0 {
+					t.Errorf("job created for an invalid bulk overwrite")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_CreateFindingOverwritesReplay(t *testing.T) {
+	db := &inMemoryOverwritesStore{}
+	srv := vulcanitoService{db: db, vulndbClient: openFindingsClient{db: db}}
+	bulk := api.BulkFindingOverwrite{
+		TeamID:     "t1",
+		UserID:     "u1",
+		FindingIDs: []string{"f1", "f2"},
+		Status:     "FALSE_POSITIVE",
+		Notes:      "notes",
+		JobID:      "j1",
+	}
+
+	want := []api.BulkFindingOverwriteResult{
+		{FindingID: "f1", StatusPrevious: "OPEN"},
+		{FindingID: "f2", StatusPrevious: "OPEN"},
+	}
+	for i := 0; i < 2; i++ {
+		got, err := srv.CreateFindingOverwrites(context.Background(), bulk)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("run %d: results mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+	// Running the job again doesn't overwrite the findings again.
+	if len(db.overwrites) != 2 {
+		t.Errorf("got %d overwrites, want 2", len(db.overwrites))
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/findingsexport"
+)
+
+// findingsExportPartSize is the number of bytes of the parts in which the
+// content of the exports is stored.
+const findingsExportPartSize = 1 << 20
+
+// ExportFindings validates an export and reads the first page of its
+// findings, so the errors are returned before anything is written, and
+// returns a writer that writes the findings of the export page by page as
+// they are read from the vulnerability db.
+func (s vulcanitoService) ExportFindings(ctx context.Context, export api.FindingsExport) (api.FindingsExportWriter, error) {
+	if err := validateFindingsExport(export); err != nil {
+		return nil, err
+	}
+	params := api.FindingsParams(export.Params)
+	params.Team = export.TeamID
+	first, err := s.vulndbClient.Findings(ctx, params, api.Pagination{Page: 1, Size: allFindingsPageSize})
+	if err != nil {
+		return nil, err
+	}
+	firstScored, err := s.scoreFindings(export.TeamID, first.Findings)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer) (int, error) {
+		fw, err := findingsexport.NewWriter(export.Format, w)
+		if err != nil {
+			return 0, errors.Validation(err)
+		}
+		n := 0
+		list, scored := first, firstScored
+		for page := 1; ; page++ {
+			if page > 1 {
+				list, err = s.vulndbClient.Findings(ctx, params, api.Pagination{Page: page, Size: allFindingsPageSize})
+				if err != nil {
+					return n, err
+				}
+				scored, err = s.scoreFindings(export.TeamID, list.Findings)
+				if err != nil {
+					return n, err
+				}
+			}
+			for _, f := range scored {
+				if err := fw.Write(f); err != nil {
+					return n, errors.Default(err)
+				}
+				n++
+			}
+			if !list.Pagination.More || len(list.Findings) == 0 {
+				break
+			}
+		}
+		if err := fw.Close(); err != nil {
+			return n, errors.Default(err)
+		}
+		return n, nil
+	}, nil
+}
+
+func validateFindingsExport(export api.FindingsExport) error {
+	if err := export.Validate(); err != nil {
+		return errors.Validation(err)
+	}
+	if strings.TrimPrefix(export.Params.SortBy, "-") == api.RiskScoreSortBy {
+		return errors.Validation("The exports can not be sorted by risk score")
+	}
+	return nil
+}
+
+// CreateFindingsExportAsync validates an export and stores it as pending, so
+// it is generated asynchronously by GenerateFindingsExport.
+func (s vulcanitoService) CreateFindingsExportAsync(ctx context.Context, export api.FindingsExport) (*api.Job, error) {
+	if err := validateFindingsExport(export); err != nil {
+		return nil, err
+	}
+	return s.db.CreateFindingsExportAsync(export)
+}
+
+// GenerateFindingsExport generates a pending export claimed by the caller,
+// storing its content in parts, and finishes the job of the export with the
+// result.
+func (s vulcanitoService) GenerateFindingsExport(ctx context.Context, export api.FindingsExport) error {
+	// The export could have been claimed again after a previous attempt to
+	// generate it was interrupted.
+	if err := s.db.DeleteFindingsExportParts(export.ID); err != nil {
+		return err
+	}
+	result := &api.JobResult{}
+	export.Status = api.FindingsExportDone
+	n, size, err := s.generateFindingsExport(ctx, export)
+	if err != nil {
+		_ = level.Error(s.logger).Log("FindingsExports", "error generating findings export", "TeamID", export.TeamID, "ExportID", export.ID, "err", err)
+		result.Error = err.Error()
+		export.Status = api.FindingsExportFailed
+		if err := s.db.DeleteFindingsExportParts(export.ID); err != nil {
+			return err
+		}
+	}
+	export.Findings = n
+	export.Size = size
+	stored, err := s.db.UpdateFindingsExport(export)
+	if err != nil {
+		return err
+	}
+	if export.JobID == nil {
+		return nil
+	}
+	if result.Error == "" {
+		stored.DownloadPath = findingsExportDownloadPath(*stored)
+		if result.Data, err = json.Marshal(stored); err != nil {
+			return err
+		}
+	}
+	_, err = s.db.UpdateJob(api.Job{ID: *export.JobID, Status: api.JobStatusDone, Result: result})
+	return err
+}
+
+func (s vulcanitoService) generateFindingsExport(ctx context.Context, export api.FindingsExport) (int, int64, error) {
+	write, err := s.ExportFindings(ctx, export)
+	if err != nil {
+		return 0, 0, err
+	}
+	pw := &findingsExportPartWriter{db: s.db, exportID: export.ID}
+	n, err := write(pw)
+	if err != nil {
+		return n, pw.size, err
+	}
+	if err := pw.flush(); err != nil {
+		return n, pw.size, err
+	}
+	return n, pw.size, nil
+}
+
+// findingsExportPartWriter stores what is written to it in parts of
+// findingsExportPartSize bytes, and fails if more than
+// api.MaxFindingsExportSize bytes are written.
+type findingsExportPartWriter struct {
+	db       api.VulcanitoStore
+	exportID string
+	part     int
+	buf      bytes.Buffer
+	size     int64
+}
+
+func (w *findingsExportPartWriter) Write(p []byte) (int, error) {
+	if w.size+int64(len(p)) > api.MaxFindingsExportSize {
+		return 0, errors.Validation(api.ErrFindingsExportTooLarge)
+	}
+	w.buf.Write(p)
+	w.size += int64(len(p))
+	for w.buf.Len() >= findingsExportPartSize {
+		if err := w.store(w.buf.Next(findingsExportPartSize)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *findingsExportPartWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	return w.store(w.buf.Next(w.buf.Len()))
+}
+
+func (w *findingsExportPartWriter) store(content []byte) error {
+	part := api.FindingsExportPart{ExportID: w.exportID, Part: w.part, Content: append([]byte(nil), content...)}
+	if err := w.db.CreateFindingsExportPart(part); err != nil {
+		return err
+	}
+	w.part++
+	return nil
+}
+
+// FindFindingsExport returns an export of a team that was generated.
+func (s vulcanitoService) FindFindingsExport(ctx context.Context, teamID, exportID string) (*api.FindingsExport, error) {
+	export, err := s.db.FindFindingsExport(teamID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != api.FindingsExportDone {
+		return nil, errors.Validation(fmt.Sprintf("The export is %s", strings.ToLower(export.Status)))
+	}
+	export.DownloadPath = findingsExportDownloadPath(*export)
+	return export, nil
+}
+
+// WriteFindingsExport writes the content of a generated export to w, reading
+// its parts one by one.
+func (s vulcanitoService) WriteFindingsExport(ctx context.Context, export api.FindingsExport, w io.Writer) error {
+	for part := 0; ; part++ {
+		p, err := s.db.FindFindingsExportPart(export.ID, part)
+		if errors.IsKind(err, errors.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(p.Content); err != nil {
+			return err
+		}
+	}
+}
+
+func findingsExportDownloadPath(export api.FindingsExport) string {
+	return fmt.Sprintf("/api/v1/teams/%s/findings/exports/%s", export.TeamID, export.ID)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+type inMemoryFindingsExportsClient struct {
+	vulnerabilitydb.Client
+	findings []vulndb.FindingExpanded
+	pageSize int
+	err      error
+}
+
+func (c *inMemoryFindingsExportsClient) Findings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	start := (pagination.Page - 1) * c.pageSize
+	end := start + c.pageSize
+	if end > len(c.findings) {
+		end = len(c.findings)
+	}
+	list := &api.FindingsList{Findings: []vulndb.FindingExpanded{}}
+	if start < end {
+		list.Findings = c.findings[start:end]
+	}
+	list.Pagination.More = end < len(c.findings)
+	return list, nil
+}
+
+type inMemoryFindingsExportsStore struct {
+	api.VulcanitoStore
+	parts  []api.FindingsExportPart
+	export *api.FindingsExport
+	job    *api.Job
+}
+
+func (s *inMemoryFindingsExportsStore) ListAssetsByIdentifiers(teamID string, identifiers []string) ([]*api.Asset, error) {
+	return nil, nil
+}
+
+func (s *inMemoryFindingsExportsStore) CreateFindingsExportPart(part api.FindingsExportPart) error {
+	s.parts = append(s.parts, part)
+	return nil
+}
+
+func (s *inMemoryFindingsExportsStore) FindFindingsExportPart(exportID string, part int) (*api.FindingsExportPart, error) {
+	for _, p := range s.parts {
+		if p.ExportID == exportID && p.Part == part {
+			return &p, nil
+		}
+	}
+	return nil, errors.NotFound("record not found")
+}
+
+func (s *inMemoryFindingsExportsStore) DeleteFindingsExportParts(exportID string) error {
+	s.parts = nil
+	return nil
+}
+
+func (s *inMemoryFindingsExportsStore) UpdateFindingsExport(export api.FindingsExport) (*api.FindingsExport, error) {
+	s.export = &export
+	return &export, nil
+}
+
+func (s *inMemoryFindingsExportsStore) UpdateJob(job api.Job) (*api.Job, error) {
+	s.job = &job
+	return &job, nil
+}
+
+func exportFindings(n int) []vulndb.FindingExpanded {
+	findings := []vulndb.FindingExpanded{}
+	for i := 0; i < n; i++ {
+		f := vulndb.FindingExpanded{}
+		f.ID = strings.Repeat("f", i+1)
+		f.Status = findingStatusOpen
+		f.Target.Identifier = "example.com"
+		f.Issue.Summary = "Outdated TLS"
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func TestVulcanitoService_ExportFindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		export    api.FindingsExport
+		client    *inMemoryFindingsExportsClient
+		wantErr   error
+		wantLines int
+	}{
+		{
+			name:      "WritesAllThePages",
+			export:    api.FindingsExport{TeamID: "t1", Format: api.FindingsExportNDJSON},
+			client:    &inMemoryFindingsExportsClient{findings: exportFindings(5), pageSize: 2},
+			wantLines: 5,
+		},
+		{
+			name:    "InvalidFormat",
+			export:  api.FindingsExport{TeamID: "t1", Format: "xml"},
+			client:  &inMemoryFindingsExportsClient{},
+			wantErr: errors.Validation(api.ErrInvalidFindingsExportFormat),
+		},
+		{
+			name:    "SortedByRiskScore",
+			export:  api.FindingsExport{TeamID: "t1", Format: api.FindingsExportCSV, Params: api.FindingsExportParams{SortBy: "-" + api.RiskScoreSortBy}},
+			client:  &inMemoryFindingsExportsClient{},
+			wantErr: errors.Validation("The exports can not be sorted by risk score"),
+		},
+		{
+			// The errors reading the first page are returned before
+			// anything is written.
+			name:    "VulnDBNotAvailable",
+			export:  api.FindingsExport{TeamID: "t1", Format: api.FindingsExportCSV},
+			client:  &inMemoryFindingsExportsClient{err: errors.Default("vulndb not available")},
+			wantErr: errors.Default("vulndb not available"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := vulcanitoService{db: &inMemoryFindingsExportsStore{}, vulndbClient: tt.client}
+			write, err := srv.ExportFindings(context.Background(), tt.export)
+			if errToStr(err) != errToStr(tt.wantErr) {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var buf bytes.Buffer
+			n, err := write(&buf)
+			if err != nil {
+				t.Fatalf("unexpected error writing the export: %v", err)
+			}
+			lines := strings.Count(buf.String(), "\n")
+			if n != tt.wantLines || lines != tt.wantLines {
+				t.Errorf("got %d findings and %d lines, want %d", n, lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_GenerateFindingsExport(t *testing.T) {
+	jobID := "j1"
+	export := api.FindingsExport{ID: "e1", TeamID: "t1", JobID: &jobID, Format: api.FindingsExportNDJSON, Status: api.FindingsExportRunning}
+	db := &inMemoryFindingsExportsStore{
+		// Parts of a previous interrupted attempt.
+		parts: []api.FindingsExportPart{{ExportID: "e1", Part: 0, Content: []byte("stale")}},
+	}
+	client := &inMemoryFindingsExportsClient{findings: exportFindings(3), pageSize: 2}
+	srv := vulcanitoService{db: db, vulndbClient: client, logger: log.NewNopLogger()}
+
+	if err := srv.GenerateFindingsExport(context.Background(), export); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.export.Status != api.FindingsExportDone || db.export.Findings != 3 {
+		t.Errorf("got export with status %s and %d findings", db.export.Status, db.export.Findings)
+	}
+	if db.job == nil || db.job.Status != api.JobStatusDone || db.job.Result.Error != "" {
+		t.Fatalf("got job %+v, want done without error", db.job)
+	}
+	var buf bytes.Buffer
+	if err := srv.WriteFindingsExport(context.Background(), *db.export, &buf); err != nil {
+		t.Fatalf("unexpected error writing the export: %v", err)
+	}
+	if int64(buf.Len()) != db.export.Size || strings.Count(buf.String(), "\n") != 3 || strings.Contains(buf.String(), "stale") {
+		t.Errorf("got content %q with size %d", buf.String(), db.export.Size)
+	}
+
+	client.err = errors.Default("vulndb not available")
+	if err := srv.GenerateFindingsExport(context.Background(), export); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.export.Status != api.FindingsExportFailed || len(db.parts) != 0 {
+		t.Errorf("got export with status %s and %d parts", db.export.Status, len(db.parts))
+	}
+	if db.job.Result.Error != "vulndb not available" {
+		t.Errorf("got job error %q", db.job.Result.Error)
+	}
+}
+
+func TestFindingsExportPartWriter(t *testing.T) {
+	db := &inMemoryFindingsExportsStore{}
+	w := &findingsExportPartWriter{db: db, exportID: "e1"}
+	content := bytes.Repeat([]byte("a"), findingsExportPartSize+10)
+	if _, err := w.Write(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(db.parts) != 2 || len(db.parts[0].Content) != findingsExportPartSize || len(db.parts[1].Content) != 10 || db.parts[1].Part != 1 {
+		t.Errorf("got %d parts", len(db.parts))
+	}
+
+	w.size = api.MaxFindingsExportSize
+	_, err := w.Write([]byte("a"))
+	if errToStr(err) != errToStr(errors.Validation(api.ErrFindingsExportTooLarge)) {
+		t.Errorf("got error %v, want too large", err)
+	}
+}
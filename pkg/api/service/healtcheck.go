@@ -6,8 +6,20 @@ package service
 
 import (
 	"context"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
 )
 
 func (s vulcanitoService) Healthcheck(ctx context.Context) error {
 	return s.db.Healthcheck()
 }
+
+// ChecktypesCacheStatus returns the freshness of the checktypes information
+// cached from vulcan-core, or nil if it is not cached.
+func (s vulcanitoService) ChecktypesCacheStatus(ctx context.Context) *api.ChecktypesCacheStatus {
+	if s.checktypesCache == nil {
+		return nil
+	}
+	status := s.checktypesCache.Status()
+	return &status
+}
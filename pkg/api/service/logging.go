@@ -8,6 +8,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -43,6 +44,11 @@ func (middleware loggingMiddleware) Healthcheck(ctx context.Context) error {
 	return middleware.next.Healthcheck(ctx)
 }
 
+func (middleware loggingMiddleware) ChecktypesCacheStatus(ctx context.Context) *api.ChecktypesCacheStatus {
+
+	return middleware.next.ChecktypesCacheStatus(ctx)
+}
+
 func (middleware loggingMiddleware) FindJob(ctx context.Context, jobID string) (*api.Job, error) {
 
 	defer func() {
@@ -329,6 +335,110 @@ func (middleware loggingMiddleware) ListRecipients(ctx context.Context, teamID s
 	return middleware.next.ListRecipients(ctx, teamID)
 }
 
+func (middleware loggingMiddleware) CreateRecipient(ctx context.Context, recipient api.Recipient) (*api.Recipient, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateRecipient", "recipient", mySprintf(recipient))
+	}()
+
+	return middleware.next.CreateRecipient(ctx, recipient)
+}
+
+func (middleware loggingMiddleware) UpdateRecipient(ctx context.Context, recipient api.Recipient, reportTypes *[]string) (*api.Recipient, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateRecipient", "recipient", mySprintf(recipient), "reportTypes", mySprintf(reportTypes))
+	}()
+
+	return middleware.next.UpdateRecipient(ctx, recipient, reportTypes)
+}
+
+func (middleware loggingMiddleware) DeleteRecipient(ctx context.Context, teamID, email string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteRecipient", "teamID", mySprintf(teamID), "email", mySprintf(email))
+	}()
+
+	return middleware.next.DeleteRecipient(ctx, teamID, email)
+}
+
+func (middleware loggingMiddleware) SendRecipientVerification(ctx context.Context, teamID, email string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SendRecipientVerification", "teamID", mySprintf(teamID), "email", mySprintf(email))
+	}()
+
+	return middleware.next.SendRecipientVerification(ctx, teamID, email)
+}
+
+func (middleware loggingMiddleware) VerifyRecipient(ctx context.Context, token string) (*api.Recipient, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "VerifyRecipient")
+	}()
+
+	return middleware.next.VerifyRecipient(ctx, token)
+}
+
+func (middleware loggingMiddleware) UnsubscribeRecipient(ctx context.Context, token string) (*api.Recipient, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UnsubscribeRecipient")
+	}()
+
+	return middleware.next.UnsubscribeRecipient(ctx, token)
+}
+
+func (middleware loggingMiddleware) DeactivateBouncedRecipients(ctx context.Context, emails []string) (int64, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeactivateBouncedRecipients", "emails", mySprintf(emails))
+	}()
+
+	return middleware.next.DeactivateBouncedRecipients(ctx, emails)
+}
+
+func (middleware loggingMiddleware) ListReportRecipients(ctx context.Context, teamID, reportType string) ([]*api.ReportRecipient, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListReportRecipients", "teamID", mySprintf(teamID), "reportType", mySprintf(reportType))
+	}()
+
+	return middleware.next.ListReportRecipients(ctx, teamID, reportType)
+}
+
 func (middleware loggingMiddleware) ListAssets(ctx context.Context, teamID string, asset api.Asset) ([]*api.Asset, error) {
 
 	defer func() {
@@ -524,6 +634,58 @@ func (middleware loggingMiddleware) DeleteAssetAnnotations(ctx context.Context,
 	return middleware.next.DeleteAssetAnnotations(ctx, teamID, assedID, annotations)
 }
 
+func (middleware loggingMiddleware) ListAssetChecktypeSettings(ctx context.Context, teamID, assetID string) ([]*api.AssetChecktypeSetting, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListAssetChecktypeSettings", "teamID", mySprintf(teamID), "assetID", mySprintf(assetID))
+	}()
+
+	return middleware.next.ListAssetChecktypeSettings(ctx, teamID, assetID)
+}
+
+func (middleware loggingMiddleware) CreateAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateAssetChecktypeSetting", "setting", mySprintf(setting))
+	}()
+
+	return middleware.next.CreateAssetChecktypeSetting(ctx, setting)
+}
+
+func (middleware loggingMiddleware) UpdateAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateAssetChecktypeSetting", "setting", mySprintf(setting))
+	}()
+
+	return middleware.next.UpdateAssetChecktypeSetting(ctx, setting)
+}
+
+func (middleware loggingMiddleware) DeleteAssetChecktypeSetting(ctx context.Context, setting api.AssetChecktypeSetting) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteAssetChecktypeSetting", "setting", mySprintf(setting))
+	}()
+
+	return middleware.next.DeleteAssetChecktypeSetting(ctx, setting)
+}
+
 func (middleware loggingMiddleware) ListGroups(ctx context.Context, teamID string, groupName string) ([]*api.Group, error) {
 
 	defer func() {
@@ -667,6 +829,19 @@ func (middleware loggingMiddleware) FindProgram(ctx context.Context, programID s
 	return middleware.next.FindProgram(ctx, programID, teamID)
 }
 
+func (middleware loggingMiddleware) ProgramPlan(ctx context.Context, programID string, teamID string) (*api.ProgramPlan, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ProgramPlan", "programID", mySprintf(programID), "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.ProgramPlan(ctx, programID, teamID)
+}
+
 func (middleware loggingMiddleware) UpdateProgram(ctx context.Context, program api.Program, teamID string) (*api.Program, error) {
 
 	defer func() {
@@ -862,353 +1037,1094 @@ func (middleware loggingMiddleware) DeleteChecktypeSetting(ctx context.Context,
 	return middleware.next.DeleteChecktypeSetting(ctx, checktypeSettingID)
 }
 
-func (middleware loggingMiddleware) ListScans(ctx context.Context, teamID string, programID string) ([]*api.Scan, error) {
+func (middleware loggingMiddleware) ListChecktypes(ctx context.Context) ([]*api.Checktype, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListScans", "teamID", mySprintf(teamID), "programID", mySprintf(programID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListChecktypes")
 	}()
 
-	return middleware.next.ListScans(ctx, teamID, programID)
+	return middleware.next.ListChecktypes(ctx)
 }
 
-func (middleware loggingMiddleware) CreateScan(ctx context.Context, scan api.Scan, teamID string) (*api.Scan, error) {
+func (middleware loggingMiddleware) ListPolicyTemplates(ctx context.Context) ([]*api.PolicyTemplate, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateScan", "scan", mySprintf(scan), "teamID", mySprintf(teamID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListPolicyTemplates")
 	}()
 
-	return middleware.next.CreateScan(ctx, scan, teamID)
+	return middleware.next.ListPolicyTemplates(ctx)
 }
 
-func (middleware loggingMiddleware) FindScan(ctx context.Context, scanID string, teamID string) (*api.Scan, error) {
+func (middleware loggingMiddleware) CreatePolicyTemplate(ctx context.Context, template api.PolicyTemplate) (*api.PolicyTemplate, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindScan", "scanID", mySprintf(scanID), "teamID", mySprintf(teamID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreatePolicyTemplate", "template", mySprintf(template))
 	}()
 
-	return middleware.next.FindScan(ctx, scanID, teamID)
+	return middleware.next.CreatePolicyTemplate(ctx, template)
 }
 
-func (middleware loggingMiddleware) AbortScan(ctx context.Context, scanID string, teamID string) (*api.Scan, error) {
+func (middleware loggingMiddleware) FindPolicyTemplate(ctx context.Context, templateID string) (*api.PolicyTemplate, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "AbortScan", "scanID", mySprintf(scanID), "teamID", mySprintf(teamID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindPolicyTemplate", "templateID", mySprintf(templateID))
 	}()
 
-	return middleware.next.AbortScan(ctx, scanID, teamID)
+	return middleware.next.FindPolicyTemplate(ctx, templateID)
 }
 
-func (middleware loggingMiddleware) UpdateScan(ctx context.Context, scan api.Scan) (*api.Scan, error) {
+func (middleware loggingMiddleware) UpdatePolicyTemplate(ctx context.Context, template api.PolicyTemplate) (*api.PolicyTemplate, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateScan", "scan", mySprintf(scan))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdatePolicyTemplate", "template", mySprintf(template))
 	}()
 
-	return middleware.next.UpdateScan(ctx, scan)
+	return middleware.next.UpdatePolicyTemplate(ctx, template)
 }
 
-func (middleware loggingMiddleware) DeleteScan(ctx context.Context, scan api.Scan) error {
+func (middleware loggingMiddleware) DeletePolicyTemplate(ctx context.Context, templateID string) error {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteScan", "scan", mySprintf(scan))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeletePolicyTemplate", "templateID", mySprintf(templateID))
 	}()
 
-	return middleware.next.DeleteScan(ctx, scan)
+	return middleware.next.DeletePolicyTemplate(ctx, templateID)
 }
 
-func (middleware loggingMiddleware) SendDigestReport(ctx context.Context, teamID string, startDate string, endDate string) error {
+func (middleware loggingMiddleware) PolicyDivergence(ctx context.Context, teamID, policyID string) (*api.PolicyDivergence, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SendDigestReport", "teamID", mySprintf(teamID), "startDate", mySprintf(startDate), "endDate", mySprintf(endDate))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "PolicyDivergence", "teamID", mySprintf(teamID), "policyID", mySprintf(policyID))
 	}()
 
-	return middleware.next.SendDigestReport(ctx, teamID, startDate, endDate)
+	return middleware.next.PolicyDivergence(ctx, teamID, policyID)
 }
 
-func (middleware loggingMiddleware) StatsCoverage(ctx context.Context, teamID string) (*api.StatsCoverage, error) {
+func (middleware loggingMiddleware) ListGlobalPolicyEvaluations(ctx context.Context) ([]*api.GlobalPolicyEvaluation, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsCoverage", "teamID", mySprintf(teamID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListGlobalPolicyEvaluations")
 	}()
 
-	return middleware.next.StatsCoverage(ctx, teamID)
+	return middleware.next.ListGlobalPolicyEvaluations(ctx)
 }
 
-func (middleware loggingMiddleware) ListIssues(ctx context.Context, pagination api.Pagination) (*api.IssuesList, error) {
+func (middleware loggingMiddleware) ListScans(ctx context.Context, teamID string, programID string) ([]*api.Scan, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListIssues", "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListScans", "teamID", mySprintf(teamID), "programID", mySprintf(programID))
 	}()
 
-	return middleware.next.ListIssues(ctx, pagination)
+	return middleware.next.ListScans(ctx, teamID, programID)
 }
 
-func (middleware loggingMiddleware) ListFindings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+func (middleware loggingMiddleware) CreateScan(ctx context.Context, scan api.Scan, teamID string) (*api.Scan, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindings", "params", mySprintf(params), "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateScan", "scan", mySprintf(scan), "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.ListFindings(ctx, params, pagination)
+	return middleware.next.CreateScan(ctx, scan, teamID)
 }
 
-func (middleware loggingMiddleware) ListFindingsIssues(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsIssuesList, error) {
+func (middleware loggingMiddleware) FindScan(ctx context.Context, scanID string, teamID string) (*api.Scan, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsIssues", "params", mySprintf(params), "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindScan", "scanID", mySprintf(scanID), "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.ListFindingsIssues(ctx, params, pagination)
+	return middleware.next.FindScan(ctx, scanID, teamID)
 }
 
-func (middleware loggingMiddleware) ListFindingsByIssue(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+func (middleware loggingMiddleware) AbortScan(ctx context.Context, scanID string, teamID string) (*api.Scan, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsByIssue", "params", mySprintf(params), "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "AbortScan", "scanID", mySprintf(scanID), "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.ListFindingsByIssue(ctx, params, pagination)
+	return middleware.next.AbortScan(ctx, scanID, teamID)
 }
 
-func (middleware loggingMiddleware) ListFindingsTargets(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsTargetsList, error) {
+func (middleware loggingMiddleware) UpdateScan(ctx context.Context, scan api.Scan) (*api.Scan, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsTargets", "params", mySprintf(params), "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateScan", "scan", mySprintf(scan))
 	}()
 
-	return middleware.next.ListFindingsTargets(ctx, params, pagination)
+	return middleware.next.UpdateScan(ctx, scan)
 }
 
-func (middleware loggingMiddleware) ListFindingsByTarget(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+func (middleware loggingMiddleware) DeleteScan(ctx context.Context, scan api.Scan) error {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsByTarget", "params", mySprintf(params), "pagination", mySprintf(pagination))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteScan", "scan", mySprintf(scan))
 	}()
 
-	return middleware.next.ListFindingsByTarget(ctx, params, pagination)
+	return middleware.next.DeleteScan(ctx, scan)
 }
 
-func (middleware loggingMiddleware) ListFindingsLabels(ctx context.Context, params api.FindingsParams) (*api.FindingsLabels, error) {
+func (middleware loggingMiddleware) ListScanQueue(ctx context.Context, teamID string) ([]*api.ScanQueueEntry, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsLabels", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListScanQueue", "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.ListFindingsLabels(ctx, params)
+	return middleware.next.ListScanQueue(ctx, teamID)
 }
 
-func (middleware loggingMiddleware) FindFinding(ctx context.Context, findingID string) (*api.Finding, error) {
+func (middleware loggingMiddleware) CancelScanQueueEntry(ctx context.Context, teamID, entryID string) error {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindFinding", "findingID", mySprintf(findingID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CancelScanQueueEntry", "teamID", mySprintf(teamID), "entryID", mySprintf(entryID))
 	}()
 
-	return middleware.next.FindFinding(ctx, findingID)
+	return middleware.next.CancelScanQueueEntry(ctx, teamID, entryID)
 }
 
-func (middleware loggingMiddleware) CreateFindingOverwrite(ctx context.Context, findingOverwrite api.FindingOverwrite) error {
+func (middleware loggingMiddleware) DiffScans(ctx context.Context, teamID string, from *api.Scan, to *api.Scan) (*api.ScanDiff, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingOverwrite", "findingOverwrite", mySprintf(findingOverwrite))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DiffScans", "teamID", mySprintf(teamID), "from", mySprintf(from), "to", mySprintf(to))
 	}()
 
-	return middleware.next.CreateFindingOverwrite(ctx, findingOverwrite)
+	return middleware.next.DiffScans(ctx, teamID, from, to)
 }
 
-func (middleware loggingMiddleware) ListFindingOverwrites(ctx context.Context, findingID string) ([]*api.FindingOverwrite, error) {
+func (middleware loggingMiddleware) DiffProgramScans(ctx context.Context, teamID string, programID string, fromID string, toID string) (*api.ScanDiff, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingOverwrites", "findingID", mySprintf(findingID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DiffProgramScans", "teamID", mySprintf(teamID), "programID", mySprintf(programID), "fromID", mySprintf(fromID), "toID", mySprintf(toID))
 	}()
 
-	return middleware.next.ListFindingOverwrites(ctx, findingID)
+	return middleware.next.DiffProgramScans(ctx, teamID, programID, fromID, toID)
 }
 
-func (middleware loggingMiddleware) StatsMTTR(ctx context.Context, params api.StatsParams) (*api.StatsMTTR, error) {
+func (middleware loggingMiddleware) ListNotificationRules(ctx context.Context, teamID, programID string) ([]*api.NotificationRule, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsMTTR", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListNotificationRules", "teamID", mySprintf(teamID), "programID", mySprintf(programID))
 	}()
 
-	return middleware.next.StatsMTTR(ctx, params)
+	return middleware.next.ListNotificationRules(ctx, teamID, programID)
 }
 
-func (middleware loggingMiddleware) StatsExposure(ctx context.Context, params api.StatsParams) (*api.StatsExposure, error) {
+func (middleware loggingMiddleware) CreateNotificationRule(ctx context.Context, rule api.NotificationRule) (*api.NotificationRule, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsExposure", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateNotificationRule", "rule", mySprintf(rule))
 	}()
 
-	return middleware.next.StatsExposure(ctx, params)
+	return middleware.next.CreateNotificationRule(ctx, rule)
 }
 
-func (middleware loggingMiddleware) StatsCurrentExposure(ctx context.Context, params api.StatsParams) (*api.StatsCurrentExposure, error) {
+func (middleware loggingMiddleware) UpdateNotificationRule(ctx context.Context, rule api.NotificationRule) (*api.NotificationRule, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsCurrentExposure", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateNotificationRule", "rule", mySprintf(rule))
 	}()
 
-	return middleware.next.StatsCurrentExposure(ctx, params)
+	return middleware.next.UpdateNotificationRule(ctx, rule)
 }
 
-func (middleware loggingMiddleware) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+func (middleware loggingMiddleware) DeleteNotificationRule(ctx context.Context, rule api.NotificationRule) error {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsOpen", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteNotificationRule", "rule", mySprintf(rule))
 	}()
 
-	return middleware.next.StatsOpen(ctx, params)
+	return middleware.next.DeleteNotificationRule(ctx, rule)
 }
 
-func (middleware loggingMiddleware) StatsFixed(ctx context.Context, params api.StatsParams) (*api.StatsFixed, error) {
+func (middleware loggingMiddleware) ListReportSubscriptions(ctx context.Context, teamID string) ([]*api.ReportSubscription, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsFixed", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListReportSubscriptions", "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.StatsFixed(ctx, params)
+	return middleware.next.ListReportSubscriptions(ctx, teamID)
 }
 
-func (middleware loggingMiddleware) StatsAssets(ctx context.Context, params api.StatsParams) (*api.StatsAssets, error) {
+func (middleware loggingMiddleware) FindReportSubscription(ctx context.Context, teamID, subscriptionID string) (*api.ReportSubscription, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsAssets", "params", mySprintf(params))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindReportSubscription", "teamID", mySprintf(teamID), "subscriptionID", mySprintf(subscriptionID))
 	}()
 
-	return middleware.next.StatsAssets(ctx, params)
+	return middleware.next.FindReportSubscription(ctx, teamID, subscriptionID)
 }
 
-func (middleware loggingMiddleware) CreateFindingTicket(ctx context.Context, ticket api.FindingTicketCreate) (*api.Ticket, error) {
+func (middleware loggingMiddleware) CreateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingTicket", "ticket", mySprintf(ticket))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateReportSubscription", "subscription", mySprintf(subscription))
 	}()
 
-	return middleware.next.CreateFindingTicket(ctx, ticket)
+	return middleware.next.CreateReportSubscription(ctx, subscription)
 }
 
-func (middleware loggingMiddleware) GetFindingTicket(ctx context.Context, findingID string, teamID string) (*api.Ticket, error) {
+func (middleware loggingMiddleware) UpdateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "GetFindingTicket", "findingID", mySprintf(findingID), "teamID", mySprintf(teamID))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateReportSubscription", "subscription", mySprintf(subscription))
 	}()
 
-	return middleware.next.GetFindingTicket(ctx, findingID, teamID)
+	return middleware.next.UpdateReportSubscription(ctx, subscription)
+}
+
+func (middleware loggingMiddleware) DeleteReportSubscription(ctx context.Context, subscription api.ReportSubscription) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteReportSubscription", "subscription", mySprintf(subscription))
+	}()
+
+	return middleware.next.DeleteReportSubscription(ctx, subscription)
+}
+
+func (middleware loggingMiddleware) SendDigestReport(ctx context.Context, teamID string, startDate string, endDate string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SendDigestReport", "teamID", mySprintf(teamID), "startDate", mySprintf(startDate), "endDate", mySprintf(endDate))
+	}()
+
+	return middleware.next.SendDigestReport(ctx, teamID, startDate, endDate)
+}
+
+func (middleware loggingMiddleware) SendReportSubscription(ctx context.Context, teamID, subscriptionID string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SendReportSubscription", "teamID", mySprintf(teamID), "subscriptionID", mySprintf(subscriptionID))
+	}()
+
+	return middleware.next.SendReportSubscription(ctx, teamID, subscriptionID)
+}
+
+func (middleware loggingMiddleware) StatsCoverage(ctx context.Context, teamID string) (*api.StatsCoverage, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsCoverage", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.StatsCoverage(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) ListIssues(ctx context.Context, pagination api.Pagination) (*api.IssuesList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListIssues", "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListIssues(ctx, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindings", "params", mySprintf(params), "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListFindings(ctx, params, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindingsIssues(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsIssuesList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsIssues", "params", mySprintf(params), "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListFindingsIssues(ctx, params, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindingsByIssue(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsByIssue", "params", mySprintf(params), "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListFindingsByIssue(ctx, params, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindingsTargets(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsTargetsList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsTargets", "params", mySprintf(params), "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListFindingsTargets(ctx, params, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindingsByTarget(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsByTarget", "params", mySprintf(params), "pagination", mySprintf(pagination))
+	}()
+
+	return middleware.next.ListFindingsByTarget(ctx, params, pagination)
+}
+
+func (middleware loggingMiddleware) ListFindingsLabels(ctx context.Context, params api.FindingsParams) (*api.FindingsLabels, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsLabels", "params", mySprintf(params))
+	}()
+
+	return middleware.next.ListFindingsLabels(ctx, params)
+}
+
+func (middleware loggingMiddleware) FindFinding(ctx context.Context, teamID string, findingID string) (*api.Finding, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindFinding", "teamID", mySprintf(teamID), "findingID", mySprintf(findingID))
+	}()
+
+	return middleware.next.FindFinding(ctx, teamID, findingID)
+}
+
+func (middleware loggingMiddleware) CreateFindingOverwrite(ctx context.Context, findingOverwrite api.FindingOverwrite) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingOverwrite", "findingOverwrite", mySprintf(findingOverwrite))
+	}()
+
+	return middleware.next.CreateFindingOverwrite(ctx, findingOverwrite)
+}
+
+func (middleware loggingMiddleware) ListFindingOverwrites(ctx context.Context, findingID string) ([]*api.FindingOverwrite, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingOverwrites", "findingID", mySprintf(findingID))
+	}()
+
+	return middleware.next.ListFindingOverwrites(ctx, findingID)
+}
+
+func (middleware loggingMiddleware) CreateFindingOverwritesAsync(ctx context.Context, bulk api.BulkFindingOverwrite) (*api.Job, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingOverwritesAsync", "bulk", mySprintf(bulk))
+	}()
+
+	return middleware.next.CreateFindingOverwritesAsync(ctx, bulk)
+}
+
+func (middleware loggingMiddleware) CreateFindingOverwrites(ctx context.Context, bulk api.BulkFindingOverwrite) ([]api.BulkFindingOverwriteResult, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingOverwrites", "bulk", mySprintf(bulk))
+	}()
+
+	return middleware.next.CreateFindingOverwrites(ctx, bulk)
+}
+
+func (middleware loggingMiddleware) ExportFindings(ctx context.Context, export api.FindingsExport) (api.FindingsExportWriter, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ExportFindings", "export", mySprintf(export))
+	}()
+
+	return middleware.next.ExportFindings(ctx, export)
+}
+
+func (middleware loggingMiddleware) CreateFindingsExportAsync(ctx context.Context, export api.FindingsExport) (*api.Job, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingsExportAsync", "export", mySprintf(export))
+	}()
+
+	return middleware.next.CreateFindingsExportAsync(ctx, export)
+}
+
+func (middleware loggingMiddleware) GenerateFindingsExport(ctx context.Context, export api.FindingsExport) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "GenerateFindingsExport", "export", mySprintf(export))
+	}()
+
+	return middleware.next.GenerateFindingsExport(ctx, export)
+}
+
+func (middleware loggingMiddleware) FindFindingsExport(ctx context.Context, teamID, exportID string) (*api.FindingsExport, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindFindingsExport", "teamID", mySprintf(teamID), "exportID", mySprintf(exportID))
+	}()
+
+	return middleware.next.FindFindingsExport(ctx, teamID, exportID)
+}
+
+func (middleware loggingMiddleware) WriteFindingsExport(ctx context.Context, export api.FindingsExport, w io.Writer) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "WriteFindingsExport", "export", mySprintf(export))
+	}()
+
+	return middleware.next.WriteFindingsExport(ctx, export, w)
+}
+
+func (middleware loggingMiddleware) ListFindingComments(ctx context.Context, teamID, findingID string) ([]*api.FindingComment, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingComments", "teamID", mySprintf(teamID), "findingID", mySprintf(findingID))
+	}()
+
+	return middleware.next.ListFindingComments(ctx, teamID, findingID)
+}
+
+func (middleware loggingMiddleware) CreateFindingComment(ctx context.Context, comment api.FindingComment) (*api.FindingComment, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingComment", "comment", mySprintf(comment))
+	}()
+
+	return middleware.next.CreateFindingComment(ctx, comment)
+}
+
+func (middleware loggingMiddleware) UpdateFindingComment(ctx context.Context, comment api.FindingComment) (*api.FindingComment, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateFindingComment", "comment", mySprintf(comment))
+	}()
+
+	return middleware.next.UpdateFindingComment(ctx, comment)
+}
+
+func (middleware loggingMiddleware) DeleteFindingComment(ctx context.Context, comment api.FindingComment) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteFindingComment", "comment", mySprintf(comment))
+	}()
+
+	return middleware.next.DeleteFindingComment(ctx, comment)
+}
+
+func (middleware loggingMiddleware) RequestRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) (*api.RiskAcceptance, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "RequestRiskAcceptance", "acceptance", mySprintf(acceptance))
+	}()
+
+	return middleware.next.RequestRiskAcceptance(ctx, acceptance)
+}
+
+func (middleware loggingMiddleware) ListRiskAcceptances(ctx context.Context, teamID, findingID, status string) ([]*api.RiskAcceptance, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListRiskAcceptances", "teamID", mySprintf(teamID), "findingID", mySprintf(findingID), "status", mySprintf(status))
+	}()
+
+	return middleware.next.ListRiskAcceptances(ctx, teamID, findingID, status)
+}
+
+func (middleware loggingMiddleware) FindRiskAcceptance(ctx context.Context, teamID, acceptanceID string) (*api.RiskAcceptance, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindRiskAcceptance", "teamID", mySprintf(teamID), "acceptanceID", mySprintf(acceptanceID))
+	}()
+
+	return middleware.next.FindRiskAcceptance(ctx, teamID, acceptanceID)
+}
+
+func (middleware loggingMiddleware) ApproveRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*api.RiskAcceptance, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ApproveRiskAcceptance", "teamID", mySprintf(teamID), "acceptanceID", mySprintf(acceptanceID), "notes", mySprintf(notes))
+	}()
+
+	return middleware.next.ApproveRiskAcceptance(ctx, teamID, acceptanceID, notes)
+}
+
+func (middleware loggingMiddleware) RejectRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*api.RiskAcceptance, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "RejectRiskAcceptance", "teamID", mySprintf(teamID), "acceptanceID", mySprintf(acceptanceID), "notes", mySprintf(notes))
+	}()
+
+	return middleware.next.RejectRiskAcceptance(ctx, teamID, acceptanceID, notes)
+}
+
+func (middleware loggingMiddleware) ExpireRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ExpireRiskAcceptance", "acceptance", mySprintf(acceptance))
+	}()
+
+	return middleware.next.ExpireRiskAcceptance(ctx, acceptance)
+}
+
+func (middleware loggingMiddleware) ListSLAPolicies(ctx context.Context, teamID string) ([]*api.SLAPolicy, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListSLAPolicies", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.ListSLAPolicies(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) CreateSLAPolicy(ctx context.Context, policy api.SLAPolicy) (*api.SLAPolicy, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateSLAPolicy", "policy", mySprintf(policy))
+	}()
+
+	return middleware.next.CreateSLAPolicy(ctx, policy)
+}
+
+func (middleware loggingMiddleware) UpdateSLAPolicy(ctx context.Context, policy api.SLAPolicy) (*api.SLAPolicy, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateSLAPolicy", "policy", mySprintf(policy))
+	}()
+
+	return middleware.next.UpdateSLAPolicy(ctx, policy)
+}
+
+func (middleware loggingMiddleware) DeleteSLAPolicy(ctx context.Context, teamID, policyID string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteSLAPolicy", "teamID", mySprintf(teamID), "policyID", mySprintf(policyID))
+	}()
+
+	return middleware.next.DeleteSLAPolicy(ctx, teamID, policyID)
+}
+
+func (middleware loggingMiddleware) ListFindingsSLA(ctx context.Context, teamID, status string) ([]*api.FindingSLA, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingsSLA", "teamID", mySprintf(teamID), "status", mySprintf(status))
+	}()
+
+	return middleware.next.ListFindingsSLA(ctx, teamID, status)
+}
+
+func (middleware loggingMiddleware) NotifySLABreaches(ctx context.Context, teamID string) (int, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "NotifySLABreaches", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.NotifySLABreaches(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) VerifyFinding(ctx context.Context, teamID string, findingID string, user api.User) (*api.FindingVerification, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "VerifyFinding", "teamID", mySprintf(teamID), "findingID", mySprintf(findingID), "user", mySprintf(user))
+	}()
+
+	return middleware.next.VerifyFinding(ctx, teamID, findingID, user)
+}
+
+func (middleware loggingMiddleware) ListFindingVerifications(ctx context.Context, teamID string, findingID string) ([]*api.FindingVerification, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingVerifications", "teamID", mySprintf(teamID), "findingID", mySprintf(findingID))
+	}()
+
+	return middleware.next.ListFindingVerifications(ctx, teamID, findingID)
+}
+
+func (middleware loggingMiddleware) StatsMTTR(ctx context.Context, params api.StatsParams) (*api.StatsMTTR, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsMTTR", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsMTTR(ctx, params)
+}
+
+func (middleware loggingMiddleware) StatsExposure(ctx context.Context, params api.StatsParams) (*api.StatsExposure, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsExposure", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsExposure(ctx, params)
+}
+
+func (middleware loggingMiddleware) StatsCurrentExposure(ctx context.Context, params api.StatsParams) (*api.StatsCurrentExposure, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsCurrentExposure", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsCurrentExposure(ctx, params)
+}
+
+func (middleware loggingMiddleware) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsOpen", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsOpen(ctx, params)
+}
+
+func (middleware loggingMiddleware) StatsFixed(ctx context.Context, params api.StatsParams) (*api.StatsFixed, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsFixed", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsFixed(ctx, params)
+}
+
+func (middleware loggingMiddleware) StatsAssets(ctx context.Context, params api.StatsParams) (*api.StatsAssets, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsAssets", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsAssets(ctx, params)
+}
+
+func (middleware loggingMiddleware) SnapshotTeamStats(ctx context.Context, teamID, date string) error {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SnapshotTeamStats", "teamID", mySprintf(teamID), "date", mySprintf(date))
+	}()
+
+	return middleware.next.SnapshotTeamStats(ctx, teamID, date)
+}
+
+func (middleware loggingMiddleware) StatsTrend(ctx context.Context, params api.StatsTrendParams) (*api.StatsTrend, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "StatsTrend", "params", mySprintf(params))
+	}()
+
+	return middleware.next.StatsTrend(ctx, params)
+}
+
+func (middleware loggingMiddleware) CreateFindingTicket(ctx context.Context, ticket api.FindingTicketCreate) (*api.Ticket, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateFindingTicket", "ticket", mySprintf(ticket))
+	}()
+
+	return middleware.next.CreateFindingTicket(ctx, ticket)
+}
+
+func (middleware loggingMiddleware) GetFindingTicket(ctx context.Context, findingID string, teamID string) (*api.Ticket, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "GetFindingTicket", "findingID", mySprintf(findingID), "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.GetFindingTicket(ctx, findingID, teamID)
+}
+
+func (middleware loggingMiddleware) IsATeamOnboardedInVulcanTracker(ctx context.Context, team api.Team) bool {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "IsATeamOnboardedInVulcanTracker", "team", mySprintf(team))
+	}()
+
+	return middleware.next.IsATeamOnboardedInVulcanTracker(ctx, team)
+}
+
+func (middleware loggingMiddleware) UpdateTeamTracker(ctx context.Context, teamID string, enabled bool) (*api.Team, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateTeamTracker", "teamID", mySprintf(teamID), "enabled", mySprintf(enabled))
+	}()
+
+	return middleware.next.UpdateTeamTracker(ctx, teamID, enabled)
+}
+
+func (middleware loggingMiddleware) ListFindingTickets(ctx context.Context, teamID string) ([]*api.FindingTicket, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "ListFindingTickets", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.ListFindingTickets(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) CreateIssueTicketsAsync(ctx context.Context, bulk api.BulkFindingTicketCreate) (*api.Job, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateIssueTicketsAsync", "bulk", mySprintf(bulk))
+	}()
+
+	return middleware.next.CreateIssueTicketsAsync(ctx, bulk)
+}
+
+func (middleware loggingMiddleware) CreateIssueTickets(ctx context.Context, bulk api.BulkFindingTicketCreate) ([]api.BulkFindingTicketResult, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "CreateIssueTickets", "bulk", mySprintf(bulk))
+	}()
+
+	return middleware.next.CreateIssueTickets(ctx, bulk)
+}
+
+func (middleware loggingMiddleware) SyncFindingTickets(ctx context.Context, teamID string) (int, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "SyncFindingTickets", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.SyncFindingTickets(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) FindTeamTicketProvider(ctx context.Context, teamID string) (*api.TicketProvider, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "FindTeamTicketProvider", "teamID", mySprintf(teamID))
+	}()
+
+	return middleware.next.FindTeamTicketProvider(ctx, teamID)
+}
+
+func (middleware loggingMiddleware) UpdateTeamTicketProvider(ctx context.Context, provider api.TicketProvider) (*api.TicketProvider, error) {
+
+	defer func() {
+		XRequestID := ""
+		if ctx != nil {
+			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
+		}
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "UpdateTeamTicketProvider", "provider", mySprintf(provider))
+	}()
+
+	return middleware.next.UpdateTeamTicketProvider(ctx, provider)
 }
 
-func (middleware loggingMiddleware) IsATeamOnboardedInVulcanTracker(ctx context.Context, teamID string, onboardedTeams []string) bool {
+func (middleware loggingMiddleware) DeleteTeamTicketProvider(ctx context.Context, teamID string) error {
 
 	defer func() {
 		XRequestID := ""
 		if ctx != nil {
 			XRequestID, _ = ctx.Value(kithttp.ContextKeyRequestXRequestID).(string)
 		}
-		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "IsATeamOnboardedInVulcanTracker", "teamID", mySprintf(teamID), "onboardedTeams", mySprintf(onboardedTeams))
+		_ = level.Debug(middleware.logger).Log("X-Request-ID", XRequestID, "service", "DeleteTeamTicketProvider", "teamID", mySprintf(teamID))
 	}()
 
-	return middleware.next.IsATeamOnboardedInVulcanTracker(ctx, teamID, onboardedTeams)
+	return middleware.next.DeleteTeamTicketProvider(ctx, teamID)
 }
@@ -7,6 +7,7 @@ package global
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	metrics "github.com/adevinta/vulcan-metrics-client"
 	"github.com/go-kit/kit/log"
@@ -42,22 +43,44 @@ type MetadataStore interface {
 	DeleteProgramMetadata(program string) error
 }
 
+// reloader is implemented by the global stores whose entities can change
+// while the API is running.
+type reloader interface {
+	OnReload(f func(changed, removed []string))
+}
+
+// policyConfigStore is implemented by the global stores that reload the
+// global policy config while the API is running.
+type policyConfigStore interface {
+	PolicyConfig() global.GlobalPolicyConfig
+}
+
+// policyEvaluator is implemented by the global stores that keep track of the
+// changes in the checktypes of the global policies.
+type policyEvaluator interface {
+	PolicyEvaluations() ([]*api.GlobalPolicyEvaluation, error)
+}
+
 type globalEntities struct {
 	api.VulcanitoService
 	store              GlobalStore
 	metadata           MetadataStore
 	logger             log.Logger
 	scheduler          *globalScheduler
-	scanEngineConfig   scanengine.Config
+	scanEngineClient   scanengine.Client
 	metricsClient      metrics.Client
 	globalPolicyConfig global.GlobalPolicyConfig
+
+	// reloadMu prevents the changes of the global programs of overlapping
+	// reloads from being applied at the same time.
+	reloadMu sync.Mutex
 }
 
 // NewEntities returns a middleware to inject global entities functionality
 // in the vulcanito service.
 func NewEntities(l log.Logger, store GlobalStore, metadataStore MetadataStore,
 	scanScheduler schedule.ScanScheduler, reportScheduler schedule.ReportScheduler,
-	sconfig scanengine.Config, metricsClient metrics.Client, gpc global.GlobalPolicyConfig) Middleware {
+	scanEngineClient scanengine.Client, metricsClient metrics.Client, gpc global.GlobalPolicyConfig) Middleware {
 
 	return func(next api.VulcanitoService) api.VulcanitoService {
 		gscheduler := &globalScheduler{
@@ -70,7 +93,7 @@ func NewEntities(l log.Logger, store GlobalStore, metadataStore MetadataStore,
 			metadata:           metadataStore,
 			logger:             l,
 			VulcanitoService:   next,
-			scanEngineConfig:   sconfig,
+			scanEngineClient:   scanEngineClient,
 			metricsClient:      metricsClient,
 			globalPolicyConfig: gpc,
 		}
@@ -82,10 +105,26 @@ func NewEntities(l log.Logger, store GlobalStore, metadataStore MetadataStore,
 		go g.scheduleGlobalProgramDefaults()
 		go g.scheduleGlobalReportDefaults()
 
+		if r, ok := store.(reloader); ok {
+			r.OnReload(func(changed, removed []string) {
+				go g.globalProgramsReloaded(changed, removed)
+			})
+		}
+
 		return g
 	}
 }
 
+// policyConfig returns the current global policy config. The config is read
+// from the store if it can be reloaded, otherwise the config given when
+// creating the middleware is used.
+func (e *globalEntities) policyConfig() global.GlobalPolicyConfig {
+	if s, ok := e.store.(policyConfigStore); ok {
+		return s.PolicyConfig()
+	}
+	return e.globalPolicyConfig
+}
+
 // globalScheduler is used to introduce specific logic to deal with the
 // scheduler for global entities.
 type globalScheduler struct {
@@ -94,9 +133,20 @@ type globalScheduler struct {
 }
 
 func (e *globalEntities) scheduleGlobalProgramDefaults() {
+	var ids []string
+	for id := range e.store.Programs() {
+		ids = append(ids, id)
+	}
+	e.scheduleGlobalPrograms(ids)
+}
+
+// scheduleGlobalPrograms ensures the teams have schedules for the global
+// programs with the given IDs that have a default cron.
+func (e *globalEntities) scheduleGlobalPrograms(ids []string) {
 	globalPrograms := e.store.Programs()
-	for name, p := range globalPrograms {
-		if p.DefaultMetadata.Cron == "" {
+	for _, name := range ids {
+		p, ok := globalPrograms[name]
+		if !ok || p.DefaultMetadata.Cron == "" {
 			continue
 		}
 		teams, err := e.VulcanitoService.ListTeams(context.Background())
@@ -121,6 +171,32 @@ func (e *globalEntities) scheduleGlobalProgramDefaults() {
 	}
 }
 
+// globalProgramsReloaded ensures the teams have schedules for the global
+// programs that changed in a reload, and removes the schedules of the ones
+// that do not exist anymore. The metadata of the teams for the removed
+// programs is kept, so their settings are restored if the programs are
+// defined again.
+func (e *globalEntities) globalProgramsReloaded(changed, removed []string) {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+	e.scheduleGlobalPrograms(changed)
+	if len(removed) == 0 {
+		return
+	}
+	teams, err := e.VulcanitoService.ListTeams(context.Background())
+	if err != nil {
+		_ = e.logger.Log("error getting teams for removing global programs schedules", err)
+		return
+	}
+	for _, programID := range removed {
+		for _, team := range teams {
+			if err := e.scheduler.DeleteSchedule(team.ID, programID); err != nil {
+				_ = e.logger.Log("DeleteGlobalProgramScheduleError", err.Error(), "program", programID, "team", team.ID)
+			}
+		}
+	}
+}
+
 func (e *globalEntities) scheduleGlobalReportDefaults() {
 	globalReports := e.store.Reports()
 	for _, r := range globalReports {
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	global "github.com/adevinta/vulcan-api/pkg/api/store/global"
+	"github.com/adevinta/vulcan-api/pkg/schedule"
+)
+
+// recordingScanScheduler records the scan schedules created and deleted.
+type recordingScanScheduler struct {
+	MockScanScheduler
+	created []string
+	deleted []string
+}
+
+func (s *recordingScanScheduler) BulkCreateScanSchedules(schedules []schedule.ScanBulkSchedule) error {
+	for _, sch := range schedules {
+		s.created = append(s.created, sch.ProgramID+" "+sch.Str)
+	}
+	return nil
+}
+
+func (s *recordingScanScheduler) DeleteScanSchedule(programID string) error {
+	s.deleted = append(s.deleted, programID)
+	return nil
+}
+
+// recordingMetadataStore has no metadata of the teams and records the
+// metadata deleted.
+type recordingMetadataStore struct {
+	MockMetadataStore
+	deleted []string
+}
+
+func (m *recordingMetadataStore) FindGlobalProgramMetadata(programID string, teamID string) (*api.GlobalProgramsMetadata, error) {
+	return nil, errors.NotFound("metadata not found")
+}
+
+func (m *recordingMetadataStore) DeleteProgramMetadata(program string) error {
+	m.deleted = append(m.deleted, program)
+	return nil
+}
+
+type teamsService struct {
+	api.VulcanitoService
+}
+
+func (s teamsService) ListTeams(ctx context.Context) ([]*api.Team, error) {
+	return []*api.Team{{ID: "t1"}}, nil
+}
+
+func TestGlobalEntities_GlobalProgramsReloaded(t *testing.T) {
+	scheduler := &recordingScanScheduler{}
+	metadata := &recordingMetadataStore{}
+	e := &globalEntities{
+		VulcanitoService: teamsService{},
+		store: &MockGlobalStore{programsRepository: map[string]global.Program{
+			"changed":   {ID: "changed", DefaultMetadata: api.GlobalProgramsMetadata{Cron: "0 3 * * *"}},
+			"unchanged": {ID: "unchanged", DefaultMetadata: api.GlobalProgramsMetadata{Cron: "0 4 * * *"}},
+		}},
+		metadata:  metadata,
+		logger:    log.NewNopLogger(),
+		scheduler: &globalScheduler{ScanScheduler: scheduler},
+	}
+
+	e.globalProgramsReloaded([]string{"changed"}, []string{"removed"})
+
+	// Only the programs that changed are scheduled again.
+	if diff := cmp.Diff([]string{"t1@changed 0 3 * * *"}, scheduler.created); diff != "" {
+		t.Errorf("created schedules mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"t1@removed"}, scheduler.deleted); diff != "" {
+		t.Errorf("deleted schedules mismatch (-want +got):\n%s", diff)
+	}
+	// The settings of the teams for the removed programs are kept.
+	if len(metadata.deleted) != 0 {
+		t.Errorf("got metadata deleted for programs %v, want none", metadata.deleted)
+	}
+}
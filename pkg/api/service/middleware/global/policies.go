@@ -23,7 +23,7 @@ func (e *globalEntities) ListPolicies(ctx context.Context, teamID string) ([]*ap
 	}
 	globalPolicies := e.store.Policies()
 	for n, p := range globalPolicies {
-		checktypes, err := p.Eval(ctx, e.globalPolicyConfig)
+		checktypes, err := p.Eval(ctx, e.policyConfig())
 		if err != nil {
 			return nil, err
 		}
@@ -43,7 +43,7 @@ func (e *globalEntities) FindPolicy(ctx context.Context, policyID string) (*api.
 	if !ok {
 		return e.VulcanitoService.FindPolicy(ctx, policyID)
 	}
-	return globalPolicyToPolicy(ctx, e.globalPolicyConfig, p)
+	return globalPolicyToPolicy(ctx, e.policyConfig(), p)
 }
 
 func (e *globalEntities) UpdatePolicy(ctx context.Context, policy api.Policy) (*api.Policy, error) {
@@ -60,12 +60,19 @@ func (e *globalEntities) DeletePolicy(ctx context.Context, policy api.Policy) er
 	return e.VulcanitoService.DeletePolicy(ctx, policy)
 }
 
+func (e *globalEntities) PolicyDivergence(ctx context.Context, teamID, policyID string) (*api.PolicyDivergence, error) {
+	if _, ok := e.store.Policies()[policyID]; ok {
+		return nil, errors.Validation("global policies do not extend templates")
+	}
+	return e.VulcanitoService.PolicyDivergence(ctx, teamID, policyID)
+}
+
 func (e *globalEntities) ListChecktypeSetting(ctx context.Context, policyID string) ([]*api.ChecktypeSetting, error) {
 	p, ok := e.store.Policies()[policyID]
 	if !ok {
 		return e.VulcanitoService.ListChecktypeSetting(ctx, policyID)
 	}
-	return p.Eval(ctx, e.globalPolicyConfig)
+	return p.Eval(ctx, e.policyConfig())
 }
 
 func (e *globalEntities) FindChecktypeSetting(ctx context.Context, policyID, checktypeSettingID string) (*api.ChecktypeSetting, error) {
@@ -74,7 +81,7 @@ func (e *globalEntities) FindChecktypeSetting(ctx context.Context, policyID, che
 		return e.VulcanitoService.FindChecktypeSetting(ctx, policyID, checktypeSettingID)
 	}
 
-	checktypes, err := p.Eval(ctx, e.globalPolicyConfig)
+	checktypes, err := p.Eval(ctx, e.policyConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +112,17 @@ func (e *globalEntities) UpdateChecktypeSetting(ctx context.Context, checktypeSe
 	return e.VulcanitoService.UpdateChecktypeSetting(ctx, checktypeSetting)
 }
 
+// ListGlobalPolicyEvaluations returns the checktypes of each global policy
+// in its last evaluation, and the changes from the previous different
+// evaluation of the policy. The policies are evaluated when the global
+// entities are reloaded.
+func (e *globalEntities) ListGlobalPolicyEvaluations(ctx context.Context) ([]*api.GlobalPolicyEvaluation, error) {
+	if s, ok := e.store.(policyEvaluator); ok {
+		return s.PolicyEvaluations()
+	}
+	return nil, errors.Default("the global policies can not be evaluated")
+}
+
 func globalPolicyToPolicy(ctx context.Context, gpc global.GlobalPolicyConfig, p global.Policy) (*api.Policy, error) {
 	settings, err := p.Eval(ctx, gpc)
 	if err != nil {
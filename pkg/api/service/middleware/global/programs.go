@@ -6,11 +6,13 @@ package global
 
 import (
 	"context"
+	errs "errors"
 	"fmt"
 
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 	global "github.com/adevinta/vulcan-api/pkg/api/store/global"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
 )
 
 const (
@@ -41,7 +43,10 @@ func (e *globalEntities) ListPrograms(ctx context.Context, teamID string) ([]*ap
 	return programs, nil
 }
 
-func (e *globalEntities) findPoliciesGroups(ctx context.Context, teamID string, policiesGroups []global.PolicyGroup) ([]*api.ProgramsGroupsPolicies, error) {
+// findPoliciesGroups returns the groups and policies of a global program. The
+// non scannable assets of the shadowed team groups are removed unless
+// keepNonScannable is true.
+func (e *globalEntities) findPoliciesGroups(ctx context.Context, teamID string, policiesGroups []global.PolicyGroup, keepNonScannable bool) ([]*api.ProgramsGroupsPolicies, error) {
 	res := []*api.ProgramsGroupsPolicies{}
 	for _, pg := range policiesGroups {
 		p, ok := e.store.Policies()[pg.Policy]
@@ -52,7 +57,7 @@ func (e *globalEntities) findPoliciesGroups(ctx context.Context, teamID string,
 		if !ok {
 			return nil, errors.Default(fmt.Sprintf("no global group with name %s defined", pg.Group))
 		}
-		policy, err := globalPolicyToPolicy(ctx, e.globalPolicyConfig, p)
+		policy, err := globalPolicyToPolicy(ctx, e.policyConfig(), p)
 		if err != nil {
 			return nil, err
 		}
@@ -72,7 +77,7 @@ func (e *globalEntities) findPoliciesGroups(ctx context.Context, teamID string,
 			}
 			return nil, err
 		}
-		if g.ShadowTeamGroup() != "" {
+		if g.ShadowTeamGroup() != "" && !keepNonScannable {
 			group.AssetGroup = filterNonScannableAssets(group.AssetGroup)
 		}
 		groupPolicy := &api.ProgramsGroupsPolicies{
@@ -91,6 +96,10 @@ func (e *globalEntities) FindProgram(ctx context.Context, programID string, team
 	if !ok {
 		return e.VulcanitoService.FindProgram(ctx, programID, teamID)
 	}
+	return e.findProgram(ctx, p, programID, teamID, false)
+}
+
+func (e *globalEntities) findProgram(ctx context.Context, p global.Program, programID string, teamID string, keepNonScannable bool) (*api.Program, error) {
 	metadata, err := e.metadata.FindGlobalProgramMetadata(programID, teamID)
 	if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
 		return nil, err
@@ -124,7 +133,7 @@ func (e *globalEntities) FindProgram(ctx context.Context, programID string, team
 		Cron:     cron,
 		Global:   &global,
 	}
-	policyGroups, err := e.findPoliciesGroups(ctx, teamID, p.Policies)
+	policyGroups, err := e.findPoliciesGroups(ctx, teamID, p.Policies, keepNonScannable)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +141,52 @@ func (e *globalEntities) FindProgram(ctx context.Context, programID string, team
 	return program, nil
 }
 
+// ProgramPlan returns the plan of a global program including the non
+// scannable assets of the shadowed team groups, so they are reported as
+// excluded.
+func (e *globalEntities) ProgramPlan(ctx context.Context, programID string, teamID string) (*api.ProgramPlan, error) {
+	p, ok := e.store.Programs()[programID]
+	if !ok {
+		return e.VulcanitoService.ProgramPlan(ctx, programID, teamID)
+	}
+	program, err := e.findProgram(ctx, p, programID, teamID, true)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := e.VulcanitoService.ListAssetChecktypeSettings(ctx, teamID, "")
+	if err != nil {
+		return nil, err
+	}
+	program.SetAssetChecktypeSettings(settings)
+	checktypes, err := e.VulcanitoService.ListChecktypes(ctx)
+	if err != nil {
+		checktypes = nil
+	}
+	plan, err := scanengine.CreateScanPlan(*program, checktypes)
+	if err != nil {
+		return nil, planError(err)
+	}
+	return plan, nil
+}
+
+// planError returns the error to report when the plan of a global program
+// can not be created. The errors that already have a kind are returned as
+// they are, and the errors of the scan engine are mapped to the kind the
+// VulcanitoService uses for them.
+func planError(err error) error {
+	var kindErr *errors.ErrorStack
+	if errs.As(err, &kindErr) {
+		return err
+	}
+	if errs.Is(err, scanengine.ErrProgramWithoutPolicyGroups) {
+		return errors.Validation(err)
+	}
+	if errs.Is(err, scanengine.ErrNotFound) {
+		return errors.NotFound(err)
+	}
+	return errors.Default(err)
+}
+
 func (e *globalEntities) UpdateProgram(ctx context.Context, program api.Program, teamID string) (*api.Program, error) {
 	gp, ok := e.store.Programs()[program.ID]
 	if !ok {
@@ -215,16 +270,6 @@ func (e *globalEntities) DeleteSchedule(ctx context.Context, programID string, t
 }
 
 func filterNonScannableAssets(ag []*api.AssetGroup) []*api.AssetGroup {
-	filtered := []*api.AssetGroup{}
-	for _, a := range ag {
-		a := a
-		if a.Asset == nil {
-			continue
-		}
-		if a.Asset.Scannable == nil || !*a.Asset.Scannable {
-			continue
-		}
-		filtered = append(filtered, a)
-	}
+	filtered, _ := scanengine.ScannableAssets(ag)
 	return filtered
 }
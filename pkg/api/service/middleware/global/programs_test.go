@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-kit/kit/log"
 
+	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 	global "github.com/adevinta/vulcan-api/pkg/api/store/global"
 	"github.com/adevinta/vulcan-api/pkg/jwt"
@@ -130,7 +131,7 @@ func Test_vulcanitoService_UpdateProgram(t *testing.T) {
 		db               api.VulcanitoStore
 		logger           log.Logger
 		programScheduler schedule.ScanScheduler
-		scanEngineConfig scanengine.Config
+		scanEngineClient scanengine.Client
 	}
 	type args struct {
 		ctx     context.Context
@@ -244,3 +245,24 @@ func Test_vulcanitoService_UpdateProgram(t *testing.T) {
 		})
 	}
 }
+
+func Test_planError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{name: "Validation", err: errors.Validation("invalid policy"), wantKind: errors.ErrValidation},
+		{name: "NotFound", err: errors.NotFound("policy not found"), wantKind: errors.ErrNotFound},
+		{name: "WithoutPolicyGroups", err: fmt.Errorf("%w: program p1", scanengine.ErrProgramWithoutPolicyGroups), wantKind: errors.ErrValidation},
+		{name: "ScanEngineNotFound", err: fmt.Errorf("%w: group g1", scanengine.ErrNotFound), wantKind: errors.ErrNotFound},
+		{name: "Other", err: fmt.Errorf("unexpected"), wantKind: errors.ErrInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := planError(tt.err); !errors.IsKind(got, tt.wantKind) {
+				t.Errorf("planError() = %v, want kind %v", got, tt.wantKind)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// CreateReportSubscription ensures the report of the subscription is a global
+// report.
+func (e *globalEntities) CreateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	if err := e.checkGlobalReport(subscription.ReportID); err != nil {
+		return nil, err
+	}
+	return e.VulcanitoService.CreateReportSubscription(ctx, subscription)
+}
+
+// UpdateReportSubscription ensures the report of the subscription, if
+// modified, is a global report.
+func (e *globalEntities) UpdateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	if subscription.ReportID != "" {
+		if err := e.checkGlobalReport(subscription.ReportID); err != nil {
+			return nil, err
+		}
+	}
+	return e.VulcanitoService.UpdateReportSubscription(ctx, subscription)
+}
+
+func (e *globalEntities) checkGlobalReport(reportID string) error {
+	if _, ok := e.store.Reports()[reportID]; !ok {
+		return errors.Validation(fmt.Sprintf("unknown report %s", reportID))
+	}
+	return nil
+}
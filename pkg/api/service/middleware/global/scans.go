@@ -7,11 +7,11 @@ package global
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/api/service"
 	"github.com/adevinta/vulcan-api/pkg/scanengine"
 	metrics "github.com/adevinta/vulcan-metrics-client"
 	scanengineData "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
@@ -44,13 +44,17 @@ func (e *globalEntities) CreateScan(ctx context.Context, scan api.Scan, teamID s
 	if err != nil {
 		return nil, errors.Default(err)
 	}
+	settings, err := e.VulcanitoService.ListAssetChecktypeSettings(ctx, teamID, "")
+	if err != nil {
+		return nil, err
+	}
+	program.SetAssetChecktypeSettings(settings)
 
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, e.scanEngineConfig)
 	req, err := scanengine.CreateScanRequest(*program, scan.ScheduledTime, externalID, scan.RequestedBy, team.Tag)
 	if err != nil {
 		return nil, err
 	}
-	scanResponse, err := scanengine.Create(req)
+	scanResponse, err := e.scanEngineClient.Create(ctx, req)
 	if err != nil {
 		return nil, errors.Default(err)
 	}
@@ -109,8 +113,7 @@ func (e *globalEntities) ListScans(ctx context.Context, teamID string, programID
 	}
 	id := encodeGlobalProgramRequestID(programID, teamID)
 	scans := []*api.Scan{}
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, e.scanEngineConfig)
-	response, err := scanengine.GetScans(id)
+	response, err := e.scanEngineClient.GetScans(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -145,9 +148,23 @@ func (e *globalEntities) FindScan(ctx context.Context, scanID, teamID string) (*
 	return scan, nil
 }
 
+// DiffProgramScans finds the scans of global programs in the middleware, as
+// the VulcanitoService does not know them, and then lets the VulcanitoService
+// compare them.
+func (e *globalEntities) DiffProgramScans(ctx context.Context, teamID, programID, fromID, toID string) (*api.ScanDiff, error) {
+	_, ok := e.store.Programs()[programID]
+	if !ok {
+		return e.VulcanitoService.DiffProgramScans(ctx, teamID, programID, fromID, toID)
+	}
+	from, to, err := service.ScansToDiff(ctx, e, teamID, programID, fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	return e.VulcanitoService.DiffScans(ctx, teamID, from, to)
+}
+
 func (e *globalEntities) findScan(ctx context.Context, scanID, teamID string) (*api.Scan, error) {
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, e.scanEngineConfig)
-	scanResponse, err := scanengine.Get(scanID)
+	scanResponse, err := e.scanEngineClient.Get(ctx, scanID)
 	if err != nil {
 		return nil, err
 	}
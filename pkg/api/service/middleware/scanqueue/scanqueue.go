@@ -0,0 +1,457 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanqueue
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+)
+
+const (
+	defaultPollInterval = 30
+
+	// scanStatusRunning is the status reported by the scan engine for the
+	// scans that have not finished yet.
+	scanStatusRunning = "RUNNING"
+
+	// reservationTimeout is the time after which the slot reserved for a
+	// scan is released if the scan was not created, for instance because
+	// the instance of the API creating it stopped.
+	reservationTimeout = 10 * time.Minute
+)
+
+// Config defines the limits enforced over the scans sent to the scan engine.
+// A zero value in any of the limits means there is no limit.
+type Config struct {
+	MaxRunningScans          int `mapstructure:"max_running_scans"`
+	MaxRunningScansPerTeam   int `mapstructure:"max_running_scans_per_team"`
+	MaxChecksInFlight        int `mapstructure:"max_checks_in_flight"`
+	MaxChecksInFlightPerTeam int `mapstructure:"max_checks_in_flight_per_team"`
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the status of the running scans.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+func (c Config) enabled() bool {
+	return c.MaxRunningScans > 0 || c.MaxRunningScansPerTeam > 0 ||
+		c.MaxChecksInFlight > 0 || c.MaxChecksInFlightPerTeam > 0
+}
+
+// Middleware defines the shape of the functions that return a vulcanito service middleware
+type Middleware func(api.VulcanitoService) api.VulcanitoService
+
+// Store defines the functionality the scan queue middleware needs to persist
+// the queue. The slots are taken through AdmitScanQueueEntries, that
+// serializes the decisions of all the instances of the API.
+type Store interface {
+	ListScanQueueEntries(teamID string, statuses []api.ScanQueueStatus) ([]*api.ScanQueueEntry, error)
+	AdmitScanQueueEntries(admit func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry) ([]*api.ScanQueueEntry, error)
+	StartScanQueueEntry(entryID, scanID string, checkCount int) (bool, error)
+	UpdateScanQueueEntryChecks(entryID string, checkCount, checksInFlight int) (bool, error)
+	FinishScanQueueEntry(entryID, reason string) (bool, error)
+}
+
+// Queue enforces the limits defined in the config over the scans created
+// through the vulcanito service. The scans of a team that exceed the limits
+// are persisted in the queue of the team and created when the running scans
+// finish.
+type Queue struct {
+	api.VulcanitoService
+	store            Store
+	logger           log.Logger
+	scanEngineClient scanengine.Client
+	cfg              Config
+}
+
+// New returns a Queue that enforces the limits defined in the given config.
+func New(l log.Logger, store Store, scanEngineClient scanengine.Client, cfg Config) *Queue {
+	return &Queue{
+		store:            store,
+		logger:           l,
+		scanEngineClient: scanEngineClient,
+		cfg:              cfg,
+	}
+}
+
+// Middleware returns a middleware that enforces the limits of the queue over
+// the scans created through the vulcanito service. If no limit is defined
+// the middleware does nothing.
+func (q *Queue) Middleware() Middleware {
+	return func(next api.VulcanitoService) api.VulcanitoService {
+		if !q.cfg.enabled() {
+			return next
+		}
+		q.VulcanitoService = next
+		go q.run()
+		return q
+	}
+}
+
+// CreateScan sends the scan to the scan engine if the limits allow it and
+// the team has no other scans waiting in the queue. Otherwise the scan is
+// queued and returned with the status QUEUED.
+func (q *Queue) CreateScan(ctx context.Context, scan api.Scan, teamID string) (*api.Scan, error) {
+	program, err := q.FindProgram(ctx, programIDFromRequest(scan.ProgramID), teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := q.estimateChecks(ctx, scan, teamID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := q.reserve(scan, teamID, checks)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == api.ScanQueueStatusRunning {
+		return q.start(ctx, entry)
+	}
+	return &api.Scan{
+		ProgramID:     program.ID,
+		Program:       program,
+		ScheduledTime: scan.ScheduledTime,
+		RequestedBy:   scan.RequestedBy,
+		Status:        api.ScanStatusQueued,
+	}, nil
+}
+
+// HandleScanEvent releases the slot of a scan when it finishes, and sends to
+// the scan engine the queued scans that fit in the released slot.
+func (q *Queue) HandleScanEvent(ctx context.Context, event scanengineapi.ScanNotification) error {
+	if !q.cfg.enabled() || event.Status == scanStatusRunning {
+		return nil
+	}
+	entries, err := q.store.ListScanQueueEntries("", []api.ScanQueueStatus{api.ScanQueueStatusRunning})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ScanID == nil || *e.ScanID != event.ScanID {
+			continue
+		}
+		if err := q.release(e, ""); err != nil {
+			return err
+		}
+		q.dispatch(ctx)
+		break
+	}
+	return nil
+}
+
+// estimateChecks returns the number of checks the scan of a program would
+// run, according to the plan of the program, so the limits of checks in
+// flight can be enforced before the scan is created. It returns 0 if there
+// are no limits of checks in flight.
+func (q *Queue) estimateChecks(ctx context.Context, scan api.Scan, teamID string) (int, error) {
+	if q.cfg.MaxChecksInFlight <= 0 && q.cfg.MaxChecksInFlightPerTeam <= 0 {
+		return 0, nil
+	}
+	plan, err := q.ProgramPlan(ctx, programIDFromRequest(scan.ProgramID), teamID)
+	if err != nil {
+		return 0, err
+	}
+	return len(plan.Checks), nil
+}
+
+// reserve creates the entry of a scan request of a team with the given
+// estimated number of checks. The entry is created in the status RUNNING,
+// taking a slot, if the limits allow it and the team has no queued scans,
+// and in the status QUEUED otherwise.
+func (q *Queue) reserve(scan api.Scan, teamID string, checks int) (*api.ScanQueueEntry, error) {
+	admitted, err := q.store.AdmitScanQueueEntries(func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry {
+		u := newUsage(entries)
+		entry := &api.ScanQueueEntry{
+			TeamID:        teamID,
+			ProgramID:     scan.ProgramID,
+			ScheduledTime: scan.ScheduledTime,
+			RequestedBy:   scan.RequestedBy,
+			Status:        api.ScanQueueStatusQueued,
+			CheckCount:    checks,
+		}
+		if u.teamQueued[teamID] == 0 && q.admits(u, teamID, checks) {
+			now := time.Now()
+			entry.Status = api.ScanQueueStatusRunning
+			entry.StartedAt = &now
+			entry.ChecksInFlight = checks
+		}
+		return []*api.ScanQueueEntry{entry}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(admitted) != 1 {
+		return nil, errors.Default("scan queue entry not created")
+	}
+	return admitted[0], nil
+}
+
+// start creates in the scan engine the scan of an entry that has a slot
+// reserved. If the scan can not be created the slot is released.
+func (q *Queue) start(ctx context.Context, e *api.ScanQueueEntry) (*api.Scan, error) {
+	scan := api.Scan{
+		ProgramID:     e.ProgramID,
+		ScheduledTime: e.ScheduledTime,
+		RequestedBy:   e.RequestedBy,
+	}
+	createdScan, err := q.VulcanitoService.CreateScan(ctx, scan, e.TeamID)
+	if err != nil {
+		if rerr := q.release(e, err.Error()); rerr != nil {
+			_ = level.Error(q.logger).Log("ScanQueue", "error releasing scan", "EntryID", e.ID, "err", rerr)
+		}
+		return nil, err
+	}
+	checkCount := e.CheckCount
+	if createdScan.CheckCount != nil {
+		checkCount = *createdScan.CheckCount
+	}
+	// The scan is already created so we don't want to return an error just
+	// because it could not be tracked.
+	if _, err := q.store.StartScanQueueEntry(e.ID, createdScan.ID, checkCount); err != nil {
+		_ = level.Error(q.logger).Log("ScanQueue", "error tracking scan", "ScanID", createdScan.ID, "err", err)
+	}
+	return createdScan, nil
+}
+
+// release marks an entry as done, freeing its slot.
+func (q *Queue) release(e *api.ScanQueueEntry, reason string) error {
+	_, err := q.store.FinishScanQueueEntry(e.ID, reason)
+	return err
+}
+
+// run checks periodically the status of the running scans, so their slots
+// are released even if the events of the scan engine are not received.
+func (q *Queue) run() {
+	interval := q.cfg.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := context.Background()
+		q.refresh(ctx)
+		q.dispatch(ctx)
+	}
+}
+
+// refresh queries the scan engine for the status of the running scans,
+// releasing the ones that have finished and the slots reserved for scans
+// that were never created.
+func (q *Queue) refresh(ctx context.Context) {
+	entries, err := q.store.ListScanQueueEntries("", []api.ScanQueueStatus{api.ScanQueueStatusRunning})
+	if err != nil {
+		_ = level.Error(q.logger).Log("ScanQueue", "error listing running scans", "err", err)
+		return
+	}
+	for _, e := range entries {
+		if e.ScanID == nil {
+			if e.StartedAt != nil && time.Since(*e.StartedAt) > reservationTimeout {
+				if err := q.release(e, "scan not created"); err != nil {
+					_ = level.Error(q.logger).Log("ScanQueue", "error releasing scan", "EntryID", e.ID, "err", err)
+				}
+			}
+			continue
+		}
+		scan, err := q.scanEngineClient.Get(ctx, *e.ScanID)
+		if err != nil {
+			_ = level.Error(q.logger).Log("ScanQueue", "error getting scan", "ScanID", *e.ScanID, "err", err)
+			continue
+		}
+		if scan.Status != scanStatusRunning {
+			if err := q.release(e, ""); err != nil {
+				_ = level.Error(q.logger).Log("ScanQueue", "error releasing scan", "ScanID", *e.ScanID, "err", err)
+			}
+			continue
+		}
+		if scan.CheckCount != nil {
+			e.CheckCount = *scan.CheckCount
+		}
+		var progress float32
+		if scan.Progress != nil {
+			progress = *scan.Progress
+		}
+		inFlight := checksInFlight(e.CheckCount, progress)
+		if _, err := q.store.UpdateScanQueueEntryChecks(e.ID, e.CheckCount, inFlight); err != nil {
+			_ = level.Error(q.logger).Log("ScanQueue", "error updating scan", "ScanID", *e.ScanID, "err", err)
+		}
+	}
+}
+
+// dispatch sends to the scan engine as many queued scans as the limits
+// allow. Every time a slot is available it is given to the team with less
+// running scans, so a team with many queued scans can not starve the others.
+// The slots of the scans that can not be created are given to the next
+// queued scans.
+func (q *Queue) dispatch(ctx context.Context) {
+	for {
+		claimed := q.claim()
+		if len(claimed) == 0 {
+			return
+		}
+		failed := false
+		for _, e := range claimed {
+			if _, err := q.start(ctx, e); err != nil {
+				_ = level.Error(q.logger).Log("ScanQueue", "error creating queued scan", "EntryID", e.ID, "err", err)
+				failed = true
+			}
+		}
+		if !failed {
+			return
+		}
+	}
+}
+
+// claim reserves a slot for as many queued scans as the limits allow and
+// returns their entries.
+func (q *Queue) claim() []*api.ScanQueueEntry {
+	claimed, err := q.store.AdmitScanQueueEntries(func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry {
+		u := newUsage(entries)
+		pending := map[string][]*api.ScanQueueEntry{}
+		for _, e := range entries {
+			if e.Status == api.ScanQueueStatusQueued {
+				pending[e.TeamID] = append(pending[e.TeamID], e)
+			}
+		}
+		var claimed []*api.ScanQueueEntry
+		for {
+			teamID := q.nextTeam(pending, u)
+			if teamID == "" {
+				return claimed
+			}
+			e := pending[teamID][0]
+			pending[teamID] = pending[teamID][1:]
+			now := time.Now()
+			e.Status = api.ScanQueueStatusRunning
+			e.StartedAt = &now
+			e.ChecksInFlight = e.CheckCount
+			u.add(e)
+			claimed = append(claimed, e)
+		}
+	})
+	if err != nil {
+		_ = level.Error(q.logger).Log("ScanQueue", "error claiming queued scans", "err", err)
+		return nil
+	}
+	return claimed
+}
+
+// nextTeam returns the team whose oldest queued scan must be sent next to
+// the scan engine, or an empty string if no queued scan fits in the limits.
+// Teams with less running scans go first, ties are broken by the age of
+// their oldest queued scan.
+func (q *Queue) nextTeam(pending map[string][]*api.ScanQueueEntry, u *usage) string {
+	teams := []string{}
+	for teamID, entries := range pending {
+		if len(entries) > 0 && q.admits(u, teamID, entries[0].CheckCount) {
+			teams = append(teams, teamID)
+		}
+	}
+	if len(teams) == 0 {
+		return ""
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		ti, tj := teams[i], teams[j]
+		if u.teamScans[ti] != u.teamScans[tj] {
+			return u.teamScans[ti] < u.teamScans[tj]
+		}
+		return pending[ti][0].CreatedAt.Before(pending[tj][0].CreatedAt)
+	})
+	return teams[0]
+}
+
+// admits returns true if a new scan of the given team with the given
+// estimated number of checks fits in the limits. A scan with more checks
+// than a limit is admitted only when there are no checks in flight counted
+// by that limit, so it does not wait forever.
+func (q *Queue) admits(u *usage, teamID string, checks int) bool {
+	if q.cfg.MaxRunningScans > 0 && u.scans >= q.cfg.MaxRunningScans {
+		return false
+	}
+	if q.cfg.MaxRunningScansPerTeam > 0 && u.teamScans[teamID] >= q.cfg.MaxRunningScansPerTeam {
+		return false
+	}
+	if !fits(u.checks, checks, q.cfg.MaxChecksInFlight) {
+		return false
+	}
+	return fits(u.teamChecks[teamID], checks, q.cfg.MaxChecksInFlightPerTeam)
+}
+
+// fits returns true if the given checks can be added to the checks in flight
+// without exceeding the limit. A zero limit means there is no limit.
+func fits(inFlight, checks, limit int) bool {
+	if limit <= 0 || inFlight == 0 {
+		return true
+	}
+	return inFlight+checks <= limit
+}
+
+// usage holds the number of running scans and checks in flight, globally
+// and per team, and the number of queued scans of each team.
+type usage struct {
+	scans      int
+	checks     int
+	teamQueued map[string]int
+	teamScans  map[string]int
+	teamChecks map[string]int
+}
+
+func newUsage(entries []*api.ScanQueueEntry) *usage {
+	u := &usage{
+		teamQueued: map[string]int{},
+		teamScans:  map[string]int{},
+		teamChecks: map[string]int{},
+	}
+	for _, e := range entries {
+		switch e.Status {
+		case api.ScanQueueStatusQueued:
+			u.teamQueued[e.TeamID]++
+		case api.ScanQueueStatusRunning:
+			u.add(e)
+		}
+	}
+	return u
+}
+
+func (u *usage) add(e *api.ScanQueueEntry) {
+	if e.Status != api.ScanQueueStatusRunning {
+		return
+	}
+	u.scans++
+	u.checks += e.ChecksInFlight
+	u.teamScans[e.TeamID]++
+	u.teamChecks[e.TeamID] += e.ChecksInFlight
+}
+
+// checksInFlight estimates the number of checks of a scan that have not
+// finished yet using the progress of the scan.
+func checksInFlight(checkCount int, progress float32) int {
+	if progress >= 1 {
+		return 0
+	}
+	return int(math.Ceil(float64(checkCount) * float64(1-progress)))
+}
+
+// programIDFromRequest returns the ID of the program from the program ID
+// received in a create scan request, which for global programs can have the
+// form teamID@programID.
+func programIDFromRequest(id string) string {
+	if i := strings.Index(id, "@"); (i > 0) && (i < len(id)-1) {
+		return id[i+1:]
+	}
+	return id
+}
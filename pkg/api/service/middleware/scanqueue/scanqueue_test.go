@@ -0,0 +1,474 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
+	scanengineData "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+)
+
+// inMemoryStore stores the entries of the scan queue.
+type inMemoryStore struct {
+	mu      sync.Mutex
+	entries []api.ScanQueueEntry
+}
+
+func (s *inMemoryStore) ListScanQueueEntries(teamID string, statuses []api.ScanQueueStatus) ([]*api.ScanQueueEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []*api.ScanQueueEntry
+	for _, e := range s.entries {
+		for _, status := range statuses {
+			if e.Status == status && (teamID == "" || e.TeamID == teamID) {
+				e := e
+				entries = append(entries, &e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (s *inMemoryStore) AdmitScanQueueEntries(admit func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry) ([]*api.ScanQueueEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []*api.ScanQueueEntry
+	for _, e := range s.entries {
+		if e.Status == api.ScanQueueStatusQueued || e.Status == api.ScanQueueStatusRunning {
+			e := e
+			entries = append(entries, &e)
+		}
+	}
+	var admitted []*api.ScanQueueEntry
+	for _, e := range admit(entries) {
+		if e.ID == "" {
+			e.ID = fmt.Sprintf("e%d", len(s.entries)+1)
+			e.CreatedAt = time.Now()
+			s.entries = append(s.entries, *e)
+			admitted = append(admitted, e)
+			continue
+		}
+		if s.update(e.ID, api.ScanQueueStatusQueued, func(stored *api.ScanQueueEntry) {
+			stored.Status = api.ScanQueueStatusRunning
+			stored.StartedAt = e.StartedAt
+			stored.ChecksInFlight = e.ChecksInFlight
+		}) {
+			admitted = append(admitted, e)
+		}
+	}
+	return admitted, nil
+}
+
+func (s *inMemoryStore) StartScanQueueEntry(entryID, scanID string, checkCount int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(entryID, api.ScanQueueStatusRunning, func(e *api.ScanQueueEntry) {
+		e.ScanID = &scanID
+		e.CheckCount = checkCount
+		e.ChecksInFlight = checkCount
+	}), nil
+}
+
+func (s *inMemoryStore) UpdateScanQueueEntryChecks(entryID string, checkCount, checksInFlight int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(entryID, api.ScanQueueStatusRunning, func(e *api.ScanQueueEntry) {
+		e.CheckCount = checkCount
+		e.ChecksInFlight = checksInFlight
+	}), nil
+}
+
+func (s *inMemoryStore) FinishScanQueueEntry(entryID, reason string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(entryID, api.ScanQueueStatusRunning, func(e *api.ScanQueueEntry) {
+		e.Status = api.ScanQueueStatusDone
+		e.ChecksInFlight = 0
+		e.Error = reason
+	}), nil
+}
+
+// update applies the given change to the entry if it has the given status.
+// The caller must hold the lock of the store.
+func (s *inMemoryStore) update(entryID string, status api.ScanQueueStatus, change func(e *api.ScanQueueEntry)) bool {
+	for i, e := range s.entries {
+		if e.ID == entryID && e.Status == status {
+			change(&s.entries[i])
+			return true
+		}
+	}
+	return false
+}
+
+func (s *inMemoryStore) inFlight() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inFlight := map[string]int{}
+	for _, e := range s.entries {
+		inFlight[e.ID] = e.ChecksInFlight
+	}
+	return inFlight
+}
+
+func (s *inMemoryStore) statuses() map[string]api.ScanQueueStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := map[string]api.ScanQueueStatus{}
+	for _, e := range s.entries {
+		statuses[e.ID] = e.Status
+	}
+	return statuses
+}
+
+// fakeScanService creates the scans in the scan engine, failing for the
+// programs in failing. The scans of the programs in checks run the given
+// number of checks, and the other ones run one check.
+type fakeScanService struct {
+	api.VulcanitoService
+	mu      sync.Mutex
+	created []string
+	failing map[string]bool
+	checks  map[string]int
+}
+
+func (s *fakeScanService) FindProgram(ctx context.Context, programID, teamID string) (*api.Program, error) {
+	return &api.Program{ID: programID}, nil
+}
+
+func (s *fakeScanService) ProgramPlan(ctx context.Context, programID, teamID string) (*api.ProgramPlan, error) {
+	plan := &api.ProgramPlan{}
+	for i := 0; i < s.programChecks(programID); i++ {
+		plan.Checks = append(plan.Checks, api.PlannedCheck{})
+	}
+	return plan, nil
+}
+
+func (s *fakeScanService) programChecks(programID string) int {
+	if n, ok := s.checks[programID]; ok {
+		return n
+	}
+	return 1
+}
+
+func (s *fakeScanService) CreateScan(ctx context.Context, scan api.Scan, teamID string) (*api.Scan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing[scan.ProgramID] {
+		return nil, errors.New("scan engine error")
+	}
+	id := fmt.Sprintf("scan-%s-%d", teamID, len(s.created)+1)
+	s.created = append(s.created, id)
+	checks := s.programChecks(scan.ProgramID)
+	return &api.Scan{ID: id, ProgramID: scan.ProgramID, CheckCount: &checks}, nil
+}
+
+func TestQueue_CreateScan(t *testing.T) {
+	store := &inMemoryStore{}
+	next := &fakeScanService{failing: map[string]bool{"failing": true}}
+	q := New(log.NewNopLogger(), store, nil, Config{MaxRunningScansPerTeam: 1})
+	q.VulcanitoService = next
+	ctx := context.Background()
+
+	// The first scan of a team is created and the next one is queued.
+	scan, err := q.CreateScan(ctx, api.Scan{ProgramID: "p1"}, "team1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scan.ID != "scan-team1-1" {
+		t.Errorf("got scan %+v, want it created", scan)
+	}
+	scan, err = q.CreateScan(ctx, api.Scan{ProgramID: "p2"}, "team1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scan.Status != api.ScanStatusQueued {
+		t.Errorf("got scan %+v, want it queued", scan)
+	}
+
+	// The queued scans of a team don't delay the scans of other teams.
+	scan, err = q.CreateScan(ctx, api.Scan{ProgramID: "p1"}, "team2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scan.ID != "scan-team2-2" {
+		t.Errorf("got scan %+v, want it created", scan)
+	}
+
+	// A scan that can't be created releases its slot.
+	if _, err := q.CreateScan(ctx, api.Scan{ProgramID: "failing"}, "team3"); err == nil {
+		t.Errorf("expected an error creating the scan")
+	}
+	if _, err := q.CreateScan(ctx, api.Scan{ProgramID: "p1"}, "team3"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The event of a finished scan releases its slot and sends the queued
+	// scan of the team.
+	events := []scanengineapi.ScanNotification{
+		{ScanID: "scan-team1-1", Status: scanStatusRunning},
+		{ScanID: "scan-team1-1", Status: "FINISHED"},
+	}
+	for _, event := range events {
+		if err := q.HandleScanEvent(ctx, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := map[string]api.ScanQueueStatus{
+		"e1": api.ScanQueueStatusDone,
+		"e2": api.ScanQueueStatusRunning,
+		"e3": api.ScanQueueStatusRunning,
+		"e4": api.ScanQueueStatusDone,
+		"e5": api.ScanQueueStatusRunning,
+	}
+	if diff := cmp.Diff(want, store.statuses()); diff != "" {
+		t.Errorf("entries mismatch (-want +got):\n%v", diff)
+	}
+	wantCreated := []string{"scan-team1-1", "scan-team2-2", "scan-team3-3", "scan-team1-4"}
+	if diff := cmp.Diff(wantCreated, next.created); diff != "" {
+		t.Errorf("created scans mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func TestQueue_CreateScanEstimatesChecks(t *testing.T) {
+	store := &inMemoryStore{}
+	next := &fakeScanService{checks: map[string]int{"big": 30, "medium": 6}}
+	q := New(log.NewNopLogger(), store, nil, Config{MaxChecksInFlight: 10})
+	q.VulcanitoService = next
+	ctx := context.Background()
+
+	// The first scan fits, the second one would exceed the limit even if
+	// the first one is not created yet, and a scan of a team without checks
+	// in flight larger than the limit waits for the checks of the others.
+	requests := []struct {
+		programID string
+		teamID    string
+		queued    bool
+	}{
+		{programID: "medium", teamID: "team1"},
+		{programID: "medium", teamID: "team2", queued: true},
+		{programID: "small", teamID: "team3"},
+		{programID: "big", teamID: "team4", queued: true},
+	}
+	for _, r := range requests {
+		scan, err := q.CreateScan(ctx, api.Scan{ProgramID: r.programID}, r.teamID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := scan.Status == api.ScanStatusQueued; got != r.queued {
+			t.Errorf("got scan of %s of %s queued %v, want %v", r.programID, r.teamID, got, r.queued)
+		}
+	}
+	want := map[string]int{"e1": 6, "e2": 0, "e3": 1, "e4": 0}
+	if diff := cmp.Diff(want, store.inFlight()); diff != "" {
+		t.Errorf("checks in flight mismatch (-want +got):\n%v", diff)
+	}
+}
+
+// finishingScanEngine finishes the entries of the queue when it returns
+// their scans, as if their scans finished while they were being refreshed.
+type finishingScanEngine struct {
+	*scanenginetest.Fake
+	store *inMemoryStore
+}
+
+func (f *finishingScanEngine) Get(ctx context.Context, scanID string) (*scanengineData.GetScanResponse, error) {
+	scan, err := f.Fake.Get(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+	entries, _ := f.store.ListScanQueueEntries("", []api.ScanQueueStatus{api.ScanQueueStatusRunning})
+	for _, e := range entries {
+		if e.ScanID != nil && *e.ScanID == scanID {
+			f.store.FinishScanQueueEntry(e.ID, "")
+		}
+	}
+	return scan, nil
+}
+
+func TestQueue_Refresh(t *testing.T) {
+	checkCount := 4
+	progress := float32(0.5)
+	scans := map[string]scanengineData.GetScanResponse{
+		"scan-team1-1": {ID: "scan-team1-1", Status: scanStatusRunning, CheckCount: &checkCount, Progress: &progress},
+	}
+	tests := []struct {
+		name         string
+		finishing    bool
+		wantStatus   api.ScanQueueStatus
+		wantInFlight int
+	}{
+		{name: "UpdatesChecksInFlight", wantStatus: api.ScanQueueStatusRunning, wantInFlight: 2},
+		{name: "DoesNotRunFinishedEntries", finishing: true, wantStatus: api.ScanQueueStatusDone, wantInFlight: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inMemoryStore{}
+			var engine scanengine.Client = &scanenginetest.Fake{Scans: scans}
+			if tt.finishing {
+				engine = &finishingScanEngine{Fake: &scanenginetest.Fake{Scans: scans}, store: store}
+			}
+			q := New(log.NewNopLogger(), store, engine, Config{MaxChecksInFlight: 10})
+			q.VulcanitoService = &fakeScanService{checks: map[string]int{"p1": 4}}
+			ctx := context.Background()
+
+			if _, err := q.CreateScan(ctx, api.Scan{ProgramID: "p1"}, "team1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			q.refresh(ctx)
+			if diff := cmp.Diff(map[string]api.ScanQueueStatus{"e1": tt.wantStatus}, store.statuses()); diff != "" {
+				t.Errorf("entries mismatch (-want +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(map[string]int{"e1": tt.wantInFlight}, store.inFlight()); diff != "" {
+				t.Errorf("checks in flight mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestScanQueue_NextTeam(t *testing.T) {
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		cfg     Config
+		entries []*api.ScanQueueEntry
+		want    string
+	}{
+		{
+			name: "OldestQueuedScanFirst",
+			cfg:  Config{MaxRunningScans: 2},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusQueued, CreatedAt: t0.Add(time.Minute)},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0},
+			},
+			want: "team2",
+		},
+		{
+			name: "TeamWithLessRunningScansFirst",
+			cfg:  Config{MaxRunningScans: 3},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusRunning},
+				{TeamID: "team1", Status: api.ScanQueueStatusQueued, CreatedAt: t0},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0.Add(time.Minute)},
+			},
+			want: "team2",
+		},
+		{
+			name: "GlobalScansLimitReached",
+			cfg:  Config{MaxRunningScans: 1},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusRunning},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0},
+			},
+			want: "",
+		},
+		{
+			name: "TeamScansLimitReached",
+			cfg:  Config{MaxRunningScansPerTeam: 1},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusRunning},
+				{TeamID: "team1", Status: api.ScanQueueStatusQueued, CreatedAt: t0},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0.Add(time.Minute)},
+			},
+			want: "team2",
+		},
+		{
+			name: "GlobalChecksLimitReached",
+			cfg:  Config{MaxChecksInFlight: 10},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusRunning, ChecksInFlight: 10},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0, CheckCount: 1},
+			},
+			want: "",
+		},
+		{
+			name: "TeamChecksLimitReached",
+			cfg:  Config{MaxChecksInFlightPerTeam: 10},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusRunning, ChecksInFlight: 8},
+				{TeamID: "team1", Status: api.ScanQueueStatusQueued, CreatedAt: t0, CheckCount: 5},
+				{TeamID: "team2", Status: api.ScanQueueStatusRunning, ChecksInFlight: 5},
+				{TeamID: "team2", Status: api.ScanQueueStatusQueued, CreatedAt: t0.Add(time.Minute), CheckCount: 5},
+			},
+			want: "team2",
+		},
+		{
+			name: "NoQueuedScans",
+			cfg:  Config{MaxRunningScans: 1},
+			entries: []*api.ScanQueueEntry{
+				{TeamID: "team1", Status: api.ScanQueueStatusDone},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queue{cfg: tt.cfg}
+			pending := map[string][]*api.ScanQueueEntry{}
+			for _, e := range tt.entries {
+				if e.Status == api.ScanQueueStatusQueued {
+					pending[e.TeamID] = append(pending[e.TeamID], e)
+				}
+			}
+			got := q.nextTeam(pending, newUsage(tt.entries))
+			if got != tt.want {
+				t.Errorf("scanQueue.nextTeam() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecksInFlight(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkCount int
+		progress   float32
+		want       int
+	}{
+		{name: "NotStarted", checkCount: 10, progress: 0, want: 10},
+		{name: "HalfDone", checkCount: 10, progress: 0.5, want: 5},
+		{name: "RoundsUp", checkCount: 10, progress: 0.95, want: 1},
+		{name: "Finished", checkCount: 10, progress: 1, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checksInFlight(tt.checkCount, tt.progress)
+			if got != tt.want {
+				t.Errorf("checksInFlight() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgramIDFromRequest(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: "b6a4a9a9-c0c2-4b4c-9b6d-4d5a3f5b6e7a", want: "b6a4a9a9-c0c2-4b4c-9b6d-4d5a3f5b6e7a"},
+		{id: "team@periodic-full-scan", want: "periodic-full-scan"},
+		{id: "@periodic-full-scan", want: "@periodic-full-scan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got := programIDFromRequest(tt.id)
+			if got != tt.want {
+				t.Errorf("programIDFromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
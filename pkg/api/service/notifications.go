@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (s vulcanitoService) ListNotificationRules(ctx context.Context, teamID, programID string) ([]*api.NotificationRule, error) {
+	return s.db.ListNotificationRules(teamID, programID)
+}
+
+func (s vulcanitoService) CreateNotificationRule(ctx context.Context, rule api.NotificationRule) (*api.NotificationRule, error) {
+	if err := validator.New().Struct(rule); err != nil {
+		return nil, errors.Validation(err)
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	if rule.Disabled == nil {
+		disabled := false
+		rule.Disabled = &disabled
+	}
+	return s.db.CreateNotificationRule(rule)
+}
+
+func (s vulcanitoService) UpdateNotificationRule(ctx context.Context, rule api.NotificationRule) (*api.NotificationRule, error) {
+	current, err := s.db.FindNotificationRule(rule.TeamID, rule.ProgramID, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Trigger != "" {
+		current.Trigger = rule.Trigger
+	}
+	if rule.MinSeverity != "" {
+		current.MinSeverity = rule.MinSeverity
+	}
+	if rule.Channel != "" {
+		current.Channel = rule.Channel
+	}
+	if rule.URL != "" {
+		current.URL = rule.URL
+	}
+	if rule.Disabled != nil {
+		current.Disabled = rule.Disabled
+	}
+	if err := current.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	return s.db.UpdateNotificationRule(*current)
+}
+
+func (s vulcanitoService) DeleteNotificationRule(ctx context.Context, rule api.NotificationRule) error {
+	return s.db.DeleteNotificationRule(rule)
+}
@@ -18,6 +18,9 @@ func (s vulcanitoService) CreatePolicy(ctx context.Context, policy api.Policy) (
 	if validationErr != nil {
 		return nil, errors.Validation(validationErr)
 	}
+	if err := s.checkPolicyTemplate(policy); err != nil {
+		return nil, err
+	}
 	return s.db.CreatePolicy(policy)
 }
 
@@ -30,9 +33,31 @@ func (s vulcanitoService) FindPolicy(ctx context.Context, policyID string) (*api
 }
 
 func (s vulcanitoService) UpdatePolicy(ctx context.Context, policy api.Policy) (*api.Policy, error) {
+	if err := s.checkPolicyTemplate(policy); err != nil {
+		return nil, err
+	}
 	return s.db.UpdatePolicy(policy)
 }
 
+// checkPolicyTemplate returns a validation error if the policy extends a
+// template that does not exist.
+func (s vulcanitoService) checkPolicyTemplate(policy api.Policy) error {
+	if policy.TemplateID == nil || *policy.TemplateID == "" {
+		return nil
+	}
+	_, err := s.db.FindPolicyTemplate(*policy.TemplateID)
+	if errors.IsKind(err, errors.ErrNotFound) {
+		return errors.Validation("policy template not found")
+	}
+	return err
+}
+
+// ListGlobalPolicyEvaluations returns an empty list as the global policies
+// are provided by the global entities middleware.
+func (s vulcanitoService) ListGlobalPolicyEvaluations(ctx context.Context) ([]*api.GlobalPolicyEvaluation, error) {
+	return []*api.GlobalPolicyEvaluation{}, nil
+}
+
 func (s vulcanitoService) DeletePolicy(ctx context.Context, policy api.Policy) error {
 	return s.db.DeletePolicy(policy)
 }
@@ -46,6 +71,11 @@ func (s vulcanitoService) CreateChecktypeSetting(ctx context.Context, setting ap
 	if validationErr != nil {
 		return nil, errors.Validation(validationErr)
 	}
+	if !setting.IsExcluded() {
+		if err := s.validateChecktypeOptions(ctx, setting.CheckTypeName, setting.Options); err != nil {
+			return nil, err
+		}
+	}
 	return s.db.CreateChecktypeSetting(setting)
 }
 
@@ -58,6 +88,11 @@ func (s vulcanitoService) UpdateChecktypeSetting(ctx context.Context, checktypeS
 	if validationErr != nil {
 		return nil, errors.Validation(validationErr)
 	}
+	if !checktypeSetting.IsExcluded() {
+		if err := s.validateChecktypeOptions(ctx, checktypeSetting.CheckTypeName, checktypeSetting.Options); err != nil {
+			return nil, err
+		}
+	}
 	return s.db.UpdateChecktypeSetting(checktypeSetting)
 }
 
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (s vulcanitoService) ListPolicyTemplates(ctx context.Context) ([]*api.PolicyTemplate, error) {
+	return s.db.ListPolicyTemplates()
+}
+
+func (s vulcanitoService) CreatePolicyTemplate(ctx context.Context, template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return s.db.CreatePolicyTemplate(template)
+}
+
+func (s vulcanitoService) FindPolicyTemplate(ctx context.Context, templateID string) (*api.PolicyTemplate, error) {
+	return s.db.FindPolicyTemplate(templateID)
+}
+
+func (s vulcanitoService) UpdatePolicyTemplate(ctx context.Context, template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	current, err := s.db.FindPolicyTemplate(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Validate the template as it will be after the update.
+	updated := *current
+	if template.Name != "" {
+		updated.Name = template.Name
+	}
+	if template.Description != nil {
+		updated.Description = template.Description
+	}
+	if template.Settings != nil {
+		updated.Settings = template.Settings
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+	// The settings are only replaced when the update includes them.
+	updated.Settings = template.Settings
+	return s.db.UpdatePolicyTemplate(updated)
+}
+
+func (s vulcanitoService) DeletePolicyTemplate(ctx context.Context, templateID string) error {
+	return s.db.DeletePolicyTemplate(templateID)
+}
+
+// PolicyDivergence returns where a policy of a team diverges from the
+// template it extends.
+func (s vulcanitoService) PolicyDivergence(ctx context.Context, teamID, policyID string) (*api.PolicyDivergence, error) {
+	policy, err := s.db.FindPolicy(policyID)
+	if err != nil {
+		return nil, err
+	}
+	if policy.TeamID != teamID {
+		return nil, errors.NotFound("policy not found")
+	}
+	divergence := policy.TemplateDivergence()
+	if divergence == nil {
+		return nil, errors.Validation("policy does not extend a template")
+	}
+	return divergence, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+// inMemoryPolicyTemplatesStore stores a single policy template.
+type inMemoryPolicyTemplatesStore struct {
+	api.VulcanitoStore
+	template api.PolicyTemplate
+	updated  *api.PolicyTemplate
+}
+
+func (s *inMemoryPolicyTemplatesStore) FindPolicyTemplate(templateID string) (*api.PolicyTemplate, error) {
+	t := s.template
+	return &t, nil
+}
+
+func (s *inMemoryPolicyTemplatesStore) UpdatePolicyTemplate(template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	s.updated = &template
+	return &template, nil
+}
+
+func TestVulcanitoService_UpdatePolicyTemplate(t *testing.T) {
+	current := api.PolicyTemplate{
+		ID:          "pt1",
+		Name:        "web",
+		Description: common.String("web checks"),
+		Settings:    []*api.PolicyTemplateSetting{{ID: "s1", PolicyTemplateID: "pt1", CheckTypeName: "vulcan-tls"}},
+	}
+	settings := []*api.PolicyTemplateSetting{{CheckTypeName: "vulcan-http-headers"}}
+
+	tests := []struct {
+		name   string
+		update api.PolicyTemplate
+		want   api.PolicyTemplate
+	}{
+		{
+			name:   "Name",
+			update: api.PolicyTemplate{ID: "pt1", Name: "websites"},
+			want:   api.PolicyTemplate{ID: "pt1", Name: "websites", Description: common.String("web checks")},
+		},
+		{
+			name:   "SettingsOnly",
+			update: api.PolicyTemplate{ID: "pt1", Settings: settings},
+			want:   api.PolicyTemplate{ID: "pt1", Name: "web", Description: common.String("web checks"), Settings: settings},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &inMemoryPolicyTemplatesStore{template: current}
+			srv := vulcanitoService{db: db}
+			if _, err := srv.UpdatePolicyTemplate(context.Background(), tt.update); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(&tt.want, db.updated); diff != "" {
+				t.Errorf("stored template mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
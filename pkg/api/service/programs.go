@@ -6,6 +6,7 @@ package service
 
 import (
 	"context"
+	errs "errors"
 	"fmt"
 
 	"gopkg.in/go-playground/validator.v9"
@@ -13,6 +14,7 @@ import (
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 	"github.com/adevinta/vulcan-api/pkg/api/store/global"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
 	"github.com/adevinta/vulcan-api/pkg/schedule"
 )
 
@@ -154,6 +156,35 @@ func (s vulcanitoService) FindProgram(ctx context.Context, programID string, tea
 	return p, nil
 }
 
+// ProgramPlan returns the checks a scan of the program would run and the
+// assets of the program that would not be scanned.
+func (s vulcanitoService) ProgramPlan(ctx context.Context, programID string, teamID string) (*api.ProgramPlan, error) {
+	program, err := s.FindProgram(ctx, programID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setAssetChecktypeSettings(ctx, program, teamID); err != nil {
+		return nil, err
+	}
+	// The plan is still useful without the asset types supported by the
+	// checktypes, so it is returned even if the catalogue is not available.
+	checktypes, err := s.ListChecktypes(ctx)
+	if err != nil {
+		checktypes = nil
+	}
+	plan, err := scanengine.CreateScanPlan(*program, checktypes)
+	if err != nil {
+		if errs.Is(err, scanengine.ErrProgramWithoutPolicyGroups) {
+			return nil, errors.Validation(err)
+		}
+		if errs.Is(err, scanengine.ErrNotFound) {
+			return nil, errors.NotFound(err)
+		}
+		return nil, errors.Default(err)
+	}
+	return plan, nil
+}
+
 func (s vulcanitoService) UpdateProgram(ctx context.Context, program api.Program, teamID string) (*api.Program, error) {
 
 	// Check that all the GroupPolicies have the policyID and groupID set.
@@ -56,7 +56,7 @@ func Test_vulcanitoService_CreateProgram(t *testing.T) {
 		db               api.VulcanitoStore
 		logger           log.Logger
 		programScheduler schedule.ScanScheduler
-		scanEngineConfig scanengine.Config
+		scanEngineClient scanengine.Client
 	}
 	type args struct {
 		ctx     context.Context
@@ -77,7 +77,7 @@ func Test_vulcanitoService_CreateProgram(t *testing.T) {
 				db:               tt.fields.db,
 				logger:           tt.fields.logger,
 				programScheduler: tt.fields.programScheduler,
-				scanEngineConfig: tt.fields.scanEngineConfig,
+				scanEngineClient: tt.fields.scanEngineClient,
 			}
 			got, err := s.CreateProgram(tt.args.ctx, tt.args.program, tt.args.team)
 			if (err != nil) != tt.wantErr {
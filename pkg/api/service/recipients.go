@@ -6,14 +6,313 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"time"
 
+	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
 )
 
+const (
+	// Types of the tokens sent to the recipients. They are different from
+	// the types of the tokens used to authenticate the users, and they do
+	// not contain the "sub" claim, so they can not be used to log in.
+	recipientVerificationToken = "RecipientVerification"
+	recipientUnsubscribeToken  = "RecipientUnsubscribe"
+
+	recipientVerificationTTL = 7 * 24 * time.Hour
+	// The unsubscribe links are included in every report sent to a
+	// recipient, so they are valid for longer than the verification ones.
+	recipientUnsubscribeTTL = 90 * 24 * time.Hour
+)
+
+// UpdateRecipients sets the recipients of a team. A verification link is
+// sent to the new recipients.
 func (s vulcanitoService) UpdateRecipients(ctx context.Context, teamID string, emails []string) error {
-	return s.db.UpdateRecipients(teamID, emails)
+	for _, e := range emails {
+		if err := (api.Recipient{Email: e}).Validate(); err != nil {
+			return errors.Validation(err, e)
+		}
+	}
+	current, err := s.db.ListRecipients(teamID)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, r := range current {
+		existing[r.Email] = true
+	}
+	if err := s.db.UpdateRecipients(teamID, emails); err != nil {
+		return err
+	}
+	for _, e := range emails {
+		if existing[e] {
+			continue
+		}
+		if err := s.SendRecipientVerification(ctx, teamID, e); err != nil {
+			_ = s.logger.Log("ErrSendRecipientVerification", err, "TeamID", teamID)
+		}
+	}
+	return nil
 }
 
 func (s vulcanitoService) ListRecipients(ctx context.Context, teamID string) ([]*api.Recipient, error) {
 	return s.db.ListRecipients(teamID)
 }
+
+// CreateRecipient adds a recipient to a team and sends it the link to verify
+// its address. The recipient does not receive reports until it is verified.
+func (s vulcanitoService) CreateRecipient(ctx context.Context, recipient api.Recipient) (*api.Recipient, error) {
+	if err := recipient.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	active := true
+	recipient.Active = &active
+	recipient.VerifiedAt = nil
+	recipient.DeactivatedReason = nil
+	created, err := s.db.CreateRecipient(recipient)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SendRecipientVerification(ctx, created.TeamID, created.Email); err != nil {
+		_ = s.logger.Log("ErrSendRecipientVerification", err, "TeamID", created.TeamID)
+	}
+	return created, nil
+}
+
+// UpdateRecipient modifies the report types and the active flag of a
+// recipient. A recipient that is activated again must verify its address
+// again.
+func (s vulcanitoService) UpdateRecipient(ctx context.Context, recipient api.Recipient, reportTypes *[]string) (*api.Recipient, error) {
+	current, err := s.db.FindRecipient(recipient.TeamID, recipient.Email)
+	if err != nil {
+		return nil, err
+	}
+	if reportTypes != nil {
+		current.SetReportTypes(*reportTypes)
+	}
+	reactivated := false
+	if recipient.Active != nil {
+		if *recipient.Active && !current.IsActive() {
+			reactivated = true
+			current.VerifiedAt = nil
+			current.DeactivatedReason = nil
+		}
+		if !*recipient.Active && current.IsActive() {
+			reason := api.RecipientDeactivatedUnsubscribed
+			current.DeactivatedReason = &reason
+			current.TokenVersion++
+		}
+		current.Active = recipient.Active
+	}
+	if err := current.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	updated, err := s.db.UpdateRecipient(*current)
+	if err != nil {
+		return nil, err
+	}
+	if reactivated {
+		if err := s.SendRecipientVerification(ctx, updated.TeamID, updated.Email); err != nil {
+			_ = s.logger.Log("ErrSendRecipientVerification", err, "TeamID", updated.TeamID)
+		}
+	}
+	return updated, nil
+}
+
+func (s vulcanitoService) DeleteRecipient(ctx context.Context, teamID, email string) error {
+	return s.db.DeleteRecipient(teamID, email)
+}
+
+// SendRecipientVerification sends to a recipient of a team the link to
+// verify its address.
+func (s vulcanitoService) SendRecipientVerification(ctx context.Context, teamID, email string) error {
+	if s.reportsClient == nil {
+		return errors.Default("reports are not configured")
+	}
+	recipient, err := s.db.FindRecipient(teamID, email)
+	if err != nil {
+		return err
+	}
+	if recipient.IsVerified() {
+		return errors.Validation("the recipient is already verified")
+	}
+	team, err := s.db.FindTeam(teamID)
+	if err != nil {
+		return err
+	}
+	verificationURL, err := s.recipientURL("verify", recipientVerificationToken, *recipient, recipientVerificationTTL)
+	if err != nil {
+		return err
+	}
+	unsubscribeURL, err := s.recipientURL("unsubscribe", recipientUnsubscribeToken, *recipient, recipientUnsubscribeTTL)
+	if err != nil {
+		return err
+	}
+	r := reports.Recipient{Email: email, UnsubscribeURL: unsubscribeURL}
+	return s.reportsClient.SendRecipientVerification(teamID, team.Name, r, verificationURL)
+}
+
+// VerifyRecipient verifies the address of the recipient the given token was
+// sent to.
+func (s vulcanitoService) VerifyRecipient(ctx context.Context, token string) (*api.Recipient, error) {
+	recipient, err := s.recipientFromToken(token, recipientVerificationToken)
+	if err != nil {
+		return nil, err
+	}
+	if !recipient.IsActive() {
+		return nil, errors.Validation("the recipient is not active")
+	}
+	if recipient.IsVerified() {
+		return recipient, nil
+	}
+	now := time.Now()
+	recipient.VerifiedAt = &now
+	return s.db.UpdateRecipient(*recipient)
+}
+
+// UnsubscribeRecipient deactivates the recipient the given token was sent
+// to.
+func (s vulcanitoService) UnsubscribeRecipient(ctx context.Context, token string) (*api.Recipient, error) {
+	recipient, err := s.recipientFromToken(token, recipientUnsubscribeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !recipient.IsActive() {
+		return recipient, nil
+	}
+	active := false
+	reason := api.RecipientDeactivatedUnsubscribed
+	recipient.Active = &active
+	recipient.DeactivatedReason = &reason
+	recipient.TokenVersion++
+	return s.db.UpdateRecipient(*recipient)
+}
+
+// DeactivateBouncedRecipients deactivates the recipients of all the teams
+// with the given addresses, as signaled by the reports service when the
+// emails sent to them bounce. The tokens already sent to the recipients are
+// invalidated, so an old verification link can not verify the address again
+// once the recipient is reactivated.
+func (s vulcanitoService) DeactivateBouncedRecipients(ctx context.Context, emails []string) (int64, error) {
+	return s.db.DeactivateRecipients(emails, api.RecipientDeactivatedBounced)
+}
+
+// ListReportRecipients returns the recipients of a team that must receive the
+// reports of the given type, together with their unsubscribe links.
+func (s vulcanitoService) ListReportRecipients(ctx context.Context, teamID, reportType string) ([]*api.ReportRecipient, error) {
+	recipients, err := s.db.ListRecipients(teamID)
+	if err != nil {
+		return nil, err
+	}
+	result := []*api.ReportRecipient{}
+	for _, r := range recipients {
+		if !r.Receives(reportType) {
+			continue
+		}
+		recipient, err := s.reportRecipient(*r)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, recipient)
+	}
+	return result, nil
+}
+
+// subscriptionRecipients returns the recipients a report subscription must
+// be sent to, together with their unsubscribe links. A subscription without
+// recipients of its own is sent to the recipients of the team subscribed to
+// the reports of the subscriptions. Otherwise it is only sent to its
+// recipients that are recipients of the team, active and verified, so the
+// addresses that unsubscribed or bounced stop receiving it.
+func (s vulcanitoService) subscriptionRecipients(ctx context.Context, subscription api.ReportSubscription) ([]*api.ReportRecipient, error) {
+	if len(subscription.Recipients) == 0 {
+		return s.ListReportRecipients(ctx, subscription.TeamID, api.RecipientReportSubscriptions)
+	}
+	emails := map[string]bool{}
+	for _, r := range subscription.Recipients {
+		emails[r.Email] = true
+	}
+	recipients, err := s.db.ListRecipients(subscription.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	result := []*api.ReportRecipient{}
+	for _, r := range recipients {
+		if !emails[r.Email] || !r.IsActive() || !r.IsVerified() {
+			continue
+		}
+		recipient, err := s.reportRecipient(*r)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, recipient)
+	}
+	return result, nil
+}
+
+// reportRecipient returns the given recipient of a report together with its
+// unsubscribe link.
+func (s vulcanitoService) reportRecipient(recipient api.Recipient) (*api.ReportRecipient, error) {
+	unsubscribeURL, err := s.recipientURL("unsubscribe", recipientUnsubscribeToken, recipient, recipientUnsubscribeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ReportRecipient{Email: recipient.Email, UnsubscribeURL: unsubscribeURL}, nil
+}
+
+// recipientURL returns the link to the given public action of the recipients
+// endpoints, including a signed token identifying the recipient. The token
+// expires after the given ttl, and it is revoked when the token version of the
+// recipient changes.
+func (s vulcanitoService) recipientURL(action, tokenType string, recipient api.Recipient, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+		"type":    tokenType,
+		"team_id": recipient.TeamID,
+		"email":   recipient.Email,
+		"version": recipient.TokenVersion,
+	}
+	token, err := s.jwtConfig.GenerateToken(claims)
+	if err != nil {
+		return "", errors.Default(err)
+	}
+	return fmt.Sprintf("%s/api/v1/recipients/%s?token=%s", s.reportsConfig.VulcanAPIURL, action, url.QueryEscape(token)), nil
+}
+
+func (s vulcanitoService) recipientFromToken(token, tokenType string) (*api.Recipient, error) {
+	claims, err := s.jwtConfig.ParseToken(token)
+	if err != nil {
+		return nil, errors.Unauthorized(err)
+	}
+	typ, _ := claims["type"].(string)
+	teamID, _ := claims["team_id"].(string)
+	email, _ := claims["email"].(string)
+	version, hasVersion := claims["version"].(float64)
+	_, hasExp := claims["exp"]
+	if typ != tokenType || teamID == "" || email == "" || !hasVersion || !hasExp {
+		return nil, errors.Unauthorized("invalid token")
+	}
+	recipient, err := s.db.FindRecipient(teamID, email)
+	if err != nil {
+		return nil, err
+	}
+	if int(version) != recipient.TokenVersion {
+		return nil, errors.Unauthorized("the token has been revoked")
+	}
+	return recipient, nil
+}
+
+// toReportsRecipients converts the recipients of a report to the type used
+// by the reports client.
+func toReportsRecipients(recipients []*api.ReportRecipient) []reports.Recipient {
+	result := []reports.Recipient{}
+	for _, r := range recipients {
+		result = append(result, reports.Recipient{Email: r.Email, UnsubscribeURL: r.UnsubscribeURL})
+	}
+	return result
+}
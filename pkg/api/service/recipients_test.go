@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/jwt"
+	"github.com/google/go-cmp/cmp"
+)
+
+type inMemoryRecipientsStore struct {
+	api.VulcanitoStore
+	recipient api.Recipient
+}
+
+func (s *inMemoryRecipientsStore) FindRecipient(teamID, email string) (*api.Recipient, error) {
+	if s.recipient.TeamID != teamID || s.recipient.Email != email {
+		return nil, errors.NotFound("record not found")
+	}
+	r := s.recipient
+	return &r, nil
+}
+
+func (s *inMemoryRecipientsStore) UpdateRecipient(recipient api.Recipient) (*api.Recipient, error) {
+	s.recipient = recipient
+	return s.FindRecipient(recipient.TeamID, recipient.Email)
+}
+
+type inMemoryTeamRecipientsStore struct {
+	api.VulcanitoStore
+	recipients []*api.Recipient
+}
+
+func (s *inMemoryTeamRecipientsStore) ListRecipients(teamID string) ([]*api.Recipient, error) {
+	recipients := []*api.Recipient{}
+	for _, r := range s.recipients {
+		if r.TeamID == teamID {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients, nil
+}
+
+func tokenFromURL(t *testing.T, link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("error parsing link %s: %v", link, err)
+	}
+	return u.Query().Get("token")
+}
+
+func TestVulcanitoService_VerifyRecipientAfterDeactivation(t *testing.T) {
+	active := true
+	db := &inMemoryRecipientsStore{recipient: api.Recipient{TeamID: "team1", Email: "a@example.com", Active: &active}}
+	srv := vulcanitoService{db: db, jwtConfig: jwt.NewJWTConfig("key")}
+	ctx := context.Background()
+
+	link, err := srv.recipientURL("verify", recipientVerificationToken, db.recipient, recipientVerificationTTL)
+	if err != nil {
+		t.Fatalf("error generating verification link: %v", err)
+	}
+	unsubscribeLink, err := srv.recipientURL("unsubscribe", recipientUnsubscribeToken, db.recipient, recipientUnsubscribeTTL)
+	if err != nil {
+		t.Fatalf("error generating unsubscribe link: %v", err)
+	}
+
+	// The recipient unsubscribes and is activated again by the team, so the
+	// verification link sent before must not verify its address.
+	if _, err := srv.UnsubscribeRecipient(ctx, tokenFromURL(t, unsubscribeLink)); err != nil {
+		t.Fatalf("UnsubscribeRecipient() error = %v", err)
+	}
+	db.recipient.Active = &active
+	db.recipient.DeactivatedReason = nil
+
+	_, err = srv.VerifyRecipient(ctx, tokenFromURL(t, link))
+	wantErr := errors.Unauthorized("the token has been revoked")
+	if errToStr(err) != errToStr(wantErr) {
+		t.Fatalf("VerifyRecipient() error = %v, want %v", err, wantErr)
+	}
+	if db.recipient.IsVerified() {
+		t.Errorf("recipient verified with a revoked token")
+	}
+
+	link, err = srv.recipientURL("verify", recipientVerificationToken, db.recipient, recipientVerificationTTL)
+	if err != nil {
+		t.Fatalf("error generating verification link: %v", err)
+	}
+	got, err := srv.VerifyRecipient(ctx, tokenFromURL(t, link))
+	if err != nil {
+		t.Fatalf("VerifyRecipient() error = %v", err)
+	}
+	if !got.IsVerified() {
+		t.Errorf("recipient not verified with a valid token")
+	}
+}
+
+func TestVulcanitoService_UnsubscribeRecipientWithExpiredToken(t *testing.T) {
+	active := true
+	db := &inMemoryRecipientsStore{recipient: api.Recipient{TeamID: "team1", Email: "a@example.com", Active: &active}}
+	srv := vulcanitoService{db: db, jwtConfig: jwt.NewJWTConfig("key")}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{
+			name: "Expired",
+			claims: map[string]interface{}{
+				"iat": time.Now().Add(-2 * time.Hour).Unix(), "exp": time.Now().Add(-time.Hour).Unix(),
+				"type": recipientUnsubscribeToken, "team_id": "team1", "email": "a@example.com", "version": 0,
+			},
+		},
+		{
+			name: "WithoutExpiration",
+			claims: map[string]interface{}{
+				"iat": time.Now().Unix(), "type": recipientUnsubscribeToken, "team_id": "team1", "email": "a@example.com", "version": 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := srv.jwtConfig.GenerateToken(tt.claims)
+			if err != nil {
+				t.Fatalf("error generating token: %v", err)
+			}
+			if _, err := srv.UnsubscribeRecipient(context.Background(), token); err == nil {
+				t.Fatalf("UnsubscribeRecipient() expected error")
+			}
+			if !db.recipient.IsActive() {
+				t.Errorf("recipient unsubscribed with an invalid token")
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_SubscriptionRecipients(t *testing.T) {
+	active, inactive := true, false
+	verified := time.Now()
+	bounced := api.RecipientDeactivatedBounced
+	db := &inMemoryTeamRecipientsStore{recipients: []*api.Recipient{
+		{TeamID: "team1", Email: "a@example.com", Active: &active, VerifiedAt: &verified},
+		{TeamID: "team1", Email: "b@example.com", Active: &active},
+		{TeamID: "team1", Email: "c@example.com", Active: &inactive, VerifiedAt: &verified, DeactivatedReason: &bounced},
+		{TeamID: "team1", Email: "d@example.com", Active: &active, VerifiedAt: &verified, ReportTypes: api.RecipientReportDigest},
+		{TeamID: "team2", Email: "e@example.com", Active: &active, VerifiedAt: &verified},
+	}}
+	srv := vulcanitoService{db: db, jwtConfig: jwt.NewJWTConfig("key")}
+
+	tests := []struct {
+		name       string
+		recipients []string
+		want       []string
+	}{
+		{
+			name: "TeamRecipients",
+			want: []string{"a@example.com"},
+		},
+		{
+			name:       "SubscriptionRecipients",
+			recipients: []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"},
+			want:       []string{"a@example.com", "d@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := api.ReportSubscription{TeamID: "team1"}
+			for _, email := range tt.recipients {
+				subscription.Recipients = append(subscription.Recipients, &api.ReportSubscriptionRecipient{Email: email})
+			}
+			got, err := srv.subscriptionRecipients(context.Background(), subscription)
+			if err != nil {
+				t.Fatalf("subscriptionRecipients() error = %v", err)
+			}
+			var emails []string
+			for _, r := range got {
+				if r.UnsubscribeURL == "" {
+					t.Errorf("recipient %s without unsubscribe link", r.Email)
+				}
+				emails = append(emails, r.Email)
+			}
+			if diff := cmp.Diff(tt.want, emails); diff != "" {
+				t.Errorf("recipients mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (s vulcanitoService) ListReportSubscriptions(ctx context.Context, teamID string) ([]*api.ReportSubscription, error) {
+	if teamID == "" {
+		return nil, errors.NotFound(`Team ID is empty`)
+	}
+	return s.db.ListReportSubscriptions(teamID)
+}
+
+func (s vulcanitoService) FindReportSubscription(ctx context.Context, teamID, subscriptionID string) (*api.ReportSubscription, error) {
+	return s.db.FindReportSubscription(teamID, subscriptionID)
+}
+
+func (s vulcanitoService) CreateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	if subscription.Disabled == nil {
+		disabled := false
+		subscription.Disabled = &disabled
+	}
+	if err := s.validateReportSubscription(subscription); err != nil {
+		return nil, err
+	}
+	if err := subscription.ScheduleNextRun(time.Now()); err != nil {
+		return nil, errors.Validation(err)
+	}
+	return s.db.CreateReportSubscription(subscription)
+}
+
+// UpdateReportSubscription modifies the fields of a subscription that are set
+// in the given one. The recipients of the subscription are replaced when
+// they are not nil.
+func (s vulcanitoService) UpdateReportSubscription(ctx context.Context, subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	current, err := s.db.FindReportSubscription(subscription.TeamID, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Name != "" {
+		current.Name = subscription.Name
+	}
+	if subscription.ReportID != "" {
+		current.ReportID = subscription.ReportID
+	}
+	reschedule := false
+	if subscription.Cron != "" && subscription.Cron != current.Cron {
+		current.Cron = subscription.Cron
+		reschedule = true
+	}
+	if subscription.Recipients != nil {
+		current.Recipients = subscription.Recipients
+	}
+	if subscription.MinSeverity != "" {
+		current.MinSeverity = subscription.MinSeverity
+	}
+	if subscription.GroupID != nil {
+		current.GroupID = subscription.GroupID
+		if *current.GroupID == "" {
+			current.GroupID = nil
+		}
+	}
+	if subscription.Disabled != nil {
+		// Re-enabling a subscription must not send the reports it missed
+		// while it was disabled.
+		if current.IsDisabled() && !*subscription.Disabled {
+			reschedule = true
+		}
+		current.Disabled = subscription.Disabled
+	}
+	if err := s.validateReportSubscription(*current); err != nil {
+		return nil, err
+	}
+	if reschedule {
+		if err := current.ScheduleNextRun(time.Now()); err != nil {
+			return nil, errors.Validation(err)
+		}
+	}
+	return s.db.UpdateReportSubscription(*current)
+}
+
+func (s vulcanitoService) DeleteReportSubscription(ctx context.Context, subscription api.ReportSubscription) error {
+	return s.db.DeleteReportSubscription(subscription)
+}
+
+// validateReportSubscription checks the fields of a subscription, and that
+// its group and its recipients belong to the team. The recipients must be
+// recipients of the team so they verify their addresses and can unsubscribe.
+func (s vulcanitoService) validateReportSubscription(subscription api.ReportSubscription) error {
+	if err := subscription.Validate(); err != nil {
+		return errors.Validation(err)
+	}
+	if len(subscription.Recipients) > 0 {
+		recipients, err := s.db.ListRecipients(subscription.TeamID)
+		if err != nil {
+			return err
+		}
+		emails := map[string]bool{}
+		for _, r := range recipients {
+			emails[r.Email] = true
+		}
+		for _, r := range subscription.Recipients {
+			if !emails[r.Email] {
+				return errors.Validation("the recipient of the report subscription is not a recipient of the team", r.Email)
+			}
+		}
+	}
+	if subscription.GroupID == nil || *subscription.GroupID == "" {
+		return nil
+	}
+	_, err := s.db.FindGroupInfo(api.Group{TeamID: subscription.TeamID, ID: *subscription.GroupID})
+	if errors.IsKind(err, errors.ErrNotFound) {
+		return errors.Validation("the group of the report subscription does not exist")
+	}
+	return err
+}
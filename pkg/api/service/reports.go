@@ -9,7 +9,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/adevinta/errors"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+
 	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
 )
 
 func (s vulcanitoService) SendDigestReport(ctx context.Context, teamID string, startDate string, endDate string) error {
@@ -20,86 +24,190 @@ func (s vulcanitoService) SendDigestReport(ctx context.Context, teamID string, s
 		return err
 	}
 
-	// Gather recipient emails.
-	recipients, err := s.ListRecipients(ctx, teamID)
+	// Gather the recipients subscribed to the digest report.
+	recipients, err := s.ListReportRecipients(ctx, teamID, api.RecipientReportDigest)
 	if err != nil {
 		_ = s.logger.Log("ErrListRecipients", err)
 		return err
 	}
 
-	var emails []string
-	for _, r := range recipients {
-		emails = append(emails, r.Email)
-	}
-
 	dateFromStr := startDate
 	dateToStr := endDate
-
-	// Default value for dateFrom is 7 days ago (one week).
-	// Default value for dateTo is the current date.
+	now := time.Now()
 	if dateFromStr == "" && dateToStr == "" {
-		dateTo := time.Now()
-		dateFrom := dateTo.Add(-(7 * 24 * time.Hour))
+		dateFromStr, dateToStr = digestPeriod(now)
+	}
+
+	// The stats are computed from the stats snapshots of the team when
+	// there is one of every day of the period, to avoid querying the
+	// vulnerability DB.
+	severitiesStats, ok, err := s.snapshotDigestStats(ctx, team.ID, dateFromStr, dateToStr, now)
+	if err != nil {
+		_ = s.logger.Log("ErrListStatsSnapshots", err)
+		return err
+	}
+	if !ok {
+		severitiesStats, err = s.digestStats(ctx, api.StatsParams{Team: team.ID}, dateFromStr, dateToStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	liveReportURL := fmt.Sprintf("%s/report/report.html?team_id=%s&minDate=%s&maxDate=%s", s.reportsConfig.VulcanUIURL, teamID, dateFromStr, dateToStr)
+
+	return s.reportsClient.GenerateDigestReport(teamID, team.Name, dateFromStr, dateToStr, liveReportURL, toReportsRecipients(recipients), severitiesStats, true)
+}
 
-		dateToStr = dateTo.Format("2006-01-02")
-		dateFromStr = dateFrom.Format("2006-01-02")
+// SendReportSubscription generates the report of a subscription of a team
+// covering the period since its previous scheduled run and sends it to the
+// recipients of the subscription or, if it has none, to the ones of the team.
+// In both cases only the verified and active recipients receive the report.
+func (s vulcanitoService) SendReportSubscription(ctx context.Context, teamID, subscriptionID string) error {
+	if s.reportsClient == nil {
+		return errors.Default("reports are not configured")
+	}
+	subscription, err := s.db.FindReportSubscription(teamID, subscriptionID)
+	if err != nil {
+		return err
+	}
+	team, err := s.FindTeam(ctx, teamID)
+	if err != nil {
+		_ = s.logger.Log("ErrFindTeam", err)
+		return err
 	}
 
-	params := api.StatsParams{
-		Team: team.ID,
+	recipients, err := s.subscriptionRecipients(ctx, *subscription)
+	if err != nil {
+		_ = s.logger.Log("ErrListRecipients", err)
+		return err
 	}
 
+	groupID := ""
+	batches := []string{""}
+	if subscription.GroupID != nil && *subscription.GroupID != "" {
+		groupID = *subscription.GroupID
+		group, err := s.db.FindGroup(api.Group{TeamID: teamID, ID: groupID})
+		if err != nil {
+			return err
+		}
+		batches = groupIdentifierBatches(*group)
+		if len(batches) == 0 {
+			return errors.Validation("the group of the report subscription has no assets")
+		}
+	}
+
+	from, to, err := subscription.Period(time.Now())
+	if err != nil {
+		return errors.Validation(err)
+	}
+	dateFromStr := from.Format("2006-01-02")
+	dateToStr := to.Format("2006-01-02")
+
+	// The findings below the min severity of the subscription are excluded
+	// both from the stats and from the content of the report.
+	minScore := subscription.MinScore()
+	var open, diff, fixed vulndb.StatsIssueSeverity
+	for _, identifiers := range batches {
+		params := api.StatsParams{Team: team.ID, Identifiers: identifiers, MinScore: minScore}
+		batchOpen, batchDiff, batchFixed, err := s.digestIssues(ctx, params, dateFromStr, dateToStr)
+		if err != nil {
+			return err
+		}
+		open = addStatsIssues(open, batchOpen)
+		diff = addStatsIssues(diff, batchDiff)
+		fixed = addStatsIssues(fixed, batchFixed)
+	}
+	severitiesStats := digestSeveritiesStats(open, diff, fixed)
+
+	liveReportURL := fmt.Sprintf("%s/report/report.html?team_id=%s&minDate=%s&maxDate=%s", s.reportsConfig.VulcanUIURL, teamID, dateFromStr, dateToStr)
+	if minScore != 0 {
+		liveReportURL = fmt.Sprintf("%s&minScore=%.1f", liveReportURL, minScore)
+	}
+
+	info := reports.Subscription{
+		ID:          subscription.ID,
+		Name:        subscription.Name,
+		ReportID:    subscription.ReportID,
+		MinSeverity: subscription.MinSeverity,
+		MinScore:    minScore,
+		GroupID:     groupID,
+	}
+	return s.reportsClient.GenerateSubscriptionReport(teamID, team.Name, info, dateFromStr, dateToStr, liveReportURL, toReportsRecipients(recipients), severitiesStats)
+}
+
+// digestPeriod returns the default period of a digest report: the week
+// before the given time, ending the same day.
+func digestPeriod(now time.Time) (string, string) {
+	dateTo := now
+	dateFrom := dateTo.Add(-(7 * 24 * time.Hour))
+	return dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")
+}
+
+// digestStats returns the number of open findings of each severity at the
+// end date, the ones detected between the given dates and the ones fixed
+// between them. The stats are filtered using the given params.
+func (s vulcanitoService) digestStats(ctx context.Context, params api.StatsParams, dateFromStr, dateToStr string) (map[string]int, error) {
+	open, diff, fixed, err := s.digestIssues(ctx, params, dateFromStr, dateToStr)
+	if err != nil {
+		return nil, err
+	}
+	return digestSeveritiesStats(open, diff, fixed), nil
+}
+
+// digestIssues returns the number of open findings of each severity at the
+// end date, the ones detected between the given dates and the ones fixed
+// between them.
+func (s vulcanitoService) digestIssues(ctx context.Context, params api.StatsParams, dateFromStr, dateToStr string) (open, diff, fixed vulndb.StatsIssueSeverity, err error) {
+	currentParams := params
 	if dateToStr != "" {
-		params.AtDate = dateToStr
+		currentParams.AtDate = dateToStr
 	}
 
-	currentStats, err := s.vulndbClient.StatsOpen(ctx, params)
+	currentStats, err := s.vulndbClient.StatsOpen(ctx, currentParams)
 	if err != nil {
 		_ = s.logger.Log("ErrStatsOpen", err)
-		return err
+		return open, diff, fixed, err
 	}
 
-	diffStats, err := s.vulndbClient.StatsOpen(ctx, api.StatsParams{
-		Team:    team.ID,
-		MinDate: dateFromStr,
-		MaxDate: dateToStr,
-	})
+	diffParams := params
+	diffParams.MinDate = dateFromStr
+	diffParams.MaxDate = dateToStr
+	diffStats, err := s.vulndbClient.StatsOpen(ctx, diffParams)
 	if err != nil {
 		_ = s.logger.Log("ErrStatsOpen", err)
-		return err
+		return open, diff, fixed, err
 	}
 
-	fixedStats, err := s.vulndbClient.StatsFixed(ctx, api.StatsParams{
-		Team:    team.ID,
-		MinDate: dateFromStr,
-		MaxDate: dateToStr,
-	})
+	fixedStats, err := s.vulndbClient.StatsFixed(ctx, diffParams)
 	if err != nil {
 		_ = s.logger.Log("ErrStatsFixed", err)
-		return err
+		return open, diff, fixed, err
 	}
 
-	//s.vulndbClient.StatsOpen(ctx, params)
-	severitiesStats := make(map[string]int)
-	severitiesStats["info"] = currentStats.OpenIssues.Informational
-	severitiesStats["low"] = currentStats.OpenIssues.Low
-	severitiesStats["medium"] = currentStats.OpenIssues.Medium
-	severitiesStats["high"] = currentStats.OpenIssues.High
-	severitiesStats["critical"] = currentStats.OpenIssues.Critical
-
-	severitiesStats["infoDiff"] = diffStats.OpenIssues.Informational
-	severitiesStats["lowDiff"] = diffStats.OpenIssues.Low
-	severitiesStats["mediumDiff"] = diffStats.OpenIssues.Medium
-	severitiesStats["highDiff"] = diffStats.OpenIssues.High
-	severitiesStats["criticalDiff"] = diffStats.OpenIssues.Critical
-
-	severitiesStats["infoFixed"] = fixedStats.FixedIssues.Informational
-	severitiesStats["lowFixed"] = fixedStats.FixedIssues.Low
-	severitiesStats["mediumFixed"] = fixedStats.FixedIssues.Medium
-	severitiesStats["highFixed"] = fixedStats.FixedIssues.High
-	severitiesStats["criticalFixed"] = fixedStats.FixedIssues.Critical
-
-	liveReportURL := fmt.Sprintf("%s/report/report.html?team_id=%s&minDate=%s&maxDate=%s", s.reportsConfig.VulcanUIURL, teamID, dateFromStr, dateToStr)
+	return currentStats.OpenIssues, diffStats.OpenIssues, fixedStats.FixedIssues, nil
+}
 
-	return s.reportsClient.GenerateDigestReport(teamID, team.Name, dateFromStr, dateToStr, liveReportURL, emails, severitiesStats, true)
+// digestSeveritiesStats returns the stats of a digest report given the number
+// of open findings of each severity, the ones detected in the period and the
+// ones fixed in it.
+func digestSeveritiesStats(open, diff, fixed vulndb.StatsIssueSeverity) map[string]int {
+	stats := make(map[string]int)
+	stats["info"] = open.Informational
+	stats["low"] = open.Low
+	stats["medium"] = open.Medium
+	stats["high"] = open.High
+	stats["critical"] = open.Critical
+
+	stats["infoDiff"] = diff.Informational
+	stats["lowDiff"] = diff.Low
+	stats["mediumDiff"] = diff.Medium
+	stats["highDiff"] = diff.High
+	stats["criticalDiff"] = diff.Critical
+
+	stats["infoFixed"] = fixed.Informational
+	stats["lowFixed"] = fixed.Low
+	stats["mediumFixed"] = fixed.Medium
+	stats["highFixed"] = fixed.High
+	stats["criticalFixed"] = fixed.Critical
+	return stats
 }
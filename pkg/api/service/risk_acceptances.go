@@ -0,0 +1,203 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// RequestRiskAcceptance requests to accept the risk of a finding of a team
+// until the given expiry date. The status of the finding does not change
+// until the acceptance is approved by a team owner or by an admin user.
+func (s vulcanitoService) RequestRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) (*api.RiskAcceptance, error) {
+	if err := acceptance.Validate(time.Now()); err != nil {
+		return nil, errors.Validation(err)
+	}
+	finding, err := s.findTeamFinding(ctx, acceptance.TeamID, acceptance.FindingID)
+	if err != nil {
+		return nil, err
+	}
+	status := finding.Finding.Status
+	if !isValidFindingTransition(api.FindingStatusAcceptedRisk, status) {
+		return nil, errors.Validation(fmt.Sprintf("Status transition not allowed: from '%s' to '%s'", status, api.FindingStatusAcceptedRisk))
+	}
+	acceptance.Status = api.RiskAcceptancePending
+	acceptance.StatusPrevious = status
+	acceptance.ReviewedBy = nil
+	acceptance.ReviewNotes = nil
+	acceptance.ReviewedAt = nil
+	return s.db.CreateRiskAcceptance(acceptance)
+}
+
+func (s vulcanitoService) ListRiskAcceptances(ctx context.Context, teamID, findingID, status string) ([]*api.RiskAcceptance, error) {
+	return s.db.ListRiskAcceptances(teamID, findingID, status)
+}
+
+func (s vulcanitoService) FindRiskAcceptance(ctx context.Context, teamID, acceptanceID string) (*api.RiskAcceptance, error) {
+	return s.db.FindRiskAcceptance(teamID, acceptanceID)
+}
+
+// ApproveRiskAcceptance approves a pending risk acceptance and changes the
+// status of its finding to ACCEPTED_RISK.
+func (s vulcanitoService) ApproveRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*api.RiskAcceptance, error) {
+	acceptance, reviewer, err := s.reviewableRiskAcceptance(ctx, teamID, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if acceptance.IsExpired(now) {
+		return nil, errors.Validation("The risk acceptance has expired")
+	}
+	finding, err := s.findTeamFinding(ctx, teamID, acceptance.FindingID)
+	if err != nil {
+		return nil, err
+	}
+	overwrite := api.FindingOverwrite{
+		UserID:         reviewer.ID,
+		FindingID:      acceptance.FindingID,
+		StatusPrevious: finding.Finding.Status,
+		Status:         api.FindingStatusAcceptedRisk,
+		Notes:          fmt.Sprintf("Risk accepted until %s: %s", acceptance.ExpiresAt.Format(time.RFC3339), acceptance.Justification),
+		TeamID:         teamID,
+	}
+	if err := validateFindingOverwrite(overwrite); err != nil {
+		return nil, err
+	}
+	return s.reviewRiskAcceptance(*acceptance, reviewer, api.RiskAcceptanceApproved, notes, &overwrite)
+}
+
+// RejectRiskAcceptance rejects a pending risk acceptance. The status of its
+// finding does not change.
+func (s vulcanitoService) RejectRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*api.RiskAcceptance, error) {
+	acceptance, reviewer, err := s.reviewableRiskAcceptance(ctx, teamID, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reviewRiskAcceptance(*acceptance, reviewer, api.RiskAcceptanceRejected, notes, nil)
+}
+
+// ExpireRiskAcceptance marks as expired a pending or approved risk acceptance
+// whose expiry date passed. If the acceptance was approved and its finding is
+// still in the ACCEPTED_RISK status, the finding is reopened.
+func (s vulcanitoService) ExpireRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) error {
+	fromStatus := acceptance.Status
+	if fromStatus != api.RiskAcceptancePending && fromStatus != api.RiskAcceptanceApproved {
+		return nil
+	}
+	var overwrite *api.FindingOverwrite
+	if fromStatus == api.RiskAcceptanceApproved {
+		finding, err := s.vulndbClient.Finding(ctx, acceptance.FindingID)
+		if err != nil {
+			return err
+		}
+		if finding.Finding.Status == api.FindingStatusAcceptedRisk {
+			userID := acceptance.RequestedBy
+			if acceptance.ReviewedBy != nil {
+				userID = *acceptance.ReviewedBy
+			}
+			overwrite = &api.FindingOverwrite{
+				UserID:         userID,
+				FindingID:      acceptance.FindingID,
+				StatusPrevious: api.FindingStatusAcceptedRisk,
+				Status:         "OPEN",
+				Notes:          fmt.Sprintf("Risk acceptance expired on %s", acceptance.ExpiresAt.Format(time.RFC3339)),
+				TeamID:         acceptance.TeamID,
+			}
+		}
+	}
+	acceptance.Status = api.RiskAcceptanceExpired
+	_, err := s.db.TransitionRiskAcceptance(acceptance, fromStatus, overwrite)
+	return err
+}
+
+// revokeRiskAcceptance applies an overwrite that moves a finding out of the
+// ACCEPTED_RISK status, marking its approved risk acceptance as expired.
+func (s vulcanitoService) revokeRiskAcceptance(findingOverwrite api.FindingOverwrite) error {
+	acceptances, err := s.db.ListRiskAcceptances(findingOverwrite.TeamID, findingOverwrite.FindingID, api.RiskAcceptanceApproved)
+	if err != nil {
+		return err
+	}
+	if len(acceptances) == 0 {
+		return s.db.CreateFindingOverwrite(findingOverwrite)
+	}
+	acceptance := *acceptances[0]
+	acceptance.Status = api.RiskAcceptanceExpired
+	ok, err := s.db.TransitionRiskAcceptance(acceptance, api.RiskAcceptanceApproved, &findingOverwrite)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Validation("The risk acceptance of the finding was modified, try again")
+	}
+	return nil
+}
+
+// reviewableRiskAcceptance returns a pending risk acceptance of a team
+// together with the user in the context, if the user can review it. The
+// acceptances can be reviewed by the admin users and by the owners of the
+// team, except the ones they requested.
+func (s vulcanitoService) reviewableRiskAcceptance(ctx context.Context, teamID, acceptanceID string) (*api.RiskAcceptance, *api.User, error) {
+	user, err := api.UserFromContext(ctx)
+	if err != nil {
+		return nil, nil, errors.Default(err)
+	}
+	acceptance, err := s.db.FindRiskAcceptance(teamID, acceptanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Admin == nil || !*user.Admin {
+		member, err := s.db.FindTeamMember(teamID, user.ID)
+		if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
+			return nil, nil, err
+		}
+		if member == nil || member.Role != api.Owner {
+			return nil, nil, errors.Forbidden("Only the owners of the team and the admin users can review risk acceptances")
+		}
+		if acceptance.RequestedBy == user.ID {
+			return nil, nil, errors.Forbidden("A risk acceptance can not be reviewed by the user that requested it")
+		}
+	}
+	if !acceptance.IsPending() {
+		return nil, nil, errors.Validation(fmt.Sprintf("The risk acceptance is not pending: '%s'", acceptance.Status))
+	}
+	return acceptance, &user, nil
+}
+
+func (s vulcanitoService) reviewRiskAcceptance(acceptance api.RiskAcceptance, reviewer *api.User, status, notes string, overwrite *api.FindingOverwrite) (*api.RiskAcceptance, error) {
+	now := time.Now()
+	acceptance.Status = status
+	acceptance.ReviewedBy = &reviewer.ID
+	acceptance.ReviewedAt = &now
+	if notes != "" {
+		acceptance.ReviewNotes = &notes
+	}
+	ok, err := s.db.TransitionRiskAcceptance(acceptance, api.RiskAcceptancePending, overwrite)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Validation("The risk acceptance is no longer pending")
+	}
+	return s.db.FindRiskAcceptance(acceptance.TeamID, acceptance.ID)
+}
+
+// findTeamFinding returns a finding from the vulnerability db, ensuring it
+// belongs to the given team.
+func (s vulcanitoService) findTeamFinding(ctx context.Context, teamID, findingID string) (*api.Finding, error) {
+	finding, err := s.vulndbClient.Finding(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(finding.Finding.Target.Teams, teamID) {
+		return nil, errors.Forbidden("Finding does not belong to given team")
+	}
+	return finding, nil
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+const (
+	// defaultFindingsPageSize is the page size used by the vulnerability db
+	// when the size is not specified.
+	defaultFindingsPageSize = 20
+
+	// riskScoresTTL is the time the risk scores of a team are cached, so
+	// the changes in its assets can take that long to be reflected.
+	riskScoresTTL = time.Minute
+	// riskScoresCacheSize is the maximum number of teams whose risk scores
+	// are cached.
+	riskScoresCacheSize = 500
+	// assetsRiskBatchSize is the maximum number of identifiers of the assets
+	// read in a single query.
+	assetsRiskBatchSize = 500
+)
+
+// riskScoresCache caches by team the risk context of its assets and its
+// findings sorted by risk score, so the pages of a list sorted by risk score
+// don't read again all the findings of the team. A nil cache caches nothing.
+type riskScoresCache struct {
+	teams *lru.Cache
+}
+
+// teamRiskScores are the risk scores cached for a team.
+type teamRiskScores struct {
+	mu      sync.Mutex
+	expires time.Time
+	// risks contains the risk context of the assets of the team by their
+	// identifier. The identifiers that are not assets of the team have the
+	// DefaultAssetRisk.
+	risks map[string]api.AssetRisk
+	// sorted contains the findings matching a filter sorted by ascending
+	// risk score.
+	sorted map[string][]api.ScoredFinding
+}
+
+func newRiskScoresCache() *riskScoresCache {
+	teams, err := lru.New(riskScoresCacheSize)
+	if err != nil {
+		// The size is a positive constant.
+		panic(err)
+	}
+	return &riskScoresCache{teams: teams}
+}
+
+// team returns the risk scores cached for a team.
+func (c *riskScoresCache) team(teamID string) *teamRiskScores {
+	now := time.Now()
+	fresh := &teamRiskScores{
+		expires: now.Add(riskScoresTTL),
+		risks:   map[string]api.AssetRisk{},
+		sorted:  map[string][]api.ScoredFinding{},
+	}
+	if c == nil {
+		return fresh
+	}
+	if v, ok := c.teams.Get(teamID); ok {
+		if t := v.(*teamRiskScores); now.Before(t.expires) {
+			return t
+		}
+	}
+	c.teams.Add(teamID, fresh)
+	return fresh
+}
+
+// FindFinding returns a finding from the vulnerability db. If the finding
+// belongs to the given team, it includes its risk score in the context of
+// the assets of the team.
+func (s vulcanitoService) FindFinding(ctx context.Context, teamID, findingID string) (*api.Finding, error) {
+	finding, err := s.vulndbClient.Finding(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if teamID == "" || !slices.Contains(finding.Finding.Target.Teams, teamID) {
+		return finding, nil
+	}
+	f := finding.Finding.FindingExpanded
+	risks, err := s.assetsRisk(teamID, []vulndb.FindingExpanded{f})
+	if err != nil {
+		return nil, err
+	}
+	finding.Finding.RiskScore = riskScore(risks, f)
+	return finding, nil
+}
+
+// assetsRisk returns the risk context of the targets of the given findings
+// in the assets of a team, indexed by their identifier. Only the assets not
+// already cached are read.
+func (s vulcanitoService) assetsRisk(teamID string, findings []vulndb.FindingExpanded) (map[string]api.AssetRisk, error) {
+	risks := map[string]api.AssetRisk{}
+	if teamID == "" {
+		return risks, nil
+	}
+	cached := s.riskScores.team(teamID)
+	cached.mu.Lock()
+	var missing []string
+	for _, f := range findings {
+		identifier := f.Target.Identifier
+		if _, ok := risks[identifier]; ok {
+			continue
+		}
+		risk, ok := cached.risks[identifier]
+		if !ok {
+			missing = append(missing, identifier)
+			risk = api.DefaultAssetRisk
+		}
+		risks[identifier] = risk
+	}
+	cached.mu.Unlock()
+
+	loaded := map[string]api.AssetRisk{}
+	for start := 0; start < len(missing); start += assetsRiskBatchSize {
+		end := min(start+assetsRiskBatchSize, len(missing))
+		assets, err := s.db.ListAssetsByIdentifiers(teamID, missing[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assets {
+			risk := api.NewAssetRisk(*a)
+			if current, ok := loaded[a.Identifier]; ok {
+				risk = api.MaxAssetRisk(current, risk)
+			}
+			loaded[a.Identifier] = risk
+		}
+	}
+
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+	for _, identifier := range missing {
+		risk, ok := loaded[identifier]
+		if !ok {
+			risk = api.DefaultAssetRisk
+		}
+		cached.risks[identifier] = risk
+		risks[identifier] = risk
+	}
+	return risks, nil
+}
+
+func riskScore(risks map[string]api.AssetRisk, finding vulndb.FindingExpanded) float32 {
+	risk, ok := risks[finding.Target.Identifier]
+	if !ok {
+		risk = api.DefaultAssetRisk
+	}
+	return risk.Score(finding.Score)
+}
+
+// scoreFindings returns the findings of a team together with their risk
+// score.
+func (s vulcanitoService) scoreFindings(teamID string, findings []vulndb.FindingExpanded) ([]api.ScoredFinding, error) {
+	risks, err := s.assetsRisk(teamID, findings)
+	if err != nil {
+		return nil, err
+	}
+	scored := []api.ScoredFinding{}
+	for _, f := range findings {
+		scored = append(scored, api.ScoredFinding{FindingExpanded: f, RiskScore: riskScore(risks, f)})
+	}
+	return scored, nil
+}
+
+func (s vulcanitoService) scoreFindingsList(teamID string, list *api.FindingsList) (*api.ScoredFindingsList, error) {
+	findings, err := s.scoreFindings(teamID, list.Findings)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ScoredFindingsList{Findings: findings, Pagination: list.Pagination}, nil
+}
+
+// listFindingsByRiskScore returns a page of the findings matching the params
+// sorted by their risk score. As the vulnerability db does not know the risk
+// score of the findings, all of them are retrieved and sorted, and the result
+// is cached so the next pages are taken from it.
+func (s vulcanitoService) listFindingsByRiskScore(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.ScoredFindingsList, error) {
+	desc := strings.HasPrefix(params.SortBy, "-")
+	params.SortBy = ""
+	findings, err := s.findingsByRiskScore(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if desc {
+		reversed := make([]api.ScoredFinding, 0, len(findings))
+		for i := len(findings) - 1; i >= 0; i-- {
+			reversed = append(reversed, findings[i])
+		}
+		findings = reversed
+	}
+
+	page, size := pagination.Page, pagination.Size
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultFindingsPageSize
+	}
+	offset := (page - 1) * size
+	start, end := offset, offset+size
+	if start > len(findings) {
+		start = len(findings)
+	}
+	if end > len(findings) {
+		end = len(findings)
+	}
+	return &api.ScoredFindingsList{
+		Findings: findings[start:end],
+		Pagination: api.PaginationInfo{
+			Limit:  size,
+			Offset: offset,
+			Total:  len(findings),
+			More:   end < len(findings),
+		},
+	}, nil
+}
+
+// findingsByRiskScore returns the findings matching the params sorted by
+// ascending risk score.
+func (s vulcanitoService) findingsByRiskScore(ctx context.Context, params api.FindingsParams) ([]api.ScoredFinding, error) {
+	key := fmt.Sprintf("%+v", params)
+	cached := s.riskScores.team(params.Team)
+	cached.mu.Lock()
+	findings, ok := cached.sorted[key]
+	cached.mu.Unlock()
+	if ok {
+		return findings, nil
+	}
+
+	all, err := s.allFindings(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	findings, err = s.scoreFindings(params.Team, all)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].RiskScore < findings[j].RiskScore
+	})
+
+	cached.mu.Lock()
+	cached.sorted[key] = findings
+	cached.mu.Unlock()
+	return findings, nil
+}
+
+// riskScoreStats counts by severity the findings of a team with the given
+// status whose risk score is in the range specified in the params.
+func (s vulcanitoService) riskScoreStats(ctx context.Context, params api.StatsParams, status string) (vulndb.StatsIssueSeverity, error) {
+	stats := vulndb.StatsIssueSeverity{}
+	if params.Team == "" {
+		return stats, errors.Validation("The risk score filter is only supported in the stats of a team")
+	}
+	all, err := s.allFindings(ctx, api.FindingsParams{
+		Team:        params.Team,
+		Status:      status,
+		MinScore:    params.MinScore,
+		MaxScore:    params.MaxScore,
+		AtDate:      params.AtDate,
+		MinDate:     params.MinDate,
+		MaxDate:     params.MaxDate,
+		Identifiers: params.Identifiers,
+		Labels:      params.Labels,
+	})
+	if err != nil {
+		return stats, err
+	}
+	findings, err := s.scoreFindings(params.Team, all)
+	if err != nil {
+		return stats, err
+	}
+	for _, f := range findings {
+		if float64(f.RiskScore) < params.MinRiskScore {
+			continue
+		}
+		if params.MaxRiskScore != 0 && float64(f.RiskScore) > params.MaxRiskScore {
+			continue
+		}
+		switch api.SeverityName(f.Score) {
+		case "critical":
+			stats.Critical++
+		case "high":
+			stats.High++
+		case "medium":
+			stats.Medium++
+		case "low":
+			stats.Low++
+		default:
+			stats.Informational++
+		}
+	}
+	return stats, nil
+}
+
+// errRiskScoreStats is returned by the stats that do not support filtering
+// the findings by their risk score.
+var errRiskScoreStats = errors.Validation("The risk score filter is only supported in the open and fixed stats")
+
+func hasRiskScoreFilter(params api.StatsParams) bool {
+	return params.MinRiskScore != 0 || params.MaxRiskScore != 0
+}
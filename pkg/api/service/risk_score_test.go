@@ -0,0 +1,191 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// inMemoryAssetsStore returns the assets of the teams.
+type inMemoryAssetsStore struct {
+	api.VulcanitoStore
+	assets map[string][]*api.Asset
+	// read contains the identifiers of the assets read.
+	read []string
+}
+
+func (s *inMemoryAssetsStore) ListAssetsByIdentifiers(teamID string, identifiers []string) ([]*api.Asset, error) {
+	s.read = append(s.read, identifiers...)
+	var assets []*api.Asset
+	for _, a := range s.assets[teamID] {
+		if slices.Contains(identifiers, a.Identifier) {
+			assets = append(assets, a)
+		}
+	}
+	return assets, nil
+}
+
+// countingFindingsClient counts the pages of findings read.
+type countingFindingsClient struct {
+	*inMemoryFindingsClient
+	pages int
+}
+
+func (c *countingFindingsClient) Findings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	c.pages++
+	return c.inMemoryFindingsClient.Findings(ctx, params, pagination)
+}
+
+func (c *countingFindingsClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	for _, f := range c.findingsAt[""] {
+		if f.ID == findingID {
+			f.Target.Teams = []string{"team1"}
+			return &api.Finding{Finding: api.FindingExpanded{ScoredFinding: api.ScoredFinding{FindingExpanded: f}}}, nil
+		}
+	}
+	return nil, errors.NotFound("finding not found")
+}
+
+func newScoredFinding(id, target string, score, riskScore float32) api.ScoredFinding {
+	f := newFinding(id, target)
+	f.Score = score
+	return api.ScoredFinding{FindingExpanded: f, RiskScore: riskScore}
+}
+
+func TestVulcanitoService_ListFindingsByRiskScore(t *testing.T) {
+	findings := []vulndb.FindingExpanded{}
+	for _, f := range []api.ScoredFinding{
+		newScoredFinding("f1", "critical.example.com", 5, 0),
+		newScoredFinding("f2", "internal.example.com", 7, 0),
+		newScoredFinding("f3", "unknown.example.com", 6, 0),
+	} {
+		findings = append(findings, f.FindingExpanded)
+	}
+	vulndbClient := &inMemoryFindingsClient{findingsAt: map[string][]vulndb.FindingExpanded{"": findings}}
+	db := &inMemoryAssetsStore{assets: map[string][]*api.Asset{
+		"team1": {
+			{Identifier: "critical.example.com", ROLFP: &api.ROLFP{IsEmpty: true}, EnvironmentalCVSS: common.String("10")},
+			{Identifier: "internal.example.com", ROLFP: &api.ROLFP{Reputation: 1}, AssetAnnotations: []*api.AssetAnnotation{
+				{Key: api.ExposureAnnotation, Value: api.ExposureInternal},
+			}},
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		sortBy     string
+		pagination api.Pagination
+		want       *api.ScoredFindingsList
+	}{
+		{
+			name:       "Descending",
+			sortBy:     "-risk_score",
+			pagination: api.Pagination{Page: 1, Size: 2},
+			want: &api.ScoredFindingsList{
+				Findings: []api.ScoredFinding{
+					newScoredFinding("f1", "critical.example.com", 5, 9),
+					newScoredFinding("f3", "unknown.example.com", 6, 7.2),
+				},
+				Pagination: api.PaginationInfo{Limit: 2, Offset: 0, Total: 3, More: true},
+			},
+		},
+		{
+			name:       "AscendingLastPage",
+			sortBy:     "risk_score",
+			pagination: api.Pagination{Page: 2, Size: 2},
+			want: &api.ScoredFindingsList{
+				Findings: []api.ScoredFinding{
+					newScoredFinding("f1", "critical.example.com", 5, 9),
+				},
+				Pagination: api.PaginationInfo{Limit: 2, Offset: 2, Total: 3, More: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := vulcanitoService{db: db, vulndbClient: vulndbClient}
+			params := api.FindingsParams{Team: "team1", SortBy: tt.sortBy}
+			got, err := srv.ListFindings(context.Background(), params, tt.pagination)
+			if err != nil {
+				t.Fatalf("ListFindings() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListFindings() mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_ListFindingsByRiskScoreCached(t *testing.T) {
+	findings := []vulndb.FindingExpanded{
+		newFinding("f1", "a.example.com"),
+		newFinding("f2", "b.example.com"),
+		newFinding("f3", "a.example.com"),
+	}
+	vulndbClient := &countingFindingsClient{inMemoryFindingsClient: &inMemoryFindingsClient{findingsAt: map[string][]vulndb.FindingExpanded{"": findings}}}
+	db := &inMemoryAssetsStore{assets: map[string][]*api.Asset{
+		"team1": {{Identifier: "a.example.com"}},
+	}}
+	srv := vulcanitoService{db: db, vulndbClient: vulndbClient, riskScores: newRiskScoresCache()}
+
+	params := api.FindingsParams{Team: "team1", SortBy: "-risk_score"}
+	for page := 1; page <= 3; page++ {
+		got, err := srv.ListFindings(context.Background(), params, api.Pagination{Page: page, Size: 1})
+		if err != nil {
+			t.Fatalf("ListFindings() error = %v", err)
+		}
+		if len(got.Findings) != 1 {
+			t.Fatalf("got %d findings in page %d, want 1", len(got.Findings), page)
+		}
+	}
+	// The findings are read once, one finding per page of the fake client,
+	// and every identifier is read once.
+	if vulndbClient.pages != len(findings) {
+		t.Errorf("got %d pages of findings read, want %d", vulndbClient.pages, len(findings))
+	}
+	if diff := cmp.Diff([]string{"a.example.com", "b.example.com"}, db.read); diff != "" {
+		t.Errorf("assets read mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func TestVulcanitoService_FindFinding(t *testing.T) {
+	f := newFinding("f1", "critical.example.com")
+	f.Score = 5
+	vulndbClient := &countingFindingsClient{inMemoryFindingsClient: &inMemoryFindingsClient{findingsAt: map[string][]vulndb.FindingExpanded{"": {f}}}}
+	db := &inMemoryAssetsStore{assets: map[string][]*api.Asset{
+		"team1": {{Identifier: "critical.example.com", ROLFP: &api.ROLFP{IsEmpty: true}, EnvironmentalCVSS: common.String("10")}},
+	}}
+	srv := vulcanitoService{db: db, vulndbClient: vulndbClient}
+
+	tests := []struct {
+		name   string
+		teamID string
+		want   float32
+	}{
+		{name: "TeamFinding", teamID: "team1", want: 9},
+		{name: "OtherTeam", teamID: "team2", want: 0},
+		{name: "NoTeam", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := srv.FindFinding(context.Background(), tt.teamID, "f1")
+			if err != nil {
+				t.Fatalf("FindFinding() error = %v", err)
+			}
+			if got.Finding.RiskScore != tt.want {
+				t.Errorf("got risk score %v, want %v", got.Finding.RiskScore, tt.want)
+			}
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"context"
 	errs "errors"
 	"fmt"
-	"net/http"
 
 	"gopkg.in/go-playground/validator.v9"
 
@@ -27,9 +26,8 @@ func (s vulcanitoService) ListScans(ctx context.Context, teamID string, programI
 	if err != nil {
 		return nil, err
 	}
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, s.scanEngineConfig)
 	scans := []*api.Scan{}
-	response, err := scanengine.GetScans(programID)
+	response, err := s.scanEngineClient.GetScans(ctx, programID)
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +73,10 @@ func (s vulcanitoService) CreateScan(ctx context.Context, scan api.Scan, teamID
 	if err != nil {
 		return nil, err
 	}
-	scanengineClient := scanengine.NewClient(ctx, http.DefaultClient, s.scanEngineConfig)
-	scanRequest, err := scanengineClient.CreateScanRequest(*program, scan.ScheduledTime, program.ID, scan.RequestedBy, team.Tag)
+	if err := s.setAssetChecktypeSettings(ctx, program, teamID); err != nil {
+		return nil, err
+	}
+	scanRequest, err := scanengine.CreateScanRequest(*program, scan.ScheduledTime, program.ID, scan.RequestedBy, team.Tag)
 	if err != nil {
 		if errs.Is(err, scanengine.ErrProgramWithoutPolicyGroups) {
 			return nil, errors.Validation(err)
@@ -87,7 +87,7 @@ func (s vulcanitoService) CreateScan(ctx context.Context, scan api.Scan, teamID
 		return nil, errors.Default(err)
 	}
 
-	scanResponse, err := scanengineClient.Create(scanRequest)
+	scanResponse, err := s.scanEngineClient.Create(ctx, scanRequest)
 	if err != nil {
 		if errs.Is(err, scanengine.ErrUnprocessableEntity) {
 			return nil, errors.Validation(err)
@@ -144,8 +144,7 @@ func (s vulcanitoService) FindScan(ctx context.Context, scanID, teamID string) (
 		ID: scanID,
 	}
 	// query scan-engine
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, s.scanEngineConfig)
-	scanResponse, err := scanengine.Get(scanID)
+	scanResponse, err := s.scanEngineClient.Get(ctx, scanID)
 	if err != nil {
 		return nil, errors.Default(err)
 	}
@@ -190,8 +189,7 @@ func (s vulcanitoService) AbortScan(ctx context.Context, scanID string, teamID s
 	if scan.Program.TeamID != teamID {
 		return nil, errors.Forbidden("Scan does not belong to given team")
 	}
-	scanengine := scanengine.NewClient(ctx, http.DefaultClient, s.scanEngineConfig)
-	scanEngineScan, err := scanengine.Abort(scanID)
+	scanEngineScan, err := s.scanEngineClient.Abort(ctx, scanID)
 	if err != nil {
 		return nil, errors.Default(err)
 	}
@@ -199,6 +197,45 @@ func (s vulcanitoService) AbortScan(ctx context.Context, scanID string, teamID s
 	return scan, nil
 }
 
+// ListScanQueue returns the scans that are waiting to be sent to the scan
+// engine or running. If teamID is empty the scans of all the teams are
+// returned.
+func (s vulcanitoService) ListScanQueue(ctx context.Context, teamID string) ([]*api.ScanQueueEntry, error) {
+	statuses := []api.ScanQueueStatus{api.ScanQueueStatusQueued, api.ScanQueueStatusRunning}
+	return s.db.ListScanQueueEntries(teamID, statuses)
+}
+
+// CancelScanQueueEntry cancels a scan of a team that is waiting in the queue
+// to be sent to the scan engine.
+func (s vulcanitoService) CancelScanQueueEntry(ctx context.Context, teamID, entryID string) error {
+	entries, err := s.db.ListScanQueueEntries(teamID, []api.ScanQueueStatus{api.ScanQueueStatusQueued, api.ScanQueueStatusRunning})
+	if err != nil {
+		return err
+	}
+	var entry *api.ScanQueueEntry
+	for _, e := range entries {
+		if e.ID == entryID {
+			entry = e
+			break
+		}
+	}
+	if entry == nil {
+		return errors.NotFound("queued scan not found")
+	}
+	if entry.Status != api.ScanQueueStatusQueued {
+		return errors.Validation("the scan is already running")
+	}
+	cancelled, err := s.db.CancelScanQueueEntry(teamID, entryID)
+	if err != nil {
+		return err
+	}
+	// The scan was sent to the scan engine after listing the queue.
+	if !cancelled {
+		return errors.Validation("the scan is already running")
+	}
+	return nil
+}
+
 // TODO: no endpoint exists for update/delete scan
 func (s vulcanitoService) UpdateScan(ctx context.Context, scan api.Scan) (*api.Scan, error) {
 	return nil, errors.Default("not implemented")
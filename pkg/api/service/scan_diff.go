@@ -0,0 +1,211 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+const vulndbDateFmt = "2006-01-02"
+
+// DiffProgramScans compares the scans fromID and toID of a program. If both
+// are empty the latest finished scan of the program is compared with the
+// previous one.
+func (s vulcanitoService) DiffProgramScans(ctx context.Context, teamID, programID, fromID, toID string) (*api.ScanDiff, error) {
+	from, to, err := ScansToDiff(ctx, s, teamID, programID, fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	return s.DiffScans(ctx, teamID, from, to)
+}
+
+// ScansToDiff returns the scans of a program to compare, found through the
+// given service, so the middlewares that handle the scans of their own
+// programs can use it. If fromID and toID are empty the latest finished scan
+// of the program is compared with the latest one finished a previous day.
+func ScansToDiff(ctx context.Context, s api.VulcanitoService, teamID, programID, fromID, toID string) (from, to *api.Scan, err error) {
+	switch {
+	case fromID == "" && toID == "":
+		scans, err := s.ListScans(ctx, teamID, programID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return latestFinishedScans(scans)
+	case fromID != "" && toID != "":
+		from, err = s.FindScan(ctx, fromID, teamID)
+		if err != nil {
+			return nil, nil, err
+		}
+		to, err = s.FindScan(ctx, toID, teamID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if from.ProgramID != programID || to.ProgramID != programID {
+			return nil, nil, errors.Validation("Scans do not belong to the given program")
+		}
+		return from, to, nil
+	default:
+		return nil, nil, errors.Validation("Provide both from and to scans or none of them")
+	}
+}
+
+// latestFinishedScans returns the latest of the finished scans and the
+// latest one finished a day before it.
+func latestFinishedScans(scans []*api.Scan) (previous, latest *api.Scan, err error) {
+	finished := []*api.Scan{}
+	for _, scan := range scans {
+		if scan.EndTime != nil {
+			finished = append(finished, scan)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].EndTime.Before(*finished[j].EndTime)
+	})
+	if len(finished) > 0 {
+		latest = finished[len(finished)-1]
+		for i := len(finished) - 2; i >= 0; i-- {
+			if !sameDiffDate(finished[i], latest) {
+				return finished[i], latest, nil
+			}
+		}
+	}
+	return nil, nil, errors.NotFound("The program does not have two scans finished different days to compare")
+}
+
+// sameDiffDate returns true if the given finished scans finished the same
+// day, so they can not be compared.
+func sameDiffDate(a, b *api.Scan) bool {
+	return a.EndTime.Format(vulndbDateFmt) == b.EndTime.Format(vulndbDateFmt)
+}
+
+// DiffScans compares two finished scans of the same program. The findings
+// are compared using their status in the vulnerability db at the end date of
+// each scan, and only the findings of the targets scanned by any of the two
+// scans are taken into account. As the vulnerability db works with dates,
+// the scans must have finished different days.
+func (s vulcanitoService) DiffScans(ctx context.Context, teamID string, from, to *api.Scan) (*api.ScanDiff, error) {
+	if from.ProgramID != to.ProgramID {
+		return nil, errors.Validation("Scans belong to different programs")
+	}
+	if from.EndTime == nil || to.EndTime == nil {
+		return nil, errors.Validation("Only finished scans can be compared")
+	}
+	if sameDiffDate(from, to) {
+		return nil, errors.Validation("Scans finished the same day can not be compared")
+	}
+
+	fromTargets, err := scanTargets(ctx, s.scanEngineClient, from.ID)
+	if err != nil {
+		return nil, err
+	}
+	toTargets, err := scanTargets(ctx, s.scanEngineClient, to.ID)
+	if err != nil {
+		return nil, err
+	}
+	scope := map[string]struct{}{}
+	for t := range fromTargets {
+		scope[t] = struct{}{}
+	}
+	for t := range toTargets {
+		scope[t] = struct{}{}
+	}
+
+	fromFindings, err := s.scopedOpenFindings(ctx, teamID, from.EndTime.Format(vulndbDateFmt), scope)
+	if err != nil {
+		return nil, err
+	}
+	toFindings, err := s.scopedOpenFindings(ctx, teamID, to.EndTime.Format(vulndbDateFmt), scope)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &api.ScanDiff{
+		From:          from,
+		To:            to,
+		NewFindings:   []vulndb.FindingExpanded{},
+		FixedFindings: []vulndb.FindingExpanded{},
+		OpenFindings:  []vulndb.FindingExpanded{},
+		AssetsEntered: targetsNotIn(toTargets, fromTargets),
+		AssetsLeft:    targetsNotIn(fromTargets, toTargets),
+	}
+	fromIDs := map[string]struct{}{}
+	for _, f := range fromFindings {
+		fromIDs[f.ID] = struct{}{}
+	}
+	toIDs := map[string]struct{}{}
+	for _, f := range toFindings {
+		toIDs[f.ID] = struct{}{}
+		if _, ok := fromIDs[f.ID]; ok {
+			diff.OpenFindings = append(diff.OpenFindings, f)
+		} else {
+			diff.NewFindings = append(diff.NewFindings, f)
+		}
+	}
+	for _, f := range fromFindings {
+		if _, ok := toIDs[f.ID]; !ok {
+			diff.FixedFindings = append(diff.FixedFindings, f)
+		}
+	}
+	return diff, nil
+}
+
+// scopedOpenFindings returns the findings of a team that were open at the
+// given date and affect any of the given targets. The findings are filtered
+// by target in the vulnerability db, in batches of at most
+// maxStatsIdentifiers targets.
+func (s vulcanitoService) scopedOpenFindings(ctx context.Context, teamID, atDate string, targets map[string]struct{}) ([]vulndb.FindingExpanded, error) {
+	identifiers := []string{}
+	for t := range targets {
+		identifiers = append(identifiers, t)
+	}
+	sort.Strings(identifiers)
+	scoped := []vulndb.FindingExpanded{}
+	for _, batch := range identifierBatches(identifiers) {
+		params := api.FindingsParams{
+			Team:        teamID,
+			Status:      vulnerabilitydb.FindingStatusOpen,
+			AtDate:      atDate,
+			Identifiers: batch,
+		}
+		findings, err := s.allFindings(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		scoped = append(scoped, findings...)
+	}
+	return scoped, nil
+}
+
+// scanTargets returns the identifiers of the targets checked by a scan.
+func scanTargets(ctx context.Context, client scanengine.Client, scanID string) (map[string]struct{}, error) {
+	checks, err := client.GetScanChecks(ctx, scanID)
+	if err != nil {
+		return nil, errors.Default(err)
+	}
+	targets := map[string]struct{}{}
+	for _, c := range checks.Checks {
+		targets[c.Target] = struct{}{}
+	}
+	return targets, nil
+}
+
+// targetsNotIn returns, sorted, the targets in a that are not in b.
+func targetsNotIn(a, b map[string]struct{}) []string {
+	targets := []string{}
+	for t := range a {
+		if _, ok := b[t]; !ok {
+			targets = append(targets, t)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	scanengineData "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// inMemoryFindingsClient returns, for each date, the findings stored for it
+// in pages of one finding, filtered by the identifiers in the params if any.
+type inMemoryFindingsClient struct {
+	vulnerabilitydb.Client
+	findingsAt map[string][]vulndb.FindingExpanded
+}
+
+func (c *inMemoryFindingsClient) Findings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	findings := c.findingsAt[params.AtDate]
+	if params.Identifiers != "" {
+		identifiers := strings.Split(params.Identifiers, ",")
+		filtered := []vulndb.FindingExpanded{}
+		for _, f := range findings {
+			if slices.Contains(identifiers, f.Target.Identifier) {
+				filtered = append(filtered, f)
+			}
+		}
+		findings = filtered
+	}
+	list := &api.FindingsList{Findings: []vulndb.FindingExpanded{}}
+	if pagination.Page <= len(findings) {
+		list.Findings = append(list.Findings, findings[pagination.Page-1])
+	}
+	list.Pagination.More = pagination.Page < len(findings)
+	return list, nil
+}
+
+func newFinding(id, target string) vulndb.FindingExpanded {
+	return vulndb.FindingExpanded{
+		Finding: vulndb.Finding{ID: id, Status: "OPEN"},
+		Target:  vulndb.Target{Identifier: target},
+	}
+}
+
+func TestVulcanitoService_DiffScans(t *testing.T) {
+	fromEnd := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	toEnd := time.Date(2021, 3, 8, 10, 0, 0, 0, time.UTC)
+	sameDayEnd := fromEnd.Add(time.Hour)
+	scanEngineClient := &scanenginetest.Fake{
+		Checks: map[string][]scanengineData.GetCheckResponse{
+			"scan1": {{Target: "a.example.com"}, {Target: "b.example.com"}},
+			"scan2": {{Target: "b.example.com"}, {Target: "c.example.com"}},
+		},
+	}
+
+	vulndbClient := &inMemoryFindingsClient{
+		findingsAt: map[string][]vulndb.FindingExpanded{
+			"2021-03-01": {
+				newFinding("f1", "a.example.com"),
+				newFinding("f2", "b.example.com"),
+				newFinding("out", "other.example.com"),
+			},
+			"2021-03-08": {
+				newFinding("f2", "b.example.com"),
+				newFinding("f3", "c.example.com"),
+				newFinding("out", "other.example.com"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		from    *api.Scan
+		to      *api.Scan
+		want    *api.ScanDiff
+		wantErr bool
+	}{
+		{
+			name: "HappyPath",
+			from: &api.Scan{ID: "scan1", ProgramID: "p1", EndTime: &fromEnd},
+			to:   &api.Scan{ID: "scan2", ProgramID: "p1", EndTime: &toEnd},
+			want: &api.ScanDiff{
+				From:          &api.Scan{ID: "scan1", ProgramID: "p1", EndTime: &fromEnd},
+				To:            &api.Scan{ID: "scan2", ProgramID: "p1", EndTime: &toEnd},
+				NewFindings:   []vulndb.FindingExpanded{newFinding("f3", "c.example.com")},
+				FixedFindings: []vulndb.FindingExpanded{newFinding("f1", "a.example.com")},
+				OpenFindings:  []vulndb.FindingExpanded{newFinding("f2", "b.example.com")},
+				AssetsEntered: []string{"c.example.com"},
+				AssetsLeft:    []string{"a.example.com"},
+			},
+		},
+		{
+			name:    "DifferentPrograms",
+			from:    &api.Scan{ID: "scan1", ProgramID: "p1", EndTime: &fromEnd},
+			to:      &api.Scan{ID: "scan2", ProgramID: "p2", EndTime: &toEnd},
+			wantErr: true,
+		},
+		{
+			name:    "SameDay",
+			from:    &api.Scan{ID: "scan1", ProgramID: "p1", EndTime: &fromEnd},
+			to:      &api.Scan{ID: "scan2", ProgramID: "p1", EndTime: &sameDayEnd},
+			wantErr: true,
+		},
+		{
+			name:    "NotFinished",
+			from:    &api.Scan{ID: "scan1", ProgramID: "p1", EndTime: &fromEnd},
+			to:      &api.Scan{ID: "scan2", ProgramID: "p1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := vulcanitoService{
+				scanEngineClient: scanEngineClient,
+				vulndbClient:     vulndbClient,
+			}
+			got, err := srv.DiffScans(context.Background(), "team1", tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DiffScans() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffScans() mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+// inMemoryScansService returns the scans stored in it.
+type inMemoryScansService struct {
+	api.VulcanitoService
+	scans []*api.Scan
+}
+
+func (s inMemoryScansService) ListScans(ctx context.Context, teamID string, programID string) ([]*api.Scan, error) {
+	scans := []*api.Scan{}
+	for _, scan := range s.scans {
+		if scan.ProgramID == programID {
+			scans = append(scans, scan)
+		}
+	}
+	return scans, nil
+}
+
+func (s inMemoryScansService) FindScan(ctx context.Context, scanID, teamID string) (*api.Scan, error) {
+	for _, scan := range s.scans {
+		if scan.ID == scanID {
+			return scan, nil
+		}
+	}
+	return nil, errors.NotFound("scan not found")
+}
+
+func TestScansToDiff(t *testing.T) {
+	day := func(d, h int) *time.Time {
+		t := time.Date(2021, 3, d, h, 0, 0, 0, time.UTC)
+		return &t
+	}
+	srv := inMemoryScansService{scans: []*api.Scan{
+		{ID: "s3", ProgramID: "p1", EndTime: day(8, 10)},
+		{ID: "s1", ProgramID: "p1", EndTime: day(1, 10)},
+		{ID: "s4", ProgramID: "p1"},
+		{ID: "s2", ProgramID: "p1", EndTime: day(4, 10)},
+		{ID: "s3b", ProgramID: "p1", EndTime: day(8, 12)},
+		{ID: "other", ProgramID: "p2", EndTime: day(5, 10)},
+	}}
+	sameDay := inMemoryScansService{scans: []*api.Scan{
+		{ID: "s1", ProgramID: "p1", EndTime: day(1, 10)},
+		{ID: "s2", ProgramID: "p1", EndTime: day(1, 12)},
+	}}
+
+	tests := []struct {
+		name     string
+		srv      api.VulcanitoService
+		fromID   string
+		toID     string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "LatestFinishedPreviousDay", srv: srv, wantFrom: "s2", wantTo: "s3b"},
+		{name: "GivenScans", srv: srv, fromID: "s1", toID: "s3", wantFrom: "s1", wantTo: "s3"},
+		{name: "OtherProgram", srv: srv, fromID: "s1", toID: "other", wantErr: true},
+		{name: "OnlyFrom", srv: srv, fromID: "s1", wantErr: true},
+		{name: "OnlySameDayScans", srv: sameDay, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ScansToDiff(context.Background(), tt.srv, "t1", "p1", tt.fromID, tt.toID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ScansToDiff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if from.ID != tt.wantFrom || to.ID != tt.wantTo {
+				t.Errorf("got scans %s and %s, want %s and %s", from.ID, to.ID, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
@@ -5,12 +5,14 @@ Copyright 2021 Adevinta
 package service
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 	"github.com/adevinta/vulcan-api/pkg/api/store"
 	"github.com/adevinta/vulcan-api/pkg/schedule"
@@ -87,3 +89,64 @@ func TestCreateScan(t *testing.T) {
 		})
 	}
 }
+
+// inMemoryScanQueueStore stores the entries of the scan queue.
+type inMemoryScanQueueStore struct {
+	api.VulcanitoStore
+	entries []*api.ScanQueueEntry
+}
+
+func (s *inMemoryScanQueueStore) ListScanQueueEntries(teamID string, statuses []api.ScanQueueStatus) ([]*api.ScanQueueEntry, error) {
+	var entries []*api.ScanQueueEntry
+	for _, e := range s.entries {
+		for _, status := range statuses {
+			if e.TeamID == teamID && e.Status == status {
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (s *inMemoryScanQueueStore) CancelScanQueueEntry(teamID, entryID string) (bool, error) {
+	for _, e := range s.entries {
+		if e.ID == entryID && e.TeamID == teamID && e.Status == api.ScanQueueStatusQueued {
+			e.Status = api.ScanQueueStatusCancelled
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestVulcanitoService_CancelScanQueueEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		teamID     string
+		entryID    string
+		wantErr    error
+		wantStatus api.ScanQueueStatus
+	}{
+		{name: "Queued", teamID: "t1", entryID: "e1", wantStatus: api.ScanQueueStatusCancelled},
+		{name: "Running", teamID: "t1", entryID: "e2", wantErr: errors.ErrValidation, wantStatus: api.ScanQueueStatusRunning},
+		{name: "OtherTeam", teamID: "t2", entryID: "e1", wantErr: errors.ErrNotFound, wantStatus: api.ScanQueueStatusQueued},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &inMemoryScanQueueStore{entries: []*api.ScanQueueEntry{
+				{ID: "e1", TeamID: "t1", Status: api.ScanQueueStatusQueued},
+				{ID: "e2", TeamID: "t1", Status: api.ScanQueueStatusRunning},
+			}}
+			srv := vulcanitoService{db: db}
+			err := srv.CancelScanQueueEntry(context.Background(), tt.teamID, tt.entryID)
+			if (tt.wantErr == nil && err != nil) || (tt.wantErr != nil && !errors.IsKind(err, tt.wantErr)) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if status := db.entries[0].Status; tt.entryID == "e1" && status != tt.wantStatus {
+				t.Errorf("got status %v, want %v", status, tt.wantStatus)
+			}
+			if status := db.entries[1].Status; tt.entryID == "e2" && status != tt.wantStatus {
+				t.Errorf("got status %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
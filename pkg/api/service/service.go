@@ -5,6 +5,8 @@ Copyright 2021 Adevinta
 package service
 
 import (
+	"context"
+
 	"github.com/go-kit/kit/log"
 
 	"github.com/adevinta/vulcan-api/pkg/api"
@@ -23,21 +25,36 @@ type AWSAccounts interface {
 	Name(AccountID string) (string, error)
 }
 
+// ChecktypesCatalogue defines the services related to the catalogue of
+// checktypes required by the Vulcan API.
+type ChecktypesCatalogue interface {
+	Checktypes(ctx context.Context) ([]*api.Checktype, error)
+	ValidateOptions(ctx context.Context, checktype, options string) error
+}
+
+// ChecktypesCache defines the services related to the cache of the
+// checktypes information read from vulcan-core.
+type ChecktypesCache interface {
+	Status() api.ChecktypesCacheStatus
+}
+
 // vulcanitoService implements VulcanitoService
 type vulcanitoService struct {
 	jwtConfig             jwt.Config
 	db                    api.VulcanitoStore
 	logger                log.Logger
 	programScheduler      schedule.ScanScheduler
-	scanEngineConfig      scanengine.Config
+	scanEngineClient      scanengine.Client
 	reportsConfig         reports.Config
 	vulndbClient          vulnerabilitydb.Client
 	vulcantrackerClient   tickets.Client
 	reportsClient         *reports.Client
 	metricsClient         metrics.Client
 	awsAccounts           AWSAccounts
-	allowedTrackerTeams   []string // feature flag.
+	checktypesCatalogue   ChecktypesCatalogue
+	checktypesCache       ChecktypesCache
 	DNSHostnameValidation bool
+	riskScores            *riskScoresCache
 }
 
 //go:generate impl -output logging.go -stub templates/logging/impl.tmpl -header templates/logging/header.tmpl "middleware loggingMiddleware" api.VulcanitoService
@@ -45,16 +62,16 @@ type vulcanitoService struct {
 
 // New returns a basic Service with all of the expected middlewares wired in.
 func New(logger log.Logger, db api.VulcanitoStore, jwtConfig jwt.Config,
-	scanEngineConfig scanengine.Config, programScheduler schedule.ScanScheduler, reportsConfig reports.Config,
+	scanEngineClient scanengine.Client, programScheduler schedule.ScanScheduler, reportsConfig reports.Config,
 	vulndbClient vulnerabilitydb.Client, vulcantrackerClient tickets.Client, reportsClient *reports.Client,
-	metricsClient metrics.Client, awsAccounts AWSAccounts, allowedTrackerTeams []string, DNSHostnameValidation bool) api.VulcanitoService {
+	metricsClient metrics.Client, awsAccounts AWSAccounts, checktypesCatalogue ChecktypesCatalogue, checktypesCache ChecktypesCache, DNSHostnameValidation bool) api.VulcanitoService {
 
 	var svc api.VulcanitoService
 	{
 		svc = vulcanitoService{db: db,
 			jwtConfig:             jwtConfig,
 			logger:                logger,
-			scanEngineConfig:      scanEngineConfig,
+			scanEngineClient:      scanEngineClient,
 			programScheduler:      programScheduler,
 			reportsConfig:         reportsConfig,
 			vulndbClient:          vulndbClient,
@@ -62,7 +79,9 @@ func New(logger log.Logger, db api.VulcanitoStore, jwtConfig jwt.Config,
 			reportsClient:         reportsClient,
 			metricsClient:         metricsClient,
 			awsAccounts:           awsAccounts,
-			allowedTrackerTeams:   allowedTrackerTeams,
+			checktypesCatalogue:   checktypesCatalogue,
+			checktypesCache:       checktypesCache,
+			riskScores:            newRiskScoresCache(),
 			DNSHostnameValidation: DNSHostnameValidation,
 		}
 	}
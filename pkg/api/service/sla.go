@@ -0,0 +1,198 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// findingStatusOpen is the status of the findings in the vulnerability db
+// that are not fixed yet.
+const findingStatusOpen = "OPEN"
+
+// ListSLAPolicies returns the SLA policies of a team, or the global ones if
+// the team is empty.
+func (s vulcanitoService) ListSLAPolicies(ctx context.Context, teamID string) ([]*api.SLAPolicy, error) {
+	return s.db.ListSLAPolicies(teamID)
+}
+
+func (s vulcanitoService) CreateSLAPolicy(ctx context.Context, policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	policy.Severity = strings.ToLower(policy.Severity)
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	return s.db.CreateSLAPolicy(policy)
+}
+
+// UpdateSLAPolicy replaces the severity, ROLFP level and days of a policy.
+func (s vulcanitoService) UpdateSLAPolicy(ctx context.Context, policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	current, err := s.db.FindSLAPolicy(slaPolicyTeam(policy), policy.ID)
+	if err != nil {
+		return nil, err
+	}
+	current.Severity = strings.ToLower(policy.Severity)
+	current.ROLFPLevel = policy.ROLFPLevel
+	current.Days = policy.Days
+	if err := current.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	return s.db.UpdateSLAPolicy(*current)
+}
+
+func (s vulcanitoService) DeleteSLAPolicy(ctx context.Context, teamID, policyID string) error {
+	policy, err := s.db.FindSLAPolicy(teamID, policyID)
+	if err != nil {
+		return err
+	}
+	return s.db.DeleteSLAPolicy(*policy)
+}
+
+// ListFindingsSLA returns the open findings of a team with the given SLA
+// status, sorted by due date. If the status is empty, the findings at risk
+// of breaching their SLA and the ones that already breached it are returned.
+func (s vulcanitoService) ListFindingsSLA(ctx context.Context, teamID, status string) ([]*api.FindingSLA, error) {
+	switch status {
+	case "", api.SLAStatusOnTrack, api.SLAStatusAtRisk, api.SLAStatusBreached:
+	default:
+		return nil, errors.Validation(fmt.Sprintf("Invalid SLA status: '%s'", status))
+	}
+	findings, err := s.findingsSLA(ctx, teamID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := []*api.FindingSLA{}
+	for _, f := range findings {
+		if f.Status == status || (status == "" && f.Status != api.SLAStatusOnTrack) {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// NotifySLABreaches sends a notification to the recipients of a team with
+// the findings that breached their SLA since the last time it was called,
+// and returns the number of breaches notified.
+func (s vulcanitoService) NotifySLABreaches(ctx context.Context, teamID string) (int, error) {
+	if s.reportsClient == nil {
+		return 0, errors.Default("reports are not configured")
+	}
+	findings, err := s.findingsSLA(ctx, teamID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	breaches := []api.SLABreach{}
+	breached := map[string]api.FindingSLA{}
+	for _, f := range findings {
+		if f.Status != api.SLAStatusBreached {
+			continue
+		}
+		breaches = append(breaches, api.SLABreach{TeamID: teamID, FindingID: f.FindingID, OpenedAt: f.LastOpenedAt, DueAt: f.DueAt})
+		breached[f.FindingID] = *f
+	}
+	if len(breaches) == 0 {
+		return 0, nil
+	}
+	// The breaches are not recorded until they can be notified, so the
+	// teams without recipients are notified once they add them.
+	recipients, err := s.ListReportRecipients(ctx, teamID, api.RecipientReportSLABreaches)
+	if err != nil {
+		return 0, err
+	}
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+	team, err := s.db.FindTeam(teamID)
+	if err != nil {
+		return 0, err
+	}
+	return s.db.RecordSLABreaches(teamID, breaches, func(pending []api.SLABreach) error {
+		notification := api.SLABreachNotification{TeamID: teamID, TeamName: team.Name}
+		for _, b := range pending {
+			notification.Findings = append(notification.Findings, breached[b.FindingID])
+		}
+		return s.reportsClient.SendSLABreachNotification(teamID, team.Name, toReportsRecipients(recipients), notification)
+	})
+}
+
+// findingsSLA returns the SLA information of the open findings of a team that
+// have a SLA policy, sorted by due date. The ROLFP level of a finding is the
+// highest level of the assets of the team with the identifier of its target.
+func (s vulcanitoService) findingsSLA(ctx context.Context, teamID string, now time.Time) ([]*api.FindingSLA, error) {
+	globalPolicies, err := s.db.ListSLAPolicies("")
+	if err != nil {
+		return nil, err
+	}
+	teamPolicies, err := s.db.ListSLAPolicies(teamID)
+	if err != nil {
+		return nil, err
+	}
+	policies := append(globalPolicies, teamPolicies...)
+	if len(policies) == 0 {
+		return []*api.FindingSLA{}, nil
+	}
+
+	assets, err := s.db.ListAssets(teamID, api.Asset{})
+	if err != nil {
+		return nil, err
+	}
+	levels := map[string]int{}
+	for _, a := range assets {
+		level := 2
+		if a.ROLFP != nil {
+			level = int(a.ROLFP.Level())
+		}
+		if current, ok := levels[a.Identifier]; !ok || level > current {
+			levels[a.Identifier] = level
+		}
+	}
+
+	findings, err := s.allFindings(ctx, api.FindingsParams{Team: teamID, Status: findingStatusOpen})
+	if err != nil {
+		return nil, err
+	}
+	result := []*api.FindingSLA{}
+	for _, f := range findings {
+		severity := api.SeverityName(f.Score)
+		level, ok := levels[f.Target.Identifier]
+		if !ok {
+			level = 2
+		}
+		policy := api.MatchSLAPolicy(policies, severity, level)
+		if policy == nil {
+			continue
+		}
+		sla := api.NewFindingSLA(*policy, f.TotalExposure, now)
+		sla.LastOpenedAt = sla.OpenSince
+		if f.OpenFinding != nil {
+			sla.LastOpenedAt = now.Add(-time.Duration(f.CurrentExposure) * time.Hour)
+		}
+		sla.FindingID = f.ID
+		sla.IssueID = f.Issue.ID
+		sla.Summary = f.Issue.Summary
+		sla.Target = f.Target.Identifier
+		sla.Score = f.Score
+		sla.Severity = severity
+		sla.ROLFPLevel = level
+		result = append(result, &sla)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DueAt.Before(result[j].DueAt)
+	})
+	return result, nil
+}
+
+func slaPolicyTeam(policy api.SLAPolicy) string {
+	if policy.TeamID == nil {
+		return ""
+	}
+	return *policy.TeamID
+}
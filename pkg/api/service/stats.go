@@ -7,32 +7,96 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 )
 
+// maxStatsIdentifiers is the maximum number of asset identifiers the stats
+// requested to the vulnerability db are filtered by, so the length of the
+// query of the requests is bounded.
+const maxStatsIdentifiers = 50
+
+// groupIdentifierBatches returns the identifiers of the assets of a group in
+// batches of at most maxStatsIdentifiers, joined as the Identifiers of the
+// stats params. A group without assets has no batches, as it has no
+// findings.
+func groupIdentifierBatches(group api.Group) []string {
+	var identifiers []string
+	seen := map[string]bool{}
+	for _, ag := range group.AssetGroup {
+		if ag.Asset == nil || seen[ag.Asset.Identifier] {
+			continue
+		}
+		seen[ag.Asset.Identifier] = true
+		identifiers = append(identifiers, ag.Asset.Identifier)
+	}
+	return identifierBatches(identifiers)
+}
+
+// identifierBatches splits the given identifiers in batches of at most
+// maxStatsIdentifiers, joined as the Identifiers of the params of the
+// vulnerability db.
+func identifierBatches(identifiers []string) []string {
+	var batches []string
+	for len(identifiers) > maxStatsIdentifiers {
+		batches = append(batches, strings.Join(identifiers[:maxStatsIdentifiers], ","))
+		identifiers = identifiers[maxStatsIdentifiers:]
+	}
+	if len(identifiers) > 0 {
+		batches = append(batches, strings.Join(identifiers, ","))
+	}
+	return batches
+}
+
 func (s vulcanitoService) StatsMTTR(ctx context.Context, params api.StatsParams) (*api.StatsMTTR, error) {
+	if hasRiskScoreFilter(params) {
+		return nil, errRiskScoreStats
+	}
 	return s.vulndbClient.StatsMTTR(ctx, params)
 }
 
 func (s vulcanitoService) StatsExposure(ctx context.Context, params api.StatsParams) (*api.StatsExposure, error) {
+	if hasRiskScoreFilter(params) {
+		return nil, errRiskScoreStats
+	}
 	return s.vulndbClient.StatsExposure(ctx, params)
 }
 
 func (s vulcanitoService) StatsCurrentExposure(ctx context.Context, params api.StatsParams) (*api.StatsCurrentExposure, error) {
+	if hasRiskScoreFilter(params) {
+		return nil, errRiskScoreStats
+	}
 	return s.vulndbClient.StatsCurrentExposure(ctx, params)
 }
 
 func (s vulcanitoService) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+	if hasRiskScoreFilter(params) {
+		stats, err := s.riskScoreStats(ctx, params, "OPEN")
+		if err != nil {
+			return nil, err
+		}
+		return &api.StatsOpen{OpenIssues: stats}, nil
+	}
 	return s.vulndbClient.StatsOpen(ctx, params)
 }
 
 func (s vulcanitoService) StatsFixed(ctx context.Context, params api.StatsParams) (*api.StatsFixed, error) {
+	if hasRiskScoreFilter(params) {
+		stats, err := s.riskScoreStats(ctx, params, "FIXED")
+		if err != nil {
+			return nil, err
+		}
+		return &api.StatsFixed{FixedIssues: stats}, nil
+	}
 	return s.vulndbClient.StatsFixed(ctx, params)
 }
 
 func (s vulcanitoService) StatsAssets(ctx context.Context, params api.StatsParams) (*api.StatsAssets, error) {
+	if hasRiskScoreFilter(params) {
+		return nil, errRiskScoreStats
+	}
 	return s.vulndbClient.StatsAssets(ctx, params)
 }
 
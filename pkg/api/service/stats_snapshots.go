@@ -0,0 +1,252 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adevinta/errors"
+	vulcanreport "github.com/adevinta/vulcan-report"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// defaultStatsTrendDays is the number of days of a stats trend without a min
+// date.
+const defaultStatsTrendDays = 30
+
+// exposureScores contains the range of scores of the findings of each
+// severity the exposure is snapshotted for. The exposure of the
+// informational findings is not snapshotted, as the stats can not be
+// filtered by a max score of zero.
+var exposureScores = map[string][2]float64{
+	"low":      {vulcanreport.SeverityThresholdNone + 0.1, vulcanreport.SeverityThresholdLow},
+	"medium":   {vulcanreport.SeverityThresholdLow + 0.1, vulcanreport.SeverityThresholdMedium},
+	"high":     {vulcanreport.SeverityThresholdMedium + 0.1, vulcanreport.SeverityThresholdHigh},
+	"critical": {vulcanreport.SeverityThresholdHigh + 0.1, vulcanreport.SeverityThresholdCritical},
+}
+
+// SnapshotTeamStats stores the stats of the findings of a team, and of each
+// of its groups, at the end of the given day. Existing snapshots of the same
+// day are replaced.
+func (s vulcanitoService) SnapshotTeamStats(ctx context.Context, teamID, date string) error {
+	day, err := time.Parse(api.StatsDateFormat, date)
+	if err != nil {
+		return errors.Validation(api.ErrInvalidStatsTrendDate)
+	}
+	snapshots, err := s.statsSnapshots(ctx, api.StatsParams{Team: teamID}, teamID, "", day)
+	if err != nil {
+		return err
+	}
+	groups, err := s.db.ListGroups(teamID, "")
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		batches := groupIdentifierBatches(*g)
+		if len(batches) == 0 {
+			for _, severity := range api.StatsSeverities {
+				snapshots = append(snapshots, api.StatsSnapshot{TeamID: teamID, GroupID: g.ID, Date: day, Severity: severity})
+			}
+			continue
+		}
+		var groupSnapshots []api.StatsSnapshot
+		for _, identifiers := range batches {
+			params := api.StatsParams{Team: teamID, Identifiers: identifiers}
+			batchSnapshots, err := s.statsSnapshots(ctx, params, teamID, g.ID, day)
+			if err != nil {
+				return err
+			}
+			groupSnapshots = mergeStatsSnapshots(groupSnapshots, batchSnapshots)
+		}
+		snapshots = append(snapshots, groupSnapshots...)
+	}
+	return s.db.UpsertStatsSnapshots(snapshots)
+}
+
+// statsSnapshots returns the snapshots of each severity of the findings that
+// match the given params at the end of the given day.
+func (s vulcanitoService) statsSnapshots(ctx context.Context, params api.StatsParams, teamID, groupID string, day time.Time) ([]api.StatsSnapshot, error) {
+	date := day.Format(api.StatsDateFormat)
+	openParams := params
+	openParams.AtDate = date
+	open, err := s.vulndbClient.StatsOpen(ctx, openParams)
+	if err != nil {
+		return nil, err
+	}
+	dayParams := params
+	dayParams.MinDate = date
+	dayParams.MaxDate = date
+	detected, err := s.vulndbClient.StatsOpen(ctx, dayParams)
+	if err != nil {
+		return nil, err
+	}
+	fixed, err := s.vulndbClient.StatsFixed(ctx, dayParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []api.StatsSnapshot
+	for _, severity := range api.StatsSeverities {
+		snapshot := api.StatsSnapshot{
+			TeamID:   teamID,
+			GroupID:  groupID,
+			Date:     day,
+			Severity: severity,
+			Open:     severityCount(open.OpenIssues, severity),
+			New:      severityCount(detected.OpenIssues, severity),
+			Fixed:    severityCount(fixed.FixedIssues, severity),
+		}
+		if scores, ok := exposureScores[severity]; ok && snapshot.Open > 0 {
+			exposureParams := openParams
+			exposureParams.MinScore = scores[0]
+			exposureParams.MaxScore = scores[1]
+			exposure, err := s.vulndbClient.StatsExposure(ctx, exposureParams)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.Exposure = exposure.Exposure.Mean
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// mergeStatsSnapshots adds the stats of the snapshots of the findings of a
+// batch of assets to the ones of the previous batches. Both contain a
+// snapshot of each severity in the same order. The mean of the exposure is
+// weighted by the open findings of each batch.
+func mergeStatsSnapshots(total, batch []api.StatsSnapshot) []api.StatsSnapshot {
+	if total == nil {
+		return batch
+	}
+	for i, b := range batch {
+		t := &total[i]
+		if open := t.Open + b.Open; open > 0 {
+			t.Exposure = (t.Exposure*float32(t.Open) + b.Exposure*float32(b.Open)) / float32(open)
+		}
+		t.Open += b.Open
+		t.New += b.New
+		t.Fixed += b.Fixed
+	}
+	return total
+}
+
+func severityCount(stats vulndb.StatsIssueSeverity, severity string) int {
+	switch severity {
+	case "critical":
+		return stats.Critical
+	case "high":
+		return stats.High
+	case "medium":
+		return stats.Medium
+	case "low":
+		return stats.Low
+	default:
+		return stats.Informational
+	}
+}
+
+// StatsTrend returns the daily stats of a team, or of a group of a team,
+// stored by the stats snapshots. The trend ends today if the max date is
+// not specified, and covers 30 days if the min date is not specified.
+func (s vulcanitoService) StatsTrend(ctx context.Context, params api.StatsTrendParams) (*api.StatsTrend, error) {
+	if params.MaxDate == "" {
+		params.MaxDate = time.Now().UTC().Format(api.StatsDateFormat)
+	}
+	if params.MinDate == "" {
+		to, err := time.Parse(api.StatsDateFormat, params.MaxDate)
+		if err != nil {
+			return nil, errors.Validation(api.ErrInvalidStatsTrendDate)
+		}
+		params.MinDate = to.AddDate(0, 0, -(defaultStatsTrendDays - 1)).Format(api.StatsDateFormat)
+	}
+	from, to, err := params.Range()
+	if err != nil {
+		return nil, errors.Validation(err)
+	}
+	if params.GroupID != "" {
+		if _, err := s.db.FindGroupInfo(api.Group{TeamID: params.TeamID, ID: params.GroupID}); err != nil {
+			return nil, err
+		}
+	}
+	snapshots, err := s.db.ListStatsSnapshots(params.TeamID, params.GroupID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewStatsTrend(params, snapshots), nil
+}
+
+// snapshotDigestStats returns the stats of a digest report computed from the
+// stats snapshots of a team. The runner only snapshots the days already
+// finished, so if the period ends today the findings detected and fixed
+// today, and the ones open now, are read from the vulnerability db. It
+// returns false if there is not a snapshot of every other day of the period.
+func (s vulcanitoService) snapshotDigestStats(ctx context.Context, teamID, dateFromStr, dateToStr string, now time.Time) (map[string]int, bool, error) {
+	from, to, err := api.StatsTrendParams{MinDate: dateFromStr, MaxDate: dateToStr}.Range()
+	if err != nil {
+		return nil, false, nil
+	}
+	today, err := time.Parse(api.StatsDateFormat, now.UTC().Format(api.StatsDateFormat))
+	if err != nil {
+		return nil, false, err
+	}
+	if to.After(today) {
+		return nil, false, nil
+	}
+	live := to.Equal(today)
+	last := to
+	if live {
+		last = today.AddDate(0, 0, -1)
+	}
+	if last.Before(from) {
+		return nil, false, nil
+	}
+
+	params := api.StatsTrendParams{
+		TeamID:  teamID,
+		MinDate: dateFromStr,
+		MaxDate: last.Format(api.StatsDateFormat),
+	}
+	snapshots, err := s.db.ListStatsSnapshots(teamID, "", from, last)
+	if err != nil {
+		return nil, false, err
+	}
+	trend := api.NewStatsTrend(params, snapshots)
+	days := int(last.Sub(from).Hours()/24) + 1
+	if len(trend.Points) != days {
+		return nil, false, nil
+	}
+
+	var detected, fixed vulndb.StatsIssueSeverity
+	for _, p := range trend.Points {
+		detected = addStatsIssues(detected, p.New)
+		fixed = addStatsIssues(fixed, p.Fixed)
+	}
+	open := trend.Points[len(trend.Points)-1].Open
+	if live {
+		date := today.Format(api.StatsDateFormat)
+		todayOpen, todayDetected, todayFixed, err := s.digestIssues(ctx, api.StatsParams{Team: teamID}, date, date)
+		if err != nil {
+			return nil, false, err
+		}
+		open = todayOpen
+		detected = addStatsIssues(detected, todayDetected)
+		fixed = addStatsIssues(fixed, todayFixed)
+	}
+	return digestSeveritiesStats(open, detected, fixed), true, nil
+}
+
+func addStatsIssues(a, b vulndb.StatsIssueSeverity) vulndb.StatsIssueSeverity {
+	return vulndb.StatsIssueSeverity{
+		Critical:      a.Critical + b.Critical,
+		High:          a.High + b.High,
+		Medium:        a.Medium + b.Medium,
+		Low:           a.Low + b.Low,
+		Informational: a.Informational + b.Informational,
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// inMemoryStatsSnapshotsStore stores the stats snapshots of the teams.
+type inMemoryStatsSnapshotsStore struct {
+	api.VulcanitoStore
+	groups    []*api.Group
+	snapshots []api.StatsSnapshot
+}
+
+func (s *inMemoryStatsSnapshotsStore) ListGroups(teamID, groupName string) ([]*api.Group, error) {
+	return s.groups, nil
+}
+
+func (s *inMemoryStatsSnapshotsStore) UpsertStatsSnapshots(snapshots []api.StatsSnapshot) error {
+	s.snapshots = append(s.snapshots, snapshots...)
+	return nil
+}
+
+func (s *inMemoryStatsSnapshotsStore) ListStatsSnapshots(teamID, groupID string, from, to time.Time) ([]*api.StatsSnapshot, error) {
+	var snapshots []*api.StatsSnapshot
+	for i, snapshot := range s.snapshots {
+		if snapshot.TeamID == teamID && snapshot.GroupID == groupID && !snapshot.Date.Before(from) && !snapshot.Date.After(to) {
+			snapshots = append(snapshots, &s.snapshots[i])
+		}
+	}
+	return snapshots, nil
+}
+
+// inMemoryStatsClient returns stats that depend on the params of the
+// requests: there are two critical findings open in the whole team and one
+// in each group, one of them detected in the snapshotted day, and a high one
+// fixed in the day.
+type inMemoryStatsClient struct {
+	vulnerabilitydb.Client
+	exposureRequests []api.StatsParams
+}
+
+func (c *inMemoryStatsClient) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+	switch {
+	case params.AtDate != "" && params.Identifiers == "":
+		return &api.StatsOpen{OpenIssues: vulndb.StatsIssueSeverity{Critical: 2}}, nil
+	case params.AtDate != "":
+		return &api.StatsOpen{OpenIssues: vulndb.StatsIssueSeverity{Critical: 1}}, nil
+	default:
+		return &api.StatsOpen{OpenIssues: vulndb.StatsIssueSeverity{Critical: 1}}, nil
+	}
+}
+
+func (c *inMemoryStatsClient) StatsFixed(ctx context.Context, params api.StatsParams) (*api.StatsFixed, error) {
+	return &api.StatsFixed{FixedIssues: vulndb.StatsIssueSeverity{High: 1}}, nil
+}
+
+func (c *inMemoryStatsClient) StatsExposure(ctx context.Context, params api.StatsParams) (*api.StatsExposure, error) {
+	c.exposureRequests = append(c.exposureRequests, params)
+	return &api.StatsExposure{Exposure: vulndb.StatsExposure{Mean: 24}}, nil
+}
+
+func TestVulcanitoService_SnapshotTeamStats(t *testing.T) {
+	db := &inMemoryStatsSnapshotsStore{groups: []*api.Group{
+		{ID: "g1", AssetGroup: []*api.AssetGroup{
+			{Asset: &api.Asset{Identifier: "example.com"}},
+			{Asset: &api.Asset{Identifier: "www.example.com"}},
+		}},
+		{ID: "g2"},
+	}}
+	vulndbClient := &inMemoryStatsClient{}
+	srv := vulcanitoService{db: db, vulndbClient: vulndbClient}
+
+	if err := srv.SnapshotTeamStats(context.Background(), "t1", "2021-03-01"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	var want []api.StatsSnapshot
+	for _, g := range []struct {
+		id       string
+		open     int
+		new      int
+		fixed    int
+		exposure float32
+	}{{"", 2, 1, 1, 24}, {"g1", 1, 1, 1, 24}, {"g2", 0, 0, 0, 0}} {
+		for _, severity := range api.StatsSeverities {
+			s := api.StatsSnapshot{TeamID: "t1", GroupID: g.id, Date: day, Severity: severity}
+			switch severity {
+			case "critical":
+				s.Open, s.New, s.Exposure = g.open, g.new, g.exposure
+			case "high":
+				s.Fixed = g.fixed
+			}
+			want = append(want, s)
+		}
+	}
+	if diff := cmp.Diff(want, db.snapshots); diff != "" {
+		t.Errorf("snapshots mismatch (-want +got):\n%s", diff)
+	}
+
+	// The exposure is only requested for the severities with open findings.
+	wantExposure := []api.StatsParams{
+		{Team: "t1", AtDate: "2021-03-01", MinScore: 9, MaxScore: 10},
+		{Team: "t1", AtDate: "2021-03-01", MinScore: 9, MaxScore: 10, Identifiers: "example.com,www.example.com"},
+	}
+	if diff := cmp.Diff(wantExposure, vulndbClient.exposureRequests); diff != "" {
+		t.Errorf("exposure requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVulcanitoService_SnapshotDigestStats(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	db := &inMemoryStatsSnapshotsStore{snapshots: []api.StatsSnapshot{
+		{TeamID: "t1", Date: day(1), Severity: "critical", Open: 3, New: 3},
+		{TeamID: "t1", Date: day(1), Severity: "low", Open: 1, New: 1},
+		{TeamID: "t1", Date: day(2), Severity: "critical", Open: 2, New: 1, Fixed: 2},
+		{TeamID: "t1", Date: day(2), Severity: "low", Open: 0, Fixed: 1},
+		{TeamID: "t1", GroupID: "g1", Date: day(3), Severity: "critical", Open: 1},
+	}}
+	srv := vulcanitoService{db: db}
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		want   map[string]int
+		wantOK bool
+	}{
+		{
+			name: "CompleteSnapshots",
+			from: "2021-03-01",
+			to:   "2021-03-02",
+			want: map[string]int{
+				"info": 0, "low": 0, "medium": 0, "high": 0, "critical": 2,
+				"infoDiff": 0, "lowDiff": 1, "mediumDiff": 0, "highDiff": 0, "criticalDiff": 4,
+				"infoFixed": 0, "lowFixed": 1, "mediumFixed": 0, "highFixed": 0, "criticalFixed": 2,
+			},
+			wantOK: true,
+		},
+		{name: "MissingDay", from: "2021-03-01", to: "2021-03-03"},
+		{name: "InvalidDates", from: "2021-03-02", to: "2021-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2021, 3, 10, 10, 0, 0, 0, time.UTC)
+			got, ok, err := srv.snapshotDigestStats(context.Background(), "t1", tt.from, tt.to, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("stats mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_SnapshotDigestStatsDefaultPeriod(t *testing.T) {
+	now := time.Date(2021, 3, 8, 10, 0, 0, 0, time.UTC)
+	from, to := digestPeriod(now)
+
+	// The runner snapshots every day of the period but today.
+	db := &inMemoryStatsSnapshotsStore{}
+	for d := 1; d < 8; d++ {
+		db.snapshots = append(db.snapshots, api.StatsSnapshot{
+			TeamID:   "t1",
+			Date:     time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC),
+			Severity: "critical",
+			Open:     d,
+			New:      1,
+		})
+	}
+	srv := vulcanitoService{db: db, vulndbClient: &inMemoryStatsClient{}}
+
+	got, ok, err := srv.snapshotDigestStats(context.Background(), "t1", from, to, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("stats not computed from the snapshots")
+	}
+	// The open findings and the ones detected and fixed today are read from
+	// the vulnerability db.
+	want := map[string]int{
+		"info": 0, "low": 0, "medium": 0, "high": 0, "critical": 2,
+		"infoDiff": 0, "lowDiff": 0, "mediumDiff": 0, "highDiff": 0, "criticalDiff": 8,
+		"infoFixed": 0, "lowFixed": 0, "mediumFixed": 0, "highFixed": 1, "criticalFixed": 0,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("stats mismatch (-want +got):\n%s", diff)
+	}
+
+	// A missing snapshot of yesterday makes the digest fall back to the
+	// vulnerability db.
+	db.snapshots = db.snapshots[:len(db.snapshots)-1]
+	if _, ok, err := srv.snapshotDigestStats(context.Background(), "t1", from, to, now); err != nil || ok {
+		t.Errorf("got ok %v and error %v with a missing snapshot, want false", ok, err)
+	}
+}
+
+func TestGroupIdentifierBatches(t *testing.T) {
+	group := api.Group{AssetGroup: []*api.AssetGroup{{}}}
+	var want []string
+	var batch []string
+	for i := 0; i < maxStatsIdentifiers+1; i++ {
+		identifier := fmt.Sprintf("%d.example.com", i)
+		group.AssetGroup = append(group.AssetGroup,
+			&api.AssetGroup{Asset: &api.Asset{Identifier: identifier}},
+			// The assets of different types with the same identifier are
+			// only filtered once.
+			&api.AssetGroup{Asset: &api.Asset{Identifier: identifier}},
+		)
+		batch = append(batch, identifier)
+		if len(batch) == maxStatsIdentifiers {
+			want = append(want, strings.Join(batch, ","))
+			batch = nil
+		}
+	}
+	want = append(want, strings.Join(batch, ","))
+	if diff := cmp.Diff(want, groupIdentifierBatches(group)); diff != "" {
+		t.Errorf("batches mismatch (-want +got):\n%s", diff)
+	}
+	if got := groupIdentifierBatches(api.Group{}); len(got) != 0 {
+		t.Errorf("got batches %v for a group without assets", got)
+	}
+}
+
+func TestMergeStatsSnapshots(t *testing.T) {
+	a := []api.StatsSnapshot{{Severity: "low", Open: 1, New: 1, Exposure: 10}, {Severity: "high"}}
+	b := []api.StatsSnapshot{{Severity: "low", Open: 3, Fixed: 2, Exposure: 30}, {Severity: "high", Fixed: 1}}
+	want := []api.StatsSnapshot{{Severity: "low", Open: 4, New: 1, Fixed: 2, Exposure: 25}, {Severity: "high", Fixed: 1}}
+	got := mergeStatsSnapshots(mergeStatsSnapshots(nil, a), b)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("snapshots mismatch (-want +got):\n%s", diff)
+	}
+}
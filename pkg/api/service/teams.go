@@ -43,7 +43,7 @@ func (s vulcanitoService) FindTeam(ctx context.Context, id string) (*api.Team, e
 	if err != nil {
 		return team, err
 	}
-	team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, id, s.allowedTrackerTeams)
+	team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, *team)
 	return team, nil
 }
 
@@ -62,7 +62,7 @@ func (s vulcanitoService) FindTeamByTag(ctx context.Context, tag string) (*api.T
 	if err != nil {
 		return team, err
 	}
-	team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, team.ID, s.allowedTrackerTeams)
+	team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, *team)
 	return team, nil
 }
 
@@ -93,7 +93,7 @@ func (s vulcanitoService) ListTeams(ctx context.Context) ([]*api.Team, error) {
 		return teams, err
 	}
 	for _, team := range teams {
-		team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, team.ID, s.allowedTrackerTeams)
+		team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, *team)
 	}
 	return teams, nil
 }
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// FindTeamTicketProvider returns the ticket provider of a team. The teams
+// without a ticket provider use vulcan-tracker.
+func (s vulcanitoService) FindTeamTicketProvider(ctx context.Context, teamID string) (*api.TicketProvider, error) {
+	provider, err := s.db.FindTeamTicketProvider(teamID)
+	if errors.IsKind(err, errors.ErrNotFound) {
+		return &api.TicketProvider{TeamID: teamID, Type: api.TicketProviderVulcanTracker}, nil
+	}
+	return provider, err
+}
+
+// UpdateTeamTicketProvider sets the ticket provider the tickets of the
+// findings of a team are created in. The tracker or the project can not be
+// changed while the team has open tickets, as they are synchronized using
+// their IDs in the current one.
+func (s vulcanitoService) UpdateTeamTicketProvider(ctx context.Context, provider api.TicketProvider) (*api.TicketProvider, error) {
+	if provider.TeamID == "" {
+		return nil, errors.Validation(`ID is empty`)
+	}
+	if err := provider.Validate(); err != nil {
+		return nil, errors.Validation(err)
+	}
+	if provider.Type == api.TicketProviderVulcanTracker {
+		provider.URL = ""
+		provider.Project = ""
+		provider.CredentialsRef = ""
+	}
+	current, err := s.FindTeamTicketProvider(ctx, provider.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if !current.SameTracker(provider) {
+		if err := s.checkNoOpenFindingTickets(provider.TeamID); err != nil {
+			return nil, err
+		}
+	}
+	return s.db.UpsertTeamTicketProvider(provider)
+}
+
+// DeleteTeamTicketProvider removes the ticket provider of a team, so it uses
+// vulcan-tracker again.
+func (s vulcanitoService) DeleteTeamTicketProvider(ctx context.Context, teamID string) error {
+	current, err := s.FindTeamTicketProvider(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if current.Type != api.TicketProviderVulcanTracker {
+		if err := s.checkNoOpenFindingTickets(teamID); err != nil {
+			return err
+		}
+	}
+	return s.db.DeleteTeamTicketProvider(teamID)
+}
+
+func (s vulcanitoService) checkNoOpenFindingTickets(teamID string) error {
+	open, err := s.db.ListFindingTickets(teamID, true)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return errors.Validation("The ticket provider can not be changed while the team has open tickets")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemoryTicketProvidersStore struct {
+	api.VulcanitoStore
+	provider *api.TicketProvider
+	open     []*api.FindingTicket
+}
+
+func (s *inMemoryTicketProvidersStore) FindTeamTicketProvider(teamID string) (*api.TicketProvider, error) {
+	if s.provider == nil {
+		return nil, errors.NotFound("record not found")
+	}
+	p := *s.provider
+	return &p, nil
+}
+
+func (s *inMemoryTicketProvidersStore) UpsertTeamTicketProvider(provider api.TicketProvider) (*api.TicketProvider, error) {
+	s.provider = &provider
+	return &provider, nil
+}
+
+func (s *inMemoryTicketProvidersStore) DeleteTeamTicketProvider(teamID string) error {
+	s.provider = nil
+	return nil
+}
+
+func (s *inMemoryTicketProvidersStore) ListFindingTickets(teamID string, onlyOpen bool) ([]*api.FindingTicket, error) {
+	return s.open, nil
+}
+
+func TestVulcanitoService_UpdateTeamTicketProvider(t *testing.T) {
+	github := api.TicketProvider{TeamID: "t1", Type: api.TicketProviderGitHub, Project: "adevinta/vulcan", CredentialsRef: "gh"}
+	otherRepo := api.TicketProvider{TeamID: "t1", Type: api.TicketProviderGitHub, Project: "adevinta/other", CredentialsRef: "gh"}
+	rotated := api.TicketProvider{TeamID: "t1", Type: api.TicketProviderGitHub, Project: "adevinta/vulcan", CredentialsRef: "gh2"}
+	open := []*api.FindingTicket{{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "12"}}
+	errOpenTickets := errors.Validation("The ticket provider can not be changed while the team has open tickets")
+
+	tests := []struct {
+		name     string
+		current  *api.TicketProvider
+		open     []*api.FindingTicket
+		provider api.TicketProvider
+		wantErr  error
+	}{
+		{
+			name:     "FromVulcanTrackerWithoutTickets",
+			provider: github,
+		},
+		{
+			name:     "FromVulcanTrackerWithOpenTickets",
+			open:     open,
+			provider: github,
+			wantErr:  errOpenTickets,
+		},
+		{
+			name:     "OtherRepositoryWithOpenTickets",
+			current:  &github,
+			open:     open,
+			provider: otherRepo,
+			wantErr:  errOpenTickets,
+		},
+		{
+			name:     "SameRepositoryWithOpenTickets",
+			current:  &github,
+			open:     open,
+			provider: rotated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &inMemoryTicketProvidersStore{provider: tt.current, open: tt.open}
+			srv := vulcanitoService{db: db}
+			_, err := srv.UpdateTeamTicketProvider(context.Background(), tt.provider)
+			if errToStr(err) != errToStr(tt.wantErr) {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVulcanitoService_DeleteTeamTicketProvider(t *testing.T) {
+	github := api.TicketProvider{TeamID: "t1", Type: api.TicketProviderGitHub, Project: "adevinta/vulcan", CredentialsRef: "gh"}
+	db := &inMemoryTicketProvidersStore{
+		provider: &github,
+		open:     []*api.FindingTicket{{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "12"}},
+	}
+	srv := vulcanitoService{db: db}
+	if err := srv.DeleteTeamTicketProvider(context.Background(), "t1"); err == nil {
+		t.Fatal("expected error deleting a provider with open tickets")
+	}
+	db.open = nil
+	if err := srv.DeleteTeamTicketProvider(context.Background(), "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
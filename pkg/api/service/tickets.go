@@ -7,7 +7,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 )
 
@@ -22,7 +27,30 @@ func (s vulcanitoService) CreateFindingTicket(ctx context.Context, ticket api.Fi
 	if !isTrackerAllowed(ctx) {
 		return nil, fmt.Errorf("unauthorized to create a ticket")
 	}
-	return s.vulcantrackerClient.CreateTicket(ctx, ticket)
+	if err := s.checkTeamUsingTracker(ticket.TeamID); err != nil {
+		return nil, err
+	}
+	var userID string
+	if user, err := api.UserFromContext(ctx); err == nil {
+		userID = user.ID
+	}
+	return s.createFindingTicket(ctx, ticket, userID)
+}
+
+// createFindingTicket creates a ticket in the ticket tracker and stores it,
+// so it is synchronized with the status of its finding.
+func (s vulcanitoService) createFindingTicket(ctx context.Context, create api.FindingTicketCreate, userID string) (*api.Ticket, error) {
+	ticket, err := s.vulcantrackerClient.CreateTicket(ctx, create)
+	if err != nil || ticket == nil || ticket.Ticket.ID == "" {
+		return ticket, err
+	}
+	_, err = s.db.UpsertFindingTicket(api.NewFindingTicket(create.TeamID, create.FindingID, *ticket, userID))
+	if err != nil {
+		// The ticket was already created, so it is returned even if it
+		// won't be synchronized.
+		_ = level.Error(s.logger).Log("Tickets", "error storing finding ticket", "TeamID", create.TeamID, "FindingID", create.FindingID, "err", err)
+	}
+	return ticket, nil
 }
 
 // GetFindingTicket makes a request to vulcan tracker to find a ticket for a team.
@@ -34,17 +62,219 @@ func (s vulcanitoService) GetFindingTicket(ctx context.Context, findingID, teamI
 }
 
 // IsATeamOnboardedInVulcanTracker return if a team is onboarded in vulcan tracker.
-func (s vulcanitoService) IsATeamOnboardedInVulcanTracker(ctx context.Context, teamID string, onboardedTeams []string) bool {
+func (s vulcanitoService) IsATeamOnboardedInVulcanTracker(ctx context.Context, team api.Team) bool {
 	if s.vulcantrackerClient == nil {
 		return false
 	}
 	if !isTrackerAllowed(ctx) {
 		return false
 	}
-	for _, team := range onboardedTeams {
-		if team == teamID || team == "*" {
-			return true
+	return team.UsingTracker
+}
+
+// UpdateTeamTracker enables or disables the integration of a team with the
+// ticket tracker.
+func (s vulcanitoService) UpdateTeamTracker(ctx context.Context, teamID string, enabled bool) (*api.Team, error) {
+	if teamID == "" {
+		return nil, errors.Validation(`ID is empty`)
+	}
+	team, err := s.db.UpdateTeamTracker(teamID, enabled)
+	if err != nil {
+		return nil, err
+	}
+	team.UsingTracker = s.IsATeamOnboardedInVulcanTracker(ctx, *team)
+	return team, nil
+}
+
+// ListFindingTickets returns the tickets created for the findings of a team.
+func (s vulcanitoService) ListFindingTickets(ctx context.Context, teamID string) ([]*api.FindingTicket, error) {
+	return s.db.ListFindingTickets(teamID, false)
+}
+
+// CreateIssueTicketsAsync validates a request to create the tickets of the
+// open findings of an issue and stores the information necessary to create
+// them asynchronously.
+func (s vulcanitoService) CreateIssueTicketsAsync(ctx context.Context, bulk api.BulkFindingTicketCreate) (*api.Job, error) {
+	if !isTrackerAllowed(ctx) {
+		return nil, errors.Forbidden("unauthorized to create a ticket")
+	}
+	if bulk.TeamID == "" || bulk.IssueID == "" || bulk.UserID == "" {
+		return nil, errors.Validation("Team, issue and user are required")
+	}
+	if err := s.checkTeamUsingTracker(bulk.TeamID); err != nil {
+		return nil, err
+	}
+	return s.db.CreateIssueTicketsAsync(bulk)
+}
+
+// CreateIssueTickets creates a ticket for each open finding of an issue of a
+// team. The findings that already have a ticket that is not closed are not
+// ticketed again.
+func (s vulcanitoService) CreateIssueTickets(ctx context.Context, bulk api.BulkFindingTicketCreate) ([]api.BulkFindingTicketResult, error) {
+	if s.vulcantrackerClient == nil {
+		return nil, errors.Default("the ticket tracker is not configured")
+	}
+	findings, err := s.allFindings(ctx, api.FindingsParams{Team: bulk.TeamID, IssueID: bulk.IssueID, Status: findingStatusOpen})
+	if err != nil {
+		return nil, err
+	}
+	results := []api.BulkFindingTicketResult{}
+	for _, f := range findings {
+		result := api.BulkFindingTicketResult{FindingID: f.ID}
+		current, err := s.db.FindFindingTicket(bulk.TeamID, f.ID)
+		if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		if current != nil && current.ClosedAt == nil {
+			result.URLTracker = current.URLTracker
+			results = append(results, result)
+			continue
+		}
+		create := api.FindingTicketCreate{
+			FindingID:   f.ID,
+			TeamID:      bulk.TeamID,
+			Summary:     fmt.Sprintf("%s in %s", f.Issue.Summary, f.Target.Identifier),
+			Description: issueTicketDescription(f.Issue.Description, f.Issue.Recommendations),
+			Labels:      bulk.Labels,
 		}
+		ticket, err := s.createFindingTicket(ctx, create, bulk.UserID)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case ticket == nil:
+			result.Error = "the ticket tracker is not available"
+		default:
+			result.URLTracker = ticket.Ticket.URLTracker
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SyncFindingTickets synchronizes the tickets of a team that are not closed
+// with the status of their findings, and returns the number of tickets that
+// were closed:
+//   - The tickets of the findings that were fixed are resolved in the ticket
+//     tracker.
+//   - When a ticket is resolved as won't fix in the ticket tracker and its
+//     finding is still open, a risk acceptance of the finding is requested on
+//     behalf of the user that created the ticket, so the team can review it.
+func (s vulcanitoService) SyncFindingTickets(ctx context.Context, teamID string) (int, error) {
+	if s.vulcantrackerClient == nil {
+		return 0, nil
+	}
+	tickets, err := s.db.ListFindingTickets(teamID, true)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, t := range tickets {
+		// An error synchronizing a ticket, for instance because its
+		// finding no longer exists, must not prevent the rest of the
+		// tickets of the team from being synchronized.
+		closed, err := s.syncFindingTicket(ctx, *t)
+		if err != nil {
+			_ = level.Error(s.logger).Log("Tickets", "error synchronizing finding ticket", "TeamID", teamID, "FindingID", t.FindingID, "TicketID", t.TicketID, "err", err)
+			continue
+		}
+		if closed {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s vulcanitoService) syncFindingTicket(ctx context.Context, ticket api.FindingTicket) (bool, error) {
+	remote, err := s.vulcantrackerClient.GetTicket(ctx, ticket.TeamID, ticket.TicketID)
+	if err != nil {
+		return false, err
+	}
+	finding, err := s.vulndbClient.Finding(ctx, ticket.FindingID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	ticket.SyncedAt = &now
+	ticket.Status = remote.Ticket.Status
+	ticket.Resolution = remote.Ticket.Resolution
+	switch {
+	case remote.Ticket.ID == "":
+		// The ticket no longer exists in the ticket tracker.
+		ticket.ClosedAt = &now
+	case remote.Ticket.Resolution != "":
+		if api.IsTicketResolutionWontFix(remote.Ticket.Resolution) && finding.Finding.Status == findingStatusOpen {
+			if err := s.proposeRiskAcceptance(ctx, ticket); err != nil {
+				return false, err
+			}
+		}
+		ticket.ClosedAt = &now
+	case finding.Finding.Status == "FIXED":
+		fixed, err := s.vulcantrackerClient.FixTicket(ctx, ticket.TeamID, ticket.TicketID)
+		if err != nil {
+			return false, err
+		}
+		ticket.Status = fixed.Ticket.Status
+		ticket.Resolution = fixed.Ticket.Resolution
+		ticket.ClosedAt = &now
+	}
+	if _, err := s.db.UpdateFindingTicket(ticket); err != nil {
+		return false, err
+	}
+	return ticket.ClosedAt != nil, nil
+}
+
+// proposeRiskAcceptance requests the acceptance of the risk of the finding of
+// a ticket that was resolved as won't fix. The request is not made if the
+// user that created the ticket is unknown or if the finding already has a
+// pending or approved acceptance.
+func (s vulcanitoService) proposeRiskAcceptance(ctx context.Context, ticket api.FindingTicket) error {
+	if ticket.CreatedBy == nil {
+		_ = level.Warn(s.logger).Log("Tickets", "risk acceptance not proposed, unknown ticket creator", "TeamID", ticket.TeamID, "FindingID", ticket.FindingID)
+		return nil
+	}
+	expiresAt := time.Now().Add(api.MaxRiskAcceptanceDuration)
+	_, err := s.RequestRiskAcceptance(ctx, api.RiskAcceptance{
+		TeamID:        ticket.TeamID,
+		FindingID:     ticket.FindingID,
+		Justification: fmt.Sprintf("Ticket %s was resolved as '%s' in the ticket tracker: %s", ticket.TicketKey, ticket.Resolution, ticket.URLTracker),
+		ExpiresAt:     &expiresAt,
+		RequestedBy:   *ticket.CreatedBy,
+	})
+	if err != nil && !errors.IsKind(err, errors.ErrDuplicated) {
+		return err
+	}
+	return nil
+}
+
+// checkTeamUsingTracker returns an error if the ticket tracker is not
+// configured or the team has not enabled the integration with it.
+func (s vulcanitoService) checkTeamUsingTracker(teamID string) error {
+	if s.vulcantrackerClient == nil {
+		return errors.Validation("The ticket tracker is not configured")
+	}
+	team, err := s.db.FindTeam(teamID)
+	if err != nil {
+		return err
+	}
+	if !team.UsingTracker {
+		return errors.Validation("The team does not use the ticket tracker")
+	}
+	return nil
+}
+
+func issueTicketDescription(description string, recommendations []string) string {
+	if len(recommendations) == 0 {
+		return description
+	}
+	var b strings.Builder
+	b.WriteString(description)
+	b.WriteString("\n\nRecommendations:\n")
+	for _, r := range recommendations {
+		b.WriteString("- ")
+		b.WriteString(r)
+		b.WriteString("\n")
 	}
-	return false
+	return b.String()
 }
@@ -8,9 +8,17 @@ import (
 	"context"
 	"testing"
 
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 	"github.com/adevinta/vulcan-api/pkg/common"
 	"github.com/adevinta/vulcan-api/pkg/tickets"
+	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
 )
 
 func TestVulcanitoService_OnboardedTeam(t *testing.T) {
@@ -22,76 +30,221 @@ func TestVulcanitoService_OnboardedTeam(t *testing.T) {
 	ctxObserver := api.ContextWithUser(ctx, api.User{Observer: common.Bool(true)})
 
 	tests := []struct {
-		name           string
-		srv            vulcanitoService
-		context        context.Context
-		onboardedTeams []string
-		teamID         string
-		want           bool
+		name    string
+		srv     vulcanitoService
+		context context.Context
+		team    api.Team
+		want    bool
 	}{
 		{
-			name:           "OnboardedAllTeamsNoClient",
-			srv:            vulcanitoService{},
-			context:        ctxAdmin,
-			onboardedTeams: []string{"whatever-team", "*"},
-			teamID:         "other-team",
-			want:           false,
+			name:    "UsingTrackerNoClient",
+			srv:     vulcanitoService{},
+			context: ctxAdmin,
+			team:    api.Team{ID: "whatever-team", UsingTracker: true},
+			want:    false,
+		},
+		{
+			name:    "HappyPath-admin",
+			srv:     srv,
+			context: ctxAdmin,
+			team:    api.Team{ID: "whatever-team", UsingTracker: true},
+			want:    true,
+		},
+		{
+			name:    "HappyPath-observer",
+			srv:     srv,
+			context: ctxObserver,
+			team:    api.Team{ID: "whatever-team", UsingTracker: true},
+			want:    true,
+		},
+		{
+			name:    "HappyPath-no-auth",
+			srv:     srv,
+			context: ctx,
+			team:    api.Team{ID: "whatever-team", UsingTracker: true},
+			want:    false,
 		},
 		{
-			name:           "HappyPath-admin",
-			srv:            srv,
-			context:        ctxAdmin,
-			onboardedTeams: []string{"whatever-team"},
-			teamID:         "whatever-team",
-			want:           true,
+			name:    "NotUsingTracker",
+			srv:     srv,
+			context: ctxAdmin,
+			team:    api.Team{ID: "other-team"},
+			want:    false,
 		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.srv.IsATeamOnboardedInVulcanTracker(tt.context, tt.team) != tt.want {
+				t.Fatal("error")
+			}
+		})
+	}
+}
+
+type inMemoryTrackerClient struct {
+	tickets.Client
+	tickets map[string]vulcantracker.Ticket
+	fixed   []string
+}
+
+func (c *inMemoryTrackerClient) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	return &api.Ticket{Ticket: c.tickets[ticketID]}, nil
+}
+
+func (c *inMemoryTrackerClient) FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	c.fixed = append(c.fixed, ticketID)
+	ticket := c.tickets[ticketID]
+	ticket.Status = "Done"
+	ticket.Resolution = "Done"
+	return &api.Ticket{Ticket: ticket}, nil
+}
+
+type inMemoryFindingClient struct {
+	vulnerabilitydb.Client
+	status map[string]string
+}
+
+func (c *inMemoryFindingClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	if _, ok := c.status[findingID]; !ok {
+		return nil, errors.NotFound("finding not found")
+	}
+	finding := api.Finding{}
+	finding.Finding.Finding = vulndb.Finding{ID: findingID, Status: c.status[findingID]}
+	return &finding, nil
+}
+
+type inMemoryFindingTicketsStore struct {
+	api.VulcanitoStore
+	tickets []*api.FindingTicket
+	updated *api.FindingTicket
+}
+
+func (s *inMemoryFindingTicketsStore) ListFindingTickets(teamID string, onlyOpen bool) ([]*api.FindingTicket, error) {
+	return s.tickets, nil
+}
+
+func (s *inMemoryFindingTicketsStore) UpdateFindingTicket(ticket api.FindingTicket) (*api.FindingTicket, error) {
+	s.updated = &ticket
+	return &ticket, nil
+}
+
+func TestVulcanitoService_SyncFindingTicket(t *testing.T) {
+	tests := []struct {
+		name          string
+		ticket        api.FindingTicket
+		remote        vulcantracker.Ticket
+		findingStatus string
+		wantClosed    bool
+		wantFixed     []string
+		wantTicket    api.FindingTicket
+	}{
 		{
-			name:           "HappyPath-observer",
-			srv:            srv,
-			context:        ctxObserver,
-			onboardedTeams: []string{"whatever-team"},
-			teamID:         "whatever-team",
-			want:           true,
+			name:          "TicketStillOpen",
+			ticket:        api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
+			remote:        vulcantracker.Ticket{ID: "10", Status: "To Do"},
+			findingStatus: "OPEN",
+			wantTicket:    api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10", Status: "To Do"},
 		},
 		{
-			name:           "HappyPath-no-auth",
-			srv:            srv,
-			context:        ctx,
-			onboardedTeams: []string{"whatever-team"},
-			teamID:         "whatever-team",
-			want:           false,
+			name:          "FindingFixed",
+			ticket:        api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
+			remote:        vulcantracker.Ticket{ID: "10", Status: "To Do"},
+			findingStatus: "FIXED",
+			wantClosed:    true,
+			wantFixed:     []string{"10"},
+			wantTicket:    api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10", Status: "Done", Resolution: "Done"},
 		},
 		{
-			name:           "NoOnboardedTeam",
-			srv:            srv,
-			context:        ctxAdmin,
-			onboardedTeams: []string{"whatever-team"},
-			teamID:         "other-team",
-			want:           false,
+			name:          "TicketNotFound",
+			ticket:        api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
+			findingStatus: "OPEN",
+			wantClosed:    true,
+			wantTicket:    api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
 		},
 		{
-			name:           "OnboardedAllTeams",
-			srv:            srv,
-			context:        ctxAdmin,
-			onboardedTeams: []string{"whatever-team", "*"},
-			teamID:         "other-team",
-			want:           true,
+			name:          "WontFixFindingAlreadyFixed",
+			ticket:        api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
+			remote:        vulcantracker.Ticket{ID: "10", Status: "Done", Resolution: "Won't Fix"},
+			findingStatus: "FIXED",
+			wantClosed:    true,
+			wantTicket:    api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10", Status: "Done", Resolution: "Won't Fix"},
 		},
 		{
-			name:           "NoOnboardedAllTeams",
-			srv:            srv,
-			context:        ctxAdmin,
-			onboardedTeams: []string{},
-			teamID:         "other-team",
-			want:           false,
+			name:          "WontFixUnknownCreator",
+			ticket:        api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10"},
+			remote:        vulcantracker.Ticket{ID: "10", Status: "Done", Resolution: "Won't Fix"},
+			findingStatus: "OPEN",
+			wantClosed:    true,
+			wantTicket:    api.FindingTicket{ID: "1", TeamID: "t1", FindingID: "f1", TicketID: "10", Status: "Done", Resolution: "Won't Fix"},
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.srv.IsATeamOnboardedInVulcanTracker(tt.context, tt.teamID, tt.onboardedTeams) != tt.want {
-				t.Fatal("error")
+			tracker := &inMemoryTrackerClient{tickets: map[string]vulcantracker.Ticket{}}
+			if tt.remote.ID != "" {
+				tracker.tickets[tt.remote.ID] = tt.remote
+			}
+			store := &inMemoryFindingTicketsStore{}
+			srv := vulcanitoService{
+				db:                  store,
+				logger:              log.NewNopLogger(),
+				vulcantrackerClient: tracker,
+				vulndbClient:        &inMemoryFindingClient{status: map[string]string{tt.ticket.FindingID: tt.findingStatus}},
+			}
+			closed, err := srv.syncFindingTicket(context.Background(), tt.ticket)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if closed != tt.wantClosed {
+				t.Errorf("closed: got %v, want %v", closed, tt.wantClosed)
+			}
+			if diff := cmp.Diff(tt.wantFixed, tracker.fixed); diff != "" {
+				t.Errorf("fixed tickets mismatch (-want +got):\n%s", diff)
+			}
+			if store.updated == nil {
+				t.Fatal("ticket not updated")
+			}
+			if store.updated.SyncedAt == nil {
+				t.Error("synced_at not set")
+			}
+			if (store.updated.ClosedAt != nil) != tt.wantClosed {
+				t.Errorf("closed_at: got %v, want closed %v", store.updated.ClosedAt, tt.wantClosed)
+			}
+			opts := cmpopts.IgnoreFields(api.FindingTicket{}, "SyncedAt", "ClosedAt")
+			if diff := cmp.Diff(tt.wantTicket, *store.updated, opts); diff != "" {
+				t.Errorf("ticket mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestVulcanitoService_SyncFindingTickets(t *testing.T) {
+	tracker := &inMemoryTrackerClient{tickets: map[string]vulcantracker.Ticket{
+		"10": {ID: "10", Status: "To Do"},
+		"11": {ID: "11", Status: "To Do"},
+	}}
+	store := &inMemoryFindingTicketsStore{tickets: []*api.FindingTicket{
+		// The finding of the first ticket no longer exists in the
+		// vulnerability db.
+		{ID: "1", TeamID: "t1", FindingID: "deleted", TicketID: "10"},
+		{ID: "2", TeamID: "t1", FindingID: "f1", TicketID: "11"},
+	}}
+	srv := vulcanitoService{
+		db:                  store,
+		logger:              log.NewNopLogger(),
+		vulcantrackerClient: tracker,
+		vulndbClient:        &inMemoryFindingClient{status: map[string]string{"f1": "FIXED"}},
+	}
+	closed, err := srv.SyncFindingTickets(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closed != 1 {
+		t.Errorf("closed: got %v, want 1", closed)
+	}
+	if diff := cmp.Diff([]string{"11"}, tracker.fixed); diff != "" {
+		t.Errorf("fixed tickets mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -20,7 +20,7 @@ import (
 	"github.com/adevinta/vulcan-api/pkg/common"
 	"github.com/adevinta/vulcan-api/pkg/jwt"
 	"github.com/adevinta/vulcan-api/pkg/reports"
-	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
 	"github.com/adevinta/vulcan-api/pkg/schedule"
 	"github.com/adevinta/vulcan-api/pkg/testutil"
 	"github.com/adevinta/vulcan-api/pkg/vulnerabilitydb"
@@ -421,9 +421,9 @@ func TestServiceGenerateAPIToken(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			testServiceToken := New(loggerUser, testStore, jwt.NewJWTConfig(tt.signKey),
-				scanengine.Config{Url: ""}, schedulerMock{}, reports.Config{},
-				vulnerabilitydb.NewClient(nil, "", true), nil, nil, nil, cgCatalogueMock{},
-				[]string{}, false)
+				&scanenginetest.Fake{}, schedulerMock{}, reports.Config{},
+				vulnerabilitydb.NewClient(nil, "", true), nil, nil, nil, cgCatalogueMock{}, nil, nil,
+				false)
 			ctx := context.WithValue(context.Background(), tt.claim, api.User{Email: tt.authenticatedUser, Admin: tt.adminUser, Observer: tt.Observer, Active: tt.activeUser})
 			got, err := testServiceToken.GenerateAPIToken(ctx, tt.userID)
 			diff := cmp.Diff(errToStr(tt.wantErr), errToStr(err))
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	vulcanreport "github.com/adevinta/vulcan-report"
+)
+
+// Statuses of an open finding regarding its SLA.
+const (
+	// SLAStatusOnTrack is the status of a finding that is not close to its
+	// due date.
+	SLAStatusOnTrack = "ON_TRACK"
+	// SLAStatusAtRisk is the status of a finding whose remaining time to be
+	// fixed is lower than SLAAtRiskRatio of its SLA.
+	SLAStatusAtRisk = "AT_RISK"
+	// SLAStatusBreached is the status of a finding that was not fixed before
+	// its due date.
+	SLAStatusBreached = "BREACHED"
+)
+
+// SLAAtRiskRatio is the fraction of the SLA of a finding below which the
+// finding is at risk of breaching it.
+const SLAAtRiskRatio = 0.2
+
+var (
+	// ErrInvalidSLASeverity is returned when the severity of a SLA policy is
+	// unknown.
+	ErrInvalidSLASeverity = errors.New("invalid sla policy severity")
+	// ErrInvalidSLADays is returned when the days of a SLA policy are not
+	// positive.
+	ErrInvalidSLADays = errors.New("invalid sla policy days")
+	// ErrInvalidSLAROLFPLevel is returned when the ROLFP level of a SLA
+	// policy is not 0, 1 or 2.
+	ErrInvalidSLAROLFPLevel = errors.New("invalid sla policy rolfp level")
+)
+
+// SLAPolicy defines the days the findings of a given severity must be fixed
+// in. A policy without team applies to all the teams, and a policy without
+// ROLFP level applies to the findings of all the assets.
+type SLAPolicy struct {
+	ID     string  `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID *string `json:"team_id"`
+	// Severity possible values are: info, low, medium, high and critical.
+	Severity   string    `json:"severity"`
+	ROLFPLevel *int      `json:"rolfp_level" gorm:"Column:rolfp_level"`
+	Days       int       `json:"days"`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+}
+
+func (SLAPolicy) TableName() string {
+	return "sla_policies"
+}
+
+// Validate checks the severity, days and ROLFP level of the policy.
+func (p SLAPolicy) Validate() error {
+	if _, ok := severityRanks[strings.ToLower(p.Severity)]; !ok {
+		return ErrInvalidSLASeverity
+	}
+	if p.Days <= 0 {
+		return ErrInvalidSLADays
+	}
+	if p.ROLFPLevel != nil && (*p.ROLFPLevel < 0 || *p.ROLFPLevel > 2) {
+		return ErrInvalidSLAROLFPLevel
+	}
+	return nil
+}
+
+// specificity returns how specific the policy is. Team policies are more
+// specific than global ones, and policies with ROLFP level are more specific
+// than the ones without it.
+func (p SLAPolicy) specificity() int {
+	s := 0
+	if p.TeamID != nil {
+		s += 2
+	}
+	if p.ROLFPLevel != nil {
+		s++
+	}
+	return s
+}
+
+type SLAPolicyResponse struct {
+	ID         string `json:"id"`
+	TeamID     string `json:"team_id,omitempty"`
+	Severity   string `json:"severity"`
+	ROLFPLevel *int   `json:"rolfp_level,omitempty"`
+	Days       int    `json:"days"`
+}
+
+func (p SLAPolicy) ToResponse() SLAPolicyResponse {
+	response := SLAPolicyResponse{
+		ID:         p.ID,
+		Severity:   p.Severity,
+		ROLFPLevel: p.ROLFPLevel,
+		Days:       p.Days,
+	}
+	if p.TeamID != nil {
+		response.TeamID = *p.TeamID
+	}
+	return response
+}
+
+// MatchSLAPolicy returns the most specific policy that applies to a finding
+// with the given severity in an asset with the given ROLFP level, or nil if
+// no policy applies.
+func MatchSLAPolicy(policies []*SLAPolicy, severity string, rolfpLevel int) *SLAPolicy {
+	var match *SLAPolicy
+	for _, p := range policies {
+		if !strings.EqualFold(p.Severity, severity) {
+			continue
+		}
+		if p.ROLFPLevel != nil && *p.ROLFPLevel != rolfpLevel {
+			continue
+		}
+		if match == nil || p.specificity() > match.specificity() {
+			match = p
+		}
+	}
+	return match
+}
+
+// SeverityName returns the name of the severity of a finding with the given
+// score.
+func SeverityName(score float32) string {
+	rank := vulcanreport.RankSeverity(score)
+	for name, r := range severityRanks {
+		if r == rank {
+			return name
+		}
+	}
+	return ""
+}
+
+// FindingSLA contains the SLA information of an open finding.
+type FindingSLA struct {
+	FindingID  string    `json:"finding_id"`
+	IssueID    string    `json:"issue_id"`
+	Summary    string    `json:"summary"`
+	Target     string    `json:"target"`
+	Score      float32   `json:"score"`
+	Severity   string    `json:"severity"`
+	ROLFPLevel int       `json:"rolfp_level"`
+	SLADays    int       `json:"sla_days"`
+	OpenSince  time.Time `json:"open_since"`
+	DueAt      time.Time `json:"due_at"`
+	Status     string    `json:"status"`
+	// LastOpenedAt is the last time the finding was opened.
+	LastOpenedAt time.Time `json:"-"`
+}
+
+// NewFindingSLA computes the due date and the SLA status, at the given time,
+// of a finding that has been open for the given hours.
+func NewFindingSLA(policy SLAPolicy, exposureHours int64, now time.Time) FindingSLA {
+	sla := time.Duration(policy.Days) * 24 * time.Hour
+	openSince := now.Add(-time.Duration(exposureHours) * time.Hour)
+	dueAt := openSince.Add(sla)
+	status := SLAStatusOnTrack
+	remaining := dueAt.Sub(now)
+	switch {
+	case remaining <= 0:
+		status = SLAStatusBreached
+	case float64(remaining) <= float64(sla)*SLAAtRiskRatio:
+		status = SLAStatusAtRisk
+	}
+	return FindingSLA{
+		SLADays:   policy.Days,
+		OpenSince: openSince,
+		DueAt:     dueAt,
+		Status:    status,
+	}
+}
+
+// SLABreach records that the breach of the SLA of a finding was notified.
+// OpenedAt is the last time the finding was opened, so a finding that is
+// fixed and opened again is notified again when it breaches its SLA.
+type SLABreach struct {
+	TeamID     string    `gorm:"primary_key"`
+	FindingID  string    `gorm:"primary_key"`
+	OpenedAt   time.Time `gorm:"primary_key"`
+	DueAt      time.Time
+	NotifiedAt time.Time
+}
+
+// SLABreachNotification contains the information sent to the recipients of a
+// team when some of its findings breach their SLA.
+type SLABreachNotification struct {
+	TeamID   string       `json:"team_id"`
+	TeamName string       `json:"team_name"`
+	Findings []FindingSLA `json:"findings"`
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestSLAPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  SLAPolicy
+		wantErr error
+	}{
+		{name: "Valid", policy: SLAPolicy{Severity: "critical", Days: 7}},
+		{name: "ValidROLFPLevel", policy: SLAPolicy{Severity: "High", Days: 30, ROLFPLevel: intPtr(2)}},
+		{name: "InvalidSeverity", policy: SLAPolicy{Severity: "urgent", Days: 7}, wantErr: ErrInvalidSLASeverity},
+		{name: "InvalidDays", policy: SLAPolicy{Severity: "critical"}, wantErr: ErrInvalidSLADays},
+		{name: "InvalidROLFPLevel", policy: SLAPolicy{Severity: "critical", Days: 7, ROLFPLevel: intPtr(3)}, wantErr: ErrInvalidSLAROLFPLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if err != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchSLAPolicy(t *testing.T) {
+	team := "t1"
+	policies := []*SLAPolicy{
+		{ID: "global-critical", Severity: "critical", Days: 7},
+		{ID: "global-high", Severity: "high", Days: 30},
+		{ID: "global-high-level2", Severity: "high", Days: 15, ROLFPLevel: intPtr(2)},
+		{ID: "team-high", TeamID: &team, Severity: "high", Days: 20},
+		{ID: "team-critical-level0", TeamID: &team, Severity: "critical", Days: 14, ROLFPLevel: intPtr(0)},
+	}
+	tests := []struct {
+		name       string
+		severity   string
+		rolfpLevel int
+		wantID     string
+	}{
+		{name: "Global", severity: "critical", rolfpLevel: 2, wantID: "global-critical"},
+		{name: "TeamOverGlobalLevel", severity: "high", rolfpLevel: 2, wantID: "team-high"},
+		{name: "TeamLevel", severity: "critical", rolfpLevel: 0, wantID: "team-critical-level0"},
+		{name: "NoPolicy", severity: "low", rolfpLevel: 1, wantID: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchSLAPolicy(policies, tt.severity, tt.rolfpLevel)
+			gotID := ""
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("got policy %q, want %q", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestNewFindingSLA(t *testing.T) {
+	now := time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC)
+	policy := SLAPolicy{Severity: "high", Days: 10}
+	tests := []struct {
+		name          string
+		exposureHours int64
+		want          FindingSLA
+	}{
+		{
+			name:          "OnTrack",
+			exposureHours: 24,
+			want: FindingSLA{SLADays: 10, OpenSince: now.AddDate(0, 0, -1), DueAt: now.AddDate(0, 0, 9),
+				Status: SLAStatusOnTrack},
+		},
+		{
+			name:          "AtRisk",
+			exposureHours: 9 * 24,
+			want: FindingSLA{SLADays: 10, OpenSince: now.AddDate(0, 0, -9), DueAt: now.AddDate(0, 0, 1),
+				Status: SLAStatusAtRisk},
+		},
+		{
+			name:          "Breached",
+			exposureHours: 10 * 24,
+			want: FindingSLA{SLADays: 10, OpenSince: now.AddDate(0, 0, -10), DueAt: now,
+				Status: SLAStatusBreached},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFindingSLA(policy, tt.exposureHours, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("sla mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSeverityName(t *testing.T) {
+	tests := []struct {
+		score float32
+		want  string
+	}{
+		{score: 0, want: "info"},
+		{score: 2, want: "low"},
+		{score: 5, want: "medium"},
+		{score: 7.5, want: "high"},
+		{score: 9.8, want: "critical"},
+	}
+	for _, tt := range tests {
+		if got := SeverityName(tt.score); got != tt.want {
+			t.Errorf("SeverityName(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
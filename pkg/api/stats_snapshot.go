@@ -0,0 +1,169 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// StatsDateFormat is the format of the dates of the stats.
+const StatsDateFormat = "2006-01-02"
+
+var (
+	// ErrInvalidStatsTrendDate is returned when a date of the range of a
+	// stats trend is not in the format yyyy-mm-dd.
+	ErrInvalidStatsTrendDate = errors.New("invalid stats trend date")
+	// ErrInvalidStatsTrendRange is returned when the min date of the range
+	// of a stats trend is after its max date.
+	ErrInvalidStatsTrendRange = errors.New("invalid stats trend range")
+
+	// StatsSeverities contains the severities the stats snapshots are
+	// stored for.
+	StatsSeverities = []string{"info", "low", "medium", "high", "critical"}
+)
+
+// StatsSnapshot contains the stats of the findings of a severity of a team,
+// or of a group of a team, at the end of a day.
+type StatsSnapshot struct {
+	TeamID string `gorm:"primary_key" json:"team_id"`
+	// GroupID is empty in the snapshots of the whole team.
+	GroupID  string    `gorm:"primary_key" json:"group_id"`
+	Date     time.Time `gorm:"primary_key;type:date" json:"date"`
+	Severity string    `gorm:"primary_key" json:"severity"`
+	// Open is the number of findings open at the end of the day.
+	Open int `gorm:"column:open_count" json:"open"`
+	// New is the number of open findings detected during the day.
+	New int `gorm:"column:new_count" json:"new"`
+	// Fixed is the number of findings fixed during the day.
+	Fixed int `gorm:"column:fixed_count" json:"fixed"`
+	// Exposure is the mean of the current exposure of the open findings. It
+	// is not computed for the informational findings.
+	Exposure  float32   `gorm:"column:exposure_mean" json:"exposure"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// TableName returns the name of the table of the stats snapshots.
+func (StatsSnapshot) TableName() string {
+	return "stats_snapshots"
+}
+
+// StatsTrendParams defines the stats snapshots of a trend. An empty GroupID
+// selects the snapshots of the whole team.
+type StatsTrendParams struct {
+	TeamID  string
+	GroupID string
+	MinDate string
+	MaxDate string
+}
+
+// Range returns the first and the last day of the trend.
+func (p StatsTrendParams) Range() (time.Time, time.Time, error) {
+	from, err := time.Parse(StatsDateFormat, p.MinDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidStatsTrendDate
+	}
+	to, err := time.Parse(StatsDateFormat, p.MaxDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidStatsTrendDate
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, ErrInvalidStatsTrendRange
+	}
+	return from, to, nil
+}
+
+// Validate checks the range of the trend is valid.
+func (p StatsTrendParams) Validate() error {
+	_, _, err := p.Range()
+	return err
+}
+
+// StatsExposureSeverity contains the mean of the exposure of the open
+// findings of each severity.
+type StatsExposureSeverity struct {
+	Critical float32 `json:"critical"`
+	High     float32 `json:"high"`
+	Medium   float32 `json:"medium"`
+	Low      float32 `json:"low"`
+}
+
+// StatsTrendPoint contains the stats of a day of a trend.
+type StatsTrendPoint struct {
+	Date     string                    `json:"date"`
+	Open     vulndb.StatsIssueSeverity `json:"open"`
+	New      vulndb.StatsIssueSeverity `json:"new"`
+	Fixed    vulndb.StatsIssueSeverity `json:"fixed"`
+	Exposure StatsExposureSeverity     `json:"exposure"`
+}
+
+// StatsTrend contains the daily stats of a team, or of a group of a team,
+// in a range of dates. The days without a snapshot have no point.
+type StatsTrend struct {
+	TeamID  string            `json:"team_id"`
+	GroupID string            `json:"group_id,omitempty"`
+	MinDate string            `json:"min_date"`
+	MaxDate string            `json:"max_date"`
+	Points  []StatsTrendPoint `json:"points"`
+}
+
+// NewStatsTrend returns the trend with the given params built from the given
+// snapshots.
+func NewStatsTrend(params StatsTrendParams, snapshots []*StatsSnapshot) *StatsTrend {
+	points := map[string]*StatsTrendPoint{}
+	for _, s := range snapshots {
+		date := s.Date.Format(StatsDateFormat)
+		p, ok := points[date]
+		if !ok {
+			p = &StatsTrendPoint{Date: date}
+			points[date] = p
+		}
+		addSeverityCount(&p.Open, s.Severity, s.Open)
+		addSeverityCount(&p.New, s.Severity, s.New)
+		addSeverityCount(&p.Fixed, s.Severity, s.Fixed)
+		switch s.Severity {
+		case "critical":
+			p.Exposure.Critical = s.Exposure
+		case "high":
+			p.Exposure.High = s.Exposure
+		case "medium":
+			p.Exposure.Medium = s.Exposure
+		case "low":
+			p.Exposure.Low = s.Exposure
+		}
+	}
+	trend := &StatsTrend{
+		TeamID:  params.TeamID,
+		GroupID: params.GroupID,
+		MinDate: params.MinDate,
+		MaxDate: params.MaxDate,
+		Points:  []StatsTrendPoint{},
+	}
+	for _, p := range points {
+		trend.Points = append(trend.Points, *p)
+	}
+	sort.Slice(trend.Points, func(i, j int) bool {
+		return trend.Points[i].Date < trend.Points[j].Date
+	})
+	return trend
+}
+
+func addSeverityCount(s *vulndb.StatsIssueSeverity, severity string, n int) {
+	switch severity {
+	case "critical":
+		s.Critical += n
+	case "high":
+		s.High += n
+	case "medium":
+		s.Medium += n
+	case "low":
+		s.Low += n
+	case "info":
+		s.Informational += n
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+func TestStatsTrendParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  StatsTrendParams
+		wantErr error
+	}{
+		{name: "Valid", params: StatsTrendParams{MinDate: "2021-03-01", MaxDate: "2021-03-31"}},
+		{name: "OneDay", params: StatsTrendParams{MinDate: "2021-03-01", MaxDate: "2021-03-01"}},
+		{name: "InvalidMinDate", params: StatsTrendParams{MinDate: "01-03-2021", MaxDate: "2021-03-31"}, wantErr: ErrInvalidStatsTrendDate},
+		{name: "NoMaxDate", params: StatsTrendParams{MinDate: "2021-03-01"}, wantErr: ErrInvalidStatsTrendDate},
+		{name: "InvalidRange", params: StatsTrendParams{MinDate: "2021-03-31", MaxDate: "2021-03-01"}, wantErr: ErrInvalidStatsTrendRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if err != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStatsTrend(t *testing.T) {
+	day1 := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+	params := StatsTrendParams{TeamID: "t1", MinDate: "2021-03-01", MaxDate: "2021-03-03"}
+	snapshots := []*StatsSnapshot{
+		{TeamID: "t1", Date: day2, Severity: "critical", Open: 1, Fixed: 2, Exposure: 12.5},
+		{TeamID: "t1", Date: day1, Severity: "critical", Open: 3, New: 3, Exposure: 2},
+		{TeamID: "t1", Date: day1, Severity: "info", Open: 4, New: 1},
+	}
+	want := &StatsTrend{
+		TeamID:  "t1",
+		MinDate: "2021-03-01",
+		MaxDate: "2021-03-03",
+		Points: []StatsTrendPoint{
+			{
+				Date:     "2021-03-01",
+				Open:     vulndb.StatsIssueSeverity{Critical: 3, Informational: 4},
+				New:      vulndb.StatsIssueSeverity{Critical: 3, Informational: 1},
+				Exposure: StatsExposureSeverity{Critical: 2},
+			},
+			{
+				Date:     "2021-03-02",
+				Open:     vulndb.StatsIssueSeverity{Critical: 1},
+				Fixed:    vulndb.StatsIssueSeverity{Critical: 2},
+				Exposure: StatsExposureSeverity{Critical: 12.5},
+			},
+		},
+	}
+	got := NewStatsTrend(params, snapshots)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("trend mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// ListAssetChecktypeSettings returns the checktype settings of an asset of a
+// team, including the expired ones. If assetID is empty the settings of all
+// the assets of the team are returned.
+func (db vulcanitoStore) ListAssetChecktypeSettings(teamID, assetID string) ([]*api.AssetChecktypeSetting, error) {
+	settings := []*api.AssetChecktypeSetting{}
+	query := db.Conn.Where("team_id = ?", teamID)
+	if assetID != "" {
+		query = query.Where("asset_id = ?", assetID)
+	}
+	result := query.Order("checktype_name asc").Find(&settings)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return settings, nil
+}
+
+func (db vulcanitoStore) FindAssetChecktypeSetting(teamID, assetID, settingID string) (*api.AssetChecktypeSetting, error) {
+	setting := &api.AssetChecktypeSetting{}
+	result := db.Conn.
+		Where("team_id = ? AND asset_id = ? AND id = ?", teamID, assetID, settingID).
+		First(setting)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return setting, nil
+}
+
+// CreateAssetChecktypeSetting creates a checktype setting for an asset,
+// ensuring the asset belongs to the team of the setting.
+func (db vulcanitoStore) CreateAssetChecktypeSetting(setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	asset := api.Asset{}
+	result := db.Conn.
+		Where("team_id = ? AND id = ?", setting.TeamID, setting.AssetID).
+		First(&asset)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	result = db.Conn.Create(&setting)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("the asset already has a setting for the checktype"))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return &setting, nil
+}
+
+func (db vulcanitoStore) UpdateAssetChecktypeSetting(setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	result := db.Conn.Save(&setting)
+	if result.Error != nil {
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	return &setting, nil
+}
+
+func (db vulcanitoStore) DeleteAssetChecktypeSetting(setting api.AssetChecktypeSetting) error {
+	result := db.Conn.
+		Where("team_id = ? AND asset_id = ?", setting.TeamID, setting.AssetID).
+		Delete(&setting)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("asset checktype setting not found"))
+	}
+	return nil
+}
@@ -61,6 +61,23 @@ func (db vulcanitoStore) ListAssets(teamID string, asset api.Asset) ([]*api.Asse
 	return assets, nil
 }
 
+// ListAssetsByIdentifiers returns the assets of a team with the given
+// identifiers, loading only their annotations.
+func (db vulcanitoStore) ListAssetsByIdentifiers(teamID string, identifiers []string) ([]*api.Asset, error) {
+	assets := []*api.Asset{}
+	if len(identifiers) == 0 {
+		return assets, nil
+	}
+	result := db.Conn.
+		Preload("AssetAnnotations").
+		Where("team_id = ? AND identifier IN (?)", teamID, identifiers).
+		Find(&assets)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return assets, nil
+}
+
 func (db vulcanitoStore) CreateAssets(assets []api.Asset, groups []api.Group) ([]api.Asset, error) {
 	tx := db.Conn.Begin()
 	if tx.Error != nil {
@@ -738,19 +755,38 @@ func (db vulcanitoStore) CreateGroup(group api.Group) (*api.Group, error) {
 	return &group, nil
 }
 
+// UpdateGroup updates a group of a team. When the group becomes dynamic, the
+// assets statically added to it are removed, as its assets are defined by
+// its selector from then on.
 func (db vulcanitoStore) UpdateGroup(group api.Group) (*api.Group, error) {
 	findGroup := api.Group{ID: group.ID}
 	if db.Conn.Where("team_id = ? and id = ?", group.TeamID, group.ID).First(&findGroup).RecordNotFound() {
 		return nil, db.logError(errors.Forbidden("group does not belong to team"))
 	}
 
-	result := db.Conn.Model(&group).Where("team_id = ?", group.TeamID).Update(group)
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	result := tx.Model(&group).Where("team_id = ?", group.TeamID).Update(group)
 	if result.RowsAffected == 0 {
+		tx.Rollback()
 		return nil, db.logError(errors.Update("Asset group was not updated"))
 	}
 	if result.Error != nil {
+		tx.Rollback()
 		return nil, db.logError(errors.Update(result.Error))
 	}
+	if group.IsDynamic() {
+		res := tx.Delete(&api.AssetGroup{}, "group_id = ?", group.ID)
+		if res.Error != nil {
+			tx.Rollback()
+			return nil, db.logError(errors.Delete(res.Error))
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
 	return &group, nil
 }
 
@@ -790,9 +826,76 @@ func (db vulcanitoStore) FindGroup(group api.Group) (*api.Group, error) {
 		return nil, db.logError(errors.Database(res.Error))
 	}
 
+	if err := db.selectDynamicGroupsAssets([]*api.Group{&foundGroup}); err != nil {
+		return nil, err
+	}
 	return &foundGroup, nil
 }
 
+// selectDynamicGroupsAssets sets the assets of the dynamic groups in the
+// given list to the assets of their teams that match their selectors. The
+// assets of each team are queried only once, and their annotations and
+// static groups are only loaded if a selector of the team compares them.
+func (db vulcanitoStore) selectDynamicGroupsAssets(groups []*api.Group) error {
+	dynamic := []*api.Group{}
+	teamsFields := map[string]map[string]bool{}
+	for _, g := range groups {
+		if g == nil || !g.IsDynamic() {
+			continue
+		}
+		selector, err := api.ParseAssetSelector(*g.Selector)
+		if err != nil {
+			return db.logError(errors.Validation(err))
+		}
+		fields, ok := teamsFields[g.TeamID]
+		if !ok {
+			fields = map[string]bool{}
+			teamsFields[g.TeamID] = fields
+		}
+		for _, f := range []string{"annotations", "group"} {
+			fields[f] = fields[f] || selector.UsesField(f)
+		}
+		dynamic = append(dynamic, g)
+	}
+	if len(dynamic) == 0 {
+		return nil
+	}
+
+	types := []*api.AssetType{}
+	if err := db.Conn.Find(&types).Error; err != nil {
+		return db.logError(errors.Database(err))
+	}
+	at := map[string]*api.AssetType{}
+	for _, t := range types {
+		at[t.ID] = t
+	}
+	teamsAssets := map[string][]*api.Asset{}
+	for _, g := range dynamic {
+		assets, ok := teamsAssets[g.TeamID]
+		if !ok {
+			query := db.Conn.Where("team_id = ?", g.TeamID)
+			if teamsFields[g.TeamID]["annotations"] {
+				query = query.Preload("AssetAnnotations")
+			}
+			if teamsFields[g.TeamID]["group"] {
+				query = query.Preload("AssetGroups.Group")
+			}
+			assets = []*api.Asset{}
+			if err := query.Find(&assets).Error; err != nil {
+				return db.logError(errors.Database(err))
+			}
+			for _, a := range assets {
+				a.AssetType = at[a.AssetTypeID]
+			}
+			teamsAssets[g.TeamID] = assets
+		}
+		if err := g.SelectAssets(assets); err != nil {
+			return db.logError(err)
+		}
+	}
+	return nil
+}
+
 func (db vulcanitoStore) FindGroupInfo(group api.Group) (*api.Group, error) {
 	foundGroup := api.Group{}
 	res := db.Conn.Find(&foundGroup, group)
@@ -887,6 +990,9 @@ func (db vulcanitoStore) ListGroups(teamID, groupName string) ([]*api.Group, err
 		return nil, db.logError(errors.Database(result.Error))
 	}
 
+	if err := db.selectDynamicGroupsAssets(groups); err != nil {
+		return nil, err
+	}
 	return groups, nil
 }
 
@@ -917,6 +1023,9 @@ func (db vulcanitoStore) groupAssetTX(tx *gorm.DB, assetsGroup api.AssetGroup, t
 	if tx.Where("team_id = ?", teamID).First(&group).RecordNotFound() {
 		return nil, db.logError(errors.Forbidden("group does not belong to team"))
 	}
+	if group.IsDynamic() {
+		return nil, db.logError(errors.Validation("the assets of a dynamic group are defined by its selector"))
+	}
 	if !tx.First(&assetsGroup).RecordNotFound() {
 		return nil, db.logError(errors.Duplicated("asset group relation already exists"))
 	}
@@ -938,6 +1047,15 @@ func (db vulcanitoStore) ListAssetGroup(assetGroup api.AssetGroup, teamID string
 	if db.Conn.Where("team_id = ?", teamID).First(&group).RecordNotFound() {
 		return nil, db.logError(errors.Forbidden("group does not belong to team"))
 	}
+	if group.IsDynamic() {
+		if err := db.selectDynamicGroupsAssets([]*api.Group{&group}); err != nil {
+			return nil, err
+		}
+		for _, ag := range group.AssetGroup {
+			ag.Group = &group
+		}
+		return group.AssetGroup, nil
+	}
 	assetGroups := []*api.AssetGroup{}
 	res := db.Conn.
 		Preload("Asset").
@@ -977,6 +1095,9 @@ func (db vulcanitoStore) ungroupAssetsTX(tx *gorm.DB, assetGroup api.AssetGroup,
 	if tx.Where("team_id = ?", teamID).First(&group).RecordNotFound() {
 		return db.logError(errors.Forbidden("group does not belong to team"))
 	}
+	if group.IsDynamic() {
+		return db.logError(errors.Validation("the assets of a dynamic group are defined by its selector"))
+	}
 	if tx.First(&assetGroup).RecordNotFound() {
 		return db.logError(errors.Duplicated("asset group relation does not exists"))
 	}
@@ -763,6 +763,43 @@ func TestVulcanitoStore_UpdateGroup(t *testing.T) {
 	}
 }
 
+func TestVulcanitoStore_UpdateGroupDynamic(t *testing.T) {
+	testStoreLocal, err := testutil.PrepareDatabaseLocal("../../../testdata/fixtures", NewDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer testStoreLocal.Close()
+
+	selector := "identifier == nfl.com"
+	group := api.Group{ID: "721d1c6b-f559-4c56-8ea5-ca1820173a3c", TeamID: "3C7C2963-6A03-4A25-A822-EBEB237DB065", Selector: &selector}
+	if _, err := testStoreLocal.UpdateGroup(group); err != nil {
+		t.Fatalf("Cannot update group: %v", err)
+	}
+
+	assetGroups := []*api.AssetGroup{}
+	if err := testStoreLocal.(Store).Conn.Find(&assetGroups, "group_id = ?", group.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(assetGroups) != 0 {
+		t.Errorf("got %d static assets in the dynamic group, want 0", len(assetGroups))
+	}
+
+	found, err := testStoreLocal.FindGroup(api.Group{ID: group.ID})
+	if err != nil {
+		t.Fatalf("Could not find group: %v", err)
+	}
+	if len(found.AssetGroup) != 1 {
+		t.Fatalf("got %d assets in the dynamic group, want 1", len(found.AssetGroup))
+	}
+	asset := found.AssetGroup[0].Asset
+	if asset.Identifier != "nfl.com" {
+		t.Errorf("got asset %s, want nfl.com", asset.Identifier)
+	}
+	if asset.AssetType == nil || asset.AssetType.ID != "1937b564-bbc4-47f6-9722-b4a8c8ac0595" {
+		t.Errorf("got asset type %+v, want the type of the asset", asset.AssetType)
+	}
+}
+
 func TestVulcanitoStore_FindGroup(t *testing.T) {
 	testStoreLocal, err := testutil.PrepareDatabaseLocal("../../../testdata/fixtures", NewDB)
 	if err != nil {
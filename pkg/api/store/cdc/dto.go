@@ -49,6 +49,20 @@ type OpFindingOverwriteDTO struct {
 	FindingOverwrite api.FindingOverwrite `json:"finding_overwrite"`
 }
 
+// OpBulkFindingOverwriteDTO represents the data to store
+// as part of CDC log for a BulkFindingOverwrite operation.
+type OpBulkFindingOverwriteDTO struct {
+	BulkFindingOverwrite api.BulkFindingOverwrite `json:"bulk_finding_overwrite"`
+	JobID                string                   `json:"job_id"`
+}
+
+// OpCreateIssueTicketsDTO represents the data to store
+// as part of CDC log for a CreateIssueTickets operation.
+type OpCreateIssueTicketsDTO struct {
+	BulkFindingTicketCreate api.BulkFindingTicketCreate `json:"bulk_finding_ticket_create"`
+	JobID                   string                      `json:"job_id"`
+}
+
 // OpMergeDiscoveredAssetsDTO represents the data to store
 // as part of CDC log for a MergeDiscoveredAsset operation.
 type OpMergeDiscoveredAssetsDTO struct {
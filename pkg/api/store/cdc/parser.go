@@ -27,6 +27,8 @@ const (
 	opDeleteAllAssets       = "DeleteAllAssets"
 	opFindingOverwrite      = "FindingOverwrite"
 	opMergeDiscoveredAssets = "MergeDiscoveredAssets"
+	opBulkFindingOverwrite  = "BulkFindingOverwrite"
+	opCreateIssueTickets    = "CreateIssueTickets"
 )
 
 var (
@@ -96,6 +98,10 @@ func (p *AsyncTxParser) Parse(log []Event) (nParsed uint) {
 			processFunc = p.processFindingOverwrite
 		case opMergeDiscoveredAssets:
 			processFunc = p.processMergeDiscoveredAssets
+		case opBulkFindingOverwrite:
+			processFunc = p.processBulkFindingOverwrite
+		case opCreateIssueTickets:
+			processFunc = p.processCreateIssueTickets
 		default:
 			// If action is not supported
 			// log err and stop processing
@@ -351,11 +357,137 @@ func (p *AsyncTxParser) processMergeDiscoveredAssets(data []byte) error {
 	return nil
 }
 
+// processBulkFindingOverwrite performs the following actions:
+// - Marks the Job as RUNNING
+// - Calls the CreateFindingOverwrites operation
+// - Marks the Job as DONE, storing the result of each finding
+// In the case that the CreateFindingOverwrites operation fails, the error is
+// added to the JobResult.
+// Errors are not returned from the function to avoid this operation to be
+// retried. They are logged instead. If the event is processed again, the
+// job is skipped if it is already DONE, and otherwise the findings already
+// overwritten by the job are not overwritten again.
+func (p *AsyncTxParser) processBulkFindingOverwrite(data []byte) error {
+	var dto OpBulkFindingOverwriteDTO
+
+	err := json.Unmarshal(data, &dto)
+	if err != nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", err, "action", opBulkFindingOverwrite,
+		)
+		return nil
+	}
+
+	if p.JobsRunner == nil || p.JobsRunner.Client == nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", errUnavailabeJobsRunner, "action", opBulkFindingOverwrite,
+		)
+		return nil
+	}
+
+	if current, err := p.JobsRunner.Client.FindJob(context.Background(), dto.JobID); err == nil && current.Status == api.JobStatusDone {
+		return nil
+	}
+
+	// Set the status of the Job to RUNNING so the user can track its progress.
+	job := api.Job{
+		ID:        dto.JobID,
+		Status:    api.JobStatusRunning,
+		Operation: opBulkFindingOverwrite,
+	}
+	if err := p.updateJob(job); err != nil {
+		return nil
+	}
+
+	// Apply the overwrite to the findings.
+	job.Result = &api.JobResult{}
+	bulk := dto.BulkFindingOverwrite
+	bulk.JobID = dto.JobID
+	results, err := p.JobsRunner.Client.CreateFindingOverwrites(context.Background(), bulk)
+	if err != nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", err, "job_id", dto.JobID, "action", opBulkFindingOverwrite,
+		)
+		job.Result.Error = err.Error()
+	}
+	job.Result.Data, err = json.Marshal(results)
+	if err != nil {
+		job.Result.Error = err.Error()
+	}
+
+	// Mark the job as DONE.
+	job.Status = api.JobStatusDone
+	if err := p.updateJob(job); err != nil {
+		return nil
+	}
+
+	return nil
+}
+
+// processCreateIssueTickets performs the following actions:
+// - Marks the Job as RUNNING
+// - Calls the CreateIssueTickets operation
+// - Marks the Job as DONE, storing the ticket of each finding
+// In the case that the CreateIssueTickets operation fails, the error is
+// added to the JobResult.
+// Errors are not returned from the function to avoid this operation to be
+// retried. They are logged instead.
+func (p *AsyncTxParser) processCreateIssueTickets(data []byte) error {
+	var dto OpCreateIssueTicketsDTO
+
+	err := json.Unmarshal(data, &dto)
+	if err != nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", err, "action", opCreateIssueTickets,
+		)
+		return nil
+	}
+
+	if p.JobsRunner == nil || p.JobsRunner.Client == nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", errUnavailabeJobsRunner, "action", opCreateIssueTickets,
+		)
+		return nil
+	}
+
+	// Set the status of the Job to RUNNING so the user can track its progress.
+	job := api.Job{
+		ID:        dto.JobID,
+		Status:    api.JobStatusRunning,
+		Operation: opCreateIssueTickets,
+	}
+	if err := p.updateJob(job); err != nil {
+		return nil
+	}
+
+	// Create the tickets of the findings.
+	job.Result = &api.JobResult{}
+	results, err := p.JobsRunner.Client.CreateIssueTickets(context.Background(), dto.BulkFindingTicketCreate)
+	if err != nil {
+		_ = level.Error(p.logger).Log(
+			"component", CDCLogTag, "error", err, "job_id", dto.JobID, "action", opCreateIssueTickets,
+		)
+		job.Result.Error = err.Error()
+	}
+	job.Result.Data, err = json.Marshal(results)
+	if err != nil {
+		job.Result.Error = err.Error()
+	}
+
+	// Mark the job as DONE.
+	job.Status = api.JobStatusDone
+	if err := p.updateJob(job); err != nil {
+		return nil
+	}
+
+	return nil
+}
+
 func (p *AsyncTxParser) updateJob(job api.Job) error {
 	_, err := p.JobsRunner.Client.UpdateJob(context.Background(), job)
 	if err != nil {
 		_ = level.Error(p.logger).Log(
-			"component", CDCLogTag, "error", err, "job_id", job.ID, "action", opMergeDiscoveredAssets,
+			"component", CDCLogTag, "error", err, "job_id", job.ID, "action", job.Operation,
 		)
 	}
 	return err
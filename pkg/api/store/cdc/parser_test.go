@@ -480,3 +480,96 @@ func newTestAsyncAPI() (*asyncapi.Vulcan, kafka.Client, error) {
 func strToPtr(s string) *string {
 	return &s
 }
+
+type mockJobsClient struct {
+	api.JobsClient
+	results []api.BulkFindingOverwriteResult
+	err     error
+	status  api.JobStatus
+	jobs    []api.Job
+	bulks   []api.BulkFindingOverwrite
+}
+
+func (m *mockJobsClient) CreateFindingOverwrites(ctx context.Context, bulk api.BulkFindingOverwrite) ([]api.BulkFindingOverwriteResult, error) {
+	m.bulks = append(m.bulks, bulk)
+	return m.results, m.err
+}
+
+func (m *mockJobsClient) FindJob(ctx context.Context, jobID string) (*api.Job, error) {
+	status := m.status
+	if status == "" {
+		status = api.JobStatusPending
+	}
+	return &api.Job{ID: jobID, Status: status}, nil
+}
+
+func (m *mockJobsClient) UpdateJob(ctx context.Context, job api.Job) (*api.Job, error) {
+	m.jobs = append(m.jobs, job)
+	return &job, nil
+}
+
+func TestProcessBulkFindingOverwrite(t *testing.T) {
+	dto, err := json.Marshal(OpBulkFindingOverwriteDTO{
+		BulkFindingOverwrite: api.BulkFindingOverwrite{TeamID: "t1", UserID: "u1", FindingIDs: []string{"f1", "f2"}, Status: "FALSE_POSITIVE", Notes: "n"},
+		JobID:                "j1",
+	})
+	if err != nil {
+		t.Fatal(errTestSetup)
+	}
+	testCases := []struct {
+		name     string
+		client   *mockJobsClient
+		wantJobs []api.Job
+	}{
+		{
+			name: "StoresTheResultOfEachFinding",
+			client: &mockJobsClient{
+				results: []api.BulkFindingOverwriteResult{
+					{FindingID: "f1", StatusPrevious: "OPEN"},
+					{FindingID: "f2", StatusPrevious: "FIXED", Error: "not allowed"},
+				},
+			},
+			wantJobs: []api.Job{
+				{ID: "j1", Status: api.JobStatusRunning, Operation: opBulkFindingOverwrite},
+				{ID: "j1", Status: api.JobStatusDone, Operation: opBulkFindingOverwrite, Result: &api.JobResult{
+					Data: json.RawMessage(`[{"finding_id":"f1","status_previous":"OPEN"},{"finding_id":"f2","status_previous":"FIXED","error":"not allowed"}]`),
+				}},
+			},
+		},
+		{
+			name:   "SkipsDoneJob",
+			client: &mockJobsClient{status: api.JobStatusDone},
+		},
+		{
+			name:   "StoresTheErrorOfTheOperation",
+			client: &mockJobsClient{err: errs.New("vulndb not available")},
+			wantJobs: []api.Job{
+				{ID: "j1", Status: api.JobStatusRunning, Operation: opBulkFindingOverwrite},
+				{ID: "j1", Status: api.JobStatusDone, Operation: opBulkFindingOverwrite, Result: &api.JobResult{
+					Data:  json.RawMessage(`null`),
+					Error: "vulndb not available",
+				}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := NewAsyncTxParser(&mockVulnDBClient{}, &api.JobsRunner{Client: tc.client}, nil, &mockLoggr{})
+			nParsed := parser.Parse([]Event{Outbox{Operation: opBulkFindingOverwrite, DTO: dto}})
+			if nParsed != 1 {
+				t.Fatalf("expected nParsed to be 1, but got %d", nParsed)
+			}
+			diff := cmp.Diff(tc.wantJobs, tc.client.jobs)
+			if diff != "" {
+				t.Fatalf("want!=got, diff: %s", diff)
+			}
+			// The findings are overwritten by the job so it can skip the
+			// ones already overwritten if it runs again.
+			for _, bulk := range tc.client.bulks {
+				if bulk.JobID != "j1" {
+					t.Errorf("got bulk overwrite for job %q, want j1", bulk.JobID)
+				}
+			}
+		})
+	}
+}
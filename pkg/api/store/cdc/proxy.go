@@ -240,6 +240,11 @@ func (b *BrokerProxy) FindTeamByTag(tag string) (*api.Team, error) {
 func (b *BrokerProxy) FindTeamByProgram(programID string) (*api.Team, error) {
 	return b.store.FindTeamByProgram(programID)
 }
+
+func (b *BrokerProxy) UpdateTeamTracker(teamID string, enabled bool) (*api.Team, error) {
+	return b.store.UpdateTeamTracker(teamID, enabled)
+}
+
 func (b *BrokerProxy) DeleteTeam(teamID string) error {
 	err := b.store.DeleteTeam(teamID)
 	go b.awakeBroker()
@@ -268,10 +273,28 @@ func (b *BrokerProxy) UpdateRecipients(teamID string, emails []string) error {
 func (b *BrokerProxy) ListRecipients(teamID string) ([]*api.Recipient, error) {
 	return b.store.ListRecipients(teamID)
 }
+func (b *BrokerProxy) FindRecipient(teamID, email string) (*api.Recipient, error) {
+	return b.store.FindRecipient(teamID, email)
+}
+func (b *BrokerProxy) CreateRecipient(recipient api.Recipient) (*api.Recipient, error) {
+	return b.store.CreateRecipient(recipient)
+}
+func (b *BrokerProxy) UpdateRecipient(recipient api.Recipient) (*api.Recipient, error) {
+	return b.store.UpdateRecipient(recipient)
+}
+func (b *BrokerProxy) DeleteRecipient(teamID, email string) error {
+	return b.store.DeleteRecipient(teamID, email)
+}
+func (b *BrokerProxy) DeactivateRecipients(emails []string, reason string) (int64, error) {
+	return b.store.DeactivateRecipients(emails, reason)
+}
 
 func (b *BrokerProxy) ListAssets(teamID string, asset api.Asset) ([]*api.Asset, error) {
 	return b.store.ListAssets(teamID, asset)
 }
+func (b *BrokerProxy) ListAssetsByIdentifiers(teamID string, identifiers []string) ([]*api.Asset, error) {
+	return b.store.ListAssetsByIdentifiers(teamID, identifiers)
+}
 func (b *BrokerProxy) FindAsset(teamID, assetID string) (*api.Asset, error) {
 	return b.store.FindAsset(teamID, assetID)
 }
@@ -438,6 +461,17 @@ func (b *BrokerProxy) UpsertGlobalProgramMetadata(teamID, program string, defaul
 func (b *BrokerProxy) DeleteProgramMetadata(program string) error {
 	return b.store.DeleteProgramMetadata(program)
 }
+func (b *BrokerProxy) ListGlobalEntityDefinitions() ([]*api.GlobalEntityDefinition, error) {
+	return b.store.ListGlobalEntityDefinitions()
+}
+
+func (b *BrokerProxy) ListGlobalPolicyEvaluations() ([]*api.GlobalPolicyEvaluation, error) {
+	return b.store.ListGlobalPolicyEvaluations()
+}
+
+func (b *BrokerProxy) ReplaceGlobalPolicyEvaluations(evaluations []*api.GlobalPolicyEvaluation) error {
+	return b.store.ReplaceGlobalPolicyEvaluations(evaluations)
+}
 
 func (b *BrokerProxy) CreateFindingOverwrite(findingOverwrite api.FindingOverwrite) error {
 	err := b.store.CreateFindingOverwrite(findingOverwrite)
@@ -448,3 +482,299 @@ func (b *BrokerProxy) CreateFindingOverwrite(findingOverwrite api.FindingOverwri
 func (b *BrokerProxy) ListFindingOverwrites(findingID string) ([]*api.FindingOverwrite, error) {
 	return b.store.ListFindingOverwrites(findingID)
 }
+
+func (b *BrokerProxy) CreateFindingOverwrites(findingOverwrites []api.FindingOverwrite) error {
+	err := b.store.CreateFindingOverwrites(findingOverwrites)
+	go b.awakeBroker()
+	return err
+}
+
+func (b *BrokerProxy) ListJobFindingOverwrites(jobID string) ([]*api.FindingOverwrite, error) {
+	return b.store.ListJobFindingOverwrites(jobID)
+}
+
+func (b *BrokerProxy) CreateFindingOverwritesAsync(bulk api.BulkFindingOverwrite) (*api.Job, error) {
+	j, err := b.store.CreateFindingOverwritesAsync(bulk)
+	go b.awakeBroker()
+	return j, err
+}
+
+func (b *BrokerProxy) CreateFindingsExportAsync(export api.FindingsExport) (*api.Job, error) {
+	return b.store.CreateFindingsExportAsync(export)
+}
+
+func (b *BrokerProxy) ClaimFindingsExport(staleBefore time.Time) (*api.FindingsExport, error) {
+	return b.store.ClaimFindingsExport(staleBefore)
+}
+
+func (b *BrokerProxy) UpdateFindingsExport(export api.FindingsExport) (*api.FindingsExport, error) {
+	return b.store.UpdateFindingsExport(export)
+}
+
+func (b *BrokerProxy) FindFindingsExport(teamID, exportID string) (*api.FindingsExport, error) {
+	return b.store.FindFindingsExport(teamID, exportID)
+}
+
+func (b *BrokerProxy) DeleteFindingsExports(createdBefore time.Time) (int, error) {
+	return b.store.DeleteFindingsExports(createdBefore)
+}
+
+func (b *BrokerProxy) CreateFindingsExportPart(part api.FindingsExportPart) error {
+	return b.store.CreateFindingsExportPart(part)
+}
+
+func (b *BrokerProxy) FindFindingsExportPart(exportID string, part int) (*api.FindingsExportPart, error) {
+	return b.store.FindFindingsExportPart(exportID, part)
+}
+
+func (b *BrokerProxy) DeleteFindingsExportParts(exportID string) error {
+	return b.store.DeleteFindingsExportParts(exportID)
+}
+
+func (b *BrokerProxy) ListFindingComments(teamID, findingID string) ([]*api.FindingComment, error) {
+	return b.store.ListFindingComments(teamID, findingID)
+}
+
+func (b *BrokerProxy) FindFindingComment(teamID, findingID, commentID string) (*api.FindingComment, error) {
+	return b.store.FindFindingComment(teamID, findingID, commentID)
+}
+
+func (b *BrokerProxy) CreateFindingComment(comment api.FindingComment) (*api.FindingComment, error) {
+	return b.store.CreateFindingComment(comment)
+}
+
+func (b *BrokerProxy) UpdateFindingComment(comment api.FindingComment, edit *api.FindingCommentEdit) (*api.FindingComment, error) {
+	return b.store.UpdateFindingComment(comment, edit)
+}
+
+func (b *BrokerProxy) DeleteFindingComment(comment api.FindingComment) error {
+	return b.store.DeleteFindingComment(comment)
+}
+
+func (b *BrokerProxy) UpsertFindingTicket(ticket api.FindingTicket) (*api.FindingTicket, error) {
+	return b.store.UpsertFindingTicket(ticket)
+}
+
+func (b *BrokerProxy) FindFindingTicket(teamID, findingID string) (*api.FindingTicket, error) {
+	return b.store.FindFindingTicket(teamID, findingID)
+}
+
+func (b *BrokerProxy) ListFindingTickets(teamID string, open bool) ([]*api.FindingTicket, error) {
+	return b.store.ListFindingTickets(teamID, open)
+}
+
+func (b *BrokerProxy) UpdateFindingTicket(ticket api.FindingTicket) (*api.FindingTicket, error) {
+	return b.store.UpdateFindingTicket(ticket)
+}
+
+func (b *BrokerProxy) CreateIssueTicketsAsync(bulk api.BulkFindingTicketCreate) (*api.Job, error) {
+	j, err := b.store.CreateIssueTicketsAsync(bulk)
+	go b.awakeBroker()
+	return j, err
+}
+
+func (b *BrokerProxy) FindTeamTicketProvider(teamID string) (*api.TicketProvider, error) {
+	return b.store.FindTeamTicketProvider(teamID)
+}
+
+func (b *BrokerProxy) UpsertTeamTicketProvider(provider api.TicketProvider) (*api.TicketProvider, error) {
+	return b.store.UpsertTeamTicketProvider(provider)
+}
+
+func (b *BrokerProxy) DeleteTeamTicketProvider(teamID string) error {
+	return b.store.DeleteTeamTicketProvider(teamID)
+}
+
+func (b *BrokerProxy) CreateRiskAcceptance(acceptance api.RiskAcceptance) (*api.RiskAcceptance, error) {
+	return b.store.CreateRiskAcceptance(acceptance)
+}
+
+func (b *BrokerProxy) ListRiskAcceptances(teamID, findingID, status string) ([]*api.RiskAcceptance, error) {
+	return b.store.ListRiskAcceptances(teamID, findingID, status)
+}
+
+func (b *BrokerProxy) ListExpiredRiskAcceptances(now time.Time) ([]*api.RiskAcceptance, error) {
+	return b.store.ListExpiredRiskAcceptances(now)
+}
+
+func (b *BrokerProxy) FindRiskAcceptance(teamID, acceptanceID string) (*api.RiskAcceptance, error) {
+	return b.store.FindRiskAcceptance(teamID, acceptanceID)
+}
+
+func (b *BrokerProxy) ListSLAPolicies(teamID string) ([]*api.SLAPolicy, error) {
+	return b.store.ListSLAPolicies(teamID)
+}
+
+func (b *BrokerProxy) FindSLAPolicy(teamID, policyID string) (*api.SLAPolicy, error) {
+	return b.store.FindSLAPolicy(teamID, policyID)
+}
+
+func (b *BrokerProxy) CreateSLAPolicy(policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	return b.store.CreateSLAPolicy(policy)
+}
+
+func (b *BrokerProxy) UpdateSLAPolicy(policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	return b.store.UpdateSLAPolicy(policy)
+}
+
+func (b *BrokerProxy) DeleteSLAPolicy(policy api.SLAPolicy) error {
+	return b.store.DeleteSLAPolicy(policy)
+}
+
+func (b *BrokerProxy) RecordSLABreaches(teamID string, breaches []api.SLABreach, notify func([]api.SLABreach) error) (int, error) {
+	return b.store.RecordSLABreaches(teamID, breaches, notify)
+}
+
+// TransitionRiskAcceptance awakes the broker when the transition creates a
+// finding overwrite, as it is pushed to the outbox.
+func (b *BrokerProxy) TransitionRiskAcceptance(acceptance api.RiskAcceptance, fromStatus string, overwrite *api.FindingOverwrite) (bool, error) {
+	ok, err := b.store.TransitionRiskAcceptance(acceptance, fromStatus, overwrite)
+	if ok && overwrite != nil {
+		go b.awakeBroker()
+	}
+	return ok, err
+}
+
+func (b *BrokerProxy) CreateFindingVerification(verification api.FindingVerification) (*api.FindingVerification, error) {
+	return b.store.CreateFindingVerification(verification)
+}
+
+func (b *BrokerProxy) ListFindingVerifications(teamID, findingID string) ([]*api.FindingVerification, error) {
+	return b.store.ListFindingVerifications(teamID, findingID)
+}
+
+func (b *BrokerProxy) ListScanQueueEntries(teamID string, statuses []api.ScanQueueStatus) ([]*api.ScanQueueEntry, error) {
+	return b.store.ListScanQueueEntries(teamID, statuses)
+}
+
+func (b *BrokerProxy) AdmitScanQueueEntries(admit func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry) ([]*api.ScanQueueEntry, error) {
+	return b.store.AdmitScanQueueEntries(admit)
+}
+
+func (b *BrokerProxy) StartScanQueueEntry(entryID, scanID string, checkCount int) (bool, error) {
+	return b.store.StartScanQueueEntry(entryID, scanID, checkCount)
+}
+
+func (b *BrokerProxy) UpdateScanQueueEntryChecks(entryID string, checkCount, checksInFlight int) (bool, error) {
+	return b.store.UpdateScanQueueEntryChecks(entryID, checkCount, checksInFlight)
+}
+
+func (b *BrokerProxy) FinishScanQueueEntry(entryID, reason string) (bool, error) {
+	return b.store.FinishScanQueueEntry(entryID, reason)
+}
+
+func (b *BrokerProxy) CancelScanQueueEntry(teamID, entryID string) (bool, error) {
+	return b.store.CancelScanQueueEntry(teamID, entryID)
+}
+
+func (b *BrokerProxy) CreateNotificationRule(rule api.NotificationRule) (*api.NotificationRule, error) {
+	return b.store.CreateNotificationRule(rule)
+}
+
+func (b *BrokerProxy) ListNotificationRules(teamID, programID string) ([]*api.NotificationRule, error) {
+	return b.store.ListNotificationRules(teamID, programID)
+}
+
+func (b *BrokerProxy) FindNotificationRule(teamID, programID, ruleID string) (*api.NotificationRule, error) {
+	return b.store.FindNotificationRule(teamID, programID, ruleID)
+}
+
+func (b *BrokerProxy) UpdateNotificationRule(rule api.NotificationRule) (*api.NotificationRule, error) {
+	return b.store.UpdateNotificationRule(rule)
+}
+
+func (b *BrokerProxy) DeleteNotificationRule(rule api.NotificationRule) error {
+	return b.store.DeleteNotificationRule(rule)
+}
+
+func (b *BrokerProxy) ListPolicyTemplates() ([]*api.PolicyTemplate, error) {
+	return b.store.ListPolicyTemplates()
+}
+
+func (b *BrokerProxy) CreatePolicyTemplate(template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	return b.store.CreatePolicyTemplate(template)
+}
+
+func (b *BrokerProxy) FindPolicyTemplate(templateID string) (*api.PolicyTemplate, error) {
+	return b.store.FindPolicyTemplate(templateID)
+}
+
+func (b *BrokerProxy) UpdatePolicyTemplate(template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	return b.store.UpdatePolicyTemplate(template)
+}
+
+func (b *BrokerProxy) DeletePolicyTemplate(templateID string) error {
+	return b.store.DeletePolicyTemplate(templateID)
+}
+
+func (b *BrokerProxy) ListAssetChecktypeSettings(teamID, assetID string) ([]*api.AssetChecktypeSetting, error) {
+	return b.store.ListAssetChecktypeSettings(teamID, assetID)
+}
+
+func (b *BrokerProxy) FindAssetChecktypeSetting(teamID, assetID, settingID string) (*api.AssetChecktypeSetting, error) {
+	return b.store.FindAssetChecktypeSetting(teamID, assetID, settingID)
+}
+
+func (b *BrokerProxy) CreateAssetChecktypeSetting(setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	return b.store.CreateAssetChecktypeSetting(setting)
+}
+
+func (b *BrokerProxy) UpdateAssetChecktypeSetting(setting api.AssetChecktypeSetting) (*api.AssetChecktypeSetting, error) {
+	return b.store.UpdateAssetChecktypeSetting(setting)
+}
+
+func (b *BrokerProxy) DeleteAssetChecktypeSetting(setting api.AssetChecktypeSetting) error {
+	return b.store.DeleteAssetChecktypeSetting(setting)
+}
+
+func (b *BrokerProxy) FindChecktypesSnapshot(name string) (*api.ChecktypesSnapshot, error) {
+	return b.store.FindChecktypesSnapshot(name)
+}
+
+func (b *BrokerProxy) UpsertChecktypesSnapshot(snapshot api.ChecktypesSnapshot) error {
+	return b.store.UpsertChecktypesSnapshot(snapshot)
+}
+
+func (b *BrokerProxy) CreateReportSubscription(subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	return b.store.CreateReportSubscription(subscription)
+}
+
+func (b *BrokerProxy) ListReportSubscriptions(teamID string) ([]*api.ReportSubscription, error) {
+	return b.store.ListReportSubscriptions(teamID)
+}
+
+func (b *BrokerProxy) ListDueReportSubscriptions(now time.Time) ([]*api.ReportSubscription, error) {
+	return b.store.ListDueReportSubscriptions(now)
+}
+
+func (b *BrokerProxy) FindReportSubscription(teamID, subscriptionID string) (*api.ReportSubscription, error) {
+	return b.store.FindReportSubscription(teamID, subscriptionID)
+}
+
+func (b *BrokerProxy) UpdateReportSubscription(subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	return b.store.UpdateReportSubscription(subscription)
+}
+
+func (b *BrokerProxy) DeleteReportSubscription(subscription api.ReportSubscription) error {
+	return b.store.DeleteReportSubscription(subscription)
+}
+
+func (b *BrokerProxy) ClaimReportSubscription(subscription api.ReportSubscription, nextRunAt, lastRunAt time.Time) (bool, error) {
+	return b.store.ClaimReportSubscription(subscription, nextRunAt, lastRunAt)
+}
+
+func (b *BrokerProxy) UpsertStatsSnapshots(snapshots []api.StatsSnapshot) error {
+	return b.store.UpsertStatsSnapshots(snapshots)
+}
+
+func (b *BrokerProxy) ListStatsSnapshots(teamID, groupID string, from, to time.Time) ([]*api.StatsSnapshot, error) {
+	return b.store.ListStatsSnapshots(teamID, groupID, from, to)
+}
+
+func (b *BrokerProxy) ClaimStatsSnapshots(teamID string, date, staleBefore time.Time) (bool, error) {
+	return b.store.ClaimStatsSnapshots(teamID, date, staleBefore)
+}
+
+func (b *BrokerProxy) ReleaseStatsSnapshots(teamID string, date time.Time, done bool) error {
+	return b.store.ReleaseStatsSnapshots(teamID, date, done)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) FindChecktypesSnapshot(name string) (*api.ChecktypesSnapshot, error) {
+	snapshot := &api.ChecktypesSnapshot{}
+	result := db.Conn.Where("name = ?", name).First(snapshot)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, errors.NotFound(result.Error)
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return snapshot, nil
+}
+
+// UpsertChecktypesSnapshot creates or replaces the snapshot with the name of
+// the given one.
+func (db vulcanitoStore) UpsertChecktypesSnapshot(snapshot api.ChecktypesSnapshot) error {
+	result := db.Conn.Exec(`INSERT INTO checktypes_snapshots (name, content, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at`,
+		snapshot.Name, snapshot.Content, snapshot.UpdatedAt)
+	if result.Error != nil {
+		return db.logError(errors.Database(result.Error))
+	}
+	return nil
+}
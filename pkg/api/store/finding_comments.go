@@ -0,0 +1,139 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// findingCommentsQuery preloads the author, the mentioned users and the edit
+// history of the comments.
+func (db vulcanitoStore) findingCommentsQuery() *gorm.DB {
+	return db.Conn.
+		Preload("Author").
+		Preload("Mentions.User").
+		Preload("Edits", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Edits.Editor")
+}
+
+// ListFindingComments returns the comments of a finding written by a team,
+// from the oldest to the newest.
+func (db vulcanitoStore) ListFindingComments(teamID, findingID string) ([]*api.FindingComment, error) {
+	comments := []*api.FindingComment{}
+	result := db.findingCommentsQuery().
+		Where("team_id = ? AND finding_id = ?", teamID, findingID).
+		Order("created_at").
+		Find(&comments)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return comments, nil
+}
+
+func (db vulcanitoStore) FindFindingComment(teamID, findingID, commentID string) (*api.FindingComment, error) {
+	comment := &api.FindingComment{}
+	result := db.findingCommentsQuery().
+		Where("team_id = ? AND finding_id = ? AND id = ?", teamID, findingID, commentID).
+		First(comment)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return comment, nil
+}
+
+func (db vulcanitoStore) CreateFindingComment(comment api.FindingComment) (*api.FindingComment, error) {
+	mentions := comment.Mentions
+	comment.Mentions = nil
+	comment.Edits = nil
+
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	if err := tx.Create(&comment).Error; err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Create(err))
+	}
+	if err := createFindingCommentMentions(tx, comment.ID, mentions); err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Create(err))
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+	return db.FindFindingComment(comment.TeamID, comment.FindingID, comment.ID)
+}
+
+// UpdateFindingComment stores the body of a comment, replacing its mentions.
+// If an edit is given, it is added to the history of the comment in the same
+// transaction.
+func (db vulcanitoStore) UpdateFindingComment(comment api.FindingComment, edit *api.FindingCommentEdit) (*api.FindingComment, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	result := tx.Model(&api.FindingComment{}).
+		Where("team_id = ? AND id = ?", comment.TeamID, comment.ID).
+		Update("body", comment.Body)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, db.logError(errors.NotFound("finding comment not found"))
+	}
+	if edit != nil {
+		edit.CommentID = comment.ID
+		if err := tx.Create(edit).Error; err != nil {
+			tx.Rollback()
+			return nil, db.logError(errors.Update(err))
+		}
+	}
+	result = tx.Delete(&api.FindingCommentMention{}, "comment_id = ?", comment.ID)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	if err := createFindingCommentMentions(tx, comment.ID, comment.Mentions); err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Update(err))
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+	return db.FindFindingComment(comment.TeamID, comment.FindingID, comment.ID)
+}
+
+func (db vulcanitoStore) DeleteFindingComment(comment api.FindingComment) error {
+	result := db.Conn.
+		Where("team_id = ? AND finding_id = ?", comment.TeamID, comment.FindingID).
+		Delete(&comment)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("finding comment not found"))
+	}
+	return nil
+}
+
+func createFindingCommentMentions(tx *gorm.DB, commentID string, mentions []*api.FindingCommentMention) error {
+	for _, m := range mentions {
+		mention := api.FindingCommentMention{CommentID: commentID, UserID: m.UserID}
+		if err := tx.Create(&mention).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
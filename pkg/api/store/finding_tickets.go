@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// UpsertFindingTicket stores the ticket of a finding of a team, replacing the
+// previous ticket of the finding if it exists.
+func (db vulcanitoStore) UpsertFindingTicket(ticket api.FindingTicket) (*api.FindingTicket, error) {
+	now := time.Now()
+	result := db.Conn.Exec(`INSERT INTO finding_tickets (team_id, finding_id, ticket_id, ticket_key, url_tracker, status, resolution, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (team_id, finding_id) DO UPDATE SET ticket_id = EXCLUDED.ticket_id, ticket_key = EXCLUDED.ticket_key,
+		url_tracker = EXCLUDED.url_tracker, status = EXCLUDED.status, resolution = EXCLUDED.resolution,
+		created_by = EXCLUDED.created_by, closed_at = NULL, synced_at = NULL, updated_at = EXCLUDED.updated_at`,
+		ticket.TeamID, ticket.FindingID, ticket.TicketID, ticket.TicketKey, ticket.URLTracker, ticket.Status,
+		ticket.Resolution, ticket.CreatedBy, now, now)
+	if result.Error != nil {
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindFindingTicket(ticket.TeamID, ticket.FindingID)
+}
+
+func (db vulcanitoStore) FindFindingTicket(teamID, findingID string) (*api.FindingTicket, error) {
+	ticket := &api.FindingTicket{}
+	result := db.Conn.
+		Where("team_id = ? AND finding_id = ?", teamID, findingID).
+		First(ticket)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return ticket, nil
+}
+
+// ListFindingTickets returns the tickets of the findings of a team, from the
+// newest to the oldest. If open is true, only the tickets that are not closed
+// are returned.
+func (db vulcanitoStore) ListFindingTickets(teamID string, open bool) ([]*api.FindingTicket, error) {
+	tickets := []*api.FindingTicket{}
+	query := db.Conn.Where("team_id = ?", teamID)
+	if open {
+		query = query.Where("closed_at IS NULL")
+	}
+	result := query.Order("created_at desc").Find(&tickets)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return tickets, nil
+}
+
+// UpdateFindingTicket stores the fields of a ticket that are synchronized
+// with the ticket tracker.
+func (db vulcanitoStore) UpdateFindingTicket(ticket api.FindingTicket) (*api.FindingTicket, error) {
+	result := db.Conn.Model(&api.FindingTicket{}).
+		Where("team_id = ? AND id = ?", ticket.TeamID, ticket.ID).
+		Updates(map[string]interface{}{
+			"status":     ticket.Status,
+			"resolution": ticket.Resolution,
+			"closed_at":  ticket.ClosedAt,
+			"synced_at":  ticket.SyncedAt,
+		})
+	if result.Error != nil {
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, db.logError(errors.NotFound("finding ticket not found"))
+	}
+	return db.FindFindingTicket(ticket.TeamID, ticket.FindingID)
+}
+
+// UpdateTeamTracker enables or disables the integration of a team with the
+// ticket tracker.
+func (db vulcanitoStore) UpdateTeamTracker(teamID string, enabled bool) (*api.Team, error) {
+	result := db.Conn.Model(&api.Team{}).
+		Where("id = ?", teamID).
+		Update("using_tracker", enabled)
+	if result.Error != nil {
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, db.logError(errors.NotFound("team not found"))
+	}
+	return db.FindTeam(teamID)
+}
+
+// CreateIssueTicketsAsync stores the information required to create the
+// tickets of the findings of an issue in the Outbox. It also creates a Job to
+// be returned to the user to track the progress of the async operation.
+func (db vulcanitoStore) CreateIssueTicketsAsync(bulk api.BulkFindingTicketCreate) (*api.Job, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+
+	job, err := db.createJobTx(
+		tx,
+		api.Job{
+			TeamID:    bulk.TeamID,
+			Operation: opCreateIssueTickets,
+			Status:    api.JobStatusPending,
+		})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := db.pushToOutbox(tx, opCreateIssueTickets, bulk, job.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if tx.Commit().Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+
+	return job, nil
+}
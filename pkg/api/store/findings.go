@@ -7,6 +7,7 @@ package store
 import (
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/jinzhu/gorm"
 )
 
 func (db vulcanitoStore) CreateFindingOverwrite(findingOverwrite api.FindingOverwrite) error {
@@ -16,14 +17,7 @@ func (db vulcanitoStore) CreateFindingOverwrite(findingOverwrite api.FindingOver
 		return db.logError(errors.Database(tx.Error))
 	}
 
-	// create entry in finding_overwrite
-	result := tx.Create(&findingOverwrite)
-	if result.Error != nil {
-		tx.Rollback()
-		return db.logError(errors.Delete(result.Error))
-	}
-
-	err := db.pushToOutbox(tx, opFindingOverwrite, findingOverwrite)
+	err := db.createFindingOverwriteTx(tx, findingOverwrite)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -37,6 +31,72 @@ func (db vulcanitoStore) CreateFindingOverwrite(findingOverwrite api.FindingOver
 
 }
 
+// CreateFindingOverwrites creates a set of finding overwrites in one
+// transaction, pushing all of them to the outbox.
+func (db vulcanitoStore) CreateFindingOverwrites(findingOverwrites []api.FindingOverwrite) error {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return db.logError(errors.Database(tx.Error))
+	}
+
+	for _, fo := range findingOverwrites {
+		if err := db.createFindingOverwriteTx(tx, fo); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if tx.Commit().Error != nil {
+		return db.logError(errors.Database(tx.Error))
+	}
+	return nil
+}
+
+// CreateFindingOverwritesAsync stores the information required to execute a
+// bulk finding overwrite in the Outbox. It also creates a Job to be returned
+// to the user to track the progress of the async operation.
+func (db vulcanitoStore) CreateFindingOverwritesAsync(bulk api.BulkFindingOverwrite) (*api.Job, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+
+	job, err := db.createJobTx(
+		tx,
+		api.Job{
+			TeamID:    bulk.TeamID,
+			Operation: opBulkFindingOverwrite,
+			Status:    api.JobStatusPending,
+		})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := db.pushToOutbox(tx, opBulkFindingOverwrite, bulk, job.ID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if tx.Commit().Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+
+	return job, nil
+}
+
+// createFindingOverwriteTx creates a finding overwrite in the given
+// transaction and pushes it to the outbox so the status of the finding is
+// updated in the vulnerability DB.
+func (db vulcanitoStore) createFindingOverwriteTx(tx *gorm.DB, findingOverwrite api.FindingOverwrite) error {
+	// create entry in finding_overwrite
+	result := tx.Create(&findingOverwrite)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	return db.pushToOutbox(tx, opFindingOverwrite, findingOverwrite)
+}
+
 func (db vulcanitoStore) ListFindingOverwrites(findingID string) ([]*api.FindingOverwrite, error) {
 	findingOverwrites := []*api.FindingOverwrite{}
 	result := db.Conn.Preload("User").Find(&findingOverwrites, "finding_id = ?", findingID)
@@ -49,3 +109,38 @@ func (db vulcanitoStore) ListFindingOverwrites(findingID string) ([]*api.Finding
 
 	return findingOverwrites, nil
 }
+
+// ListJobFindingOverwrites returns the finding overwrites created by the job
+// of a bulk finding overwrite.
+func (db vulcanitoStore) ListJobFindingOverwrites(jobID string) ([]*api.FindingOverwrite, error) {
+	findingOverwrites := []*api.FindingOverwrite{}
+	result := db.Conn.Find(&findingOverwrites, "job_id = ?", jobID)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return findingOverwrites, nil
+}
+
+func (db vulcanitoStore) CreateFindingVerification(verification api.FindingVerification) (*api.FindingVerification, error) {
+	result := db.Conn.Create(&verification)
+	if result.Error != nil {
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return &verification, nil
+}
+
+func (db vulcanitoStore) ListFindingVerifications(teamID, findingID string) ([]*api.FindingVerification, error) {
+	verifications := []*api.FindingVerification{}
+	result := db.Conn.Preload("User").
+		Where("team_id = ? AND finding_id = ?", teamID, findingID).
+		Order("created_at desc").
+		Find(&verifications)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+
+	return verifications, nil
+}
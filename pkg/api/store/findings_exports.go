@@ -0,0 +1,139 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// CreateFindingsExportAsync stores a pending findings export, to be claimed
+// and generated by any of the instances of the API. It also creates a Job to
+// be returned to the user to track the progress of the async operation.
+func (db vulcanitoStore) CreateFindingsExportAsync(export api.FindingsExport) (*api.Job, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+
+	job, err := db.createJobTx(
+		tx,
+		api.Job{
+			TeamID:    export.TeamID,
+			Operation: opExportFindings,
+			Status:    api.JobStatusPending,
+		})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	export.ID = ""
+	export.JobID = &job.ID
+	export.Status = api.FindingsExportPending
+	if err := tx.Create(&export).Error; err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Create(err))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+
+	return job, nil
+}
+
+// ClaimFindingsExport marks as running the oldest pending export, or the
+// oldest running export claimed before staleBefore, and returns it. The
+// exports claimed by an instance are skipped by the others. It returns nil
+// if there are no exports to generate.
+func (db vulcanitoStore) ClaimFindingsExport(staleBefore time.Time) (*api.FindingsExport, error) {
+	export := &api.FindingsExport{}
+	res := db.Conn.Raw(`
+		UPDATE findings_exports SET status = ?, claimed_at = NOW()
+		WHERE id = (
+			SELECT id FROM findings_exports
+			WHERE status = ? OR (status = ? AND claimed_at < ?)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		api.FindingsExportRunning, api.FindingsExportPending, api.FindingsExportRunning, staleBefore,
+	).Scan(export)
+	if res.Error != nil {
+		if db.NotFoundError(res.Error) {
+			return nil, nil
+		}
+		return nil, db.logError(errors.Database(res.Error))
+	}
+	return export, nil
+}
+
+// UpdateFindingsExport stores the status and the size of an export.
+func (db vulcanitoStore) UpdateFindingsExport(export api.FindingsExport) (*api.FindingsExport, error) {
+	res := db.Conn.Model(&api.FindingsExport{}).
+		Where("id = ?", export.ID).
+		Updates(map[string]interface{}{
+			"status":   export.Status,
+			"findings": export.Findings,
+			"size":     export.Size,
+		})
+	if res.Error != nil {
+		return nil, db.logError(errors.Update(res.Error))
+	}
+	return db.FindFindingsExport(export.TeamID, export.ID)
+}
+
+func (db vulcanitoStore) FindFindingsExport(teamID, exportID string) (*api.FindingsExport, error) {
+	export := &api.FindingsExport{}
+	result := db.Conn.Where("team_id = ? AND id = ?", teamID, exportID).First(export)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return export, nil
+}
+
+// DeleteFindingsExports deletes the exports, and their content, created
+// before the given time, and returns the number of exports deleted.
+func (db vulcanitoStore) DeleteFindingsExports(createdBefore time.Time) (int, error) {
+	res := db.Conn.Where("created_at < ?", createdBefore).Delete(&api.FindingsExport{})
+	if res.Error != nil {
+		return 0, db.logError(errors.Delete(res.Error))
+	}
+	return int(res.RowsAffected), nil
+}
+
+func (db vulcanitoStore) CreateFindingsExportPart(part api.FindingsExportPart) error {
+	if err := db.Conn.Create(&part).Error; err != nil {
+		return db.logError(errors.Create(err))
+	}
+	return nil
+}
+
+func (db vulcanitoStore) FindFindingsExportPart(exportID string, part int) (*api.FindingsExportPart, error) {
+	p := &api.FindingsExportPart{}
+	result := db.Conn.Where("export_id = ? AND part = ?", exportID, part).First(p)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, errors.NotFound(result.Error)
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return p, nil
+}
+
+func (db vulcanitoStore) DeleteFindingsExportParts(exportID string) error {
+	res := db.Conn.Where("export_id = ?", exportID).Delete(&api.FindingsExportPart{})
+	if res.Error != nil {
+		return db.logError(errors.Delete(res.Error))
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/adevinta/errors"
+	"github.com/robfig/cron"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// Config defines the global entities declared in the configuration of the
+// API.
+type Config struct {
+	Definitions `mapstructure:",squash"`
+	// ReloadInterval is the number of seconds between two reloads of the
+	// global entities defined in the database. A value of 0 means the
+	// entities are only reloaded when the config file is reloaded.
+	ReloadInterval int `mapstructure:"reload_interval"`
+	// WatchConfig makes the global entities and the global policy config be
+	// reloaded every time the config file changes.
+	WatchConfig bool `mapstructure:"watch_config"`
+}
+
+// Definitions contains the global groups, policies and programs that are not
+// built into the API but declared in the config or the database, and the
+// global policy config.
+type Definitions struct {
+	Groups   []GroupDefinition   `mapstructure:"groups"`
+	Policies []PolicyDefinition  `mapstructure:"policies"`
+	Programs []ProgramDefinition `mapstructure:"programs"`
+	// PolicyConfig is read from its own section of the config.
+	PolicyConfig GlobalPolicyConfig `mapstructure:"-"`
+}
+
+// merge returns the definitions in d plus the ones in other. The definitions
+// in other replace the ones in d with the same name.
+func (d Definitions) merge(other Definitions) Definitions {
+	merged := Definitions{}
+	groups := map[string]bool{}
+	for _, g := range other.Groups {
+		groups[g.Name] = true
+	}
+	for _, g := range d.Groups {
+		if !groups[g.Name] {
+			merged.Groups = append(merged.Groups, g)
+		}
+	}
+	merged.Groups = append(merged.Groups, other.Groups...)
+
+	policies := map[string]bool{}
+	for _, p := range other.Policies {
+		policies[p.Name] = true
+	}
+	for _, p := range d.Policies {
+		if !policies[p.Name] {
+			merged.Policies = append(merged.Policies, p)
+		}
+	}
+	merged.Policies = append(merged.Policies, other.Policies...)
+
+	programs := map[string]bool{}
+	for _, p := range other.Programs {
+		programs[p.ID] = true
+	}
+	for _, p := range d.Programs {
+		if !programs[p.ID] {
+			merged.Programs = append(merged.Programs, p)
+		}
+	}
+	merged.Programs = append(merged.Programs, other.Programs...)
+
+	if len(d.PolicyConfig) > 0 || len(other.PolicyConfig) > 0 {
+		merged.PolicyConfig = GlobalPolicyConfig{}
+		for name, entry := range d.PolicyConfig {
+			merged.PolicyConfig[name] = entry
+		}
+		for name, entry := range other.PolicyConfig {
+			merged.PolicyConfig[name] = entry
+		}
+	}
+	return merged
+}
+
+// decodeDefinitions returns the definitions stored in the database.
+func decodeDefinitions(stored []*api.GlobalEntityDefinition) (Definitions, error) {
+	defs := Definitions{}
+	for _, s := range stored {
+		var err error
+		switch s.Kind {
+		case api.GlobalEntityGroup:
+			g := GroupDefinition{}
+			err = json.Unmarshal([]byte(s.Definition), &g)
+			g.Name = s.Name
+			defs.Groups = append(defs.Groups, g)
+		case api.GlobalEntityPolicy:
+			p := PolicyDefinition{}
+			err = json.Unmarshal([]byte(s.Definition), &p)
+			p.Name = s.Name
+			defs.Policies = append(defs.Policies, p)
+		case api.GlobalEntityProgram:
+			p := ProgramDefinition{}
+			err = json.Unmarshal([]byte(s.Definition), &p)
+			p.ID = s.Name
+			defs.Programs = append(defs.Programs, p)
+		case api.GlobalEntityPolicyConfig:
+			c := GlobalPolicyConfigEntry{}
+			err = json.Unmarshal([]byte(s.Definition), &c)
+			if defs.PolicyConfig == nil {
+				defs.PolicyConfig = GlobalPolicyConfig{}
+			}
+			defs.PolicyConfig[s.Name] = c
+		default:
+			err = fmt.Errorf("unknown kind %q", s.Kind)
+		}
+		if err != nil {
+			return Definitions{}, fmt.Errorf("invalid global %s definition %q: %w", s.Kind, s.Name, err)
+		}
+	}
+	return defs, nil
+}
+
+// GroupDefinition defines a global group whose assets are the assets of the
+// team matching a selector expression. See api.AssetSelector for the syntax
+// of the expressions.
+type GroupDefinition struct {
+	Name        string `mapstructure:"name" json:"name"`
+	Description string `mapstructure:"description" json:"description"`
+	Options     string `mapstructure:"options" json:"options"`
+	Selector    string `mapstructure:"selector" json:"selector"`
+}
+
+func (d GroupDefinition) build(store api.VulcanitoStore) (Group, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("global group without name")
+	}
+	selector, err := api.ParseAssetSelector(d.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("global group %q: %w", d.Name, err)
+	}
+	if d.Options != "" && !json.Valid([]byte(d.Options)) {
+		return nil, fmt.Errorf("global group %q: options are not valid JSON", d.Name)
+	}
+	return &selectorGroup{
+		globalGroup: &globalGroup{Store: store},
+		def:         d,
+		selector:    selector,
+	}, nil
+}
+
+// selectorGroup is a global group defined by a GroupDefinition.
+type selectorGroup struct {
+	*globalGroup
+	def      GroupDefinition
+	selector *api.AssetSelector
+}
+
+// Name returns the name of the group.
+func (g *selectorGroup) Name() string {
+	return g.def.Name
+}
+
+// Description returns the description of the group.
+func (g *selectorGroup) Description() string {
+	return g.def.Description
+}
+
+// Options returns the options of the group.
+func (g *selectorGroup) Options() string {
+	return g.def.Options
+}
+
+// Eval returns the assets of a team matching the selector of the group.
+func (g *selectorGroup) Eval(teamID string) ([]*api.Asset, error) {
+	assets, err := g.Store.ListAssets(teamID, api.Asset{})
+	if err != nil {
+		if errors.IsRootOfKind(err, errors.ErrNotFound) {
+			return []*api.Asset{}, nil
+		}
+		return nil, err
+	}
+	selected := []*api.Asset{}
+	for _, a := range assets {
+		if g.selector.Matches(a) {
+			selected = append(selected, a)
+		}
+	}
+	return selected, nil
+}
+
+// PolicyDefinition defines a global policy containing the checktypes that
+// pass the given filters. As with the built-in policies, the filters can be
+// replaced using the global policy config.
+type PolicyDefinition struct {
+	Name                    string `mapstructure:"name" json:"name"`
+	Description             string `mapstructure:"description" json:"description"`
+	GlobalPolicyConfigEntry `mapstructure:",squash"`
+}
+
+func (d PolicyDefinition) build(informer ChecktypesInformer) (Policy, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("global policy without name")
+	}
+	return &definedPolicy{def: d, checktypeInformer: informer}, nil
+}
+
+// definedPolicy is a global policy defined by a PolicyDefinition.
+type definedPolicy struct {
+	def               PolicyDefinition
+	checktypeInformer ChecktypesInformer
+}
+
+// Name returns the name of the policy.
+func (p *definedPolicy) Name() string {
+	return p.def.Name
+}
+
+// Description returns the description of the policy.
+func (p *definedPolicy) Description() string {
+	return p.def.Description
+}
+
+func (p *definedPolicy) Init(informer ChecktypesInformer) error {
+	p.checktypeInformer = informer
+	return nil
+}
+
+func (p *definedPolicy) Eval(ctx context.Context, gpc GlobalPolicyConfig) ([]*api.ChecktypeSetting, error) {
+	checkTypesInfo, err := p.checktypeInformer.ByAssettype(ctx)
+	if err != nil {
+		return nil, errors.Default(err)
+	}
+	config, ok := gpc[p.Name()]
+	if !ok {
+		config = p.def.GlobalPolicyConfigEntry
+	}
+	return evalWithConfig(ctx, config, checkTypesInfo)
+}
+
+// ProgramDefinition defines a global program.
+type ProgramDefinition struct {
+	ID       string        `mapstructure:"id" json:"id"`
+	Name     string        `mapstructure:"name" json:"name"`
+	Policies []PolicyGroup `mapstructure:"policies" json:"policies"`
+	// Cron is the default schedule of the program for all the teams. Empty
+	// means the program is not scheduled.
+	Cron     string `mapstructure:"cron" json:"cron"`
+	Autosend bool   `mapstructure:"autosend" json:"autosend"`
+	Disabled bool   `mapstructure:"disabled" json:"disabled"`
+}
+
+func (d ProgramDefinition) build(groups map[string]Group, policies map[string]Policy) (Program, error) {
+	if d.ID == "" {
+		return Program{}, fmt.Errorf("global program without id")
+	}
+	if len(d.Policies) == 0 {
+		return Program{}, fmt.Errorf("global program %q has no policies", d.ID)
+	}
+	for _, pg := range d.Policies {
+		if _, ok := groups[pg.Group]; !ok {
+			return Program{}, fmt.Errorf("global program %q: unknown global group %q", d.ID, pg.Group)
+		}
+		if _, ok := policies[pg.Policy]; !ok {
+			return Program{}, fmt.Errorf("global program %q: unknown global policy %q", d.ID, pg.Policy)
+		}
+	}
+	if d.Cron != "" {
+		if _, err := cron.ParseStandard(d.Cron); err != nil {
+			return Program{}, fmt.Errorf("global program %q: invalid cron: %w", d.ID, err)
+		}
+	}
+	name := d.Name
+	if name == "" {
+		name = d.ID
+	}
+	autosend, disabled := d.Autosend, d.Disabled
+	return Program{
+		ID:       d.ID,
+		Name:     name,
+		Policies: d.Policies,
+		DefaultMetadata: api.GlobalProgramsMetadata{
+			Cron:     d.Cron,
+			Autosend: &autosend,
+			Disabled: &disabled,
+		},
+	}, nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemoryStore struct {
+	api.VulcanitoStore
+	definitions []*api.GlobalEntityDefinition
+	assets      []*api.Asset
+	evaluations []*api.GlobalPolicyEvaluation
+}
+
+func (s *inMemoryStore) ListGlobalPolicyEvaluations() ([]*api.GlobalPolicyEvaluation, error) {
+	return s.evaluations, nil
+}
+
+func (s *inMemoryStore) ReplaceGlobalPolicyEvaluations(evaluations []*api.GlobalPolicyEvaluation) error {
+	s.evaluations = evaluations
+	return nil
+}
+
+func (s *inMemoryStore) ListGlobalEntityDefinitions() ([]*api.GlobalEntityDefinition, error) {
+	return s.definitions, nil
+}
+
+func (s *inMemoryStore) ListAssets(teamID string, asset api.Asset) ([]*api.Asset, error) {
+	return s.assets, nil
+}
+
+var webDefinitions = Definitions{
+	Groups: []GroupDefinition{
+		{Name: "prod-web-global", Selector: `type in (Hostname, WebAddress) and annotations["env"] == "prod"`},
+	},
+	Policies: []PolicyDefinition{
+		{Name: "web-global", GlobalPolicyConfigEntry: GlobalPolicyConfigEntry{AllowedChecks: []string{"vulcan-zap"}}},
+	},
+	Programs: []ProgramDefinition{
+		{
+			ID:       "prod-web-scan",
+			Cron:     "0 4 * * 1",
+			Policies: []PolicyGroup{{Group: "prod-web-global", Policy: "web-global"}},
+		},
+	},
+}
+
+func TestEntities_Reload(t *testing.T) {
+	tests := []struct {
+		name         string
+		defs         Definitions
+		stored       []*api.GlobalEntityDefinition
+		wantErr      bool
+		wantPrograms []string
+		wantReloaded bool
+	}{
+		{
+			name:         "BuiltInOnly",
+			wantPrograms: []string{"cp-scan", "periodic-full-scan", "redcon-scan", "web-scanning"},
+		},
+		{
+			name:         "FromConfig",
+			defs:         webDefinitions,
+			wantPrograms: []string{"cp-scan", "periodic-full-scan", "prod-web-scan", "redcon-scan", "web-scanning"},
+			wantReloaded: true,
+		},
+		{
+			name: "FromDatabase",
+			stored: []*api.GlobalEntityDefinition{
+				{Kind: api.GlobalEntityProgram, Name: "db-scan", Definition: `{"policies":[{"group":"default-global","policy":"default-global"}]}`},
+			},
+			wantPrograms: []string{"cp-scan", "db-scan", "periodic-full-scan", "redcon-scan", "web-scanning"},
+			wantReloaded: true,
+		},
+		{
+			name: "DatabaseReplacesConfig",
+			defs: webDefinitions,
+			stored: []*api.GlobalEntityDefinition{
+				{Kind: api.GlobalEntityGroup, Name: "prod-web-global", Definition: `{"selector":"type == Hostname"}`},
+			},
+			wantPrograms: []string{"cp-scan", "periodic-full-scan", "prod-web-scan", "redcon-scan", "web-scanning"},
+			wantReloaded: true,
+		},
+		{
+			name: "InvalidSelector",
+			defs: Definitions{
+				Groups: []GroupDefinition{{Name: "bad-global", Selector: "type >= Hostname"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "BuiltInRedefined",
+			defs: Definitions{
+				Groups: []GroupDefinition{{Name: "default-global", Selector: "type == Hostname"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "UnknownPolicy",
+			defs: Definitions{
+				Programs: []ProgramDefinition{
+					{ID: "bad-scan", Policies: []PolicyGroup{{Group: "default-global", Policy: "unknown"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidCron",
+			defs: Definitions{
+				Programs: []ProgramDefinition{
+					{ID: "bad-scan", Cron: "every monday", Policies: []PolicyGroup{{Group: "default-global", Policy: "default-global"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidStoredDefinition",
+			stored: []*api.GlobalEntityDefinition{
+				{Kind: api.GlobalEntityPolicy, Name: "bad-global", Definition: `[]`},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inMemoryStore{}
+			e, err := NewEntities(store, &inMemoryChecktypesInformer{}, Definitions{})
+			if err != nil {
+				t.Fatalf("NewEntities() error = %v", err)
+			}
+			var reloaded bool
+			e.OnReload(func(changed, removed []string) { reloaded = true })
+			before := e.Programs()
+
+			store.definitions = tt.stored
+			err = e.Reload(tt.defs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Entities.Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if reloaded {
+					t.Errorf("listeners called after a failed reload")
+				}
+				if diff := cmp.Diff(keys(before), keys(e.Programs())); diff != "" {
+					t.Errorf("programs changed after a failed reload (-want +got):\n%v", diff)
+				}
+				return
+			}
+			if reloaded != tt.wantReloaded {
+				t.Errorf("got listeners called %v, want %v", reloaded, tt.wantReloaded)
+			}
+			if diff := cmp.Diff(tt.wantPrograms, keys(e.Programs())); diff != "" {
+				t.Errorf("programs mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestEntities_ReloadChangedPrograms(t *testing.T) {
+	e, err := NewEntities(&inMemoryStore{}, &inMemoryChecktypesInformer{}, webDefinitions)
+	if err != nil {
+		t.Fatalf("NewEntities() error = %v", err)
+	}
+	var calls int
+	var gotChanged, gotRemoved []string
+	e.OnReload(func(changed, removed []string) {
+		calls++
+		gotChanged, gotRemoved = changed, removed
+	})
+
+	// Reloading the same definitions does not call the listeners.
+	if err := e.Reload(webDefinitions); err != nil {
+		t.Fatalf("Entities.Reload() error = %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("got %d calls to the listeners without changes, want 0", calls)
+	}
+
+	if err := e.Reload(Definitions{}); err != nil {
+		t.Fatalf("Entities.Reload() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls to the listeners, want 1", calls)
+	}
+	if len(gotChanged) != 0 {
+		t.Errorf("got changed programs %v, want none", gotChanged)
+	}
+	if diff := cmp.Diff([]string{"prod-web-scan"}, gotRemoved); diff != "" {
+		t.Errorf("removed programs mismatch (-want +got):\n%v", diff)
+	}
+
+	changed := webDefinitions
+	changed.Programs = append([]ProgramDefinition(nil), webDefinitions.Programs...)
+	changed.Programs[0].Cron = "0 3 * * *"
+	if err := e.Reload(changed); err != nil {
+		t.Fatalf("Entities.Reload() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"prod-web-scan"}, gotChanged); diff != "" {
+		t.Errorf("changed programs mismatch (-want +got):\n%v", diff)
+	}
+	if len(gotRemoved) != 0 {
+		t.Errorf("got removed programs %v, want none", gotRemoved)
+	}
+}
+
+func TestSelectorGroup_Eval(t *testing.T) {
+	hostname := &api.AssetType{Name: "Hostname"}
+	prod := []*api.AssetAnnotation{{Key: "env", Value: "prod"}}
+	store := &inMemoryStore{
+		assets: []*api.Asset{
+			{ID: "a1", AssetType: hostname, AssetAnnotations: prod},
+			{ID: "a2", AssetType: hostname},
+			{ID: "a3", AssetType: &api.AssetType{Name: "IP"}, AssetAnnotations: prod},
+		},
+	}
+	e, err := NewEntities(store, &inMemoryChecktypesInformer{}, webDefinitions)
+	if err != nil {
+		t.Fatalf("NewEntities() error = %v", err)
+	}
+	assets, err := e.Groups()["prod-web-global"].Eval("team1")
+	if err != nil {
+		t.Fatalf("Group.Eval() error = %v", err)
+	}
+	ids := []string{}
+	for _, a := range assets {
+		ids = append(ids, a.ID)
+	}
+	if diff := cmp.Diff([]string{"a1"}, ids); diff != "" {
+		t.Errorf("assets mismatch (-want +got):\n%v", diff)
+	}
+}
+
+func keys(programs map[string]Program) []string {
+	ids := []string{}
+	for id := range programs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// PolicyEvaluations returns the last stored evaluation of each global policy
+// sorted by policy name.
+func (c *Entities) PolicyEvaluations() ([]*api.GlobalPolicyEvaluation, error) {
+	return c.store.ListGlobalPolicyEvaluations()
+}
+
+// EvaluatePolicies evaluates all the global policies with the current global
+// policy config and checktypes, and stores and returns the resulting
+// checktypes of each policy along with the changes from the previous
+// different evaluation. The evaluations are stored so they are shared by all
+// the instances of the API, and returned sorted by policy name.
+func (c *Entities) EvaluatePolicies(ctx context.Context) ([]*api.GlobalPolicyEvaluation, error) {
+	policies := c.Policies()
+	gpc := c.PolicyConfig()
+	names := []string{}
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.evalMu.Lock()
+	defer c.evalMu.Unlock()
+	stored, err := c.store.ListGlobalPolicyEvaluations()
+	if err != nil {
+		return nil, err
+	}
+	evaluations := map[string]*api.GlobalPolicyEvaluation{}
+	for _, e := range stored {
+		evaluations[e.Policy] = e
+	}
+	now := time.Now()
+	res := []*api.GlobalPolicyEvaluation{}
+	for _, name := range names {
+		last := evaluations[name]
+		settings, err := policies[name].Eval(ctx, gpc)
+		if err != nil {
+			// Keep the last known checktypes of the policy.
+			eval := &api.GlobalPolicyEvaluation{Policy: name, EvaluatedAt: now, Error: err.Error()}
+			if last != nil {
+				eval.Checktypes, eval.Added, eval.Removed, eval.ChangedAt = last.Checktypes, last.Added, last.Removed, last.ChangedAt
+			}
+			res = append(res, eval)
+			continue
+		}
+		checktypes := []string{}
+		for _, s := range settings {
+			checktypes = append(checktypes, s.CheckTypeName)
+		}
+		sort.Strings(checktypes)
+
+		eval := &api.GlobalPolicyEvaluation{
+			Policy:      name,
+			Checktypes:  checktypes,
+			Added:       []string{},
+			Removed:     []string{},
+			EvaluatedAt: now,
+		}
+		switch {
+		case last == nil:
+			// This is the first evaluation so there is nothing to compare with.
+		case equalStrings(last.Checktypes, checktypes):
+			eval.Added, eval.Removed, eval.ChangedAt = last.Added, last.Removed, last.ChangedAt
+		default:
+			eval.Added = difference(checktypes, last.Checktypes)
+			eval.Removed = difference(last.Checktypes, checktypes)
+			changedAt := now
+			eval.ChangedAt = &changedAt
+		}
+		res = append(res, eval)
+	}
+	// The evaluations of the policies that do not exist anymore are removed.
+	if err := c.store.ReplaceGlobalPolicyEvaluations(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// difference returns the elements of a that are not in b.
+func difference(a, b []string) []string {
+	in := map[string]bool{}
+	for _, s := range b {
+		in[s] = true
+	}
+	res := []string{}
+	for _, s := range a {
+		if !in[s] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
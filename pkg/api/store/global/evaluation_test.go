@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package global
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func findEvaluation(evals []*api.GlobalPolicyEvaluation, policy string) *api.GlobalPolicyEvaluation {
+	for _, e := range evals {
+		if e.Policy == policy {
+			return e
+		}
+	}
+	return nil
+}
+
+func evaluatePolicy(t *testing.T, e *Entities, policy string) *api.GlobalPolicyEvaluation {
+	t.Helper()
+	evals, err := e.EvaluatePolicies(context.Background())
+	if err != nil {
+		t.Fatalf("Entities.EvaluatePolicies() error = %v", err)
+	}
+	return findEvaluation(evals, policy)
+}
+
+func TestEntities_EvaluatePolicies(t *testing.T) {
+	informer := &inMemoryChecktypesInformer{
+		checktypes: map[string][]string{
+			"Hostname":   {"vulcan-tls", "vulcan-nessus"},
+			"WebAddress": {"vulcan-zap", "vulcan-burp"},
+		},
+	}
+	defs := webDefinitions
+	defs.PolicyConfig = GlobalPolicyConfig{
+		"web-global": {AllowedChecks: []string{"vulcan-zap", "vulcan-tls"}},
+	}
+	store := &inMemoryStore{}
+	e, err := NewEntities(store, informer, defs)
+	if err != nil {
+		t.Fatalf("NewEntities() error = %v", err)
+	}
+
+	got := evaluatePolicy(t, e, "web-global")
+	if got == nil {
+		t.Fatalf("no evaluation for policy web-global")
+	}
+	if diff := cmp.Diff([]string{"vulcan-tls", "vulcan-zap"}, got.Checktypes); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+	if len(got.Added) > 0 || len(got.Removed) > 0 || got.ChangedAt != nil {
+		t.Errorf("first evaluation has changes: %+v", got)
+	}
+
+	// The config stored in the database replaces the one in the config file.
+	store.definitions = []*api.GlobalEntityDefinition{
+		{Kind: api.GlobalEntityPolicyConfig, Name: "web-global", Definition: `{"allowed_checks":["vulcan-zap","vulcan-burp"]}`},
+	}
+	if err := e.Reload(defs); err != nil {
+		t.Fatalf("Entities.Reload() error = %v", err)
+	}
+	got = evaluatePolicy(t, e, "web-global")
+	want := &api.GlobalPolicyEvaluation{
+		Policy:     "web-global",
+		Checktypes: []string{"vulcan-burp", "vulcan-zap"},
+		Added:      []string{"vulcan-burp"},
+		Removed:    []string{"vulcan-tls"},
+	}
+	if got.ChangedAt == nil || !got.ChangedAt.Equal(got.EvaluatedAt) {
+		t.Errorf("changed at = %v, want %v", got.ChangedAt, got.EvaluatedAt)
+	}
+	ignoreTimes := cmp.FilterPath(func(p cmp.Path) bool {
+		name := p.Last().String()
+		return name == ".EvaluatedAt" || name == ".ChangedAt"
+	}, cmp.Ignore())
+	if diff := cmp.Diff(want, got, ignoreTimes); diff != "" {
+		t.Errorf("evaluation mismatch (-want +got):\n%v", diff)
+	}
+
+	// The changes are kept while the checktypes of the policy do not change.
+	changedAt := got.ChangedAt
+	got = evaluatePolicy(t, e, "web-global")
+	if diff := cmp.Diff(want, got, ignoreTimes); diff != "" {
+		t.Errorf("evaluation mismatch (-want +got):\n%v", diff)
+	}
+	if !got.ChangedAt.Equal(*changedAt) {
+		t.Errorf("changed at = %v, want %v", got.ChangedAt, changedAt)
+	}
+
+	// The evaluations are stored, so they are kept after a restart and
+	// reading them does not evaluate the policies again.
+	e, err = NewEntities(store, informer, defs)
+	if err != nil {
+		t.Fatalf("NewEntities() error = %v", err)
+	}
+	informer.checktypes["WebAddress"] = []string{"vulcan-zap"}
+	evals, err := e.PolicyEvaluations()
+	if err != nil {
+		t.Fatalf("Entities.PolicyEvaluations() error = %v", err)
+	}
+	if diff := cmp.Diff(want, findEvaluation(evals, "web-global"), ignoreTimes); diff != "" {
+		t.Errorf("stored evaluation mismatch (-want +got):\n%v", diff)
+	}
+	got = evaluatePolicy(t, e, "web-global")
+	want = &api.GlobalPolicyEvaluation{
+		Policy:     "web-global",
+		Checktypes: []string{"vulcan-zap"},
+		Added:      []string{},
+		Removed:    []string{"vulcan-burp"},
+	}
+	if diff := cmp.Diff(want, got, ignoreTimes); diff != "" {
+		t.Errorf("evaluation after restart mismatch (-want +got):\n%v", diff)
+	}
+}
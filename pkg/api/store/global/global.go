@@ -6,6 +6,10 @@ package global
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
 
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
@@ -45,61 +49,188 @@ type ChecktypesInformer interface {
 type Entities struct {
 	store    api.VulcanitoStore
 	informer ChecktypesInformer
-	groups   map[string]Group
-	policies map[string]Policy
-	programs map[string]Program
-	reports  map[string]Report
+
+	mu           sync.RWMutex
+	groups       map[string]Group
+	policies     map[string]Policy
+	programs     map[string]Program
+	reports      map[string]Report
+	policyConfig GlobalPolicyConfig
+	listeners    []func(changed, removed []string)
+
+	evalMu sync.Mutex
 }
 
 // NewEntities returns a struct that exposes the current defined global
-// entities.
-func NewEntities(store api.VulcanitoStore, informer ChecktypesInformer) (*Entities, error) {
-	// Initialize global entities with the required dependencies.
+// entities: the ones built into the API plus the ones in the given
+// definitions and in the database.
+func NewEntities(store api.VulcanitoStore, informer ChecktypesInformer, defs Definitions) (*Entities, error) {
 	globals := &Entities{
-		groups:   groups,
-		policies: policies,
-		programs: programs,
+		store:    store,
+		informer: informer,
 		reports:  reports,
 	}
-	// Inject required dependencies in each global entity.
-	for k, g := range globals.groups {
-		err := g.Init(store)
+	if err := globals.Reload(defs); err != nil {
+		return nil, errors.Default(err)
+	}
+	return globals, nil
+}
+
+// Reload builds again the global entities using the given definitions and the
+// ones stored in the database, that take precedence. The entities are only
+// replaced if all the definitions are valid, otherwise the current ones are
+// kept and an error is returned.
+func (c *Entities) Reload(defs Definitions) error {
+	if c.store != nil {
+		stored, err := c.store.ListGlobalEntityDefinitions()
 		if err != nil {
-			return nil, errors.Default(err)
+			return err
 		}
-		globals.groups[k] = g
+		storedDefs, err := decodeDefinitions(stored)
+		if err != nil {
+			return err
+		}
+		defs = defs.merge(storedDefs)
+	}
+	groups, policies, programs, err := c.build(defs)
+	if err != nil {
+		return err
 	}
 
-	for k, p := range globals.policies {
-		err := p.Init(informer)
+	c.mu.Lock()
+	changed, removed := diffPrograms(c.programs, programs)
+	c.groups = groups
+	c.policies = policies
+	c.programs = programs
+	c.policyConfig = defs.PolicyConfig
+	listeners := c.listeners
+	c.mu.Unlock()
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	for _, l := range listeners {
+		l(changed, removed)
+	}
+	return nil
+}
+
+// diffPrograms returns the IDs of the programs that are new or whose default
+// metadata changed, and the IDs of the ones that do not exist anymore, both
+// sorted.
+func diffPrograms(old, current map[string]Program) (changed, removed []string) {
+	for id, p := range current {
+		if o, ok := old[id]; !ok || !reflect.DeepEqual(o.DefaultMetadata, p.DefaultMetadata) {
+			changed = append(changed, id)
+		}
+	}
+	for id := range old {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed
+}
+
+// OnReload registers a function to be called when a reload of the global
+// entities changes the programs. The function receives the IDs of the
+// programs that are new or whose default metadata changed, and the IDs of
+// the ones that do not exist anymore.
+func (c *Entities) OnReload(f func(changed, removed []string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, f)
+}
+
+// build returns the built-in global entities plus the ones in the
+// definitions, after validating them.
+func (c *Entities) build(defs Definitions) (map[string]Group, map[string]Policy, map[string]Program, error) {
+	newGroups := map[string]Group{}
+	for k, g := range groups {
+		if err := g.Init(c.store); err != nil {
+			return nil, nil, nil, err
+		}
+		newGroups[k] = g
+	}
+	for _, d := range defs.Groups {
+		g, err := d.build(c.store)
 		if err != nil {
-			return nil, errors.Default(err)
+			return nil, nil, nil, err
+		}
+		if _, ok := newGroups[d.Name]; ok {
+			return nil, nil, nil, fmt.Errorf("global group %q is already defined", d.Name)
 		}
-		globals.policies[k] = p
+		newGroups[d.Name] = g
+	}
+
+	newPolicies := map[string]Policy{}
+	for k, p := range policies {
+		if err := p.Init(c.informer); err != nil {
+			return nil, nil, nil, err
+		}
+		newPolicies[k] = p
+	}
+	for _, d := range defs.Policies {
+		p, err := d.build(c.informer)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, ok := newPolicies[d.Name]; ok {
+			return nil, nil, nil, fmt.Errorf("global policy %q is already defined", d.Name)
+		}
+		newPolicies[d.Name] = p
 	}
-	// By now no need to initialize scans.
-	return globals, nil
 
+	newPrograms := map[string]Program{}
+	for k, p := range programs {
+		newPrograms[k] = p
+	}
+	for _, d := range defs.Programs {
+		p, err := d.build(newGroups, newPolicies)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, ok := newPrograms[d.ID]; ok {
+			return nil, nil, nil, fmt.Errorf("global program %q is already defined", d.ID)
+		}
+		newPrograms[d.ID] = p
+	}
+	return newGroups, newPolicies, newPrograms, nil
 }
 
 // Groups returns the current defined current groups.
 func (c *Entities) Groups() map[string]Group {
-	return groups
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.groups
 }
 
 // Policies returns current defined global policies.
 func (c *Entities) Policies() map[string]Policy {
-	return policies
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policies
 }
 
 // Programs returns current defined global programs.
 func (c *Entities) Programs() map[string]Program {
-	return programs
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.programs
+}
+
+// PolicyConfig returns the current global policy config.
+func (c *Entities) PolicyConfig() GlobalPolicyConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policyConfig
 }
 
 // Reports returns current defined global reports.
 func (c *Entities) Reports() map[string]Report {
-	return reports
+	return c.reports
 }
 
 // Group defines the methods all the global groups must implement.
@@ -133,9 +264,11 @@ type Program struct {
 	DefaultMetadata api.GlobalProgramsMetadata
 }
 
+// PolicyGroup defines the policy used to scan the assets of a group in a
+// global program.
 type PolicyGroup struct {
-	Group  string
-	Policy string
+	Group  string `mapstructure:"group" json:"group"`
+	Policy string `mapstructure:"policy" json:"policy"`
 }
 
 type ChecksByAssetType struct {
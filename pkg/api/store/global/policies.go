@@ -22,11 +22,11 @@ type GlobalPolicyConfig map[string]GlobalPolicyConfigEntry
 // Blocking takes precedence.
 // Empty allowed slices means ALL allowed.
 type GlobalPolicyConfigEntry struct {
-	AllowedChecks     []string `mapstructure:"allowed_checks"`
-	BlockedChecks     []string `mapstructure:"blocked_checks"`
-	AllowedAssettypes []string `mapstructure:"allowed_assettypes"`
-	BlockedAssettypes []string `mapstructure:"blocked_assettypes"`
-	ExcludingSuffixes []string `mapstructure:"excluding_suffixes"`
+	AllowedChecks     []string `mapstructure:"allowed_checks" json:"allowed_checks"`
+	BlockedChecks     []string `mapstructure:"blocked_checks" json:"blocked_checks"`
+	AllowedAssettypes []string `mapstructure:"allowed_assettypes" json:"allowed_assettypes"`
+	BlockedAssettypes []string `mapstructure:"blocked_assettypes" json:"blocked_assettypes"`
+	ExcludingSuffixes []string `mapstructure:"excluding_suffixes" json:"excluding_suffixes"`
 }
 
 func init() {
@@ -6,6 +6,8 @@ package global
 
 func init() {
 	registerReport(PeriodicDigestReport)
+	registerReport(CriticalFindingsReport)
+	registerReport(ExecutiveSummaryReport)
 }
 
 var (
@@ -16,4 +18,20 @@ var (
 		Name:            "Periodic Digest Report",
 		DefaultSchedule: "0 8 * * 3",
 	}
+
+	// CriticalFindingsReport specifies the data for a report with the high
+	// and critical findings of a team. It has no default schedule, it is only
+	// sent by the report subscriptions of the teams.
+	CriticalFindingsReport = Report{
+		ID:   "critical-findings-report",
+		Name: "Critical Findings Report",
+	}
+
+	// ExecutiveSummaryReport specifies the data for a report summarizing the
+	// evolution of the findings of a team. It has no default schedule, it is
+	// only sent by the report subscriptions of the teams.
+	ExecutiveSummaryReport = Report{
+		ID:   "executive-summary-report",
+		Name: "Executive Summary Report",
+	}
 )
@@ -5,6 +5,9 @@ Copyright 2021 Adevinta
 package store
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/adevinta/errors"
 	"github.com/adevinta/vulcan-api/pkg/api"
 )
@@ -85,3 +88,66 @@ func (db vulcanitoStore) UpdateGlobalProgramMetadata(metadata api.GlobalPrograms
 func (db vulcanitoStore) DeleteProgramMetadata(program string) error {
 	return db.Conn.Exec("delete from global_programs_metadata where program = ?", program).Error
 }
+
+// ListGlobalEntityDefinitions returns the definitions of the global entities
+// stored in the database.
+func (db vulcanitoStore) ListGlobalEntityDefinitions() ([]*api.GlobalEntityDefinition, error) {
+	definitions := []*api.GlobalEntityDefinition{}
+	result := db.Conn.Order("kind, name").Find(&definitions)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return definitions, nil
+}
+
+// ListGlobalPolicyEvaluations returns the last stored evaluation of each
+// global policy sorted by policy name.
+func (db vulcanitoStore) ListGlobalPolicyEvaluations() ([]*api.GlobalPolicyEvaluation, error) {
+	var rows []struct {
+		Evaluation string
+	}
+	result := db.Conn.Raw("SELECT evaluation FROM global_policy_evaluations ORDER BY policy").Scan(&rows)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	evaluations := []*api.GlobalPolicyEvaluation{}
+	for _, r := range rows {
+		e := &api.GlobalPolicyEvaluation{}
+		if err := json.Unmarshal([]byte(r.Evaluation), e); err != nil {
+			return nil, db.logError(errors.Database(err))
+		}
+		evaluations = append(evaluations, e)
+	}
+	return evaluations, nil
+}
+
+// ReplaceGlobalPolicyEvaluations replaces the stored evaluations of the
+// global policies with the given ones.
+func (db vulcanitoStore) ReplaceGlobalPolicyEvaluations(evaluations []*api.GlobalPolicyEvaluation) error {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return db.logError(errors.Database(tx.Error))
+	}
+	if err := tx.Exec("DELETE FROM global_policy_evaluations").Error; err != nil {
+		tx.Rollback()
+		return db.logError(errors.Database(err))
+	}
+	now := time.Now()
+	for _, e := range evaluations {
+		content, err := json.Marshal(e)
+		if err != nil {
+			tx.Rollback()
+			return db.logError(errors.Database(err))
+		}
+		err = tx.Exec("INSERT INTO global_policy_evaluations (policy, evaluation, updated_at) VALUES (?, ?, ?)",
+			e.Policy, string(content), now).Error
+		if err != nil {
+			tx.Rollback()
+			return db.logError(errors.Database(err))
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return db.logError(errors.Database(err))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) CreateNotificationRule(rule api.NotificationRule) (*api.NotificationRule, error) {
+	result := db.Conn.Create(&rule)
+	if result.Error != nil {
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return &rule, nil
+}
+
+// ListNotificationRules returns the notification rules of a program. If
+// teamID is empty the rules of all the teams are returned, which is needed
+// to process the scans of the programs when the team is not known.
+func (db vulcanitoStore) ListNotificationRules(teamID, programID string) ([]*api.NotificationRule, error) {
+	rules := []*api.NotificationRule{}
+	query := db.Conn.Where("program_id = ?", programID)
+	if teamID != "" {
+		query = query.Where("team_id = ?", teamID)
+	}
+	result := query.Order("created_at asc").Find(&rules)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return rules, nil
+}
+
+func (db vulcanitoStore) FindNotificationRule(teamID, programID, ruleID string) (*api.NotificationRule, error) {
+	rule := &api.NotificationRule{}
+	result := db.Conn.
+		Where("team_id = ? AND program_id = ? AND id = ?", teamID, programID, ruleID).
+		First(rule)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return rule, nil
+}
+
+func (db vulcanitoStore) UpdateNotificationRule(rule api.NotificationRule) (*api.NotificationRule, error) {
+	result := db.Conn.Save(&rule)
+	if result.Error != nil {
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	return &rule, nil
+}
+
+func (db vulcanitoStore) DeleteNotificationRule(rule api.NotificationRule) error {
+	result := db.Conn.
+		Where("team_id = ? AND program_id = ?", rule.TeamID, rule.ProgramID).
+		Delete(&rule)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("notification rule not found"))
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ const (
 	opDeleteAllAssets       = "DeleteAllAssets"
 	opFindingOverwrite      = "FindingOverwrite"
 	opMergeDiscoveredAssets = "MergeDiscoveredAssets"
+	opBulkFindingOverwrite  = "BulkFindingOverwrite"
+	opExportFindings        = "ExportFindings"
+	opCreateIssueTickets    = "CreateIssueTickets"
 )
 
 var (
@@ -47,6 +50,10 @@ func (db vulcanitoStore) pushToOutbox(tx *gorm.DB, op string, data ...interface{
 		buildFunc = db.buildFindingOverwriteDTO
 	case opMergeDiscoveredAssets:
 		buildFunc = db.buildMergeDiscoveredAssetsDTO
+	case opBulkFindingOverwrite:
+		buildFunc = db.buildBulkFindingOverwriteDTO
+	case opCreateIssueTickets:
+		buildFunc = db.buildCreateIssueTicketsDTO
 	default:
 		return errUnimplementedOp
 	}
@@ -237,6 +244,46 @@ func (db vulcanitoStore) buildMergeDiscoveredAssetsDTO(tx *gorm.DB, data ...inte
 	return cdc.OpMergeDiscoveredAssetsDTO{TeamID: teamID, Assets: assets, GroupName: groupName, JobID: jobID}, nil
 }
 
+// buildBulkFindingOverwriteDTO builds a BulkFindingOverwrite action DTO for
+// outbox. Expected input:
+//   - api.BulkFindingOverwrite
+//   - jobID
+func (db vulcanitoStore) buildBulkFindingOverwriteDTO(tx *gorm.DB, data ...interface{}) (interface{}, error) {
+	if len(data) != 2 {
+		return nil, errInvalidParams
+	}
+	bulk, ok := data[0].(api.BulkFindingOverwrite)
+	if !ok {
+		return nil, errInvalidParams
+	}
+	jobID, ok := data[1].(string)
+	if !ok {
+		return nil, errInvalidParams
+	}
+
+	return cdc.OpBulkFindingOverwriteDTO{BulkFindingOverwrite: bulk, JobID: jobID}, nil
+}
+
+// buildCreateIssueTicketsDTO builds a CreateIssueTickets action DTO for
+// outbox. Expected input:
+//   - api.BulkFindingTicketCreate
+//   - jobID
+func (db vulcanitoStore) buildCreateIssueTicketsDTO(tx *gorm.DB, data ...interface{}) (interface{}, error) {
+	if len(data) != 2 {
+		return nil, errInvalidParams
+	}
+	bulk, ok := data[0].(api.BulkFindingTicketCreate)
+	if !ok {
+		return nil, errInvalidParams
+	}
+	jobID, ok := data[1].(string)
+	if !ok {
+		return nil, errInvalidParams
+	}
+
+	return cdc.OpCreateIssueTicketsDTO{BulkFindingTicketCreate: bulk, JobID: jobID}, nil
+}
+
 func (db vulcanitoStore) insertIntoOutbox(tx *gorm.DB, outbox cdc.Outbox) error {
 	res := tx.Create(&outbox)
 	if res.Error != nil {
@@ -16,6 +16,7 @@ func (db vulcanitoStore) CreatePolicy(policy api.Policy) (*api.Policy, error) {
 	}
 	db.Conn.
 		Preload("Team").
+		Preload("Template.Settings").
 		First(&policy)
 	return &policy, nil
 }
@@ -24,6 +25,7 @@ func (db vulcanitoStore) ListPolicies(teamID string) ([]*api.Policy, error) {
 	policies := []*api.Policy{}
 	res := db.Conn.Preload("Team").
 		Preload("ChecktypeSettings").
+		Preload("Template.Settings").
 		Preload("ProgramsGroupsPolicies").
 		Preload("ProgramsGroupsPolicies.Program").
 		Preload("ProgramsGroupsPolicies.Policy").
@@ -41,7 +43,11 @@ func (db vulcanitoStore) ListPolicies(teamID string) ([]*api.Policy, error) {
 
 func (db vulcanitoStore) FindPolicy(policyID string) (*api.Policy, error) {
 	policy := &api.Policy{ID: policyID}
-	res := db.Conn.Preload("Team").Preload("ChecktypeSettings").Find(&policy)
+	res := db.Conn.
+		Preload("Team").
+		Preload("ChecktypeSettings").
+		Preload("Template.Settings").
+		Find(&policy)
 
 	if res.Error != nil {
 		if db.NotFoundError(res.Error) {
@@ -54,6 +60,17 @@ func (db vulcanitoStore) FindPolicy(policyID string) (*api.Policy, error) {
 }
 
 func (db vulcanitoStore) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
+	// An empty template id means the policy does not extend a template
+	// anymore.
+	if policy.TemplateID != nil && *policy.TemplateID == "" {
+		result := db.Conn.Model(&policy).
+			Where("team_id = ?", policy.TeamID).
+			Update("template_id", nil)
+		if result.Error != nil {
+			return nil, db.logError(errors.Update(result.Error))
+		}
+		policy.TemplateID = nil
+	}
 	result := db.Conn.Model(&policy).
 		Preload("Group").
 		Where("team_id = ?", policy.TeamID).
@@ -64,6 +81,7 @@ func (db vulcanitoStore) UpdatePolicy(policy api.Policy) (*api.Policy, error) {
 
 	db.Conn.Preload("Team").
 		Preload("ChecktypeSettings").
+		Preload("Template.Settings").
 		Preload("Programs").First(&policy)
 	return &policy, nil
 }
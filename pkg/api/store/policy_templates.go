@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) ListPolicyTemplates() ([]*api.PolicyTemplate, error) {
+	templates := []*api.PolicyTemplate{}
+	result := db.Conn.
+		Preload("Settings").
+		Order("name asc").
+		Find(&templates)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return templates, nil
+}
+
+func (db vulcanitoStore) CreatePolicyTemplate(template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	result := db.Conn.Create(&template)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "duplicate") {
+			return nil, db.logError(errors.Duplicated(result.Error))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindPolicyTemplate(template.ID)
+}
+
+func (db vulcanitoStore) FindPolicyTemplate(templateID string) (*api.PolicyTemplate, error) {
+	template := &api.PolicyTemplate{}
+	result := db.Conn.
+		Preload("Settings").
+		Where("id = ?", templateID).
+		First(template)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return template, nil
+}
+
+// UpdatePolicyTemplate updates the name and description of a template. If
+// the settings of the given template are not nil, they replace the current
+// settings of the template.
+func (db vulcanitoStore) UpdatePolicyTemplate(template api.PolicyTemplate) (*api.PolicyTemplate, error) {
+	settings := template.Settings
+	template.Settings = nil
+
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	result := tx.Model(&template).Update(template)
+	if result.Error != nil {
+		tx.Rollback()
+		if strings.Contains(result.Error.Error(), "duplicate") {
+			return nil, db.logError(errors.Duplicated(result.Error))
+		}
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, db.logError(errors.NotFound("policy template not found"))
+	}
+	if settings != nil {
+		result = tx.Delete(&api.PolicyTemplateSetting{}, "policy_template_id = ?", template.ID)
+		if result.Error != nil {
+			tx.Rollback()
+			return nil, db.logError(errors.Update(result.Error))
+		}
+		for _, s := range settings {
+			s.ID = ""
+			s.PolicyTemplateID = template.ID
+			if err := tx.Create(s).Error; err != nil {
+				tx.Rollback()
+				return nil, db.logError(errors.Update(err))
+			}
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+	return db.FindPolicyTemplate(template.ID)
+}
+
+// DeletePolicyTemplate deletes a template. Templates extended by policies
+// can not be deleted.
+func (db vulcanitoStore) DeletePolicyTemplate(templateID string) error {
+	var count int
+	result := db.Conn.Model(&api.Policy{}).Where("template_id = ?", templateID).Count(&count)
+	if result.Error != nil {
+		return db.logError(errors.Database(result.Error))
+	}
+	if count > 0 {
+		return db.logError(errors.Delete(fmt.Sprintf("policy template is extended by %d policies", count)))
+	}
+	result = db.Conn.Delete(&api.PolicyTemplate{ID: templateID})
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("policy template not found"))
+	}
+	return nil
+}
@@ -20,12 +20,16 @@ func (db vulcanitoStore) ListPrograms(teamID string) ([]*api.Program, error) {
 		Preload("ProgramsGroupsPolicies.Policy").
 		Preload("ProgramsGroupsPolicies.Policy.Team").
 		Preload("ProgramsGroupsPolicies.Policy.ChecktypeSettings").
+		Preload("ProgramsGroupsPolicies.Policy.Template.Settings").
 		Find(&programs)
 
 	if result.Error != nil {
 		return nil, db.logError(errors.Database(result.Error))
 	}
 
+	if err := db.resolvePrograms(programs...); err != nil {
+		return nil, err
+	}
 	return programs, nil
 }
 
@@ -54,8 +58,12 @@ func (db vulcanitoStore) CreateProgram(program api.Program, teamID string) (*api
 		Preload("ProgramsGroupsPolicies.Policy").
 		Preload("ProgramsGroupsPolicies.Policy.Team").
 		Preload("ProgramsGroupsPolicies.Policy.ChecktypeSettings").
+		Preload("ProgramsGroupsPolicies.Policy.Template.Settings").
 		First(&program)
 
+	if err := db.resolvePrograms(&program); err != nil {
+		return nil, err
+	}
 	return &program, nil
 }
 
@@ -70,6 +78,7 @@ func (db vulcanitoStore) FindProgram(programID string, teamID string) (*api.Prog
 		Preload("ProgramsGroupsPolicies.Policy").
 		Preload("ProgramsGroupsPolicies.Policy.Team").
 		Preload("ProgramsGroupsPolicies.Policy.ChecktypeSettings").
+		Preload("ProgramsGroupsPolicies.Policy.Template.Settings").
 		Find(&program, "id = ? and team_id = ?", programID, teamID)
 
 	if result.Error != nil {
@@ -79,6 +88,9 @@ func (db vulcanitoStore) FindProgram(programID string, teamID string) (*api.Prog
 		return nil, db.logError(errors.Database(result.Error))
 	}
 
+	if err := db.resolvePrograms(program); err != nil {
+		return nil, err
+	}
 	return program, nil
 }
 
@@ -124,7 +136,8 @@ func (db vulcanitoStore) UpdateProgram(program api.Program, teamID string) (*api
 		Preload("ProgramsGroupsPolicies.Group.AssetGroup.Asset.Team").
 		Preload("ProgramsGroupsPolicies.Policy").
 		Preload("ProgramsGroupsPolicies.Policy.Team").
-		Preload("ProgramsGroupsPolicies.Policy.ChecktypeSettings").First(&program).Error
+		Preload("ProgramsGroupsPolicies.Policy.ChecktypeSettings").
+		Preload("ProgramsGroupsPolicies.Policy.Template.Settings").First(&program).Error
 	if err != nil {
 		tx.Rollback()
 		return nil, errors.Database(err)
@@ -133,9 +146,27 @@ func (db vulcanitoStore) UpdateProgram(program api.Program, teamID string) (*api
 	if err = tx.Commit().Error; err != nil {
 		return nil, errors.Database(err)
 	}
+	if err := db.resolvePrograms(&program); err != nil {
+		return nil, err
+	}
 	return &program, nil
 }
 
+// resolvePrograms sets the assets of the dynamic groups and the effective
+// checktype settings of the policies referenced by the given programs.
+func (db vulcanitoStore) resolvePrograms(programs ...*api.Program) error {
+	groups := []*api.Group{}
+	for _, p := range programs {
+		for _, pgp := range p.ProgramsGroupsPolicies {
+			groups = append(groups, pgp.Group)
+			if pgp.Policy != nil {
+				pgp.Policy.ChecktypeSettings = pgp.Policy.EffectiveSettings()
+			}
+		}
+	}
+	return db.selectDynamicGroupsAssets(groups)
+}
+
 func (db vulcanitoStore) checkProgramPolicyGroupsTeam(teamID string, PGroups []*api.ProgramsGroupsPolicies) (bool, error) {
 	if len(PGroups) < 1 {
 		return true, nil
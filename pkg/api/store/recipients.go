@@ -5,11 +5,17 @@ Copyright 2021 Adevinta
 package store
 
 import (
+	"strings"
+
+	"github.com/jinzhu/gorm"
+
 	"github.com/adevinta/vulcan-api/pkg/api"
 	"github.com/adevinta/errors"
 )
 
-// UpdateRecipients ...
+// UpdateRecipients sets the recipients of a team to the given emails. The
+// recipients already in the team keep their verification status and
+// subscriptions.
 func (db vulcanitoStore) UpdateRecipients(teamID string, emails []string) error {
 	// Start a new transaction
 	tx := db.Conn.Begin()
@@ -17,19 +23,19 @@ func (db vulcanitoStore) UpdateRecipients(teamID string, emails []string) error
 		return db.logError(errors.Database(tx.Error))
 	}
 
-	result := tx.Delete(api.Recipient{}, "team_id = ?", teamID)
+	result := tx.Where("team_id = ?", teamID)
+	if len(emails) > 0 {
+		result = result.Where("email NOT IN (?)", emails)
+	}
+	result = result.Delete(api.Recipient{})
 	if result.Error != nil {
 		tx.Rollback()
 		return db.logError(errors.Delete(result.Error))
 	}
 
 	for _, e := range emails {
-		r := &api.Recipient{
-			TeamID: teamID,
-			Email:  e,
-		}
-
-		result := tx.Create(r)
+		result := tx.Exec(`INSERT INTO recipients (team_id, email, created_at, updated_at)
+			VALUES (?, ?, NOW(), NOW()) ON CONFLICT DO NOTHING`, teamID, e)
 		if result.Error != nil {
 			tx.Rollback()
 			return db.logError(errors.Create(result.Error))
@@ -54,3 +60,69 @@ func (db vulcanitoStore) ListRecipients(teamID string) ([]*api.Recipient, error)
 
 	return rs, nil
 }
+
+func (db vulcanitoStore) FindRecipient(teamID, email string) (*api.Recipient, error) {
+	r := &api.Recipient{}
+	result := db.Conn.Where("team_id = ? AND email = ?", teamID, email).First(r)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return r, nil
+}
+
+func (db vulcanitoStore) CreateRecipient(recipient api.Recipient) (*api.Recipient, error) {
+	result := db.Conn.Create(&recipient)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("the recipient already exists in the team"))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindRecipient(recipient.TeamID, recipient.Email)
+}
+
+func (db vulcanitoStore) UpdateRecipient(recipient api.Recipient) (*api.Recipient, error) {
+	result := db.Conn.Save(&recipient)
+	if result.Error != nil {
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	return db.FindRecipient(recipient.TeamID, recipient.Email)
+}
+
+func (db vulcanitoStore) DeleteRecipient(teamID, email string) error {
+	result := db.Conn.Delete(api.Recipient{}, "team_id = ? AND email = ?", teamID, email)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("recipient not found"))
+	}
+	return nil
+}
+
+// DeactivateRecipients deactivates the recipients with the given emails in
+// all the teams, and returns the number of recipients deactivated.
+func (db vulcanitoStore) DeactivateRecipients(emails []string, reason string) (int64, error) {
+	lowered := []string{}
+	for _, e := range emails {
+		lowered = append(lowered, strings.ToLower(e))
+	}
+	if len(lowered) == 0 {
+		return 0, nil
+	}
+	result := db.Conn.
+		Model(&api.Recipient{}).
+		Where("lower(email) IN (?) AND active", lowered).
+		Updates(map[string]interface{}{
+			"active":             false,
+			"deactivated_reason": reason,
+			"token_version":      gorm.Expr("token_version + 1"),
+		})
+	if result.Error != nil {
+		return 0, db.logError(errors.Update(result.Error))
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) CreateReportSubscription(subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	result := db.Conn.Create(&subscription)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("a report subscription with the same name already exists"))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindReportSubscription(subscription.TeamID, subscription.ID)
+}
+
+func (db vulcanitoStore) ListReportSubscriptions(teamID string) ([]*api.ReportSubscription, error) {
+	subscriptions := []*api.ReportSubscription{}
+	result := db.Conn.
+		Preload("Recipients").
+		Where("team_id = ?", teamID).
+		Order("name asc").
+		Find(&subscriptions)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return subscriptions, nil
+}
+
+// ListDueReportSubscriptions returns the enabled report subscriptions of all
+// the teams that must be sent at the given time.
+func (db vulcanitoStore) ListDueReportSubscriptions(now time.Time) ([]*api.ReportSubscription, error) {
+	subscriptions := []*api.ReportSubscription{}
+	result := db.Conn.
+		Preload("Recipients").
+		Where("NOT disabled AND next_run_at <= ?", now).
+		Order("next_run_at asc").
+		Find(&subscriptions)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return subscriptions, nil
+}
+
+func (db vulcanitoStore) FindReportSubscription(teamID, subscriptionID string) (*api.ReportSubscription, error) {
+	subscription := &api.ReportSubscription{}
+	result := db.Conn.
+		Preload("Recipients").
+		Where("team_id = ? AND id = ?", teamID, subscriptionID).
+		First(subscription)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return subscription, nil
+}
+
+// UpdateReportSubscription saves the given subscription, replacing its
+// recipients.
+func (db vulcanitoStore) UpdateReportSubscription(subscription api.ReportSubscription) (*api.ReportSubscription, error) {
+	recipients := subscription.Recipients
+	subscription.Recipients = nil
+
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	result := tx.Save(&subscription)
+	if result.Error != nil {
+		tx.Rollback()
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("a report subscription with the same name already exists"))
+		}
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	result = tx.Delete(&api.ReportSubscriptionRecipient{}, "subscription_id = ?", subscription.ID)
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	for _, r := range recipients {
+		r.SubscriptionID = subscription.ID
+		if err := tx.Create(r).Error; err != nil {
+			tx.Rollback()
+			return nil, db.logError(errors.Update(err))
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+	return db.FindReportSubscription(subscription.TeamID, subscription.ID)
+}
+
+func (db vulcanitoStore) DeleteReportSubscription(subscription api.ReportSubscription) error {
+	result := db.Conn.
+		Where("team_id = ?", subscription.TeamID).
+		Delete(&subscription)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("report subscription not found"))
+	}
+	return nil
+}
+
+// ClaimReportSubscription sets the next and last run times of a subscription
+// only if its next run time was not modified, and returns true in that case.
+// This ensures only one instance of the API sends each report.
+func (db vulcanitoStore) ClaimReportSubscription(subscription api.ReportSubscription, nextRunAt, lastRunAt time.Time) (bool, error) {
+	result := db.Conn.
+		Model(&api.ReportSubscription{}).
+		Where("id = ? AND next_run_at = ?", subscription.ID, subscription.NextRunAt).
+		Updates(map[string]interface{}{"next_run_at": nextRunAt, "last_run_at": lastRunAt})
+	if result.Error != nil {
+		return false, db.logError(errors.Update(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) CreateRiskAcceptance(acceptance api.RiskAcceptance) (*api.RiskAcceptance, error) {
+	result := db.Conn.Create(&acceptance)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("the finding already has a pending or approved risk acceptance"))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindRiskAcceptance(acceptance.TeamID, acceptance.ID)
+}
+
+// ListRiskAcceptances returns the risk acceptances of a team, optionally
+// filtered by finding and status, from the newest to the oldest.
+func (db vulcanitoStore) ListRiskAcceptances(teamID, findingID, status string) ([]*api.RiskAcceptance, error) {
+	acceptances := []*api.RiskAcceptance{}
+	query := db.Conn.Preload("Requester").Preload("Reviewer").Where("team_id = ?", teamID)
+	if findingID != "" {
+		query = query.Where("finding_id = ?", findingID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("created_at desc").Find(&acceptances)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return acceptances, nil
+}
+
+// ListExpiredRiskAcceptances returns the pending and approved risk
+// acceptances of all the teams whose expiry date is not after the given
+// time.
+func (db vulcanitoStore) ListExpiredRiskAcceptances(now time.Time) ([]*api.RiskAcceptance, error) {
+	acceptances := []*api.RiskAcceptance{}
+	result := db.Conn.
+		Where("status IN (?) AND expires_at <= ?", []string{api.RiskAcceptancePending, api.RiskAcceptanceApproved}, now).
+		Order("expires_at").
+		Find(&acceptances)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return acceptances, nil
+}
+
+func (db vulcanitoStore) FindRiskAcceptance(teamID, acceptanceID string) (*api.RiskAcceptance, error) {
+	acceptance := &api.RiskAcceptance{}
+	result := db.Conn.Preload("Requester").Preload("Reviewer").
+		Where("team_id = ? AND id = ?", teamID, acceptanceID).
+		First(acceptance)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return acceptance, nil
+}
+
+// TransitionRiskAcceptance stores the status and the review fields of a risk
+// acceptance only if its current status is the given one, and returns true in
+// that case. If an overwrite is given, it is created in the same transaction,
+// so the status of the finding changes together with the acceptance. This
+// ensures only one instance of the API applies each transition.
+func (db vulcanitoStore) TransitionRiskAcceptance(acceptance api.RiskAcceptance, fromStatus string, overwrite *api.FindingOverwrite) (bool, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return false, db.logError(errors.Database(tx.Error))
+	}
+
+	result := tx.Model(&api.RiskAcceptance{}).
+		Where("id = ? AND status = ?", acceptance.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":       acceptance.Status,
+			"reviewed_by":  acceptance.ReviewedBy,
+			"review_notes": acceptance.ReviewNotes,
+			"reviewed_at":  acceptance.ReviewedAt,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return false, db.logError(errors.Update(result.Error))
+	}
+	if result.RowsAffected != 1 {
+		tx.Rollback()
+		return false, nil
+	}
+
+	if overwrite != nil {
+		if err := db.createFindingOverwriteTx(tx, *overwrite); err != nil {
+			tx.Rollback()
+			return false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, db.logError(errors.Database(err))
+	}
+	return true, nil
+}
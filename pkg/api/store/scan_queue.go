@@ -0,0 +1,157 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// ListScanQueueEntries returns the entries of the scan queue with the given
+// statuses, sorted by creation time. If teamID is empty the entries of all
+// the teams are returned.
+func (db vulcanitoStore) ListScanQueueEntries(teamID string, statuses []api.ScanQueueStatus) ([]*api.ScanQueueEntry, error) {
+	entries := []*api.ScanQueueEntry{}
+	query := db.Conn.Where("status IN (?)", statuses)
+	if teamID != "" {
+		query = query.Where("team_id = ?", teamID)
+	}
+	result := query.Order("created_at asc").Find(&entries)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return entries, nil
+}
+
+// scanQueueLock is the name of the advisory lock that serializes, across
+// all the instances of the API, the changes in the scan queue that take or
+// give up slots.
+const scanQueueLock = "scan_queue"
+
+// AdmitScanQueueEntries calls admit with the queued and running entries of
+// the scan queue, sorted by creation time, while holding a lock shared by all
+// the instances of the API, so the decisions of admit are based on the
+// current usage of the slots. The entries returned by admit without an ID
+// are created, and the queued entries returned by admit are changed to
+// running. It returns the entries created or changed.
+func (db vulcanitoStore) AdmitScanQueueEntries(admit func(entries []*api.ScanQueueEntry) []*api.ScanQueueEntry) ([]*api.ScanQueueEntry, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return nil, db.logError(errors.Database(tx.Error))
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", scanQueueLock).Error; err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Database(err))
+	}
+	entries := []*api.ScanQueueEntry{}
+	statuses := []api.ScanQueueStatus{api.ScanQueueStatusQueued, api.ScanQueueStatusRunning}
+	if err := tx.Where("status IN (?)", statuses).Order("created_at asc").Find(&entries).Error; err != nil {
+		tx.Rollback()
+		return nil, db.logError(errors.Database(err))
+	}
+	admitted := []*api.ScanQueueEntry{}
+	for _, e := range admit(entries) {
+		if e.ID == "" {
+			if err := tx.Create(e).Error; err != nil {
+				tx.Rollback()
+				return nil, db.logError(errors.Create(err))
+			}
+			admitted = append(admitted, e)
+			continue
+		}
+		result := tx.Model(&api.ScanQueueEntry{}).
+			Where("id = ? AND status = ?", e.ID, api.ScanQueueStatusQueued).
+			Updates(map[string]interface{}{
+				"status":           api.ScanQueueStatusRunning,
+				"started_at":       e.StartedAt,
+				"checks_in_flight": e.ChecksInFlight,
+			})
+		if result.Error != nil {
+			tx.Rollback()
+			return nil, db.logError(errors.Update(result.Error))
+		}
+		if result.RowsAffected == 1 {
+			admitted = append(admitted, e)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, db.logError(errors.Database(err))
+	}
+	return admitted, nil
+}
+
+// StartScanQueueEntry sets the scan created for a running entry and its
+// number of checks, all of them in flight. It returns false if the entry was
+// not running anymore.
+func (db vulcanitoStore) StartScanQueueEntry(entryID, scanID string, checkCount int) (bool, error) {
+	return db.updateRunningScanQueueEntry(entryID, map[string]interface{}{
+		"scan_id":          scanID,
+		"check_count":      checkCount,
+		"checks_in_flight": checkCount,
+	})
+}
+
+// UpdateScanQueueEntryChecks updates the number of checks and checks in
+// flight of a running entry. It returns false if the entry was not running
+// anymore.
+func (db vulcanitoStore) UpdateScanQueueEntryChecks(entryID string, checkCount, checksInFlight int) (bool, error) {
+	return db.updateRunningScanQueueEntry(entryID, map[string]interface{}{
+		"check_count":      checkCount,
+		"checks_in_flight": checksInFlight,
+	})
+}
+
+// FinishScanQueueEntry changes the status of a running entry to done,
+// freeing its slot, and sets the reason, if any, why its scan could not be
+// created. It returns false if the entry was not running anymore.
+func (db vulcanitoStore) FinishScanQueueEntry(entryID, reason string) (bool, error) {
+	return db.updateRunningScanQueueEntry(entryID, map[string]interface{}{
+		"status":           api.ScanQueueStatusDone,
+		"checks_in_flight": 0,
+		"error":            reason,
+		"finished_at":      time.Now(),
+	})
+}
+
+func (db vulcanitoStore) updateRunningScanQueueEntry(entryID string, fields map[string]interface{}) (bool, error) {
+	result := db.Conn.Model(&api.ScanQueueEntry{}).
+		Where("id = ? AND status = ?", entryID, api.ScanQueueStatusRunning).
+		Updates(fields)
+	if result.Error != nil {
+		return false, db.logError(errors.Update(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CancelScanQueueEntry changes the status of a queued entry of a team to
+// cancelled. It returns false if the entry was not in the queued status
+// anymore. It takes the lock of the scan queue so an entry is not cancelled
+// while it is being admitted.
+func (db vulcanitoStore) CancelScanQueueEntry(teamID, entryID string) (bool, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return false, db.logError(errors.Database(tx.Error))
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", scanQueueLock).Error; err != nil {
+		tx.Rollback()
+		return false, db.logError(errors.Database(err))
+	}
+	result := tx.Model(&api.ScanQueueEntry{}).
+		Where("id = ? AND team_id = ? AND status = ?", entryID, teamID, api.ScanQueueStatusQueued).
+		Updates(map[string]interface{}{
+			"status":      api.ScanQueueStatusCancelled,
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return false, db.logError(errors.Update(result.Error))
+	}
+	if err := tx.Commit().Error; err != nil {
+		return false, db.logError(errors.Database(err))
+	}
+	return result.RowsAffected == 1, nil
+}
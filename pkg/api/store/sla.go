@@ -0,0 +1,123 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// ListSLAPolicies returns the SLA policies of a team, or the global ones if
+// the team is empty.
+func (db vulcanitoStore) ListSLAPolicies(teamID string) ([]*api.SLAPolicy, error) {
+	policies := []*api.SLAPolicy{}
+	query := db.Conn.Where("team_id IS NULL")
+	if teamID != "" {
+		query = db.Conn.Where("team_id = ?", teamID)
+	}
+	result := query.Order("severity, rolfp_level").Find(&policies)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return policies, nil
+}
+
+func (db vulcanitoStore) FindSLAPolicy(teamID, policyID string) (*api.SLAPolicy, error) {
+	policy := &api.SLAPolicy{}
+	query := db.Conn.Where("id = ? AND team_id IS NULL", policyID)
+	if teamID != "" {
+		query = db.Conn.Where("id = ? AND team_id = ?", policyID, teamID)
+	}
+	result := query.First(policy)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return policy, nil
+}
+
+func (db vulcanitoStore) CreateSLAPolicy(policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	result := db.Conn.Create(&policy)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("a sla policy for the same severity and rolfp level already exists"))
+		}
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return &policy, nil
+}
+
+func (db vulcanitoStore) UpdateSLAPolicy(policy api.SLAPolicy) (*api.SLAPolicy, error) {
+	result := db.Conn.Save(&policy)
+	if result.Error != nil {
+		if db.IsDuplicateError(result.Error) {
+			return nil, db.logError(errors.Duplicated("a sla policy for the same severity and rolfp level already exists"))
+		}
+		return nil, db.logError(errors.Update(result.Error))
+	}
+	return &policy, nil
+}
+
+func (db vulcanitoStore) DeleteSLAPolicy(policy api.SLAPolicy) error {
+	result := db.Conn.Delete(&policy)
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	return nil
+}
+
+// RecordSLABreaches records the breaches of the SLA of the findings of a team
+// that were not notified since their findings were opened for the last time,
+// and calls notify with them. The breaches are recorded in the same
+// transaction and only if notify succeeds, so a breach that fails to be
+// notified is notified again in the next check. The transaction holds a lock
+// on the breaches of the team, so each breach is only notified once even if
+// several instances of the API check the SLAs; the instances that find the
+// team locked do not notify anything.
+func (db vulcanitoStore) RecordSLABreaches(teamID string, breaches []api.SLABreach, notify func([]api.SLABreach) error) (int, error) {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return 0, db.logError(errors.Database(tx.Error))
+	}
+	var locked bool
+	if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", "sla_breaches:"+teamID).Row().Scan(&locked); err != nil {
+		tx.Rollback()
+		return 0, db.logError(errors.Database(err))
+	}
+	if !locked {
+		tx.Rollback()
+		return 0, nil
+	}
+	pending := []api.SLABreach{}
+	for _, b := range breaches {
+		result := tx.Exec(`INSERT INTO sla_breaches (team_id, finding_id, opened_at, due_at, notified_at)
+			SELECT ?, ?, ?, ?, NOW()
+			WHERE NOT EXISTS (
+				SELECT 1 FROM sla_breaches WHERE team_id = ? AND finding_id = ? AND notified_at >= ?
+			) ON CONFLICT DO NOTHING`,
+			teamID, b.FindingID, b.OpenedAt, b.DueAt, teamID, b.FindingID, b.OpenedAt)
+		if result.Error != nil {
+			tx.Rollback()
+			return 0, db.logError(errors.Create(result.Error))
+		}
+		if result.RowsAffected == 1 {
+			pending = append(pending, b)
+		}
+	}
+	if len(pending) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+	if err := notify(pending); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0, db.logError(errors.Database(err))
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/testutil"
+)
+
+func TestStoreRecordSLABreaches(t *testing.T) {
+	testStore, err := testutil.PrepareDatabaseLocal("../../../testdata/fixtures", NewDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testStore.Close()
+
+	teamID := "a14c7c65-66ab-4676-bcf6-0dea9719f5c6"
+	openedAt := time.Now().Add(-30 * 24 * time.Hour)
+	breach := api.SLABreach{TeamID: teamID, FindingID: "f1", OpenedAt: openedAt, DueAt: openedAt.Add(7 * 24 * time.Hour)}
+
+	var notified []api.SLABreach
+	notify := func(pending []api.SLABreach) error {
+		notified = append(notified, pending...)
+		return nil
+	}
+	failing := func(pending []api.SLABreach) error {
+		return errors.New("reports not available")
+	}
+
+	// A breach that fails to be notified is not recorded.
+	if _, err := testStore.RecordSLABreaches(teamID, []api.SLABreach{breach}, failing); err == nil {
+		t.Fatal("expected error notifying the breaches")
+	}
+	n, err := testStore.RecordSLABreaches(teamID, []api.SLABreach{breach}, notify)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(notified) != 1 {
+		t.Fatalf("got %d breaches recorded and %d notified, want 1", n, len(notified))
+	}
+
+	// The same breach is not notified twice in the same open cycle, even if
+	// the open time of the finding is computed again.
+	again := breach
+	again.OpenedAt = openedAt.Add(time.Hour)
+	n, err = testStore.RecordSLABreaches(teamID, []api.SLABreach{again}, notify)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("got %d breaches recorded, want 0", n)
+	}
+
+	// The finding is fixed and opened again, so it is notified again when it
+	// breaches its SLA.
+	reopened := breach
+	reopened.OpenedAt = time.Now().Add(time.Minute)
+	n, err = testStore.RecordSLABreaches(teamID, []api.SLABreach{reopened}, notify)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(notified) != 2 {
+		t.Fatalf("got %d breaches recorded and %d notified, want 1 and 2", n, len(notified))
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// UpsertStatsSnapshots stores the given stats snapshots, replacing the ones
+// of the same team, group, date and severity if they exist.
+func (db vulcanitoStore) UpsertStatsSnapshots(snapshots []api.StatsSnapshot) error {
+	tx := db.Conn.Begin()
+	if tx.Error != nil {
+		return db.logError(errors.Database(tx.Error))
+	}
+	now := time.Now()
+	for _, s := range snapshots {
+		result := tx.Exec(`INSERT INTO stats_snapshots (team_id, group_id, date, severity, open_count, new_count, fixed_count, exposure_mean, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (team_id, group_id, date, severity) DO UPDATE SET open_count = EXCLUDED.open_count,
+			new_count = EXCLUDED.new_count, fixed_count = EXCLUDED.fixed_count, exposure_mean = EXCLUDED.exposure_mean,
+			created_at = EXCLUDED.created_at`,
+			s.TeamID, s.GroupID, s.Date.Format(api.StatsDateFormat), s.Severity, s.Open, s.New, s.Fixed, s.Exposure, now)
+		if result.Error != nil {
+			tx.Rollback()
+			return db.logError(errors.Create(result.Error))
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return db.logError(errors.Database(err))
+	}
+	return nil
+}
+
+// ListStatsSnapshots returns the stats snapshots of a team, or of a group of
+// the team if the groupID is not empty, taken between the given days, both
+// included.
+func (db vulcanitoStore) ListStatsSnapshots(teamID, groupID string, from, to time.Time) ([]*api.StatsSnapshot, error) {
+	snapshots := []*api.StatsSnapshot{}
+	result := db.Conn.
+		Where("team_id = ? AND group_id = ? AND date BETWEEN ? AND ?", teamID, groupID, from.Format(api.StatsDateFormat), to.Format(api.StatsDateFormat)).
+		Order("date, severity").
+		Find(&snapshots)
+	if result.Error != nil {
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return snapshots, nil
+}
+
+// ClaimStatsSnapshots claims the snapshots of the stats of a team of a day
+// and returns true if they were not claimed yet, or if they were claimed
+// before the given time and are not done. This ensures only one instance of
+// the API snapshots the stats of each team once a day.
+func (db vulcanitoStore) ClaimStatsSnapshots(teamID string, date, staleBefore time.Time) (bool, error) {
+	result := db.Conn.Exec(`INSERT INTO stats_snapshot_claims (team_id, date, claimed_at) VALUES (?, ?, ?)
+		ON CONFLICT (team_id, date) DO UPDATE SET claimed_at = EXCLUDED.claimed_at
+		WHERE NOT stats_snapshot_claims.done AND stats_snapshot_claims.claimed_at < ?`,
+		teamID, date.Format(api.StatsDateFormat), time.Now(), staleBefore)
+	if result.Error != nil {
+		return false, db.logError(errors.Create(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseStatsSnapshots releases the claim of the snapshots of the stats of
+// a team of a day. If the snapshots are done the claim is kept, so they are
+// not taken again, otherwise it is removed so they can be retried.
+func (db vulcanitoStore) ReleaseStatsSnapshots(teamID string, date time.Time, done bool) error {
+	var result *gorm.DB
+	if done {
+		result = db.Conn.Exec(`UPDATE stats_snapshot_claims SET done = TRUE WHERE team_id = ? AND date = ?`,
+			teamID, date.Format(api.StatsDateFormat))
+	} else {
+		result = db.Conn.Exec(`DELETE FROM stats_snapshot_claims WHERE team_id = ? AND date = ? AND NOT done`,
+			teamID, date.Format(api.StatsDateFormat))
+	}
+	if result.Error != nil {
+		return db.logError(errors.Update(result.Error))
+	}
+	return nil
+}
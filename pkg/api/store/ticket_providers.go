@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package store
+
+import (
+	"time"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+func (db vulcanitoStore) FindTeamTicketProvider(teamID string) (*api.TicketProvider, error) {
+	provider := &api.TicketProvider{}
+	result := db.Conn.Where("team_id = ?", teamID).First(provider)
+	if result.Error != nil {
+		if db.NotFoundError(result.Error) {
+			return nil, db.logError(errors.NotFound(result.Error))
+		}
+		return nil, db.logError(errors.Database(result.Error))
+	}
+	return provider, nil
+}
+
+// UpsertTeamTicketProvider stores the ticket provider of a team, replacing
+// the previous one if it exists.
+func (db vulcanitoStore) UpsertTeamTicketProvider(provider api.TicketProvider) (*api.TicketProvider, error) {
+	now := time.Now()
+	result := db.Conn.Exec(`INSERT INTO team_ticket_providers (team_id, type, url, project, credentials_ref, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (team_id) DO UPDATE SET type = EXCLUDED.type, url = EXCLUDED.url, project = EXCLUDED.project,
+		credentials_ref = EXCLUDED.credentials_ref, updated_at = EXCLUDED.updated_at`,
+		provider.TeamID, provider.Type, provider.URL, provider.Project, provider.CredentialsRef, now, now)
+	if result.Error != nil {
+		return nil, db.logError(errors.Create(result.Error))
+	}
+	return db.FindTeamTicketProvider(provider.TeamID)
+}
+
+func (db vulcanitoStore) DeleteTeamTicketProvider(teamID string) error {
+	result := db.Conn.Where("team_id = ?", teamID).Delete(&api.TicketProvider{})
+	if result.Error != nil {
+		return db.logError(errors.Delete(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return db.logError(errors.NotFound("ticket provider not found"))
+	}
+	return nil
+}
@@ -18,7 +18,7 @@ type Team struct {
 	Assets       []*Asset    `json:"assets"`    // This line is infered from other tables.
 	UserTeam     []*UserTeam `json:"user_team"` // This line is infered from other tables.
 	Groups       []*Group
-	UsingTracker bool `json:"using_tracker" sql:"-"` // feature flag.
+	UsingTracker bool `json:"using_tracker"`
 }
 
 func (t Team) ToResponse() *TeamResponse {
@@ -36,5 +36,5 @@ type TeamResponse struct {
 	Name         string `json:"name"`
 	Description  string `json:"description"`
 	Tag          string `json:"tag"`
-	UsingTracker bool   `json:"using_tracker"` // feature flag.
+	UsingTracker bool   `json:"using_tracker"`
 }
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Types of the ticket providers a team can use.
+const (
+	// TicketProviderVulcanTracker creates the tickets through the
+	// vulcan-tracker component.
+	TicketProviderVulcanTracker = "vulcantracker"
+	// TicketProviderJira creates the tickets in a Jira compatible REST API.
+	TicketProviderJira = "jira"
+	// TicketProviderGitHub creates the tickets as GitHub Issues.
+	TicketProviderGitHub = "github"
+)
+
+var (
+	// ErrInvalidTicketProviderType is returned when the type of a ticket
+	// provider is unknown.
+	ErrInvalidTicketProviderType = errors.New("invalid ticket provider type")
+	// ErrTicketProviderProjectRequired is returned when a ticket provider
+	// that is not vulcan-tracker has no project.
+	ErrTicketProviderProjectRequired = errors.New("ticket provider project is required")
+	// ErrInvalidTicketProviderProject is returned when the project of a
+	// GitHub ticket provider is not in the form owner/repository.
+	ErrInvalidTicketProviderProject = errors.New("invalid ticket provider project")
+	// ErrTicketProviderCredentialsRequired is returned when a ticket provider
+	// that is not vulcan-tracker has no credentials reference.
+	ErrTicketProviderCredentialsRequired = errors.New("ticket provider credentials reference is required")
+	// ErrInvalidTicketProviderURL is returned when the URL of a ticket
+	// provider is not an absolute http or https URL.
+	ErrInvalidTicketProviderURL = errors.New("invalid ticket provider url")
+)
+
+// TicketProvider defines where the tickets of the findings of a team are
+// created. The teams without a ticket provider use vulcan-tracker.
+type TicketProvider struct {
+	TeamID string `gorm:"primary_key" json:"team_id"`
+	// Type possible values are: vulcantracker, jira and github.
+	Type string `json:"type"`
+	// URL is the base URL of the API of the provider. It is required by the
+	// jira provider and optional for the github one.
+	URL string `json:"url"`
+	// Project is the key of the Jira project or the owner/repository of the
+	// GitHub repository the tickets are created in.
+	Project string `json:"project"`
+	// CredentialsRef is the name of the credentials used to authenticate
+	// against the provider. The credentials themselves are never stored.
+	CredentialsRef string    `json:"credentials_ref"`
+	CreatedAt      time.Time `json:"-"`
+	UpdatedAt      time.Time `json:"-"`
+}
+
+func (TicketProvider) TableName() string {
+	return "team_ticket_providers"
+}
+
+// Validate checks the type, project, credentials reference and URL of the
+// provider.
+func (p TicketProvider) Validate() error {
+	switch p.Type {
+	case TicketProviderVulcanTracker:
+		return nil
+	case TicketProviderJira:
+		if p.URL == "" {
+			return ErrInvalidTicketProviderURL
+		}
+	case TicketProviderGitHub:
+		parts := strings.Split(p.Project, "/")
+		if p.Project != "" && (len(parts) != 2 || parts[0] == "" || parts[1] == "") {
+			return ErrInvalidTicketProviderProject
+		}
+	default:
+		return ErrInvalidTicketProviderType
+	}
+	if p.Project == "" {
+		return ErrTicketProviderProjectRequired
+	}
+	if p.CredentialsRef == "" {
+		return ErrTicketProviderCredentialsRequired
+	}
+	if p.URL != "" {
+		u, err := url.Parse(p.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidTicketProviderURL
+		}
+	}
+	return nil
+}
+
+// SameTracker returns true if both providers create the tickets in the same
+// tracker and project, so the IDs of the tickets created by one of them are
+// valid for the other.
+func (p TicketProvider) SameTracker(other TicketProvider) bool {
+	return p.Type == other.Type &&
+		strings.TrimSuffix(p.URL, "/") == strings.TrimSuffix(other.URL, "/") &&
+		strings.EqualFold(p.Project, other.Project)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package api
+
+import (
+	"testing"
+)
+
+func TestTicketProviderValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider TicketProvider
+		wantErr  error
+	}{
+		{name: "VulcanTracker", provider: TicketProvider{Type: TicketProviderVulcanTracker}},
+		{name: "Jira", provider: TicketProvider{Type: TicketProviderJira, URL: "https://jira.example.com", Project: "SEC", CredentialsRef: "jira"}},
+		{name: "GitHub", provider: TicketProvider{Type: TicketProviderGitHub, Project: "adevinta/vulcan", CredentialsRef: "github"}},
+		{name: "GitHubEnterprise", provider: TicketProvider{Type: TicketProviderGitHub, URL: "https://github.example.com/api/v3", Project: "adevinta/vulcan", CredentialsRef: "github"}},
+		{name: "InvalidType", provider: TicketProvider{Type: "gitlab", Project: "adevinta/vulcan", CredentialsRef: "gitlab"}, wantErr: ErrInvalidTicketProviderType},
+		{name: "JiraNoURL", provider: TicketProvider{Type: TicketProviderJira, Project: "SEC", CredentialsRef: "jira"}, wantErr: ErrInvalidTicketProviderURL},
+		{name: "JiraInvalidURL", provider: TicketProvider{Type: TicketProviderJira, URL: "jira.example.com", Project: "SEC", CredentialsRef: "jira"}, wantErr: ErrInvalidTicketProviderURL},
+		{name: "JiraNoProject", provider: TicketProvider{Type: TicketProviderJira, URL: "https://jira.example.com", CredentialsRef: "jira"}, wantErr: ErrTicketProviderProjectRequired},
+		{name: "GitHubInvalidProject", provider: TicketProvider{Type: TicketProviderGitHub, Project: "vulcan", CredentialsRef: "github"}, wantErr: ErrInvalidTicketProviderProject},
+		{name: "GitHubNoCredentials", provider: TicketProvider{Type: TicketProviderGitHub, Project: "adevinta/vulcan"}, wantErr: ErrTicketProviderCredentialsRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.provider.Validate()
+			if err != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,9 +5,27 @@ Copyright 2023 Adevinta
 package api
 
 import (
+	"strings"
+	"time"
+
 	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
 )
 
+// TicketResolutionsWontFix are the resolutions of the tickets that were
+// closed without fixing their finding.
+var TicketResolutionsWontFix = []string{"Won't Fix", "Won't Do"}
+
+// IsTicketResolutionWontFix returns true if the given resolution of a ticket
+// means its finding will not be fixed.
+func IsTicketResolutionWontFix(resolution string) bool {
+	for _, r := range TicketResolutionsWontFix {
+		if strings.EqualFold(r, strings.TrimSpace(resolution)) {
+			return true
+		}
+	}
+	return false
+}
+
 // FindingTicketCreate represents the data needed to create a ticket.
 type FindingTicketCreate struct {
 	FindingID   string   `json:"finding_id" validate:"required"`
@@ -37,3 +55,59 @@ func (t Ticket) ToResponse() FindingTicketCreateResponse {
 
 	return output
 }
+
+// FindingTicket links a finding of a team with the ticket created for it in
+// the ticket tracker. The tickets are synchronized with the status of their
+// findings until they are closed.
+type FindingTicket struct {
+	ID         string  `gorm:"primary_key;AUTO_INCREMENT" json:"id" sql:"DEFAULT:gen_random_uuid()"`
+	TeamID     string  `json:"team_id" validate:"required"`
+	FindingID  string  `json:"finding_id" validate:"required"`
+	TicketID   string  `json:"ticket_id" validate:"required"`
+	TicketKey  string  `json:"ticket_key"`
+	URLTracker string  `json:"url_tracker"`
+	Status     string  `json:"status"`
+	Resolution string  `json:"resolution"`
+	CreatedBy  *string `json:"created_by"`
+	// ClosedAt is the time the ticket was found resolved in the ticket
+	// tracker, or the time it was closed because its finding was fixed.
+	ClosedAt  *time.Time `json:"closed_at"`
+	SyncedAt  *time.Time `json:"synced_at"`
+	CreatedAt time.Time  `json:"-"`
+	UpdatedAt time.Time  `json:"-"`
+}
+
+// NewFindingTicket returns the link between a finding of a team and a ticket
+// of the ticket tracker.
+func NewFindingTicket(teamID, findingID string, ticket Ticket, createdBy string) FindingTicket {
+	t := FindingTicket{
+		TeamID:     teamID,
+		FindingID:  findingID,
+		TicketID:   ticket.Ticket.ID,
+		TicketKey:  ticket.Ticket.Key,
+		URLTracker: ticket.Ticket.URLTracker,
+		Status:     ticket.Ticket.Status,
+		Resolution: ticket.Ticket.Resolution,
+	}
+	if createdBy != "" {
+		t.CreatedBy = &createdBy
+	}
+	return t
+}
+
+// BulkFindingTicketCreate requests the creation of a ticket for each open
+// finding of an issue of a team.
+type BulkFindingTicketCreate struct {
+	TeamID  string   `json:"team_id"`
+	IssueID string   `json:"issue_id"`
+	UserID  string   `json:"user_id"`
+	Labels  []string `json:"labels"`
+}
+
+// BulkFindingTicketResult contains the result of creating the ticket of one
+// finding. Error is empty if the ticket was created or already existed.
+type BulkFindingTicketResult struct {
+	FindingID  string `json:"finding_id"`
+	URLTracker string `json:"url_tracker,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
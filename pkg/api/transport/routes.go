@@ -49,6 +49,10 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("GET").Path("/api/v1/teams/{team_id}").Handler(newServer(e[endpoint.FindTeam], endpoint.TeamRequest{}, logger, endpoint.FindTeam))
 	r.Methods("PATCH").Path("/api/v1/teams/{team_id}").Handler(newServer(e[endpoint.UpdateTeam], endpoint.TeamUpdateRequest{}, logger, endpoint.UpdateTeam))
 	r.Methods("DELETE").Path("/api/v1/teams/{team_id}").Handler(newServer(e[endpoint.DeleteTeam], endpoint.TeamRequest{}, logger, endpoint.DeleteTeam))
+	r.Methods("PUT").Path("/api/v1/teams/{team_id}/tracker").Handler(newServer(e[endpoint.UpdateTeamTracker], endpoint.TeamTrackerRequest{}, logger, endpoint.UpdateTeamTracker))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/tracker/provider").Handler(newServer(e[endpoint.FindTeamTicketProvider], endpoint.TicketProviderRequest{}, logger, endpoint.FindTeamTicketProvider))
+	r.Methods("PUT").Path("/api/v1/teams/{team_id}/tracker/provider").Handler(newServer(e[endpoint.UpdateTeamTicketProvider], endpoint.TicketProviderRequest{}, logger, endpoint.UpdateTeamTicketProvider))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/tracker/provider").Handler(newServer(e[endpoint.DeleteTeamTicketProvider], endpoint.TicketProviderRequest{}, logger, endpoint.DeleteTeamTicketProvider))
 
 	// Team members
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/members").Handler(newServer(e[endpoint.CreateTeamMember], endpoint.TeamMemberRequest{}, logger, endpoint.CreateTeamMember))
@@ -60,6 +64,14 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	// Team recipients
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/recipients").Handler(newServer(e[endpoint.ListRecipients], endpoint.RecipientsData{}, logger, endpoint.ListRecipients))
 	r.Methods("PUT").Path("/api/v1/teams/{team_id}/recipients").Handler(newServer(e[endpoint.UpdateRecipients], endpoint.RecipientsData{}, logger, endpoint.UpdateRecipients))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/recipients").Handler(newServer(e[endpoint.CreateRecipient], endpoint.RecipientRequest{}, logger, endpoint.CreateRecipient))
+	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/recipients/{email}").Handler(newServer(e[endpoint.UpdateRecipient], endpoint.RecipientRequest{}, logger, endpoint.UpdateRecipient))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/recipients/{email}").Handler(newServer(e[endpoint.DeleteRecipient], endpoint.RecipientRequest{}, logger, endpoint.DeleteRecipient))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/recipients/{email}/verification").Handler(newServer(e[endpoint.SendRecipientVerification], endpoint.RecipientRequest{}, logger, endpoint.SendRecipientVerification))
+	r.Methods("GET").Path("/api/v1/recipients/verify").Handler(newServer(e[endpoint.VerifyRecipient], endpoint.RecipientTokenRequest{}, logger, endpoint.VerifyRecipient))
+	r.Methods("GET").Path("/api/v1/recipients/unsubscribe").Handler(newServer(e[endpoint.UnsubscribeRecipientPage], endpoint.RecipientTokenRequest{}, logger, endpoint.UnsubscribeRecipientPage))
+	r.Methods("POST").Path("/api/v1/recipients/unsubscribe").Handler(newServer(e[endpoint.UnsubscribeRecipient], endpoint.RecipientTokenRequest{}, logger, endpoint.UnsubscribeRecipient))
+	r.Methods("POST").Path("/api/v1/recipients/bounces").Handler(newServer(e[endpoint.DeactivateBouncedRecipients], endpoint.BouncedRecipientsRequest{}, logger, endpoint.DeactivateBouncedRecipients))
 
 	// Assets
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/assets").Handler(newServer(e[endpoint.ListAssets], endpoint.AssetRequest{}, logger, endpoint.ListAssets))
@@ -78,6 +90,12 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("PUT").Path("/api/v1/teams/{team_id}/assets/{asset_id}/annotations").Handler(newServer(e[endpoint.PutAssetAnnotations], endpoint.AssetAnnotationRequest{}, logger, endpoint.PutAssetAnnotations))
 	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/assets/{asset_id}/annotations").Handler(newServer(e[endpoint.DeleteAssetAnnotations], endpoint.AssetAnnotationDeleteRequest{}, logger, endpoint.DeleteAssetAnnotations))
 
+	// Asset Checktype Settings
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/assets/{asset_id}/checktype-settings").Handler(newServer(e[endpoint.ListAssetChecktypeSettings], endpoint.AssetChecktypeSettingRequest{}, logger, endpoint.ListAssetChecktypeSettings))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/assets/{asset_id}/checktype-settings").Handler(newServer(e[endpoint.CreateAssetChecktypeSetting], endpoint.AssetChecktypeSettingRequest{}, logger, endpoint.CreateAssetChecktypeSetting))
+	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/assets/{asset_id}/checktype-settings/{setting_id}").Handler(newServer(e[endpoint.UpdateAssetChecktypeSetting], endpoint.AssetChecktypeSettingRequest{}, logger, endpoint.UpdateAssetChecktypeSetting))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/assets/{asset_id}/checktype-settings/{setting_id}").Handler(newServer(e[endpoint.DeleteAssetChecktypeSetting], endpoint.AssetChecktypeSettingRequest{}, logger, endpoint.DeleteAssetChecktypeSetting))
+
 	// Groups
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/groups").Handler(newServer(e[endpoint.CreateGroup], endpoint.AssetsGroupRequest{}, logger, endpoint.CreateGroup))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/groups").Handler(newServer(e[endpoint.ListGroups], endpoint.ListGroupsRequest{}, logger, endpoint.ListGroups))
@@ -96,6 +114,12 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/programs/{program_id}").Handler(newServer(e[endpoint.FindProgram], endpoint.ProgramRequest{}, logger, endpoint.FindProgram))
 	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/programs/{program_id}").Handler(newServer(e[endpoint.UpdateProgram], endpoint.ProgramRequest{}, logger, endpoint.UpdateProgram))
 	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/programs/{program_id}").Handler(newServer(e[endpoint.DeleteProgram], endpoint.ProgramRequest{}, logger, endpoint.DeleteProgram))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/programs/{program_id}/plan").Handler(newServer(e[endpoint.ProgramPlan], endpoint.ProgramRequest{}, logger, endpoint.ProgramPlan))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/programs/{program_id}/scans/diff").Handler(newServer(e[endpoint.DiffScans], endpoint.DiffScansRequest{}, logger, endpoint.DiffScans))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/programs/{program_id}/notifications").Handler(newServer(e[endpoint.ListNotificationRules], endpoint.NotificationRuleRequest{}, logger, endpoint.ListNotificationRules))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/programs/{program_id}/notifications").Handler(newServer(e[endpoint.CreateNotificationRule], endpoint.NotificationRuleRequest{}, logger, endpoint.CreateNotificationRule))
+	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/programs/{program_id}/notifications/{notification_id}").Handler(newServer(e[endpoint.UpdateNotificationRule], endpoint.NotificationRuleRequest{}, logger, endpoint.UpdateNotificationRule))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/programs/{program_id}/notifications/{notification_id}").Handler(newServer(e[endpoint.DeleteNotificationRule], endpoint.NotificationRuleRequest{}, logger, endpoint.DeleteNotificationRule))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/programs/{program_id}/scans").Handler(newServer(e[endpoint.ListProgramScans], endpoint.ListProgramScansRequest{}, logger, endpoint.ListProgramScans))
 
 	// Schedules
@@ -109,6 +133,7 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/policies/{policy_id}").Handler(newServer(e[endpoint.FindPolicy], endpoint.PolicyRequest{}, logger, endpoint.FindPolicy))
 	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/policies/{policy_id}").Handler(newServer(e[endpoint.UpdatePolicy], endpoint.PolicyRequest{}, logger, endpoint.UpdatePolicy))
 	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/policies/{policy_id}").Handler(newServer(e[endpoint.DeletePolicy], endpoint.PolicyRequest{}, logger, endpoint.DeletePolicy))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/policies/{policy_id}/divergence").Handler(newServer(e[endpoint.PolicyDivergence], endpoint.PolicyRequest{}, logger, endpoint.PolicyDivergence))
 
 	// Policiy x CheckType Settings
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/policies/{policy_id}/settings").Handler(newServer(e[endpoint.ListChecktypeSetting], endpoint.ChecktypeSettingRequest{}, logger, endpoint.ListChecktypeSetting))
@@ -117,14 +142,38 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/policies/{policy_id}/settings/{setting_id}").Handler(newServer(e[endpoint.UpdateChecktypeSetting], endpoint.ChecktypeSettingRequest{}, logger, endpoint.UpdateChecktypeSetting))
 	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/policies/{policy_id}/settings/{setting_id}").Handler(newServer(e[endpoint.DeleteChecktypeSetting], endpoint.ChecktypeSettingRequest{}, logger, endpoint.DeleteChecktypeSetting))
 
+	// Checktypes
+	r.Methods("GET").Path("/api/v1/checktypes").Handler(newServer(e[endpoint.ListChecktypes], endpoint.EmptyRequest{}, logger, endpoint.ListChecktypes))
+
+	// Global policies
+	r.Methods("GET").Path("/api/v1/global-policies/checktypes").Handler(newServer(e[endpoint.ListGlobalPolicyEvaluations], endpoint.EmptyRequest{}, logger, endpoint.ListGlobalPolicyEvaluations))
+
+	// Policy templates
+	r.Methods("GET").Path("/api/v1/policy-templates").Handler(newServer(e[endpoint.ListPolicyTemplates], endpoint.EmptyRequest{}, logger, endpoint.ListPolicyTemplates))
+	r.Methods("POST").Path("/api/v1/policy-templates").Handler(newServer(e[endpoint.CreatePolicyTemplate], endpoint.PolicyTemplateRequest{}, logger, endpoint.CreatePolicyTemplate))
+	r.Methods("GET").Path("/api/v1/policy-templates/{template_id}").Handler(newServer(e[endpoint.FindPolicyTemplate], endpoint.PolicyTemplateRequest{}, logger, endpoint.FindPolicyTemplate))
+	r.Methods("PATCH").Path("/api/v1/policy-templates/{template_id}").Handler(newServer(e[endpoint.UpdatePolicyTemplate], endpoint.PolicyTemplateRequest{}, logger, endpoint.UpdatePolicyTemplate))
+	r.Methods("DELETE").Path("/api/v1/policy-templates/{template_id}").Handler(newServer(e[endpoint.DeletePolicyTemplate], endpoint.PolicyTemplateRequest{}, logger, endpoint.DeletePolicyTemplate))
+
 	// scans
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/scans").Handler(newServer(e[endpoint.CreateScan], endpoint.ScanRequest{}, logger, endpoint.CreateScan))
+	r.Methods("GET").Path("/api/v1/scans/queue").Handler(newServer(e[endpoint.ListScanQueue], endpoint.ScanQueueRequest{}, logger, endpoint.ListScanQueue))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/scans/queue").Handler(newServer(e[endpoint.ListScanQueue], endpoint.ScanQueueRequest{}, logger, endpoint.ListScanQueue))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/scans/queue/{entry_id}").Handler(newServer(e[endpoint.CancelScanQueueEntry], endpoint.ScanQueueRequest{}, logger, endpoint.CancelScanQueueEntry))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/scans/{scan_id}").Handler(newServer(e[endpoint.FindScan], endpoint.ScanRequest{}, logger, endpoint.FindScan))
 	r.Methods("PUT").Path("/api/v1/teams/{team_id}/scans/{scan_id}/abort").Handler(newServer(e[endpoint.AbortScan], endpoint.ScanRequest{}, logger, endpoint.AbortScan))
 
 	// Send Digest Report
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/report/digest").Handler(newServer(e[endpoint.SendDigestReport], endpoint.SendDigestReportRequest{}, logger, endpoint.SendDigestReport))
 
+	// Report subscriptions
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/report-subscriptions").Handler(newServer(e[endpoint.ListReportSubscriptions], endpoint.ReportSubscriptionRequest{}, logger, endpoint.ListReportSubscriptions))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/report-subscriptions/{subscription_id}").Handler(newServer(e[endpoint.FindReportSubscription], endpoint.ReportSubscriptionRequest{}, logger, endpoint.FindReportSubscription))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/report-subscriptions").Handler(newServer(e[endpoint.CreateReportSubscription], endpoint.ReportSubscriptionRequest{}, logger, endpoint.CreateReportSubscription))
+	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/report-subscriptions/{subscription_id}").Handler(newServer(e[endpoint.UpdateReportSubscription], endpoint.ReportSubscriptionRequest{}, logger, endpoint.UpdateReportSubscription))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/report-subscriptions/{subscription_id}").Handler(newServer(e[endpoint.DeleteReportSubscription], endpoint.ReportSubscriptionRequest{}, logger, endpoint.DeleteReportSubscription))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/report-subscriptions/{subscription_id}/send").Handler(newServer(e[endpoint.SendReportSubscription], endpoint.ReportSubscriptionRequest{}, logger, endpoint.SendReportSubscription))
+
 	// Stats
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/coverage").Handler(newServer(e[endpoint.StatsCoverage], endpoint.StatsCoverageRequest{}, logger, endpoint.StatsCoverage))
 
@@ -136,15 +185,42 @@ func AttachRoutes(e endpoint.Endpoints, logger kitlog.Logger) http.Handler {
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/targets").Handler(newServer(e[endpoint.ListFindingsTargets], endpoint.FindingsRequest{}, logger, endpoint.ListFindingsTargets))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/targets/{target_id}").Handler(newServer(e[endpoint.ListFindingsByTarget], endpoint.FindingsByTargetRequest{}, logger, endpoint.ListFindingsByTarget))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/labels").Handler(newServer(e[endpoint.ListFindingsLabels], endpoint.FindingsRequest{}, logger, endpoint.ListFindingsLabels))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/export").Handler(newServer(e[endpoint.ExportFindings], endpoint.FindingsExportRequest{}, logger, endpoint.ExportFindings))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/exports").Handler(newServer(e[endpoint.CreateFindingsExport], endpoint.FindingsExportRequest{}, logger, endpoint.CreateFindingsExport))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/exports/{export_id}").Handler(newServer(e[endpoint.DownloadFindingsExport], endpoint.FindingsExportRequest{}, logger, endpoint.DownloadFindingsExport))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/tickets").Handler(newServer(e[endpoint.ListFindingTickets], endpoint.FindingTicketsRequest{}, logger, endpoint.ListFindingTickets))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/issues/{issue_id}/tickets").Handler(newServer(e[endpoint.CreateIssueTickets], endpoint.IssueTicketsRequest{}, logger, endpoint.CreateIssueTickets))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/sla").Handler(newServer(e[endpoint.ListFindingsSLA], endpoint.FindingsSLARequest{}, logger, endpoint.ListFindingsSLA))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/overwrites").Handler(newServer(e[endpoint.CreateFindingOverwrites], endpoint.BulkFindingOverwriteRequest{}, logger, endpoint.CreateFindingOverwrites))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/{finding_id}").Handler(newServer(e[endpoint.FindFinding], endpoint.FindingsRequest{}, logger, endpoint.FindFinding))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/{finding_id}/overwrites").Handler(newServer(e[endpoint.ListFindingOverwrites], endpoint.FindingsRequest{}, logger, endpoint.ListFindingOverwrites))
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/{finding_id}/overwrites").Handler(newServer(e[endpoint.CreateFindingOverwrite], endpoint.FindingOverwriteRequest{}, logger, endpoint.CreateFindingOverwrite))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/{finding_id}/comments").Handler(newServer(e[endpoint.ListFindingComments], endpoint.FindingCommentRequest{}, logger, endpoint.ListFindingComments))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/{finding_id}/comments").Handler(newServer(e[endpoint.CreateFindingComment], endpoint.FindingCommentRequest{}, logger, endpoint.CreateFindingComment))
+	r.Methods("PATCH").Path("/api/v1/teams/{team_id}/findings/{finding_id}/comments/{comment_id}").Handler(newServer(e[endpoint.UpdateFindingComment], endpoint.FindingCommentRequest{}, logger, endpoint.UpdateFindingComment))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/findings/{finding_id}/comments/{comment_id}").Handler(newServer(e[endpoint.DeleteFindingComment], endpoint.FindingCommentRequest{}, logger, endpoint.DeleteFindingComment))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/{finding_id}/risk-acceptances").Handler(newServer(e[endpoint.RequestRiskAcceptance], endpoint.RiskAcceptanceRequest{}, logger, endpoint.RequestRiskAcceptance))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/risk-acceptances").Handler(newServer(e[endpoint.ListRiskAcceptances], endpoint.RiskAcceptanceRequest{}, logger, endpoint.ListRiskAcceptances))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/risk-acceptances/{risk_acceptance_id}").Handler(newServer(e[endpoint.FindRiskAcceptance], endpoint.RiskAcceptanceRequest{}, logger, endpoint.FindRiskAcceptance))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/risk-acceptances/{risk_acceptance_id}/approve").Handler(newServer(e[endpoint.ApproveRiskAcceptance], endpoint.RiskAcceptanceRequest{}, logger, endpoint.ApproveRiskAcceptance))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/risk-acceptances/{risk_acceptance_id}/reject").Handler(newServer(e[endpoint.RejectRiskAcceptance], endpoint.RiskAcceptanceRequest{}, logger, endpoint.RejectRiskAcceptance))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/findings/{finding_id}/verify").Handler(newServer(e[endpoint.ListFindingVerifications], endpoint.FindingsRequest{}, logger, endpoint.ListFindingVerifications))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/{finding_id}/verify").Handler(newServer(e[endpoint.VerifyFinding], endpoint.FindingsRequest{}, logger, endpoint.VerifyFinding))
 	r.Methods("POST").Path("/api/v1/teams/{team_id}/findings/{finding_id}/ticket").Handler(newServer(e[endpoint.CreateFindingTicket], endpoint.FindingCreateTicketRequest{}, logger, endpoint.CreateFindingTicket))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/sla-policies").Handler(newServer(e[endpoint.ListTeamSLAPolicies], endpoint.SLAPolicyRequest{}, logger, endpoint.ListTeamSLAPolicies))
+	r.Methods("POST").Path("/api/v1/teams/{team_id}/sla-policies").Handler(newServer(e[endpoint.CreateTeamSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.CreateTeamSLAPolicy))
+	r.Methods("PUT").Path("/api/v1/teams/{team_id}/sla-policies/{policy_id}").Handler(newServer(e[endpoint.UpdateTeamSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.UpdateTeamSLAPolicy))
+	r.Methods("DELETE").Path("/api/v1/teams/{team_id}/sla-policies/{policy_id}").Handler(newServer(e[endpoint.DeleteTeamSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.DeleteTeamSLAPolicy))
+	r.Methods("GET").Path("/api/v1/sla-policies").Handler(newServer(e[endpoint.ListSLAPolicies], endpoint.SLAPolicyRequest{}, logger, endpoint.ListSLAPolicies))
+	r.Methods("POST").Path("/api/v1/sla-policies").Handler(newServer(e[endpoint.CreateSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.CreateSLAPolicy))
+	r.Methods("PUT").Path("/api/v1/sla-policies/{policy_id}").Handler(newServer(e[endpoint.UpdateSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.UpdateSLAPolicy))
+	r.Methods("DELETE").Path("/api/v1/sla-policies/{policy_id}").Handler(newServer(e[endpoint.DeleteSLAPolicy], endpoint.SLAPolicyRequest{}, logger, endpoint.DeleteSLAPolicy))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/mttr").Handler(newServer(e[endpoint.StatsMTTR], endpoint.StatsRequest{}, logger, endpoint.StatsMTTR))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/exposure").Handler(newServer(e[endpoint.StatsExposure], endpoint.StatsRequest{}, logger, endpoint.StatsExposure))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/exposure/current").Handler(newServer(e[endpoint.StatsCurrentExposure], endpoint.StatsRequest{}, logger, endpoint.StatsCurrentExposure))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/open").Handler(newServer(e[endpoint.StatsOpen], endpoint.StatsRequest{}, logger, endpoint.StatsOpen))
 	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/fixed").Handler(newServer(e[endpoint.StatsFixed], endpoint.StatsRequest{}, logger, endpoint.StatsFixed))
+	r.Methods("GET").Path("/api/v1/teams/{team_id}/stats/trend").Handler(newServer(e[endpoint.StatsTrend], endpoint.StatsTrendRequest{}, logger, endpoint.StatsTrend))
 	r.Methods("GET").Path("/api/v1/stats/mttr").Handler(newServer(e[endpoint.GlobalStatsMTTR], endpoint.GlobalStatsRequest{}, logger, endpoint.GlobalStatsMTTR))
 	r.Methods("GET").Path("/api/v1/stats/exposure").Handler(newServer(e[endpoint.GlobalStatsExposure], endpoint.GlobalStatsRequest{}, logger, endpoint.GlobalStatsExposure))
 	r.Methods("GET").Path("/api/v1/stats/exposure/current").Handler(newServer(e[endpoint.GlobalStatsCurrentExposure], endpoint.GlobalStatsRequest{}, logger, endpoint.GlobalStatsCurrentExposure))
@@ -63,11 +63,30 @@ func newServer(e kitendpoint.Endpoint, request interface{}, logger kitlog.Logger
 	return kithttp.NewServer(
 		e,
 		makeDecodeRequestFunc(request),
-		kithttp.EncodeJSONResponse,
+		encodeResponse,
 		options(logger, endpoint)...,
 	)
 }
 
+// encodeResponse writes the files and the HTML pages returned by the
+// endpoints as they are, and encodes as JSON the rest of the responses.
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if h, ok := response.(vulcanendpoint.HTML); ok {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(h.StatusCode())
+		_, err := w.Write(h.Body)
+		return err
+	}
+	f, ok := response.(vulcanendpoint.File)
+	if !ok {
+		return kithttp.EncodeJSONResponse(ctx, w, response)
+	}
+	w.Header().Set("Content-Type", f.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", f.Name))
+	w.WriteHeader(f.StatusCode())
+	return f.WriteTo(w)
+}
+
 func HTTPGenerateXRequestID() kithttp.RequestFunc {
 	return func(ctx context.Context, r *http.Request) context.Context {
 		XRequestID, _ := uuid.NewV4()
@@ -6,12 +6,14 @@ package api
 
 import (
 	"context"
+	"io"
 )
 
 // VulcanitoService represents all operations provided by Vulcanito
 type VulcanitoService interface {
 	// Healthcheck
 	Healthcheck(ctx context.Context) error
+	ChecktypesCacheStatus(ctx context.Context) *ChecktypesCacheStatus
 
 	// Jobs
 	FindJob(ctx context.Context, jobID string) (*Job, error)
@@ -45,6 +47,14 @@ type VulcanitoService interface {
 	// Recipients
 	UpdateRecipients(ctx context.Context, teamID string, emails []string) error
 	ListRecipients(ctx context.Context, teamID string) ([]*Recipient, error)
+	CreateRecipient(ctx context.Context, recipient Recipient) (*Recipient, error)
+	UpdateRecipient(ctx context.Context, recipient Recipient, reportTypes *[]string) (*Recipient, error)
+	DeleteRecipient(ctx context.Context, teamID, email string) error
+	SendRecipientVerification(ctx context.Context, teamID, email string) error
+	VerifyRecipient(ctx context.Context, token string) (*Recipient, error)
+	UnsubscribeRecipient(ctx context.Context, token string) (*Recipient, error)
+	DeactivateBouncedRecipients(ctx context.Context, emails []string) (int64, error)
+	ListReportRecipients(ctx context.Context, teamID, reportType string) ([]*ReportRecipient, error)
 
 	// Assets
 	ListAssets(ctx context.Context, teamID string, asset Asset) ([]*Asset, error)
@@ -65,6 +75,12 @@ type VulcanitoService interface {
 	PutAssetAnnotations(ctx context.Context, teamID string, assetID string, annotations []*AssetAnnotation) ([]*AssetAnnotation, error)
 	DeleteAssetAnnotations(ctx context.Context, teamID string, assedID string, annotations []*AssetAnnotation) error
 
+	// Asset Checktype Settings
+	ListAssetChecktypeSettings(ctx context.Context, teamID, assetID string) ([]*AssetChecktypeSetting, error)
+	CreateAssetChecktypeSetting(ctx context.Context, setting AssetChecktypeSetting) (*AssetChecktypeSetting, error)
+	UpdateAssetChecktypeSetting(ctx context.Context, setting AssetChecktypeSetting) (*AssetChecktypeSetting, error)
+	DeleteAssetChecktypeSetting(ctx context.Context, setting AssetChecktypeSetting) error
+
 	ListGroups(ctx context.Context, teamID, groupName string) ([]*Group, error)
 	CreateGroup(ctx context.Context, group Group) (*Group, error)
 	FindGroup(ctx context.Context, group Group) (*Group, error)
@@ -78,6 +94,7 @@ type VulcanitoService interface {
 	ListPrograms(ctx context.Context, teamID string) ([]*Program, error)
 	CreateProgram(ctx context.Context, program Program, teamID string) (*Program, error)
 	FindProgram(ctx context.Context, programID string, teamID string) (*Program, error)
+	ProgramPlan(ctx context.Context, programID string, teamID string) (*ProgramPlan, error)
 	UpdateProgram(ctx context.Context, program Program, teamID string) (*Program, error)
 	DeleteProgram(ctx context.Context, program Program, teamID string) error
 
@@ -98,38 +115,99 @@ type VulcanitoService interface {
 	UpdateChecktypeSetting(ctx context.Context, checktypeSetting ChecktypeSetting) (*ChecktypeSetting, error)
 	DeleteChecktypeSetting(ctx context.Context, checktypeSettingID string) error
 
+	ListChecktypes(ctx context.Context) ([]*Checktype, error)
+
+	ListPolicyTemplates(ctx context.Context) ([]*PolicyTemplate, error)
+	CreatePolicyTemplate(ctx context.Context, template PolicyTemplate) (*PolicyTemplate, error)
+	FindPolicyTemplate(ctx context.Context, templateID string) (*PolicyTemplate, error)
+	UpdatePolicyTemplate(ctx context.Context, template PolicyTemplate) (*PolicyTemplate, error)
+	DeletePolicyTemplate(ctx context.Context, templateID string) error
+	PolicyDivergence(ctx context.Context, teamID, policyID string) (*PolicyDivergence, error)
+	ListGlobalPolicyEvaluations(ctx context.Context) ([]*GlobalPolicyEvaluation, error)
+
 	ListScans(ctx context.Context, teamID string, programID string) ([]*Scan, error)
 	CreateScan(ctx context.Context, scan Scan, teamID string) (*Scan, error)
 	FindScan(ctx context.Context, scanID, teamID string) (*Scan, error)
 	AbortScan(ctx context.Context, scanID string, teamID string) (*Scan, error)
 	UpdateScan(ctx context.Context, scan Scan) (*Scan, error)
 	DeleteScan(ctx context.Context, scan Scan) error
+	ListScanQueue(ctx context.Context, teamID string) ([]*ScanQueueEntry, error)
+	CancelScanQueueEntry(ctx context.Context, teamID, entryID string) error
+	DiffScans(ctx context.Context, teamID string, from, to *Scan) (*ScanDiff, error)
+	DiffProgramScans(ctx context.Context, teamID, programID, fromID, toID string) (*ScanDiff, error)
+
+	ListNotificationRules(ctx context.Context, teamID, programID string) ([]*NotificationRule, error)
+	CreateNotificationRule(ctx context.Context, rule NotificationRule) (*NotificationRule, error)
+	UpdateNotificationRule(ctx context.Context, rule NotificationRule) (*NotificationRule, error)
+	DeleteNotificationRule(ctx context.Context, rule NotificationRule) error
+
+	ListReportSubscriptions(ctx context.Context, teamID string) ([]*ReportSubscription, error)
+	FindReportSubscription(ctx context.Context, teamID, subscriptionID string) (*ReportSubscription, error)
+	CreateReportSubscription(ctx context.Context, subscription ReportSubscription) (*ReportSubscription, error)
+	UpdateReportSubscription(ctx context.Context, subscription ReportSubscription) (*ReportSubscription, error)
+	DeleteReportSubscription(ctx context.Context, subscription ReportSubscription) error
 
 	SendDigestReport(ctx context.Context, teamID string, startDate string, endDate string) error
+	SendReportSubscription(ctx context.Context, teamID, subscriptionID string) error
 
 	// Stats
 	StatsCoverage(ctx context.Context, teamID string) (*StatsCoverage, error)
 
 	// VulnerabilityDB Stats
 	ListIssues(ctx context.Context, pagination Pagination) (*IssuesList, error)
-	ListFindings(ctx context.Context, params FindingsParams, pagination Pagination) (*FindingsList, error)
+	ListFindings(ctx context.Context, params FindingsParams, pagination Pagination) (*ScoredFindingsList, error)
 	ListFindingsIssues(ctx context.Context, params FindingsParams, pagination Pagination) (*FindingsIssuesList, error)
-	ListFindingsByIssue(ctx context.Context, params FindingsParams, pagination Pagination) (*FindingsList, error)
+	ListFindingsByIssue(ctx context.Context, params FindingsParams, pagination Pagination) (*ScoredFindingsList, error)
 	ListFindingsTargets(ctx context.Context, params FindingsParams, pagination Pagination) (*FindingsTargetsList, error)
-	ListFindingsByTarget(ctx context.Context, params FindingsParams, pagination Pagination) (*FindingsList, error)
+	ListFindingsByTarget(ctx context.Context, params FindingsParams, pagination Pagination) (*ScoredFindingsList, error)
 	ListFindingsLabels(ctx context.Context, params FindingsParams) (*FindingsLabels, error)
-	FindFinding(ctx context.Context, findingID string) (*Finding, error)
+	FindFinding(ctx context.Context, teamID, findingID string) (*Finding, error)
 	CreateFindingOverwrite(ctx context.Context, findingOverwrite FindingOverwrite) error
 	ListFindingOverwrites(ctx context.Context, findingID string) ([]*FindingOverwrite, error)
+	CreateFindingOverwritesAsync(ctx context.Context, bulk BulkFindingOverwrite) (*Job, error)
+	CreateFindingOverwrites(ctx context.Context, bulk BulkFindingOverwrite) ([]BulkFindingOverwriteResult, error)
+	ExportFindings(ctx context.Context, export FindingsExport) (FindingsExportWriter, error)
+	CreateFindingsExportAsync(ctx context.Context, export FindingsExport) (*Job, error)
+	GenerateFindingsExport(ctx context.Context, export FindingsExport) error
+	FindFindingsExport(ctx context.Context, teamID, exportID string) (*FindingsExport, error)
+	WriteFindingsExport(ctx context.Context, export FindingsExport, w io.Writer) error
+	ListFindingComments(ctx context.Context, teamID, findingID string) ([]*FindingComment, error)
+	CreateFindingComment(ctx context.Context, comment FindingComment) (*FindingComment, error)
+	UpdateFindingComment(ctx context.Context, comment FindingComment) (*FindingComment, error)
+	DeleteFindingComment(ctx context.Context, comment FindingComment) error
+	RequestRiskAcceptance(ctx context.Context, acceptance RiskAcceptance) (*RiskAcceptance, error)
+	ListRiskAcceptances(ctx context.Context, teamID, findingID, status string) ([]*RiskAcceptance, error)
+	FindRiskAcceptance(ctx context.Context, teamID, acceptanceID string) (*RiskAcceptance, error)
+	ApproveRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*RiskAcceptance, error)
+	RejectRiskAcceptance(ctx context.Context, teamID, acceptanceID, notes string) (*RiskAcceptance, error)
+	ExpireRiskAcceptance(ctx context.Context, acceptance RiskAcceptance) error
+	ListSLAPolicies(ctx context.Context, teamID string) ([]*SLAPolicy, error)
+	CreateSLAPolicy(ctx context.Context, policy SLAPolicy) (*SLAPolicy, error)
+	UpdateSLAPolicy(ctx context.Context, policy SLAPolicy) (*SLAPolicy, error)
+	DeleteSLAPolicy(ctx context.Context, teamID, policyID string) error
+	ListFindingsSLA(ctx context.Context, teamID, status string) ([]*FindingSLA, error)
+	NotifySLABreaches(ctx context.Context, teamID string) (int, error)
+	VerifyFinding(ctx context.Context, teamID, findingID string, user User) (*FindingVerification, error)
+	ListFindingVerifications(ctx context.Context, teamID, findingID string) ([]*FindingVerification, error)
 	StatsMTTR(ctx context.Context, params StatsParams) (*StatsMTTR, error)
 	StatsExposure(ctx context.Context, params StatsParams) (*StatsExposure, error)
 	StatsCurrentExposure(ctx context.Context, params StatsParams) (*StatsCurrentExposure, error)
 	StatsOpen(ctx context.Context, params StatsParams) (*StatsOpen, error)
 	StatsFixed(ctx context.Context, params StatsParams) (*StatsFixed, error)
 	StatsAssets(ctx context.Context, params StatsParams) (*StatsAssets, error)
+	SnapshotTeamStats(ctx context.Context, teamID, date string) error
+	StatsTrend(ctx context.Context, params StatsTrendParams) (*StatsTrend, error)
 
 	// Vulcan Tracker
 	CreateFindingTicket(ctx context.Context, ticket FindingTicketCreate) (*Ticket, error)
 	GetFindingTicket(ctx context.Context, findingID, teamID string) (*Ticket, error)
-	IsATeamOnboardedInVulcanTracker(ctx context.Context, teamID string, onboardedTeams []string) bool
+	IsATeamOnboardedInVulcanTracker(ctx context.Context, team Team) bool
+	UpdateTeamTracker(ctx context.Context, teamID string, enabled bool) (*Team, error)
+	ListFindingTickets(ctx context.Context, teamID string) ([]*FindingTicket, error)
+	CreateIssueTicketsAsync(ctx context.Context, bulk BulkFindingTicketCreate) (*Job, error)
+	CreateIssueTickets(ctx context.Context, bulk BulkFindingTicketCreate) ([]BulkFindingTicketResult, error)
+	SyncFindingTickets(ctx context.Context, teamID string) (int, error)
+	FindTeamTicketProvider(ctx context.Context, teamID string) (*TicketProvider, error)
+	UpdateTeamTicketProvider(ctx context.Context, provider TicketProvider) (*TicketProvider, error)
+	DeleteTeamTicketProvider(ctx context.Context, teamID string) error
 }
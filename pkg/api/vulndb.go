@@ -57,8 +57,11 @@ type FindingsTargetsList struct {
 }
 
 type FindingExpanded struct {
-	vulndb.FindingExpanded
+	ScoredFinding
 	TicketURL string `json:"url_tracker"`
+	// Comments is the discussion thread of the finding in the team it was
+	// requested for. It is only set when a single finding is requested.
+	Comments []FindingCommentResponse `json:"comments,omitempty"`
 }
 
 // Finding represents the response data returned from the vulnerability DB for
@@ -120,6 +123,10 @@ type StatsParams struct {
 	MaxScore    float64
 	Identifiers string
 	Labels      string
+	// MinRiskScore and MaxRiskScore filter the findings by their risk score.
+	// They are only supported in the open and fixed stats of a team.
+	MinRiskScore float64
+	MaxRiskScore float64
 }
 
 // GlobalStatsParams represents the group of parameters that can be used to
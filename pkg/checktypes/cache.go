@@ -0,0 +1,185 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package checktypes
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+const byAssettypeSnapshot = "by_assettype"
+
+// ByAssettypeInformer defines the component that provides the checktypes
+// that can be run against each assettype.
+type ByAssettypeInformer interface {
+	ByAssettype(ctx context.Context) (map[string][]string, error)
+}
+
+// SnapshotStore defines the methods needed by the CachedInformer to persist
+// the last checktypes information read from vulcan-core.
+type SnapshotStore interface {
+	FindChecktypesSnapshot(name string) (*api.ChecktypesSnapshot, error)
+	UpsertChecktypesSnapshot(snapshot api.ChecktypesSnapshot) error
+}
+
+// CachedInformer provides the checktypes that can be run against each
+// assettype from the checktypes cached by a Catalogue. The last checktypes
+// read from vulcan-core are stored in the database, so they can be used if
+// vulcan-core is not available when the API starts.
+type CachedInformer struct {
+	catalogue *Catalogue
+	store     SnapshotStore
+
+	// state is replaced as a whole, so reading the status never waits for
+	// a load of the checktypes.
+	state atomic.Pointer[cacheState]
+}
+
+// cacheState contains the checktypes for each assettype returned by a
+// CachedInformer. It must not be modified once stored.
+type cacheState struct {
+	checktypes map[string][]string
+	source     string
+	updated    time.Time
+	err        error
+}
+
+// NewCachedInformer returns a CachedInformer that gets the checktypes from
+// the given catalogue and persists them in the given store. The store can be
+// nil.
+func NewCachedInformer(catalogue *Catalogue, store SnapshotStore) *CachedInformer {
+	return &CachedInformer{catalogue: catalogue, store: store}
+}
+
+// ByAssettype returns the checktypes for each assettype. If the catalogue
+// has no checktypes, they are read from the database.
+func (c *CachedInformer) ByAssettype(ctx context.Context) (map[string][]string, error) {
+	current, err := c.catalogue.current(ctx)
+	old := c.state.Load()
+	if err != nil {
+		if old == nil {
+			state, serr := c.loadSnapshot(err)
+			if serr != nil {
+				return nil, err
+			}
+			c.state.CompareAndSwap(nil, state)
+			return copyByAssettype(state.checktypes), nil
+		}
+		if old.err != err {
+			state := *old
+			state.err = err
+			c.state.CompareAndSwap(old, &state)
+		}
+		return copyByAssettype(old.checktypes), nil
+	}
+
+	if old != nil && old.source == api.ChecktypesSourceVulcanCore && old.updated.Equal(current.updated) {
+		if old.err != current.err {
+			state := *old
+			state.err = current.err
+			c.state.CompareAndSwap(old, &state)
+		}
+		return copyByAssettype(old.checktypes), nil
+	}
+	state := &cacheState{
+		checktypes: byAssettype(current.checktypes),
+		source:     api.ChecktypesSourceVulcanCore,
+		updated:    current.updated,
+		err:        current.err,
+	}
+	// Only the caller that replaces the state stores the new checktypes in
+	// the database.
+	if c.state.CompareAndSwap(old, state) {
+		if serr := c.storeSnapshot(state); serr != nil {
+			stored := *state
+			stored.err = serr
+			c.state.CompareAndSwap(state, &stored)
+		}
+	}
+	return copyByAssettype(state.checktypes), nil
+}
+
+// Status returns the freshness of the checktypes for each assettype.
+func (c *CachedInformer) Status() api.ChecktypesCacheStatus {
+	state := c.state.Load()
+	status := api.ChecktypesCacheStatus{Stale: true}
+	if state == nil {
+		return status
+	}
+	updated := state.updated
+	age := c.catalogue.now().Sub(updated)
+	status.Source = state.source
+	status.UpdatedAt = &updated
+	status.AgeSeconds = int64(age.Seconds())
+	status.Stale = age >= c.catalogue.ttl
+	if state.err != nil {
+		status.LastError = state.err.Error()
+	}
+	return status
+}
+
+// loadSnapshot returns the checktypes stored in the database. The given error
+// is the one returned by the catalogue.
+func (c *CachedInformer) loadSnapshot(err error) (*cacheState, error) {
+	if c.store == nil {
+		return nil, err
+	}
+	snapshot, serr := c.store.FindChecktypesSnapshot(byAssettypeSnapshot)
+	if serr != nil {
+		return nil, serr
+	}
+	stored := map[string][]string{}
+	if serr := json.Unmarshal([]byte(snapshot.Content), &stored); serr != nil {
+		return nil, serr
+	}
+	return &cacheState{
+		checktypes: stored,
+		source:     api.ChecktypesSourceDatabase,
+		updated:    snapshot.UpdatedAt,
+		err:        err,
+	}, nil
+}
+
+// storeSnapshot stores the checktypes of the given state in the database.
+func (c *CachedInformer) storeSnapshot(state *cacheState) error {
+	if c.store == nil {
+		return nil
+	}
+	content, err := json.Marshal(state.checktypes)
+	if err != nil {
+		return err
+	}
+	return c.store.UpsertChecktypesSnapshot(api.ChecktypesSnapshot{
+		Name:      byAssettypeSnapshot,
+		Content:   string(content),
+		UpdatedAt: state.updated,
+	})
+}
+
+// byAssettype returns the names of the given checktypes that can be run
+// against each assettype.
+func byAssettype(checktypes []*api.Checktype) map[string][]string {
+	ret := map[string][]string{}
+	for _, ct := range checktypes {
+		for _, assettype := range ct.AssetTypes {
+			ret[assettype] = append(ret[assettype], ct.Name)
+		}
+	}
+	return ret
+}
+
+// copyByAssettype returns a copy of the given checktypes for each assettype,
+// so the callers can't modify the cached ones.
+func copyByAssettype(checktypes map[string][]string) map[string][]string {
+	ret := make(map[string][]string, len(checktypes))
+	for assettype, names := range checktypes {
+		ret[assettype] = append([]string{}, names...)
+	}
+	return ret
+}
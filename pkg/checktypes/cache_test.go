@@ -0,0 +1,135 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package checktypes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adevinta/vulcan-core-cli/vulcan-core/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemorySnapshotStore struct {
+	snapshots map[string]api.ChecktypesSnapshot
+}
+
+func (s *inMemorySnapshotStore) FindChecktypesSnapshot(name string) (*api.ChecktypesSnapshot, error) {
+	snapshot, ok := s.snapshots[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &snapshot, nil
+}
+
+func (s *inMemorySnapshotStore) UpsertChecktypesSnapshot(snapshot api.ChecktypesSnapshot) error {
+	s.snapshots[snapshot.Name] = snapshot
+	return nil
+}
+
+func TestCachedInformer_ByAssettype(t *testing.T) {
+	v1 := map[string][]string{"Hostname": {"vulcan-tls"}, "WebAddress": {"vulcan-zap"}}
+	v2 := map[string][]string{"Hostname": {"vulcan-nessus", "vulcan-tls"}, "WebAddress": {"vulcan-zap"}}
+	errUnavailable := errors.New("vulcan-core unavailable")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	informer := &fakeInformer{checktypes: testChecktypes}
+	catalogue := NewCatalogue(informer, CatalogueConfig{TTL: 60})
+	catalogue.now = func() time.Time { return now }
+	store := &inMemorySnapshotStore{snapshots: map[string]api.ChecktypesSnapshot{}}
+	c := NewCachedInformer(catalogue, store)
+
+	got, err := c.ByAssettype(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(v1, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+	if _, ok := store.snapshots[byAssettypeSnapshot]; !ok {
+		t.Errorf("the checktypes were not stored in the database")
+	}
+
+	// The callers get a copy of the cached checktypes.
+	got["Hostname"][0] = "modified"
+	delete(got, "WebAddress")
+	got, _ = c.ByAssettype(context.Background())
+	if diff := cmp.Diff(v1, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+
+	// The checktypes are refreshed in the background with the catalogue,
+	// and the expired ones are returned meanwhile.
+	informer.checktypes = append(testChecktypes, &client.ChecktypeType{
+		Name:   "vulcan-nessus",
+		Image:  "vulcan-nessus",
+		Assets: []string{"Hostname"},
+	})
+	now = now.Add(2 * time.Minute)
+	got, _ = c.ByAssettype(context.Background())
+	if diff := cmp.Diff(v1, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+	catalogue.refreshes.Wait()
+	got, _ = c.ByAssettype(context.Background())
+	if diff := cmp.Diff(v2, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+
+	// A failing refresh keeps the cached checktypes.
+	informer.err = errUnavailable
+	now = now.Add(2 * time.Minute)
+	c.ByAssettype(context.Background())
+	catalogue.refreshes.Wait()
+	got, err = c.ByAssettype(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(v2, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+	want := api.ChecktypesCacheStatus{
+		Source:     api.ChecktypesSourceVulcanCore,
+		UpdatedAt:  &time.Time{},
+		AgeSeconds: 120,
+		Stale:      true,
+		LastError:  errUnavailable.Error(),
+	}
+	status := c.Status()
+	*want.UpdatedAt = now.Add(-2 * time.Minute)
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Errorf("status mismatch (-want +got):\n%v", diff)
+	}
+
+	// A new informer uses the checktypes stored in the database when
+	// vulcan-core is not available.
+	catalogue = NewCatalogue(informer, CatalogueConfig{TTL: 60})
+	catalogue.now = func() time.Time { return now }
+	c = NewCachedInformer(catalogue, store)
+	got, err = c.ByAssettype(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(v2, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+	if status := c.Status(); status.Source != api.ChecktypesSourceDatabase || !status.Stale || status.LastError == "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// Without checktypes in the database the error of the catalogue is
+	// returned.
+	c = NewCachedInformer(NewCatalogue(informer, CatalogueConfig{}), &inMemorySnapshotStore{snapshots: map[string]api.ChecktypesSnapshot{}})
+	if _, err := c.ByAssettype(context.Background()); !errors.Is(err, errUnavailable) {
+		t.Errorf("error = %v, want %v", err, errUnavailable)
+	}
+	if status := c.Status(); !status.Stale || status.UpdatedAt != nil {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package checktypes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adevinta/vulcan-core-cli/vulcan-core/client"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+const (
+	defaultCatalogueTTL = 5 * time.Minute
+	// catalogueLoadTimeout is the maximum time a load of the checktypes
+	// from vulcan-core can last.
+	catalogueLoadTimeout = 30 * time.Second
+
+	// minCatalogueBackoff and maxCatalogueBackoff bound the time the
+	// catalogue waits before trying to load the checktypes again after
+	// failing to load them.
+	minCatalogueBackoff = 5 * time.Second
+	maxCatalogueBackoff = 5 * time.Minute
+)
+
+var (
+	// ErrUnknownChecktype is returned when validating the options of a
+	// checktype that is not in the catalogue.
+	ErrUnknownChecktype = errors.New("unknown checktype")
+	// ErrInvalidOptions is returned when the options of a checktype do not
+	// conform to its schema.
+	ErrInvalidOptions = errors.New("invalid checktype options")
+)
+
+// ChecktypeInformer defines the methods needed by the Catalogue to get the
+// checktypes from vulcan-core.
+type ChecktypeInformer interface {
+	IndexChecktypes(ctx context.Context, path string, enabled *string, name *string) (*http.Response, error)
+	DecodeChecktypes(resp *http.Response) (*client.Checktypes, error)
+}
+
+// CatalogueConfig defines the configuration of the checktypes catalogue.
+type CatalogueConfig struct {
+	// TTL is the number of seconds the checktypes are cached.
+	TTL int
+	// SchemasDir is a directory containing files named <checktype>.json with
+	// the JSON schema of the options of the checktype. The schema of the
+	// checktypes without a file is inferred from their default options.
+	SchemasDir string
+}
+
+// Catalogue provides the description of the enabled checktypes defined in
+// vulcan-core, including the schema of their options. The checktypes are
+// cached for the configured TTL. Once expired, the cached checktypes are
+// still returned while they are refreshed in the background. If refreshing
+// them fails, vulcan-core is not called again until a backoff that grows
+// with each failure expires.
+type Catalogue struct {
+	informer   ChecktypeInformer
+	ttl        time.Duration
+	schemasDir string
+	now        func() time.Time
+	loads      singleflight.Group
+	// refreshes tracks the refreshes running in the background.
+	refreshes sync.WaitGroup
+
+	mu         sync.Mutex
+	state      catalogueState
+	backoff    time.Duration
+	retryAt    time.Time
+	refreshing bool
+}
+
+// catalogueState contains the checktypes loaded by a Catalogue.
+type catalogueState struct {
+	checktypes []*api.Checktype
+	schemas    map[string]*Schema
+	updated    time.Time
+	// err is the error of the last load if it failed.
+	err error
+}
+
+// NewCatalogue returns a catalogue that gets the checktypes using the given
+// informer.
+func NewCatalogue(informer ChecktypeInformer, cfg CatalogueConfig) *Catalogue {
+	ttl := time.Duration(cfg.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = defaultCatalogueTTL
+	}
+	return &Catalogue{
+		informer:   informer,
+		ttl:        ttl,
+		schemasDir: cfg.SchemasDir,
+		now:        time.Now,
+	}
+}
+
+// Checktypes returns the checktypes in the catalogue sorted by name.
+func (c *Catalogue) Checktypes(ctx context.Context) ([]*api.Checktype, error) {
+	state, err := c.current(ctx)
+	return state.checktypes, err
+}
+
+// ValidateOptions returns an error wrapping ErrUnknownChecktype if the
+// checktype is not in the catalogue or ErrInvalidOptions if the options do
+// not conform to the schema of the checktype.
+func (c *Catalogue) ValidateOptions(ctx context.Context, checktype, options string) error {
+	state, err := c.current(ctx)
+	if err != nil {
+		return err
+	}
+	schema, ok := state.schemas[checktype]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownChecktype, checktype)
+	}
+	if err := schema.Validate(options); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidOptions, checktype, err)
+	}
+	return nil
+}
+
+// current returns the cached checktypes. If they are expired and the
+// catalogue is not in the backoff that follows a failure, they are refreshed
+// in the background. The callers only wait for a load of the checktypes when
+// there are no checktypes cached.
+func (c *Catalogue) current(ctx context.Context) (catalogueState, error) {
+	c.mu.Lock()
+	state := c.state
+	cached := state.checktypes != nil
+	fresh := cached && c.now().Sub(state.updated) < c.ttl
+	waiting := c.now().Before(c.retryAt)
+	if cached && !fresh && !waiting && !c.refreshing {
+		c.refreshing = true
+		c.refreshes.Add(1)
+		go c.refresh()
+	}
+	c.mu.Unlock()
+	if cached {
+		return state, nil
+	}
+	if waiting {
+		return state, state.err
+	}
+
+	err := c.reload(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.checktypes == nil {
+		return c.state, err
+	}
+	return c.state, nil
+}
+
+// refresh reloads the expired checktypes in the background.
+func (c *Catalogue) refresh() {
+	defer c.refreshes.Done()
+	_ = c.reload(context.Background())
+	c.mu.Lock()
+	c.refreshing = false
+	c.mu.Unlock()
+}
+
+// reload loads the checktypes from vulcan-core and stores them in the
+// catalogue. Only one load runs at a time, without holding the mutex, and the
+// callers that need it wait for its result.
+func (c *Catalogue) reload(ctx context.Context) error {
+	// The load is shared by all the callers waiting for it, so it must not
+	// be cancelled when the caller that started it is.
+	_, err, _ := c.loads.Do("checktypes", func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), catalogueLoadTimeout)
+		defer cancel()
+		checktypes, schemas, err := c.load(ctx)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil {
+			c.backoff *= 2
+			if c.backoff < minCatalogueBackoff {
+				c.backoff = minCatalogueBackoff
+			}
+			if c.backoff > maxCatalogueBackoff {
+				c.backoff = maxCatalogueBackoff
+			}
+			c.retryAt = c.now().Add(c.backoff)
+			c.state.err = err
+			return nil, err
+		}
+		c.state = catalogueState{checktypes: checktypes, schemas: schemas, updated: c.now()}
+		c.backoff, c.retryAt = 0, time.Time{}
+		return nil, nil
+	})
+	return err
+}
+
+func (c *Catalogue) load(ctx context.Context) ([]*api.Checktype, map[string]*Schema, error) {
+	enabled := "true"
+	resp, err := c.informer.IndexChecktypes(ctx, client.IndexChecktypesPath(), &enabled, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status listing checktypes: %s", resp.Status)
+	}
+	decoded, err := c.informer.DecodeChecktypes(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	checktypes := []*api.Checktype{}
+	schemas := map[string]*Schema{}
+	for _, ct := range decoded.Checktypes {
+		if ct == nil {
+			continue
+		}
+		schema, err := c.schema(ct)
+		if err != nil {
+			return nil, nil, fmt.Errorf("checktype %s: %w", ct.Name, err)
+		}
+		encoded, err := json.Marshal(schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		description := ""
+		if ct.Description != nil {
+			description = *ct.Description
+		}
+		assettypes := append([]string{}, ct.Assets...)
+		checktypes = append(checktypes, &api.Checktype{
+			Name:          ct.Name,
+			Description:   description,
+			Version:       imageVersion(ct.Image),
+			Image:         ct.Image,
+			AssetTypes:    assettypes,
+			Options:       ct.Options,
+			OptionsSchema: encoded,
+		})
+		schemas[ct.Name] = schema
+	}
+	sort.Slice(checktypes, func(i, j int) bool {
+		return checktypes[i].Name < checktypes[j].Name
+	})
+	return checktypes, schemas, nil
+}
+
+// schema returns the schema of the options of a checktype, reading it from
+// the schemas dir or inferring it from the default options.
+func (c *Catalogue) schema(ct *client.ChecktypeType) (*Schema, error) {
+	if c.schemasDir != "" {
+		content, err := os.ReadFile(filepath.Join(c.schemasDir, ct.Name+".json"))
+		if err == nil {
+			schema := &Schema{}
+			if err := json.Unmarshal(content, schema); err != nil {
+				return nil, fmt.Errorf("invalid options schema: %w", err)
+			}
+			return schema, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	defaults := ""
+	if ct.Options != nil {
+		defaults = *ct.Options
+	}
+	return inferSchema(defaults)
+}
+
+// imageVersion returns the tag of a docker image, or "latest" if the image
+// has no tag.
+func imageVersion(image string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		return name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[i+1:]
+	}
+	return "latest"
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package checktypes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adevinta/vulcan-core-cli/vulcan-core/client"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type fakeInformer struct {
+	checktypes []*client.ChecktypeType
+	err        error
+	calls      int
+}
+
+func (f *fakeInformer) IndexChecktypes(ctx context.Context, path string, enabled *string, name *string) (*http.Response, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (f *fakeInformer) DecodeChecktypes(resp *http.Response) (*client.Checktypes, error) {
+	return &client.Checktypes{Checktypes: f.checktypes}, nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+var testChecktypes = []*client.ChecktypeType{
+	{
+		Name:        "vulcan-zap",
+		Description: strPtr("Runs ZAP"),
+		Image:       "registry.example.com/vulcan/zap:1.2",
+		Assets:      []string{"WebAddress"},
+		Options:     strPtr(`{"depth": 2, "active": false, "paths": ["/"]}`),
+	},
+	{
+		Name:   "vulcan-tls",
+		Image:  "localhost:5000/vulcan-tls",
+		Assets: []string{"Hostname"},
+	},
+}
+
+func TestCatalogue_Checktypes(t *testing.T) {
+	informer := &fakeInformer{checktypes: testChecktypes}
+	c := NewCatalogue(informer, CatalogueConfig{TTL: 60})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	got, err := c.Checktypes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*api.Checktype{
+		{
+			Name:          "vulcan-tls",
+			Version:       "latest",
+			Image:         "localhost:5000/vulcan-tls",
+			AssetTypes:    []string{"Hostname"},
+			OptionsSchema: []byte(`{"type":"object"}`),
+		},
+		{
+			Name:          "vulcan-zap",
+			Description:   "Runs ZAP",
+			Version:       "1.2",
+			Image:         "registry.example.com/vulcan/zap:1.2",
+			AssetTypes:    []string{"WebAddress"},
+			Options:       strPtr(`{"depth": 2, "active": false, "paths": ["/"]}`),
+			OptionsSchema: []byte(`{"type":"object","properties":{"active":{"type":"boolean"},"depth":{"type":"number"},"paths":{"type":"array","items":{"type":"string"}}}}`),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("checktypes mismatch (-want +got):\n%v", diff)
+	}
+
+	// The checktypes are cached until the TTL expires, and the cached ones
+	// are returned while they are refreshed and if refreshing them fails.
+	informer.err = errors.New("vulcan-core down")
+	if _, err := c.Checktypes(context.Background()); err != nil || informer.calls != 1 {
+		t.Errorf("cached checktypes: err = %v, calls = %d, want nil, 1", err, informer.calls)
+	}
+	now = now.Add(2 * time.Minute)
+	_, err = c.Checktypes(context.Background())
+	c.refreshes.Wait()
+	if err != nil || informer.calls != 2 {
+		t.Errorf("stale checktypes: err = %v, calls = %d, want nil, 2", err, informer.calls)
+	}
+	// After a failure vulcan-core is not called again until the backoff
+	// expires.
+	if _, err := c.Checktypes(context.Background()); err != nil || informer.calls != 2 {
+		t.Errorf("backoff checktypes: err = %v, calls = %d, want nil, 2", err, informer.calls)
+	}
+	now = now.Add(minCatalogueBackoff)
+	_, err = c.Checktypes(context.Background())
+	c.refreshes.Wait()
+	if err != nil || informer.calls != 3 {
+		t.Errorf("retried checktypes: err = %v, calls = %d, want nil, 3", err, informer.calls)
+	}
+
+	empty := NewCatalogue(informer, CatalogueConfig{})
+	empty.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if _, err := empty.Checktypes(context.Background()); err == nil {
+			t.Errorf("expected an error with vulcan-core down and no cached checktypes")
+		}
+	}
+	if informer.calls != 4 {
+		t.Errorf("got %d calls with no cached checktypes, want 4", informer.calls)
+	}
+}
+
+// slowInformer is a fakeInformer that, once blocked, does not answer until
+// it is released.
+type slowInformer struct {
+	*fakeInformer
+	release chan struct{}
+}
+
+func (s *slowInformer) IndexChecktypes(ctx context.Context, path string, enabled *string, name *string) (*http.Response, error) {
+	if s.release != nil {
+		<-s.release
+	}
+	return s.fakeInformer.IndexChecktypes(ctx, path, enabled, name)
+}
+
+func TestCatalogue_ChecktypesStale(t *testing.T) {
+	informer := &slowInformer{fakeInformer: &fakeInformer{checktypes: testChecktypes}}
+	c := NewCatalogue(informer, CatalogueConfig{TTL: 60})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	if _, err := c.Checktypes(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A read of the expired checktypes does not wait for vulcan-core.
+	informer.release = make(chan struct{})
+	now = now.Add(2 * time.Minute)
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Checktypes(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the read of the stale checktypes waited for vulcan-core")
+	}
+	close(informer.release)
+	c.refreshes.Wait()
+	if informer.calls != 2 {
+		t.Errorf("got %d calls, want 2", informer.calls)
+	}
+	c.mu.Lock()
+	updated := c.state.updated
+	c.mu.Unlock()
+	if !updated.Equal(now) {
+		t.Errorf("checktypes updated at %v, want %v", updated, now)
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	tests := map[string]string{
+		`{"paths": ["/a", "/b"]}`:               `{"type":"object","properties":{"paths":{"type":"array","items":{"type":"string"}}}}`,
+		`{"ports": [80, "443"]}`:                `{"type":"object","properties":{"ports":{"type":"array"}}}`,
+		`{"rules": [{"id": 1}, {"id": 2}]}`:     `{"type":"object","properties":{"rules":{"type":"array","items":{"type":"object","properties":{"id":{"type":"number"}}}}}}`,
+		`{"rules": [{"id": 1}, {"name": "a"}]}`: `{"type":"object","properties":{"rules":{"type":"array"}}}`,
+	}
+	for defaults, want := range tests {
+		schema, err := inferSchema(defaults)
+		if err != nil {
+			t.Fatalf("inferSchema(%s) error = %v", defaults, err)
+		}
+		got, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("inferSchema(%s) = %s, want %s", defaults, got, want)
+		}
+	}
+}
+
+func TestCatalogue_ValidateOptions(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{"type":"object","required":["port"],"properties":{"port":{"type":"integer","minimum":1,"maximum":65535}}}`
+	if err := os.WriteFile(filepath.Join(dir, "vulcan-tls.json"), []byte(schema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCatalogue(&fakeInformer{checktypes: testChecktypes}, CatalogueConfig{SchemasDir: dir})
+
+	tests := []struct {
+		name      string
+		checktype string
+		options   string
+		wantErr   error
+	}{
+		{name: "EmptyOptions", checktype: "vulcan-zap", options: ""},
+		{name: "InferredSchema", checktype: "vulcan-zap", options: `{"depth": 5, "paths": ["/a", "/b"]}`},
+		{name: "WrongType", checktype: "vulcan-zap", options: `{"depth": "5"}`, wantErr: ErrInvalidOptions},
+		{name: "WrongItemType", checktype: "vulcan-zap", options: `{"paths": [1]}`, wantErr: ErrInvalidOptions},
+		{name: "OptionWithoutDefault", checktype: "vulcan-zap", options: `{"deep": 5}`},
+		{name: "InvalidJSON", checktype: "vulcan-zap", options: `{`, wantErr: ErrInvalidOptions},
+		{name: "SchemaFile", checktype: "vulcan-tls", options: `{"port": 443, "other": true}`},
+		{name: "SchemaFileRequired", checktype: "vulcan-tls", options: `{}`, wantErr: ErrInvalidOptions},
+		{name: "SchemaFileNotInteger", checktype: "vulcan-tls", options: `{"port": 44.3}`, wantErr: ErrInvalidOptions},
+		{name: "SchemaFileMaximum", checktype: "vulcan-tls", options: `{"port": 70000}`, wantErr: ErrInvalidOptions},
+		{name: "UnknownChecktype", checktype: "vulcan-unknown", options: `{}`, wantErr: ErrUnknownChecktype},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.ValidateOptions(context.Background(), tt.checktype, tt.options)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("ValidateOptions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageVersion(t *testing.T) {
+	tests := map[string]string{
+		"vulcan/zap:1.2":                  "1.2",
+		"vulcan/zap":                      "latest",
+		"localhost:5000/vulcan/zap":       "latest",
+		"localhost:5000/vulcan/zap:edge":  "edge",
+		"vulcan/zap@sha256:0123456789abc": "sha256:0123456789abc",
+	}
+	for image, want := range tests {
+		if got := imageVersion(image); got != want {
+			t.Errorf("imageVersion(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package checktypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Schema is the subset of JSON schema used to describe the options of a
+// checktype. It supports the keywords type, properties, required,
+// additionalProperties, items, enum, minimum and maximum.
+type Schema struct {
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// inferSchema returns the schema of the options of a checktype inferred from
+// its default options: the options must be an object, and the keys present in
+// the default options must have values of the same type. Other keys are
+// accepted, as the checktypes can have options without a default.
+func inferSchema(defaults string) (*Schema, error) {
+	if defaults == "" {
+		defaults = "{}"
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(defaults), &v); err != nil {
+		return nil, fmt.Errorf("invalid default options: %w", err)
+	}
+	s := inferValueSchema(v)
+	if s.Type != "object" {
+		return nil, fmt.Errorf("default options are not an object")
+	}
+	return s, nil
+}
+
+func inferValueSchema(v interface{}) *Schema {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		s := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{},
+		}
+		for k, pv := range v {
+			s.Properties[k] = inferValueSchema(pv)
+		}
+		return s
+	case []interface{}:
+		// The items are only constrained if all the default ones have the
+		// same schema.
+		s := &Schema{Type: "array"}
+		for i, item := range v {
+			is := inferValueSchema(item)
+			if i > 0 && !reflect.DeepEqual(is, s.Items) {
+				s.Items = nil
+				break
+			}
+			s.Items = is
+		}
+		return s
+	case string:
+		return &Schema{Type: "string"}
+	case float64:
+		return &Schema{Type: "number"}
+	case bool:
+		return &Schema{Type: "boolean"}
+	}
+	// The type of null defaults is unknown so any value is accepted.
+	return &Schema{}
+}
+
+// Validate returns an error if the given options, encoded in JSON, do not
+// conform to the schema.
+func (s *Schema) Validate(options string) error {
+	if options == "" {
+		options = "{}"
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(options), &v); err != nil {
+		return fmt.Errorf("options are not valid JSON: %w", err)
+	}
+	return s.validate("options", v)
+}
+
+func (s *Schema) validate(path string, v interface{}) error {
+	if s.Type != "" && !hasType(v, s.Type) {
+		return fmt.Errorf("%s must be of type %s", path, s.Type)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %v", path, s.Enum)
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, r := range s.Required {
+			if _, ok := v[r]; !ok {
+				return fmt.Errorf("%s.%s is required", path, r)
+			}
+		}
+		keys := []string{}
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ps, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s.%s is not a valid option", path, k)
+				}
+				continue
+			}
+			if err := ps.validate(path+"."+k, v[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fmt.Errorf("%s must be greater than or equal to %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fmt.Errorf("%s must be less than or equal to %v", path, *s.Maximum)
+		}
+	}
+	return nil
+}
+
+func hasType(v interface{}, t string) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return t == "object"
+	case []interface{}:
+		return t == "array"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || (t == "integer" && v == float64(int64(v)))
+	case bool:
+		return t == "boolean"
+	case nil:
+		return t == "null"
+	}
+	return false
+}
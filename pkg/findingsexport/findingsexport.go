@@ -0,0 +1,307 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package findingsexport encodes the findings of the teams in the formats
+// supported by the findings exports. The findings are written as they are
+// received, so the exports can be streamed while the findings are read from
+// the vulnerability db.
+package findingsexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// Writer writes findings in a given format.
+type Writer interface {
+	// Write writes a finding.
+	Write(f api.ScoredFinding) error
+	// Close writes the data needed to complete the export, if any. It does
+	// not close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a Writer that writes findings to w in the given format.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case api.FindingsExportSARIF:
+		return newSARIFWriter(w), nil
+	case api.FindingsExportCSV:
+		return newCSVWriter(w), nil
+	case api.FindingsExportNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, api.ErrInvalidFindingsExportFormat
+	}
+}
+
+// ndjsonWriter writes every finding as a JSON document in its own line.
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(f api.ScoredFinding) error {
+	return w.enc.Encode(f)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+var csvHeader = []string{
+	"id", "issue_id", "summary", "target", "affected_resource", "status",
+	"score", "severity", "risk_score", "total_exposure", "labels", "source",
+}
+
+// csvWriter writes a header and a row per finding.
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(f api.ScoredFinding) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	return w.w.Write([]string{
+		f.ID,
+		f.Issue.ID,
+		f.Issue.Summary,
+		f.Target.Identifier,
+		f.AffectedResource,
+		f.Status,
+		formatScore(f.Score),
+		api.SeverityName(f.Score),
+		formatScore(f.RiskScore),
+		strconv.FormatInt(f.TotalExposure, 10),
+		strings.Join(f.Issue.Labels, ";"),
+		f.Source.Name,
+	})
+}
+
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func formatScore(score float32) string {
+	return strconv.FormatFloat(float64(score), 'f', -1, 32)
+}
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags,omitempty"`
+	// SecuritySeverity is the property used by the code scanning tools to
+	// get the severity of the security issues.
+	SecuritySeverity string `json:"security-severity"`
+}
+
+type sarifRule struct {
+	ID               string              `json:"id"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	FullDescription  *sarifMessage       `json:"fullDescription,omitempty"`
+	Help             *sarifMessage       `json:"help,omitempty"`
+	HelpURI          string              `json:"helpUri,omitempty"`
+	Properties       sarifRuleProperties `json:"properties"`
+
+	score float32
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+// sarifWriter writes a SARIF 2.1.0 log with one run. The results are written
+// as they are received, and the rules, one per issue, are written after them
+// when the writer is closed.
+type sarifWriter struct {
+	w       io.Writer
+	rules   []*sarifRule
+	ruleIDs map[string]*sarifRule
+	started bool
+	results int
+}
+
+func newSARIFWriter(w io.Writer) *sarifWriter {
+	return &sarifWriter{w: w, ruleIDs: map[string]*sarifRule{}}
+}
+
+func (w *sarifWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := fmt.Fprintf(w.w, `{"$schema":%q,"version":"2.1.0","runs":[{"results":[`, sarifSchema)
+	return err
+}
+
+func (w *sarifWriter) Write(f api.ScoredFinding) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.addRule(f)
+
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(f.Target.Identifier)}},
+		LogicalLocations: []sarifLogicalLocation{{Name: f.Target.Identifier, Kind: "target"}},
+	}
+	if f.AffectedResource != "" {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{Name: f.AffectedResource, Kind: "resource"})
+	}
+	message := f.Issue.Summary
+	if f.Details != "" {
+		message = fmt.Sprintf("%s\n\n%s", message, f.Details)
+	}
+	result := sarifResult{
+		RuleID:              f.Issue.ID,
+		Level:               sarifLevel(f.Score),
+		Message:             sarifMessage{Text: message},
+		Locations:           []sarifLocation{location},
+		PartialFingerprints: map[string]string{"vulcanFindingId": f.ID},
+		Properties: map[string]interface{}{
+			"status":     f.Status,
+			"score":      f.Score,
+			"risk_score": f.RiskScore,
+		},
+	}
+	content, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if w.results > 0 {
+		if _, err := io.WriteString(w.w, ","); err != nil {
+			return err
+		}
+	}
+	w.results++
+	_, err = w.w.Write(content)
+	return err
+}
+
+// sarifURI returns the identifier of a target as a URI reference, as required
+// by the artifact locations. The identifiers that are absolute URLs are kept,
+// and the rest, like hostnames, ARNs or Docker images, are encoded as a
+// relative reference of one segment.
+func sarifURI(identifier string) string {
+	if u, err := url.Parse(identifier); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.String()
+	}
+	// A colon in the first segment of a relative reference would be parsed
+	// as the end of a scheme.
+	return strings.ReplaceAll(url.PathEscape(identifier), ":", "%3A")
+}
+
+// addRule adds the issue of a finding to the rules of the run. The severity
+// of a rule is the one of the finding of the issue with the highest score.
+func (w *sarifWriter) addRule(f api.ScoredFinding) {
+	rule, ok := w.ruleIDs[f.Issue.ID]
+	if !ok {
+		rule = &sarifRule{
+			ID:               f.Issue.ID,
+			ShortDescription: sarifMessage{Text: f.Issue.Summary},
+			Properties:       sarifRuleProperties{Tags: append([]string{"security"}, f.Issue.Labels...)},
+			score:            -1,
+		}
+		if f.Issue.Description != "" {
+			rule.FullDescription = &sarifMessage{Text: f.Issue.Description}
+		}
+		if len(f.Issue.Recommendations) > 0 {
+			rule.Help = &sarifMessage{Text: strings.Join(f.Issue.Recommendations, "\n")}
+		}
+		if len(f.Issue.ReferenceLinks) > 0 {
+			rule.HelpURI = f.Issue.ReferenceLinks[0]
+		}
+		w.rules = append(w.rules, rule)
+		w.ruleIDs[f.Issue.ID] = rule
+	}
+	if f.Score > rule.score {
+		rule.score = f.Score
+		rule.Properties.SecuritySeverity = formatScore(f.Score)
+	}
+}
+
+func (w *sarifWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	tool, err := json.Marshal(sarifTool{Driver: sarifDriver{
+		Name:           "Vulcan",
+		InformationURI: "https://github.com/adevinta/vulcan-api",
+		Rules:          append([]*sarifRule{}, w.rules...),
+	}})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w.w, `],"tool":%s}]}`, tool)
+	return err
+}
+
+// sarifLevel returns the SARIF level of a finding with the given score.
+func sarifLevel(score float32) string {
+	switch api.SeverityName(score) {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package findingsexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+func newFinding(id, issueID, target string, score, riskScore float32) api.ScoredFinding {
+	return api.ScoredFinding{
+		FindingExpanded: vulndb.FindingExpanded{
+			Finding: vulndb.Finding{ID: id, Status: "OPEN", Score: score, TotalExposure: 48},
+			Issue:   vulndb.Issue{ID: issueID, Summary: "Summary " + issueID, Labels: []string{"web", "ssl"}},
+			Target:  vulndb.Target{Identifier: target},
+			Source:  vulndb.Source{Name: "vulcan-tls"},
+		},
+		RiskScore: riskScore,
+	}
+}
+
+var testFindings = []api.ScoredFinding{
+	newFinding("f1", "i1", "a.example.com", 9.1, 10),
+	newFinding("f2", "i2", "b.example.com", 5, 4),
+	newFinding("f3", "i1", "b.example.com", 7.5, 9),
+}
+
+func writeAll(t *testing.T, format string, findings []api.ScoredFinding) string {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, f := range findings {
+		if err := w.Write(f); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []api.ScoredFinding
+		want     string
+	}{
+		{
+			name:     "Findings",
+			findings: testFindings[:2],
+			want: "id,issue_id,summary,target,affected_resource,status,score,severity,risk_score,total_exposure,labels,source\n" +
+				"f1,i1,Summary i1,a.example.com,,OPEN,9.1,critical,10,48,web;ssl,vulcan-tls\n" +
+				"f2,i2,Summary i2,b.example.com,,OPEN,5,medium,4,48,web;ssl,vulcan-tls\n",
+		},
+		{
+			name: "NoFindings",
+			want: "id,issue_id,summary,target,affected_resource,status,score,severity,risk_score,total_exposure,labels,source\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := writeAll(t, api.FindingsExportCSV, tt.findings)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("csv mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	got := writeAll(t, api.FindingsExportNDJSON, testFindings)
+	lines := bytes.Split(bytes.TrimSpace([]byte(got)), []byte("\n"))
+	if len(lines) != len(testFindings) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(testFindings))
+	}
+	for i, l := range lines {
+		var f api.ScoredFinding
+		if err := json.Unmarshal(l, &f); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if diff := cmp.Diff(testFindings[i], f); diff != "" {
+			t.Errorf("finding %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestSARIFWriter(t *testing.T) {
+	type sarifLog struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []sarifResult `json:"results"`
+			Tool    struct {
+				Driver struct {
+					Rules []sarifRule `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	tests := []struct {
+		name        string
+		findings    []api.ScoredFinding
+		wantResults []string
+		wantLevels  []string
+		wantRules   map[string]string
+	}{
+		{
+			name:        "Findings",
+			findings:    testFindings,
+			wantResults: []string{"f1", "f2", "f3"},
+			wantLevels:  []string{"error", "warning", "error"},
+			wantRules:   map[string]string{"i1": "9.1", "i2": "5"},
+		},
+		{
+			name:        "NoFindings",
+			wantResults: []string{},
+			wantLevels:  []string{},
+			wantRules:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log sarifLog
+			if err := json.Unmarshal([]byte(writeAll(t, api.FindingsExportSARIF, tt.findings)), &log); err != nil {
+				t.Fatalf("invalid SARIF log: %v", err)
+			}
+			if log.Version != "2.1.0" || len(log.Runs) != 1 {
+				t.Fatalf("unexpected SARIF log: version %q, %d runs", log.Version, len(log.Runs))
+			}
+			results, levels := []string{}, []string{}
+			for _, r := range log.Runs[0].Results {
+				results = append(results, r.PartialFingerprints["vulcanFindingId"])
+				levels = append(levels, r.Level)
+			}
+			rules := map[string]string{}
+			for _, r := range log.Runs[0].Tool.Driver.Rules {
+				rules[r.ID] = r.Properties.SecuritySeverity
+			}
+			if diff := cmp.Diff(tt.wantResults, results); diff != "" {
+				t.Errorf("results mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantLevels, levels); diff != "" {
+				t.Errorf("levels mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRules, rules); diff != "" {
+				t.Errorf("rules mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSARIFURI(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       string
+	}{
+		{identifier: "example.com", want: "example.com"},
+		{identifier: "https://example.com/login?next=/", want: "https://example.com/login?next=/"},
+		{identifier: "arn:aws:iam::123456789012:root", want: "arn%3Aaws%3Aiam%3A%3A123456789012%3Aroot"},
+		{identifier: "registry.example.com/team/image:1.0", want: "registry.example.com%2Fteam%2Fimage%3A1.0"},
+		{identifier: "10.0.0.0/24", want: "10.0.0.0%2F24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			got := sarifURI(tt.identifier)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("invalid URI reference: %v", err)
+			}
+			if u.Host == "" && u.Scheme != "" {
+				t.Errorf("relative reference %q parsed with scheme %q", got, u.Scheme)
+			}
+		})
+	}
+}
+
+func TestNewWriterInvalidFormat(t *testing.T) {
+	if _, err := NewWriter("xml", &bytes.Buffer{}); err != api.ErrInvalidFindingsExportFormat {
+		t.Errorf("got error %v, want %v", err, api.ErrInvalidFindingsExportFormat)
+	}
+}
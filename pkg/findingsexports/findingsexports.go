@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package findingsexports generates the findings exports requested
+// asynchronously by the teams and deletes the old ones.
+package findingsexports
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const (
+	defaultPollInterval = 10
+	defaultRetention    = 168
+
+	// staleClaim is the time after which an export that is still running
+	// is considered abandoned by the instance that claimed it, so it can be
+	// claimed again.
+	staleClaim = time.Hour
+)
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the pending exports.
+	PollInterval int `mapstructure:"poll_interval"`
+	// Retention is the number of hours the exports are kept.
+	Retention int `mapstructure:"retention"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ClaimFindingsExport(staleBefore time.Time) (*api.FindingsExport, error)
+	DeleteFindingsExports(createdBefore time.Time) (int, error)
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	GenerateFindingsExport(ctx context.Context, export api.FindingsExport) error
+}
+
+// Runner periodically generates the pending exports one at a time.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Run generates the pending exports until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, func(ctx context.Context) {
+		r.deleteExpired()
+		r.generate(ctx)
+	})
+}
+
+// generate generates the pending exports until there are none left.
+func (r *Runner) generate(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := r.store.ClaimFindingsExport(time.Now().Add(-staleClaim))
+		if err != nil {
+			_ = level.Error(r.logger).Log("FindingsExports", "error claiming findings export", "err", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+		if err := r.service.GenerateFindingsExport(ctx, *export); err != nil {
+			_ = level.Error(r.logger).Log("FindingsExports", "error generating findings export", "TeamID", export.TeamID, "ExportID", export.ID, "err", err)
+		}
+	}
+}
+
+func (r *Runner) deleteExpired() {
+	retention := r.cfg.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	n, err := r.store.DeleteFindingsExports(time.Now().Add(-time.Duration(retention) * time.Hour))
+	if err != nil {
+		_ = level.Error(r.logger).Log("FindingsExports", "error deleting expired findings exports", "err", err)
+		return
+	}
+	if n > 0 {
+		_ = level.Info(r.logger).Log("FindingsExports", "expired findings exports deleted", "Exports", n)
+	}
+}
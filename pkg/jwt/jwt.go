@@ -54,3 +54,16 @@ func (c Config) ValidateToken(tokenString string) error {
 	}
 	return ErrTokenInvalid
 }
+
+// ParseToken validates the given token and returns its claims.
+func (c Config) ParseToken(tokenString string) (map[string]interface{}, error) {
+	token, err := libjwt.Parse(tokenString, c.KeyFunc)
+	if err != nil || !token.Valid {
+		return nil, ErrTokenInvalid
+	}
+	claims, ok := token.Claims.(libjwt.MapClaims)
+	if !ok {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
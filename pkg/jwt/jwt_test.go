@@ -113,3 +113,37 @@ func TestValidateToken(t *testing.T) {
 		}
 	})
 }
+
+func TestParseToken(t *testing.T) {
+	jwtx := NewJWTConfig("secret")
+	t.Run("valid token", func(t *testing.T) {
+		token, err := jwtx.GenerateToken(map[string]interface{}{
+			"type":  "RecipientUnsubscribe",
+			"email": "email",
+		})
+		if err != nil {
+			t.Fatalf("expected no error generating token but got: %v", err)
+		}
+
+		claims, err := jwtx.ParseToken(token)
+		if err != nil {
+			t.Fatalf("expected no error parsing token but got: %v", err)
+		}
+		want := map[string]interface{}{"type": "RecipientUnsubscribe", "email": "email"}
+		if !reflect.DeepEqual(claims, want) {
+			t.Fatalf("expected claims %v but got %v", want, claims)
+		}
+	})
+
+	t.Run("token signed with other key", func(t *testing.T) {
+		token, err := NewJWTConfig("other").GenerateToken(map[string]interface{}{"email": "email"})
+		if err != nil {
+			t.Fatalf("expected no error generating token but got: %v", err)
+		}
+
+		if _, err := jwtx.ParseToken(token); !errors.Is(err, ErrTokenInvalid) {
+			t.Fatalf("expected error parsing token to be %v but got: %v",
+				ErrTokenInvalid, err)
+		}
+	})
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package notifications evaluates the notification rules of the programs
+// when their scans finish and sends the corresponding notifications.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+)
+
+const (
+	// scanStatusFinished is the status reported by the scan engine for the
+	// scans that have all their checks in a terminal status.
+	scanStatusFinished = "FINISHED"
+
+	// dayFmt is the format of the dates the findings of the scans are
+	// compared at in the vulnerability db.
+	dayFmt = "2006-01-02"
+
+	defaultSendTimeout = 10 * time.Second
+)
+
+// failedCheckStatuses contains the terminal statuses of a check that mean
+// the check could not be run properly.
+var failedCheckStatuses = map[string]bool{
+	"FAILED":    true,
+	"MALFORMED": true,
+	"ABORTED":   true,
+	"KILLED":    true,
+	"TIMEOUT":   true,
+}
+
+// Store defines the store methods needed by the Notifier.
+type Store interface {
+	ListNotificationRules(teamID, programID string) ([]*api.NotificationRule, error)
+}
+
+// Service defines the service methods needed by the Notifier. It must be the
+// service with all its middlewares so the scans of global programs can be
+// resolved.
+type Service interface {
+	FindTeam(ctx context.Context, teamID string) (*api.Team, error)
+	ListReportRecipients(ctx context.Context, teamID, reportType string) ([]*api.ReportRecipient, error)
+	FindScan(ctx context.Context, scanID, teamID string) (*api.Scan, error)
+	ListScans(ctx context.Context, teamID string, programID string) ([]*api.Scan, error)
+	DiffScans(ctx context.Context, teamID string, from, to *api.Scan) (*api.ScanDiff, error)
+}
+
+// EmailSender sends a notification by email to the given recipients of a
+// team.
+type EmailSender interface {
+	SendScanNotification(teamID, teamName string, recipients []reports.Recipient, notification interface{}) error
+}
+
+// Notification contains the information sent through the channels of a
+// notification rule.
+type Notification struct {
+	Trigger      api.NotificationTrigger `json:"trigger"`
+	TeamID       string                  `json:"team_id"`
+	TeamName     string                  `json:"team_name"`
+	ProgramID    string                  `json:"program_id"`
+	ProgramName  string                  `json:"program_name"`
+	ScanID       string                  `json:"scan_id"`
+	StartTime    *time.Time              `json:"start_time"`
+	EndTime      *time.Time              `json:"end_time"`
+	CheckCount   int                     `json:"check_count"`
+	FailedChecks int                     `json:"failed_checks"`
+	NewFindings  []Finding               `json:"new_findings,omitempty"`
+}
+
+// Finding summarizes a new finding detected by a scan.
+type Finding struct {
+	ID      string  `json:"id"`
+	Summary string  `json:"summary"`
+	Target  string  `json:"target"`
+	Score   float32 `json:"score"`
+}
+
+// Notifier processes the scan events sent by the scan engine.
+type Notifier struct {
+	store            Store
+	service          Service
+	email            EmailSender
+	scanEngineClient scanengine.Client
+	httpClient       *http.Client
+	logger           log.Logger
+}
+
+// New returns a Notifier. The email sender can be nil, in which case the
+// rules using the email channel are ignored.
+func New(logger log.Logger, store Store, service Service, email EmailSender, scanEngineClient scanengine.Client) *Notifier {
+	return &Notifier{
+		store:            store,
+		service:          service,
+		email:            email,
+		scanEngineClient: scanEngineClient,
+		httpClient:       newHTTPClient(),
+		logger:           logger,
+	}
+}
+
+// scanResult holds the information about a finished scan shared by all the
+// rules of a team.
+type scanResult struct {
+	team         *api.Team
+	scan         *api.Scan
+	failedChecks *int
+	diff         *api.ScanDiff
+	diffDone     bool
+}
+
+// HandleScanEvent evaluates the notification rules of the program of a scan
+// when the scan finishes. Errors sending the notifications are logged but
+// not returned, so the event is not processed again and the notifications
+// already sent are not duplicated.
+//
+// The scan engine only reports the RUNNING and FINISHED statuses for a scan,
+// so there is no event for a scan that fails as a whole: a scan that could
+// not be run properly, including an aborted one, finishes with its checks in
+// a failed status and is notified by the FAILED trigger.
+func (n *Notifier) HandleScanEvent(ctx context.Context, event scanengineapi.ScanNotification) error {
+	if event.Status != scanStatusFinished {
+		return nil
+	}
+	teamID, programID := decodeProgramID(event.ProgramID)
+	rules, err := n.store.ListNotificationRules(teamID, programID)
+	if err != nil {
+		return err
+	}
+	results := map[string]*scanResult{}
+	for _, rule := range rules {
+		if rule.IsDisabled() {
+			continue
+		}
+		res, ok := results[rule.TeamID]
+		if !ok {
+			res, err = n.scanResult(ctx, rule.TeamID, event.ScanID)
+			if err != nil {
+				return err
+			}
+			results[rule.TeamID] = res
+		}
+		notification, err := n.evaluate(ctx, *rule, res)
+		if err != nil {
+			_ = level.Error(n.logger).Log("Notifications", "error evaluating rule", "RuleID", rule.ID, "ScanID", event.ScanID, "err", err)
+			continue
+		}
+		if notification == nil {
+			continue
+		}
+		if err := n.send(ctx, *rule, *notification); err != nil {
+			_ = level.Error(n.logger).Log("Notifications", "error sending notification", "RuleID", rule.ID, "ScanID", event.ScanID, "err", err)
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) scanResult(ctx context.Context, teamID, scanID string) (*scanResult, error) {
+	team, err := n.service.FindTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	scan, err := n.service.FindScan(ctx, scanID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return &scanResult{team: team, scan: scan}, nil
+}
+
+// evaluate returns the notification to send for the given rule, or nil if the
+// scan does not match the rule.
+func (n *Notifier) evaluate(ctx context.Context, rule api.NotificationRule, res *scanResult) (*Notification, error) {
+	notification := newNotification(rule, res.team, res.scan)
+	switch rule.Trigger {
+	case api.NotificationTriggerCompleted:
+		return notification, nil
+	case api.NotificationTriggerFailed:
+		if res.failedChecks == nil {
+			failed, err := n.failedChecks(ctx, res.scan.ID)
+			if err != nil {
+				return nil, err
+			}
+			res.failedChecks = &failed
+		}
+		if *res.failedChecks == 0 {
+			return nil, nil
+		}
+		notification.FailedChecks = *res.failedChecks
+		return notification, nil
+	case api.NotificationTriggerNewFindings:
+		if !res.diffDone {
+			diff, err := n.diff(ctx, res.team.ID, res.scan)
+			if err != nil {
+				return nil, err
+			}
+			res.diff, res.diffDone = diff, true
+		}
+		if res.diff == nil {
+			return nil, nil
+		}
+		for _, f := range res.diff.NewFindings {
+			if !rule.MatchesSeverity(f.Score) {
+				continue
+			}
+			notification.NewFindings = append(notification.NewFindings, Finding{
+				ID:      f.ID,
+				Summary: f.Issue.Summary,
+				Target:  f.Target.Identifier,
+				Score:   f.Score,
+			})
+		}
+		if len(notification.NewFindings) == 0 {
+			return nil, nil
+		}
+		return notification, nil
+	}
+	return nil, fmt.Errorf("%w: %s", api.ErrInvalidNotificationTrigger, rule.Trigger)
+}
+
+func (n *Notifier) failedChecks(ctx context.Context, scanID string) (int, error) {
+	resp, err := n.scanEngineClient.GetScanChecks(ctx, scanID)
+	if err != nil {
+		return 0, err
+	}
+	failed := 0
+	for _, c := range resp.Checks {
+		if failedCheckStatuses[c.Status] {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// diff returns the differences between the given scan and the previous
+// finished scan of the same program. It returns nil if there is no previous
+// scan, because the first scan of a program only establishes the baseline.
+func (n *Notifier) diff(ctx context.Context, teamID string, scan *api.Scan) (*api.ScanDiff, error) {
+	scans, err := n.service.ListScans(ctx, teamID, scan.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	previous := previousScan(scans, scan)
+	if previous == nil {
+		return nil, nil
+	}
+	return n.service.DiffScans(ctx, teamID, previous, scan)
+}
+
+// previousScan returns the latest finished scan that finished a day before
+// the given one, as the scans finished the same day can not be compared.
+func previousScan(scans []*api.Scan, scan *api.Scan) *api.Scan {
+	if scan.EndTime == nil {
+		return nil
+	}
+	day := scan.EndTime.Format(dayFmt)
+	candidates := []*api.Scan{}
+	for _, s := range scans {
+		if s.ID == scan.ID || s.Status != scanStatusFinished || s.EndTime == nil {
+			continue
+		}
+		if s.EndTime.Format(dayFmt) < day {
+			candidates = append(candidates, s)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].EndTime.After(*candidates[j].EndTime)
+	})
+	return candidates[0]
+}
+
+func newNotification(rule api.NotificationRule, team *api.Team, scan *api.Scan) *Notification {
+	notification := &Notification{
+		Trigger:   rule.Trigger,
+		TeamID:    team.ID,
+		TeamName:  team.Name,
+		ProgramID: scan.ProgramID,
+		ScanID:    scan.ID,
+		StartTime: scan.StartTime,
+		EndTime:   scan.EndTime,
+	}
+	if scan.Program != nil {
+		notification.ProgramName = scan.Program.Name
+	}
+	if scan.CheckCount != nil {
+		notification.CheckCount = *scan.CheckCount
+	}
+	return notification
+}
+
+// decodeProgramID returns the team and the program from the program ID
+// received in a scan event, which for global programs has the form
+// teamID@programID. For the rest of programs the team is empty.
+func decodeProgramID(id string) (teamID, programID string) {
+	if i := strings.Index(id, "@"); (i > 0) && (i < len(id)-1) {
+		return id[:i], id[i+1:]
+	}
+	return "", id
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
+	"github.com/adevinta/vulcan-api/pkg/scanengine/scanenginetest"
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+	scanengineData "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+type inMemoryStore struct {
+	rules []*api.NotificationRule
+}
+
+func (s inMemoryStore) ListNotificationRules(teamID, programID string) ([]*api.NotificationRule, error) {
+	rules := []*api.NotificationRule{}
+	for _, r := range s.rules {
+		if r.ProgramID == programID && (teamID == "" || r.TeamID == teamID) {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+type inMemoryService struct {
+	scans []*api.Scan
+	diff  *api.ScanDiff
+}
+
+func (s inMemoryService) FindTeam(ctx context.Context, teamID string) (*api.Team, error) {
+	return &api.Team{ID: teamID, Name: "Team " + teamID}, nil
+}
+
+func (s inMemoryService) ListReportRecipients(ctx context.Context, teamID, reportType string) ([]*api.ReportRecipient, error) {
+	return []*api.ReportRecipient{{Email: "security@example.com"}}, nil
+}
+
+func (s inMemoryService) FindScan(ctx context.Context, scanID, teamID string) (*api.Scan, error) {
+	for _, scan := range s.scans {
+		if scan.ID == scanID {
+			return scan, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s inMemoryService) ListScans(ctx context.Context, teamID string, programID string) ([]*api.Scan, error) {
+	return s.scans, nil
+}
+
+func (s inMemoryService) DiffScans(ctx context.Context, teamID string, from, to *api.Scan) (*api.ScanDiff, error) {
+	return s.diff, nil
+}
+
+type emailSenderMock struct {
+	sent []Notification
+}
+
+func (m *emailSenderMock) SendScanNotification(teamID, teamName string, recipients []reports.Recipient, notification interface{}) error {
+	m.sent = append(m.sent, notification.(Notification))
+	return nil
+}
+
+func TestNotifier_HandleScanEvent(t *testing.T) {
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	t1 := t0.Add(24 * time.Hour)
+	checkCount := 3
+	program := &api.Program{ID: "p1", Name: "Daily"}
+	previous := &api.Scan{ID: "scan1", ProgramID: "p1", Program: program, Status: "FINISHED", EndTime: &t0, CheckCount: &checkCount}
+	current := &api.Scan{ID: "scan2", ProgramID: "p1", Program: program, Status: "FINISHED", EndTime: &t1, CheckCount: &checkCount}
+	diff := &api.ScanDiff{
+		NewFindings: []vulndb.FindingExpanded{
+			{
+				Finding: vulndb.Finding{ID: "f1", Score: 9.5},
+				Issue:   vulndb.Issue{Summary: "Critical issue"},
+				Target:  vulndb.Target{Identifier: "example.com"},
+			},
+			{
+				Finding: vulndb.Finding{ID: "f2", Score: 2},
+				Issue:   vulndb.Issue{Summary: "Low issue"},
+				Target:  vulndb.Target{Identifier: "example.com"},
+			},
+		},
+	}
+
+	scanEngineClient := &scanenginetest.Fake{
+		Checks: map[string][]scanengineData.GetCheckResponse{
+			"scan2": {{Status: "FINISHED"}, {Status: "FAILED"}, {Status: "FINISHED"}},
+		},
+	}
+
+	var mu sync.Mutex
+	received := map[string][]string{}
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], string(body))
+		mu.Unlock()
+	}))
+	defer hooks.Close()
+
+	disabled := true
+	tests := []struct {
+		name      string
+		rules     []*api.NotificationRule
+		event     scanengineapi.ScanNotification
+		scans     []*api.Scan
+		wantHooks map[string]int
+		wantEmail []Notification
+	}{
+		{
+			name: "CompletedByEmail",
+			rules: []*api.NotificationRule{
+				{ID: "r1", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerCompleted, Channel: api.NotificationChannelEmail},
+			},
+			event: scanengineapi.ScanNotification{ScanID: "scan2", ProgramID: "p1", Status: "FINISHED"},
+			scans: []*api.Scan{previous, current},
+			wantEmail: []Notification{
+				{
+					Trigger: api.NotificationTriggerCompleted, TeamID: "t1", TeamName: "Team t1", ProgramID: "p1",
+					ProgramName: "Daily", ScanID: "scan2", EndTime: &t1, CheckCount: 3,
+				},
+			},
+		},
+		{
+			name: "FailedAndNewFindingsByHooks",
+			rules: []*api.NotificationRule{
+				{ID: "r1", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerFailed, Channel: api.NotificationChannelChat, URL: hooks.URL + "/chat"},
+				{ID: "r2", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerNewFindings, MinSeverity: "high", Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/webhook"},
+				{ID: "r3", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerCompleted, Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/disabled", Disabled: &disabled},
+			},
+			event:     scanengineapi.ScanNotification{ScanID: "scan2", ProgramID: "p1", Status: "FINISHED"},
+			scans:     []*api.Scan{previous, current},
+			wantHooks: map[string]int{"/chat": 1, "/webhook": 1},
+		},
+		{
+			name: "FirstScanDoesNotNotifyNewFindings",
+			rules: []*api.NotificationRule{
+				{ID: "r1", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerNewFindings, MinSeverity: "low", Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/webhook"},
+			},
+			event: scanengineapi.ScanNotification{ScanID: "scan2", ProgramID: "p1", Status: "FINISHED"},
+			scans: []*api.Scan{current},
+		},
+		{
+			name: "GlobalProgramOnlyNotifiesItsTeam",
+			rules: []*api.NotificationRule{
+				{ID: "r1", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerCompleted, Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/t1"},
+				{ID: "r2", TeamID: "t2", ProgramID: "p1", Trigger: api.NotificationTriggerCompleted, Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/t2"},
+			},
+			event:     scanengineapi.ScanNotification{ScanID: "scan2", ProgramID: "t2@p1", Status: "FINISHED"},
+			scans:     []*api.Scan{previous, current},
+			wantHooks: map[string]int{"/t2": 1},
+		},
+		{
+			name: "RunningScansAreIgnored",
+			rules: []*api.NotificationRule{
+				{ID: "r1", TeamID: "t1", ProgramID: "p1", Trigger: api.NotificationTriggerCompleted, Channel: api.NotificationChannelWebhook, URL: hooks.URL + "/webhook"},
+			},
+			event: scanengineapi.ScanNotification{ScanID: "scan2", ProgramID: "p1", Status: "RUNNING"},
+			scans: []*api.Scan{previous, current},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = map[string][]string{}
+			email := &emailSenderMock{}
+			n := New(log.NewNopLogger(), inMemoryStore{tt.rules}, inMemoryService{scans: tt.scans, diff: diff}, email, scanEngineClient)
+			// The test server listens on a loopback address, which the
+			// default client of the Notifier refuses to connect to.
+			n.httpClient = hooks.Client()
+			if err := n.HandleScanEvent(context.Background(), tt.event); err != nil {
+				t.Fatalf("Notifier.HandleScanEvent() error = %v", err)
+			}
+			gotHooks := map[string]int{}
+			for path, bodies := range received {
+				gotHooks[path] = len(bodies)
+			}
+			wantHooks := tt.wantHooks
+			if wantHooks == nil {
+				wantHooks = map[string]int{}
+			}
+			if diff := cmp.Diff(wantHooks, gotHooks); diff != "" {
+				t.Errorf("hooks mismatch (-want +got):\n%v", diff)
+			}
+			if diff := cmp.Diff(tt.wantEmail, email.sent); diff != "" {
+				t.Errorf("emails mismatch (-want +got):\n%v", diff)
+			}
+			if webhooks := received["/webhook"]; len(webhooks) > 0 {
+				got := Notification{}
+				if err := json.Unmarshal([]byte(webhooks[0]), &got); err != nil {
+					t.Fatalf("error decoding webhook payload: %v", err)
+				}
+				ids := []string{}
+				for _, f := range got.NewFindings {
+					ids = append(ids, f.ID)
+				}
+				sort.Strings(ids)
+				if diff := cmp.Diff([]string{"f1"}, ids); diff != "" {
+					t.Errorf("new findings mismatch (-want +got):\n%v", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifier_PostToNonPublicAddress(t *testing.T) {
+	called := false
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer hook.Close()
+
+	n := New(log.NewNopLogger(), inMemoryStore{}, inMemoryService{}, nil, &scanenginetest.Fake{})
+	err := n.post(context.Background(), hook.URL, Notification{})
+	if !errors.Is(err, api.ErrNotificationURLNotAllowed) {
+		t.Errorf("Notifier.post() error = %v, want %v", err, api.ErrNotificationURLNotAllowed)
+	}
+	if called {
+		t.Errorf("notification was posted to a loopback address")
+	}
+}
+
+func TestPreviousScan(t *testing.T) {
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	t1 := t0.Add(24 * time.Hour)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(time.Hour)
+	scans := []*api.Scan{
+		{ID: "old", Status: "FINISHED", EndTime: &t0},
+		{ID: "prev", Status: "FINISHED", EndTime: &t1},
+		{ID: "running", Status: "RUNNING"},
+		{ID: "sameday", Status: "FINISHED", EndTime: &t2},
+		{ID: "current", Status: "FINISHED", EndTime: &t3},
+	}
+	got := previousScan(scans, scans[4])
+	if got == nil || got.ID != "prev" {
+		t.Errorf("previousScan() = %+v, want scan prev", got)
+	}
+	if got := previousScan(scans, scans[0]); got != nil {
+		t.Errorf("previousScan() = %+v, want nil", got)
+	}
+}
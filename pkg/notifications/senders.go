@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/reports"
+)
+
+var (
+	// ErrEmailNotConfigured is returned when a rule uses the email channel
+	// but the Notifier does not have an email sender.
+	ErrEmailNotConfigured = errors.New("email notifications are not configured")
+	// ErrNoRecipients is returned when a rule uses the email channel but the
+	// team does not have recipients.
+	ErrNoRecipients = errors.New("the team does not have recipients")
+)
+
+// chatMessage is the payload accepted by the incoming webhooks of the most
+// common chat systems.
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+// newHTTPClient returns the client used to post the notifications. It refuses
+// to connect to non public addresses, so a rule can not be used to reach the
+// internal services, even if the DNS records of its host change after the
+// rule was validated. Proxies are not used for the same reason.
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: defaultSendTimeout, Control: publicAddressesOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: defaultSendTimeout, Transport: transport}
+}
+
+// publicAddressesOnly is called with the resolved address before a
+// connection is established.
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !api.IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", api.ErrNotificationURLNotAllowed, host)
+	}
+	return nil
+}
+
+func (n *Notifier) send(ctx context.Context, rule api.NotificationRule, notification Notification) error {
+	switch rule.Channel {
+	case api.NotificationChannelEmail:
+		return n.sendEmail(ctx, notification)
+	case api.NotificationChannelWebhook:
+		return n.post(ctx, rule.URL, notification)
+	case api.NotificationChannelChat:
+		return n.post(ctx, rule.URL, chatMessage{Text: notification.Text()})
+	}
+	return fmt.Errorf("%w: %s", api.ErrInvalidNotificationChannel, rule.Channel)
+}
+
+func (n *Notifier) sendEmail(ctx context.Context, notification Notification) error {
+	if n.email == nil {
+		return ErrEmailNotConfigured
+	}
+	recipients, err := n.service.ListReportRecipients(ctx, notification.TeamID, api.RecipientReportScanNotifications)
+	if err != nil {
+		return err
+	}
+	emails := []reports.Recipient{}
+	for _, r := range recipients {
+		emails = append(emails, reports.Recipient{Email: r.Email, UnsubscribeURL: r.UnsubscribeURL})
+	}
+	if len(emails) == 0 {
+		return ErrNoRecipients
+	}
+	return n.email.SendScanNotification(notification.TeamID, notification.TeamName, emails, notification)
+}
+
+func (n *Notifier) post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d sending notification", resp.StatusCode)
+	}
+	return nil
+}
+
+// Text returns a human readable description of the notification.
+func (n Notification) Text() string {
+	program := n.ProgramName
+	if program == "" {
+		program = n.ProgramID
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scan %s of program %q of team %q finished", n.ScanID, program, n.TeamName)
+	switch n.Trigger {
+	case api.NotificationTriggerFailed:
+		fmt.Fprintf(&b, " with %d of %d checks failed.", n.FailedChecks, n.CheckCount)
+	case api.NotificationTriggerNewFindings:
+		fmt.Fprintf(&b, " with %d new findings:", len(n.NewFindings))
+		for _, f := range n.NewFindings {
+			fmt.Fprintf(&b, "\n- [%.1f] %s in %s", f.Score, f.Summary, f.Target)
+		}
+	default:
+		fmt.Fprintf(&b, " running %d checks.", n.CheckCount)
+	}
+	return b.String()
+}
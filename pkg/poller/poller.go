@@ -0,0 +1,29 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package poller runs the periodic tasks of the API.
+package poller
+
+import (
+	"context"
+	"time"
+)
+
+// Run calls f every interval seconds, or every defaultInterval seconds if
+// interval is not positive, until the context is cancelled.
+func Run(ctx context.Context, interval, defaultInterval int, f func(ctx context.Context)) {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f(ctx)
+		}
+	}
+}
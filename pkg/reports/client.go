@@ -17,9 +17,23 @@ import (
 )
 
 const (
-	livereportType = "livereport"
+	livereportType            = "livereport"
+	scanNotificationType      = "scannotification"
+	recipientVerificationType = "recipientverification"
+	slaBreachType             = "slabreach"
+	findingCommentMentionType = "findingcommentmention"
+	criticalFindingsType      = "criticalfindings"
+	executiveSummaryType      = "executivesummary"
 )
 
+// subscriptionReportTypes maps the IDs of the global reports to the type of
+// the events that generate them. The reports not in the map are generated by
+// a livereport event.
+var subscriptionReportTypes = map[string]string{
+	"critical-findings-report": criticalFindingsType,
+	"executive-summary-report": executiveSummaryType,
+}
+
 type Client struct {
 	cfg        Config
 	httpClient *http.Client
@@ -64,41 +78,134 @@ func NewClient(cfg Config) (*Client, error) {
 // GenerateDigestReport pushes an SNS event to trigger the digest report
 // generation for the specified teamID.
 func (c *Client) GenerateDigestReport(teamID, teamName, dateFrom, dateTo, liveReportURL string,
-	recipients []string, severitiesStats map[string]int, autoSend bool) error {
+	recipients []Recipient, severitiesStats map[string]int, autoSend bool) error {
 	event := genDigestReportEvent{
-		Typ: livereportType,
-		TeamInfo: teamInfo{
-			ID:         teamID,
-			Name:       teamName,
-			Recipients: recipients,
-		},
-		Data: digestReportData{
-			TeamID:        teamID,
-			DateFrom:      dateFrom,
-			DateTo:        dateTo,
-			LiveReportURL: liveReportURL,
-			Info:          severitiesStats["info"],
-			InfoDiff:      severitiesStats["infoDiff"],
-			InfoFixed:     severitiesStats["infoFixed"],
-			Low:           severitiesStats["low"],
-			LowDiff:       severitiesStats["lowDiff"],
-			LowFixed:      severitiesStats["lowFixed"],
-			Medium:        severitiesStats["medium"],
-			MediumDiff:    severitiesStats["mediumDiff"],
-			MediumFixed:   severitiesStats["mediumFixed"],
-			High:          severitiesStats["high"],
-			HighDiff:      severitiesStats["highDiff"],
-			HighFixed:     severitiesStats["highFixed"],
-			Critical:      severitiesStats["critical"],
-			CriticalDiff:  severitiesStats["criticalDiff"],
-			CriticalFixed: severitiesStats["criticalFixed"],
-		},
+		Typ:      livereportType,
+		TeamInfo: newTeamInfo(teamID, teamName, recipients),
+		Data:     newDigestReportData(teamID, dateFrom, dateTo, liveReportURL, severitiesStats),
 		AutoSend: autoSend,
 	}
 
 	return c.publish(event)
 }
 
+// GenerateSubscriptionReport pushes an SNS event to trigger the generation of
+// the report of a subscription of the specified teamID. The report is sent to
+// the given recipients.
+func (c *Client) GenerateSubscriptionReport(teamID, teamName string, subscription Subscription, dateFrom, dateTo, liveReportURL string,
+	recipients []Recipient, severitiesStats map[string]int) error {
+	typ, ok := subscriptionReportTypes[subscription.ReportID]
+	if !ok {
+		typ = livereportType
+	}
+	event := genDigestReportEvent{
+		Typ:          typ,
+		TeamInfo:     newTeamInfo(teamID, teamName, recipients),
+		Data:         newDigestReportData(teamID, dateFrom, dateTo, liveReportURL, severitiesStats),
+		AutoSend:     true,
+		Subscription: &subscription,
+	}
+
+	return c.publish(event)
+}
+
+func newDigestReportData(teamID, dateFrom, dateTo, liveReportURL string, severitiesStats map[string]int) digestReportData {
+	return digestReportData{
+		TeamID:        teamID,
+		DateFrom:      dateFrom,
+		DateTo:        dateTo,
+		LiveReportURL: liveReportURL,
+		Info:          severitiesStats["info"],
+		InfoDiff:      severitiesStats["infoDiff"],
+		InfoFixed:     severitiesStats["infoFixed"],
+		Low:           severitiesStats["low"],
+		LowDiff:       severitiesStats["lowDiff"],
+		LowFixed:      severitiesStats["lowFixed"],
+		Medium:        severitiesStats["medium"],
+		MediumDiff:    severitiesStats["mediumDiff"],
+		MediumFixed:   severitiesStats["mediumFixed"],
+		High:          severitiesStats["high"],
+		HighDiff:      severitiesStats["highDiff"],
+		HighFixed:     severitiesStats["highFixed"],
+		Critical:      severitiesStats["critical"],
+		CriticalDiff:  severitiesStats["criticalDiff"],
+		CriticalFixed: severitiesStats["criticalFixed"],
+	}
+}
+
+// SendScanNotification pushes an SNS event to send a notification about a
+// scan of a program to the given recipients of the team.
+func (c *Client) SendScanNotification(teamID, teamName string, recipients []Recipient, notification interface{}) error {
+	event := scanNotificationEvent{
+		Typ:      scanNotificationType,
+		TeamInfo: newTeamInfo(teamID, teamName, recipients),
+		Data:     notification,
+		AutoSend: true,
+	}
+
+	return c.publish(event)
+}
+
+// SendSLABreachNotification pushes an SNS event to send a notification about
+// the findings of a team that breached their SLA to the given recipients.
+func (c *Client) SendSLABreachNotification(teamID, teamName string, recipients []Recipient, notification interface{}) error {
+	event := slaBreachEvent{
+		Typ:      slaBreachType,
+		TeamInfo: newTeamInfo(teamID, teamName, recipients),
+		Data:     notification,
+		AutoSend: true,
+	}
+
+	return c.publish(event)
+}
+
+// SendFindingCommentMention pushes an SNS event to notify the members of a
+// team mentioned in a comment of a finding.
+func (c *Client) SendFindingCommentMention(teamID, teamName string, recipients []Recipient, notification interface{}) error {
+	event := findingCommentMentionEvent{
+		Typ:      findingCommentMentionType,
+		TeamInfo: newTeamInfo(teamID, teamName, recipients),
+		Data:     notification,
+		AutoSend: true,
+	}
+
+	return c.publish(event)
+}
+
+// SendRecipientVerification pushes an SNS event to send the link to verify
+// the address of a new recipient of a team.
+func (c *Client) SendRecipientVerification(teamID, teamName string, recipient Recipient, verificationURL string) error {
+	event := recipientVerificationEvent{
+		Typ:      recipientVerificationType,
+		TeamInfo: newTeamInfo(teamID, teamName, []Recipient{recipient}),
+		Data: recipientVerificationData{
+			Email:           recipient.Email,
+			VerificationURL: verificationURL,
+		},
+		AutoSend: true,
+	}
+
+	return c.publish(event)
+}
+
+func newTeamInfo(teamID, teamName string, recipients []Recipient) teamInfo {
+	info := teamInfo{
+		ID:   teamID,
+		Name: teamName,
+	}
+	for _, r := range recipients {
+		info.Recipients = append(info.Recipients, r.Email)
+		if r.UnsubscribeURL == "" {
+			continue
+		}
+		if info.UnsubscribeURLs == nil {
+			info.UnsubscribeURLs = map[string]string{}
+		}
+		info.UnsubscribeURLs[r.Email] = r.UnsubscribeURL
+	}
+	return info
+}
+
 func (c *Client) publish(event interface{}) error {
 	eventPayload, err := json.Marshal(event)
 	if err != nil {
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package reports
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+type snsMock struct {
+	snsiface.SNSAPI
+	messages []string
+}
+
+func (m *snsMock) Publish(input *sns.PublishInput) (*sns.PublishOutput, error) {
+	m.messages = append(m.messages, aws.StringValue(input.Message))
+	return &sns.PublishOutput{}, nil
+}
+
+func TestClient_GenerateSubscriptionReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		reportID string
+		wantType string
+	}{
+		{name: "PeriodicDigest", reportID: "periodic-digest-report", wantType: livereportType},
+		{name: "CriticalFindings", reportID: "critical-findings-report", wantType: criticalFindingsType},
+		{name: "ExecutiveSummary", reportID: "executive-summary-report", wantType: executiveSummaryType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &snsMock{}
+			c := &Client{snsAPI: mock}
+			subscription := Subscription{ID: "s1", ReportID: tt.reportID, MinSeverity: "high", MinScore: 7}
+			err := c.GenerateSubscriptionReport("t1", "team", subscription, "2021-01-01", "2021-01-08", "", nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var event genDigestReportEvent
+			if err := json.Unmarshal([]byte(mock.messages[0]), &event); err != nil {
+				t.Fatalf("unexpected error decoding the event: %v", err)
+			}
+			if event.Typ != tt.wantType {
+				t.Errorf("got event type %q, want %q", event.Typ, tt.wantType)
+			}
+			if event.Subscription == nil || event.Subscription.MinScore != 7 {
+				t.Errorf("got subscription %+v, want min score 7", event.Subscription)
+			}
+		})
+	}
+}
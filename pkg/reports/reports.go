@@ -11,6 +11,9 @@ type Config struct {
 	SNSEndpoint string `mapstructure:"sns_endpoint"`
 	InsecureTLS bool   `mapstructure:"insecure_tls"`
 	VulcanUIURL string `mapstructure:"vulcanui_url"`
+	// VulcanAPIURL is the public URL of the API, used to build the links
+	// sent to the recipients to verify their address and to unsubscribe.
+	VulcanAPIURL string `mapstructure:"vulcanapi_url"`
 }
 
 // Notification represents
@@ -21,10 +24,20 @@ type Notification struct {
 	Format  string `json:"format"`
 }
 
+// Recipient is an email address a report is sent to.
+type Recipient struct {
+	Email string
+	// UnsubscribeURL is the link the recipient can use to stop receiving
+	// the reports of the team without logging in.
+	UnsubscribeURL string
+}
+
 type teamInfo struct {
 	ID         string   `json:"id"`
 	Name       string   `json:"name"`
 	Recipients []string `json:"recipients"`
+	// UnsubscribeURLs contains the unsubscribe link of each recipient.
+	UnsubscribeURLs map[string]string `json:"unsubscribe_urls,omitempty"`
 }
 
 // genDigestReportEvent represents the
@@ -34,6 +47,62 @@ type genDigestReportEvent struct {
 	TeamInfo teamInfo         `json:"team_info"`
 	Data     digestReportData `json:"data"`
 	AutoSend bool             `json:"auto_send"`
+	// Subscription is only set for the reports generated by a report
+	// subscription of a team.
+	Subscription *Subscription `json:"subscription,omitempty"`
+}
+
+// Subscription identifies the report subscription of a team that
+// generates a report. The findings with a score lower than MinScore must not
+// be included in the report.
+type Subscription struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	ReportID    string  `json:"report_id"`
+	MinSeverity string  `json:"min_severity,omitempty"`
+	MinScore    float64 `json:"min_score,omitempty"`
+	GroupID     string  `json:"group_id,omitempty"`
+}
+
+// scanNotificationEvent represents the payload
+// for a scan notification event.
+type scanNotificationEvent struct {
+	Typ      string      `json:"type"`
+	TeamInfo teamInfo    `json:"team_info"`
+	Data     interface{} `json:"data"`
+	AutoSend bool        `json:"auto_send"`
+}
+
+// slaBreachEvent represents the payload for the event sent when findings of a
+// team breach their SLA.
+type slaBreachEvent struct {
+	Typ      string      `json:"type"`
+	TeamInfo teamInfo    `json:"team_info"`
+	Data     interface{} `json:"data"`
+	AutoSend bool        `json:"auto_send"`
+}
+
+// findingCommentMentionEvent represents the payload for the event sent to the
+// members of a team mentioned in a comment of a finding.
+type findingCommentMentionEvent struct {
+	Typ      string      `json:"type"`
+	TeamInfo teamInfo    `json:"team_info"`
+	Data     interface{} `json:"data"`
+	AutoSend bool        `json:"auto_send"`
+}
+
+// recipientVerificationEvent represents the payload for the event sent to
+// verify the address of a new recipient of a team.
+type recipientVerificationEvent struct {
+	Typ      string                    `json:"type"`
+	TeamInfo teamInfo                  `json:"team_info"`
+	Data     recipientVerificationData `json:"data"`
+	AutoSend bool                      `json:"auto_send"`
+}
+
+type recipientVerificationData struct {
+	Email           string `json:"email"`
+	VerificationURL string `json:"verification_url"`
 }
 
 type digestReportData struct {
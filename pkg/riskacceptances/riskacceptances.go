@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package riskacceptances expires the risk acceptances of the findings of the
+// teams when their expiry date passes, reopening the findings.
+package riskacceptances
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const defaultPollInterval = 300
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the risk acceptances that expired.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ListExpiredRiskAcceptances(now time.Time) ([]*api.RiskAcceptance, error)
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	ExpireRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) error
+}
+
+// Runner periodically expires the risk acceptances whose expiry date passed.
+// The service only expires an acceptance if its status was not modified in
+// the meantime.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+	now     func() time.Time
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Run expires the risk acceptances until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, r.expire)
+}
+
+// expire expires the risk acceptances whose expiry date passed. The ones
+// that fail to expire are still listed as expired the next time.
+func (r *Runner) expire(ctx context.Context) {
+	acceptances, err := r.store.ListExpiredRiskAcceptances(r.now())
+	if err != nil {
+		_ = level.Error(r.logger).Log("RiskAcceptances", "error listing expired risk acceptances", "err", err)
+		return
+	}
+	for _, a := range acceptances {
+		if err := r.service.ExpireRiskAcceptance(ctx, *a); err != nil {
+			_ = level.Error(r.logger).Log("RiskAcceptances", "error expiring risk acceptance", "RiskAcceptanceID", a.ID, "TeamID", a.TeamID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package riskacceptances
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemoryStore struct {
+	acceptances []*api.RiskAcceptance
+}
+
+func (s *inMemoryStore) ListExpiredRiskAcceptances(now time.Time) ([]*api.RiskAcceptance, error) {
+	expired := []*api.RiskAcceptance{}
+	for _, a := range s.acceptances {
+		if (a.Status == api.RiskAcceptancePending || a.Status == api.RiskAcceptanceApproved) && a.IsExpired(now) {
+			copied := *a
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+type inMemoryService struct {
+	expired []string
+	// errs contains the errors returned when expiring the acceptances with
+	// the given IDs.
+	errs map[string]error
+}
+
+func (s *inMemoryService) ExpireRiskAcceptance(ctx context.Context, acceptance api.RiskAcceptance) error {
+	if err := s.errs[acceptance.ID]; err != nil {
+		return err
+	}
+	s.expired = append(s.expired, acceptance.TeamID+"/"+acceptance.ID)
+	return nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestRunnerExpire(t *testing.T) {
+	now := time.Date(2021, 6, 7, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		acceptances []*api.RiskAcceptance
+		serviceErrs map[string]error
+		wantExpired []string
+	}{
+		{
+			name: "ExpiresPastAcceptances",
+			acceptances: []*api.RiskAcceptance{
+				{ID: "r1", TeamID: "t1", Status: api.RiskAcceptanceApproved, ExpiresAt: timePtr(now.Add(-time.Minute))},
+				{ID: "r2", TeamID: "t1", Status: api.RiskAcceptancePending, ExpiresAt: timePtr(now)},
+				{ID: "r3", TeamID: "t2", Status: api.RiskAcceptanceApproved, ExpiresAt: timePtr(now.Add(time.Hour))},
+			},
+			wantExpired: []string{"t1/r1", "t1/r2"},
+		},
+		{
+			name: "SkipsReviewedAcceptances",
+			acceptances: []*api.RiskAcceptance{
+				{ID: "r1", TeamID: "t1", Status: api.RiskAcceptanceRejected, ExpiresAt: timePtr(now.Add(-time.Minute))},
+				{ID: "r2", TeamID: "t1", Status: api.RiskAcceptanceExpired, ExpiresAt: timePtr(now.Add(-time.Minute))},
+			},
+		},
+		{
+			name: "ContinuesWhenExpiringFails",
+			acceptances: []*api.RiskAcceptance{
+				{ID: "r1", TeamID: "t1", Status: api.RiskAcceptanceApproved, ExpiresAt: timePtr(now.Add(-time.Minute))},
+				{ID: "r2", TeamID: "t2", Status: api.RiskAcceptanceApproved, ExpiresAt: timePtr(now.Add(-time.Minute))},
+			},
+			serviceErrs: map[string]error{"r1": errors.New("vulnerability db not available")},
+			wantExpired: []string{"t2/r2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inMemoryStore{acceptances: tt.acceptances}
+			service := &inMemoryService{errs: tt.serviceErrs}
+			r := New(log.NewNopLogger(), store, service, Config{})
+			r.now = func() time.Time { return now }
+
+			r.expire(context.Background())
+
+			if diff := cmp.Diff(tt.wantExpired, service.expired); diff != "" {
+				t.Errorf("expired acceptances mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanengine
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when the scan engine has failed too many
+// consecutive times and the requests are not sent to it.
+var ErrCircuitOpen = errors.New("scan engine circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops sending requests to the scan engine after a number of
+// consecutive failures. Once the cooldown has passed it lets one request
+// through: if it succeeds the circuit is closed again, otherwise it stays
+// open for another cooldown.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns true if a request can be sent to the scan engine.
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// There is already a request checking if the scan engine has
+		// recovered.
+		return false
+	}
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// abort is called when a request let through by the breaker does not get a
+// response because the caller cancelled it. It is not counted as a failure,
+// but if the request was checking if the scan engine has recovered, another
+// request is let through to check it.
+func (b *circuitBreaker) abort() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+func (b *circuitBreaker) failure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	metrics "github.com/adevinta/vulcan-metrics-client"
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	scanengine "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+)
+
+const (
+	defaultTimeout         = 30
+	defaultRetryInterval   = 1
+	defaultBreakerCooldown = 30
+
+	metricDuration = "vulcan.scanengine.request.duration"
+	metricFailed   = "vulcan.scanengine.request.failed"
+
+	opCreate        = "create"
+	opGet           = "get"
+	opGetScans      = "getscans"
+	opGetScanChecks = "getscanchecks"
+	opAbort         = "abort"
+)
+
+type client struct {
+	baseURL       string
+	httpClient    *http.Client
+	retries       int
+	retryInterval time.Duration
+	breaker       *circuitBreaker
+	metricsClient metrics.Client
+	// sleep waits for the given duration or until the context is done. It
+	// is a field so the tests do not need to wait between retries.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient returns a scan engine client with the given config. The client is
+// safe for concurrent use and is meant to be created once and shared. If the
+// httpClient is nil a new one is created with the timeout defined in the
+// config. The metricsClient can be nil.
+func NewClient(httpClient *http.Client, config Config, metricsClient metrics.Client) Client {
+	if httpClient == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		httpClient = &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	}
+	retryInterval := config.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = defaultRetryInterval
+	}
+	cooldown := config.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &client{
+		baseURL:       config.Url,
+		httpClient:    httpClient,
+		retries:       config.Retries,
+		retryInterval: time.Duration(retryInterval) * time.Second,
+		breaker:       newCircuitBreaker(config.BreakerThreshold, time.Duration(cooldown)*time.Second),
+		metricsClient: metricsClient,
+		sleep:         sleep,
+	}
+}
+
+// Create creates a scan in the scan engine. As creating a scan is not
+// idempotent, the request is never retried.
+func (c *client) Create(ctx context.Context, request scanengine.ScanRequest) (*scanengine.ScanResponse, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	status, body, err := c.do(ctx, opCreate, http.MethodPost, "scans", data, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreatingScan, err)
+	}
+	if status != http.StatusCreated {
+		err = GenericError{Code: status, Msg: string(body)}
+		if status == http.StatusUnprocessableEntity {
+			err = fmt.Errorf("%w: %v", ErrUnprocessableEntity, err)
+		}
+		return nil, fmt.Errorf("%v: %w", ErrCreatingScan, err)
+	}
+	scanResponse := &scanengine.ScanResponse{}
+	if err := json.Unmarshal(body, scanResponse); err != nil {
+		return nil, err
+	}
+	return scanResponse, nil
+}
+
+// Get returns a scan.
+func (c *client) Get(ctx context.Context, scanID string) (*scanengine.GetScanResponse, error) {
+	status, body, err := c.do(ctx, opGet, http.MethodGet, fmt.Sprintf("scans/%v", scanID), nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGettingScan, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: %v", ErrGettingScan, GenericError{Code: status, Msg: string(body)})
+	}
+	getScanResponse := &scanengine.GetScanResponse{}
+	if err := json.Unmarshal(body, getScanResponse); err != nil {
+		return nil, fmt.Errorf("error unmarshalling")
+	}
+	return getScanResponse, nil
+}
+
+// GetScans returns all the scans with the given external ID, that is, the
+// scans of a program.
+func (c *client) GetScans(ctx context.Context, externalID string) (*scanengine.GetScansResponse, error) {
+	path := fmt.Sprintf("scans?external_id=%v&all=true", url.QueryEscape(externalID))
+	status, body, err := c.do(ctx, opGetScans, http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGettingScans, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: %v", ErrGettingScans, GenericError{Code: status, Msg: string(body)})
+	}
+	getScansResponse := &scanengine.GetScansResponse{}
+	if err := json.Unmarshal(body, getScansResponse); err != nil {
+		return nil, fmt.Errorf("error unmarshalling")
+	}
+	return getScansResponse, nil
+}
+
+// GetScanChecks returns the checks of a scan.
+func (c *client) GetScanChecks(ctx context.Context, scanID string) (*scanengine.GetChecksResponse, error) {
+	status, body, err := c.do(ctx, opGetScanChecks, http.MethodGet, fmt.Sprintf("scans/%v/checks", scanID), nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGettingScanChecks, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: %v", ErrGettingScanChecks, GenericError{Code: status, Msg: string(body)})
+	}
+	getChecksResponse := &scanengine.GetChecksResponse{}
+	if err := json.Unmarshal(body, getChecksResponse); err != nil {
+		return nil, fmt.Errorf("error unmarshalling")
+	}
+	return getChecksResponse, nil
+}
+
+// Abort send a signal to the scan engine to try to abort a scan.
+func (c *client) Abort(ctx context.Context, scanID string) (*scanengine.GetScanResponse, error) {
+	status, body, err := c.do(ctx, opAbort, http.MethodPut, fmt.Sprintf("scans/%v/abort", scanID), nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAbortingScans, err)
+	}
+	if status != http.StatusAccepted {
+		return nil, fmt.Errorf("%w: %v", ErrAbortingScans, GenericError{Code: status, Msg: string(body)})
+	}
+	getScanResponse := &scanengine.GetScanResponse{}
+	if err := json.Unmarshal(body, getScanResponse); err != nil {
+		return nil, err
+	}
+	return getScanResponse, nil
+}
+
+// do performs a request to the scan engine and returns the status code and
+// the body of the response. If retry is true, the request is retried when the
+// scan engine can not be reached or returns a server error.
+func (c *client) do(ctx context.Context, op, method, path string, payload []byte, retry bool) (int, []byte, error) {
+	attempts := 1
+	if retry {
+		attempts += c.retries
+	}
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if err := c.sleep(ctx, backoff(c.retryInterval, i)); err != nil {
+				return 0, nil, err
+			}
+		}
+		if !c.breaker.allow() {
+			c.pushMetrics(op, 0, 0, ErrCircuitOpen)
+			return 0, nil, ErrCircuitOpen
+		}
+		status, body, err = c.doOnce(ctx, op, method, path, payload)
+		if err == nil && !retryableStatus(status) {
+			c.breaker.success()
+			return status, body, nil
+		}
+		// A request cancelled by the caller, or whose deadline expired,
+		// says nothing about the health of the scan engine.
+		if err != nil && ctx.Err() != nil {
+			c.breaker.abort()
+			break
+		}
+		c.breaker.failure()
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return status, body, nil
+}
+
+func (c *client) doOnce(ctx context.Context, op, method, path string, payload []byte) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if xReqID, ok := ctx.Value(kithttp.ContextKeyRequestXRequestID).(string); ok {
+		req.Header.Set("X-Request-Id", xReqID)
+	}
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.pushMetrics(op, 0, time.Since(start), err)
+		return 0, nil, err
+	}
+	defer resp.Body.Close() // nolint
+	body, err := io.ReadAll(resp.Body)
+	c.pushMetrics(op, resp.StatusCode, time.Since(start), err)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (c *client) pushMetrics(op string, status int, duration time.Duration, err error) {
+	if c.metricsClient == nil {
+		return
+	}
+	tags := []string{"component:api", "action:" + op, "status:" + strconv.Itoa(status)}
+	if err == nil && !retryableStatus(status) {
+		c.metricsClient.Push(metrics.Metric{
+			Name:  metricDuration,
+			Typ:   metrics.Histogram,
+			Value: float64(duration.Milliseconds()),
+			Tags:  tags,
+		})
+		return
+	}
+	c.metricsClient.Push(metrics.Metric{
+		Name:  metricFailed,
+		Typ:   metrics.Count,
+		Value: 1,
+		Tags:  tags,
+	})
+}
+
+// retryableStatus returns true for the status codes that mean the scan
+// engine is not able to process requests at the moment.
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// backoff returns the time to wait before the given retry. It is a random
+// value between zero and the base interval doubled for every retry, so the
+// retries of different requests do not hit the scan engine at the same time.
+func backoff(interval time.Duration, retry int) time.Duration {
+	max := interval << uint(retry-1)
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max))) // nolint
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
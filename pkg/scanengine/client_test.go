@@ -0,0 +1,250 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanengine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	metrics "github.com/adevinta/vulcan-metrics-client"
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+)
+
+type metricsMock struct {
+	mu      sync.Mutex
+	metrics []metrics.Metric
+}
+
+func (m *metricsMock) Push(metric metrics.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, metric)
+}
+
+func (m *metricsMock) PushWithRate(metric metrics.RatedMetric) {
+	m.Push(metric.Metric)
+}
+
+func (m *metricsMock) count(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, metric := range m.metrics {
+		if metric.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+// responses returns a handler that answers the requests with the given
+// status codes, in order, repeating the last one.
+func responses(codes ...int) (http.HandlerFunc, *int) {
+	var mu sync.Mutex
+	calls := new(int)
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		code := codes[len(codes)-1]
+		if *calls < len(codes) {
+			code = codes[*calls]
+		}
+		*calls++
+		mu.Unlock()
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{"id":"scan1","scan_id":"scan1","status":"RUNNING"}`))
+	}, calls
+}
+
+func newTestClient(url string, cfg Config, m metrics.Client) *client {
+	cfg.Url = url + "/"
+	c := NewClient(nil, cfg, m).(*client)
+	c.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return c
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		codes     []int
+		call      func(c Client) error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:  "GetRetriesServerErrors",
+			codes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			call: func(c Client) error {
+				_, err := c.Get(context.Background(), "scan1")
+				return err
+			},
+			wantCalls: 3,
+		},
+		{
+			name:  "GetScansRetriesTooManyRequests",
+			codes: []int{http.StatusTooManyRequests, http.StatusOK},
+			call: func(c Client) error {
+				_, err := c.GetScans(context.Background(), "team1@program1")
+				return err
+			},
+			wantCalls: 2,
+		},
+		{
+			name:  "AbortGivesUpAfterRetries",
+			codes: []int{http.StatusInternalServerError},
+			call: func(c Client) error {
+				_, err := c.Abort(context.Background(), "scan1")
+				return err
+			},
+			wantCalls: 4,
+			wantErr:   true,
+		},
+		{
+			name:  "GetDoesNotRetryClientErrors",
+			codes: []int{http.StatusNotFound},
+			call: func(c Client) error {
+				_, err := c.Get(context.Background(), "scan1")
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:  "CreateIsNotRetried",
+			codes: []int{http.StatusBadGateway, http.StatusCreated},
+			call: func(c Client) error {
+				_, err := c.Create(context.Background(), scanengineapi.ScanRequest{})
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := responses(tt.codes...)
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+			c := newTestClient(srv.URL, Config{Retries: 3}, nil)
+			err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	handler, calls := responses(http.StatusInternalServerError)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	m := &metricsMock{}
+	c := newTestClient(srv.URL, Config{BreakerThreshold: 2, BreakerCooldown: 60}, m)
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(context.Background(), "scan1"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: unexpected open circuit", i)
+		}
+	}
+	_, err := c.Get(context.Background(), "scan1")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if *calls != 2 {
+		t.Errorf("calls = %d, want 2", *calls)
+	}
+	if got := m.count(metricFailed); got != 3 {
+		t.Errorf("failed metrics = %d, want 3", got)
+	}
+
+	// After the cooldown one request is let through, and as it fails the
+	// circuit is opened again.
+	now = now.Add(61 * time.Second)
+	if _, err := c.Get(context.Background(), "scan1"); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("unexpected open circuit after cooldown")
+	}
+	if _, err := c.Get(context.Background(), "scan1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3", *calls)
+	}
+}
+
+func TestClient_Context(t *testing.T) {
+	var gotReqID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReqID = r.Header.Get("X-Request-Id")
+		_, _ = w.Write([]byte(`{"id":"scan1"}`))
+	}))
+	defer srv.Close()
+	m := &metricsMock{}
+	c := newTestClient(srv.URL, Config{Retries: 3}, m)
+
+	ctx := context.WithValue(context.Background(), kithttp.ContextKeyRequestXRequestID, "req1")
+	if _, err := c.Get(ctx, "scan1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if gotReqID != "req1" {
+		t.Errorf("X-Request-Id = %q, want %q", gotReqID, "req1")
+	}
+	if got := m.count(metricDuration); got != 1 {
+		t.Errorf("duration metrics = %d, want 1", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Get(ctx, "scan1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_ContextBreaker(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(block)
+	c := newTestClient(srv.URL, Config{BreakerThreshold: 1, BreakerCooldown: 60}, &metricsMock{})
+
+	// The cancelled requests and the ones whose deadline expires don't open
+	// the circuit.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Get(ctx, "scan1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, "scan1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !c.breaker.allow() {
+		t.Errorf("circuit opened by cancelled requests")
+	}
+
+	// A cancelled request checking if the scan engine has recovered lets
+	// another one check it.
+	c.breaker.state = breakerHalfOpen
+	c.breaker.abort()
+	if !c.breaker.allow() {
+		t.Errorf("no request let through after an aborted check")
+	}
+}
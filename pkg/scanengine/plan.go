@@ -0,0 +1,143 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanengine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// CreateScanPlan returns the checks the scan engine would create for the scan
+// request built by CreateScanRequest for the given program, and the assets of
+// the program that would not be scanned. The given checktypes are used to
+// discard the checks of checktypes that do not support the type of an asset.
+// If they are nil, the asset types are not checked.
+func CreateScanPlan(program api.Program, checktypes []*api.Checktype) (*api.ProgramPlan, error) {
+	plan := &api.ProgramPlan{
+		ProgramID:      program.ID,
+		Checks:         []api.PlannedCheck{},
+		ExcludedAssets: []api.ExcludedAsset{},
+	}
+	var assettypes map[string]map[string]bool
+	if checktypes == nil {
+		plan.Warnings = append(plan.Warnings, "the checktypes catalogue is not available, the asset types supported by the checktypes are not checked")
+	} else {
+		assettypes = map[string]map[string]bool{}
+		for _, ct := range checktypes {
+			assettypes[ct.Name] = map[string]bool{}
+			for _, at := range ct.AssetTypes {
+				assettypes[ct.Name][at] = true
+			}
+		}
+	}
+	supports := func(checktype, assettype string) bool {
+		if assettypes == nil {
+			return true
+		}
+		return assettypes[checktype][assettype]
+	}
+
+	// The exclusions must be computed before building the scan request
+	// because it removes the non scannable assets from the groups.
+	now := time.Now()
+	unknown := map[string]bool{}
+	for _, gp := range program.ProgramsGroupsPolicies {
+		if gp.Group == nil || gp.Policy == nil {
+			continue
+		}
+		if assettypes != nil {
+			for _, s := range gp.Policy.ChecktypeSettings {
+				if _, ok := assettypes[s.CheckTypeName]; !ok {
+					unknown[s.CheckTypeName] = true
+				}
+			}
+		}
+		scannable, notScannable := ScannableAssets(gp.Group.AssetGroup)
+		for _, ag := range notScannable {
+			reason := api.ExclusionNotScannable
+			if ag.Asset == nil {
+				reason = api.ExclusionAssetNotFound
+			}
+			plan.ExcludedAssets = append(plan.ExcludedAssets, excludedAsset(gp, ag, reason))
+		}
+		for _, ag := range scannable {
+			if len(gp.Policy.ChecktypeSettings) < 1 {
+				plan.ExcludedAssets = append(plan.ExcludedAssets, excludedAsset(gp, ag, api.ExclusionPolicyWithoutCheck))
+				continue
+			}
+			checktypes := checktypesFromChecktypesSettings(gp.Policy.ChecktypeSettings)
+			checktypes, _ = applyAssetChecktypeSettings(checktypes, ag.Asset.ChecktypeSettings, now)
+			if len(checktypes) < 1 {
+				plan.ExcludedAssets = append(plan.ExcludedAssets, excludedAsset(gp, ag, api.ExclusionChecktypesExcluded))
+				continue
+			}
+			supported := false
+			for _, ct := range checktypes {
+				if supports(ct.Name, assetTypeName(ag.Asset)) {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				plan.ExcludedAssets = append(plan.ExcludedAssets, excludedAsset(gp, ag, api.ExclusionAssettypeMismatch))
+			}
+		}
+	}
+	names := []string{}
+	for n := range unknown {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("the checktype %s is not enabled in the checktypes catalogue", n))
+	}
+
+	req, err := CreateScanRequest(program, nil, program.ID, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, tg := range req.TargetGroups {
+		for _, target := range tg.TargetGroup.Targets {
+			for _, ct := range tg.ChecktypesGroup.Checktypes {
+				if !supports(ct.Name, target.Type) {
+					continue
+				}
+				plan.Checks = append(plan.Checks, api.PlannedCheck{
+					Group:      tg.TargetGroup.Name,
+					Policy:     tg.ChecktypesGroup.Name,
+					Identifier: target.Identifier,
+					AssetType:  target.Type,
+					Checktype:  ct.Name,
+					Options:    ct.Options,
+				})
+			}
+		}
+	}
+	return plan, nil
+}
+
+func excludedAsset(gp *api.ProgramsGroupsPolicies, ag *api.AssetGroup, reason string) api.ExcludedAsset {
+	excluded := api.ExcludedAsset{
+		Group:   gp.Group.Name,
+		Policy:  gp.Policy.Name,
+		AssetID: ag.AssetID,
+		Reason:  reason,
+	}
+	if ag.Asset != nil {
+		excluded.Identifier = ag.Asset.Identifier
+		excluded.AssetType = assetTypeName(ag.Asset)
+	}
+	return excluded
+}
+
+func assetTypeName(a *api.Asset) string {
+	if a == nil || a.AssetType == nil {
+		return ""
+	}
+	return a.AssetType.Name
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanengine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+func testAsset(id, identifier, assettype string, scannable bool) *api.AssetGroup {
+	return &api.AssetGroup{
+		AssetID: id,
+		Asset: &api.Asset{
+			ID:         id,
+			Identifier: identifier,
+			AssetType:  &api.AssetType{Name: assettype},
+			Scannable:  common.Bool(scannable),
+		},
+	}
+}
+
+func testProgram() api.Program {
+	return api.Program{
+		ID: "p1",
+		ProgramsGroupsPolicies: []*api.ProgramsGroupsPolicies{
+			{
+				Group: &api.Group{
+					Name: "web",
+					AssetGroup: []*api.AssetGroup{
+						testAsset("a1", "www.example.com", "Hostname", true),
+						testAsset("a2", "https://www.example.com", "WebAddress", true),
+						testAsset("a3", "old.example.com", "Hostname", false),
+						testAsset("a4", "10.0.0.1", "IP", true),
+						{AssetID: "a5"},
+					},
+				},
+				Policy: &api.Policy{
+					Name: "web-policy",
+					ChecktypeSettings: []*api.ChecktypeSetting{
+						{CheckTypeName: "vulcan-tls"},
+						{CheckTypeName: "vulcan-zap", Options: common.String(`{"depth":2}`)},
+					},
+				},
+			},
+			{
+				Group: &api.Group{
+					Name:       "empty-policy",
+					AssetGroup: []*api.AssetGroup{testAsset("a1", "www.example.com", "Hostname", true)},
+				},
+				Policy: &api.Policy{Name: "no-checks"},
+			},
+		},
+	}
+}
+
+func TestCreateScanPlan(t *testing.T) {
+	catalogue := []*api.Checktype{
+		{Name: "vulcan-tls", AssetTypes: []string{"Hostname"}},
+		{Name: "vulcan-zap", AssetTypes: []string{"WebAddress"}},
+	}
+	tests := []struct {
+		name       string
+		checktypes []*api.Checktype
+		want       *api.ProgramPlan
+	}{
+		{
+			name:       "WithCatalogue",
+			checktypes: catalogue,
+			want: &api.ProgramPlan{
+				ProgramID: "p1",
+				Checks: []api.PlannedCheck{
+					{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-tls"},
+					{Group: "web", Policy: "web-policy", Identifier: "https://www.example.com", AssetType: "WebAddress", Checktype: "vulcan-zap", Options: `{"depth":2}`},
+				},
+				ExcludedAssets: []api.ExcludedAsset{
+					{Group: "web", Policy: "web-policy", AssetID: "a3", Identifier: "old.example.com", AssetType: "Hostname", Reason: api.ExclusionNotScannable},
+					{Group: "web", Policy: "web-policy", AssetID: "a5", Reason: api.ExclusionAssetNotFound},
+					{Group: "web", Policy: "web-policy", AssetID: "a4", Identifier: "10.0.0.1", AssetType: "IP", Reason: api.ExclusionAssettypeMismatch},
+					{Group: "empty-policy", Policy: "no-checks", AssetID: "a1", Identifier: "www.example.com", AssetType: "Hostname", Reason: api.ExclusionPolicyWithoutCheck},
+				},
+			},
+		},
+		{
+			name:       "UnknownChecktype",
+			checktypes: catalogue[:1],
+			want: &api.ProgramPlan{
+				ProgramID: "p1",
+				Checks: []api.PlannedCheck{
+					{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-tls"},
+				},
+				ExcludedAssets: []api.ExcludedAsset{
+					{Group: "web", Policy: "web-policy", AssetID: "a3", Identifier: "old.example.com", AssetType: "Hostname", Reason: api.ExclusionNotScannable},
+					{Group: "web", Policy: "web-policy", AssetID: "a5", Reason: api.ExclusionAssetNotFound},
+					{Group: "web", Policy: "web-policy", AssetID: "a2", Identifier: "https://www.example.com", AssetType: "WebAddress", Reason: api.ExclusionAssettypeMismatch},
+					{Group: "web", Policy: "web-policy", AssetID: "a4", Identifier: "10.0.0.1", AssetType: "IP", Reason: api.ExclusionAssettypeMismatch},
+					{Group: "empty-policy", Policy: "no-checks", AssetID: "a1", Identifier: "www.example.com", AssetType: "Hostname", Reason: api.ExclusionPolicyWithoutCheck},
+				},
+				Warnings: []string{"the checktype vulcan-zap is not enabled in the checktypes catalogue"},
+			},
+		},
+		{
+			name: "WithoutCatalogue",
+			want: &api.ProgramPlan{
+				ProgramID: "p1",
+				Checks: []api.PlannedCheck{
+					{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-tls"},
+					{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-zap", Options: `{"depth":2}`},
+					{Group: "web", Policy: "web-policy", Identifier: "https://www.example.com", AssetType: "WebAddress", Checktype: "vulcan-tls"},
+					{Group: "web", Policy: "web-policy", Identifier: "https://www.example.com", AssetType: "WebAddress", Checktype: "vulcan-zap", Options: `{"depth":2}`},
+					{Group: "web", Policy: "web-policy", Identifier: "10.0.0.1", AssetType: "IP", Checktype: "vulcan-tls"},
+					{Group: "web", Policy: "web-policy", Identifier: "10.0.0.1", AssetType: "IP", Checktype: "vulcan-zap", Options: `{"depth":2}`},
+				},
+				ExcludedAssets: []api.ExcludedAsset{
+					{Group: "web", Policy: "web-policy", AssetID: "a3", Identifier: "old.example.com", AssetType: "Hostname", Reason: api.ExclusionNotScannable},
+					{Group: "web", Policy: "web-policy", AssetID: "a5", Reason: api.ExclusionAssetNotFound},
+					{Group: "empty-policy", Policy: "no-checks", AssetID: "a1", Identifier: "www.example.com", AssetType: "Hostname", Reason: api.ExclusionPolicyWithoutCheck},
+				},
+				Warnings: []string{"the checktypes catalogue is not available, the asset types supported by the checktypes are not checked"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateScanPlan(testProgram(), tt.checktypes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("plan mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestCreateScanPlan_AssetChecktypeSettings(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+	program := testProgram()
+	program.SetAssetChecktypeSettings([]*api.AssetChecktypeSetting{
+		// Overrides the options of vulcan-zap for a2.
+		{AssetID: "a2", ChecktypeName: "vulcan-zap", Options: common.String(`{"depth":1}`), ExpiresAt: &future},
+		// Excludes all the checktypes of the policy for a4.
+		{AssetID: "a4", ChecktypeName: "vulcan-tls", Excluded: common.Bool(true), ExpiresAt: &future},
+		{AssetID: "a4", ChecktypeName: "vulcan-zap", Excluded: common.Bool(true), ExpiresAt: &future},
+		// Expired settings are ignored.
+		{AssetID: "a1", ChecktypeName: "vulcan-tls", Excluded: common.Bool(true), ExpiresAt: &past},
+	})
+	want := &api.ProgramPlan{
+		ProgramID: "p1",
+		Checks: []api.PlannedCheck{
+			{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-tls"},
+			{Group: "web", Policy: "web-policy", Identifier: "www.example.com", AssetType: "Hostname", Checktype: "vulcan-zap", Options: `{"depth":2}`},
+			{Group: "web", Policy: "web-policy", Identifier: "https://www.example.com", AssetType: "WebAddress", Checktype: "vulcan-tls"},
+			{Group: "web", Policy: "web-policy", Identifier: "https://www.example.com", AssetType: "WebAddress", Checktype: "vulcan-zap", Options: `{"depth":1}`},
+		},
+		ExcludedAssets: []api.ExcludedAsset{
+			{Group: "web", Policy: "web-policy", AssetID: "a3", Identifier: "old.example.com", AssetType: "Hostname", Reason: api.ExclusionNotScannable},
+			{Group: "web", Policy: "web-policy", AssetID: "a5", Reason: api.ExclusionAssetNotFound},
+			{Group: "web", Policy: "web-policy", AssetID: "a4", Identifier: "10.0.0.1", AssetType: "IP", Reason: api.ExclusionChecktypesExcluded},
+			{Group: "empty-policy", Policy: "no-checks", AssetID: "a1", Identifier: "www.example.com", AssetType: "Hostname", Reason: api.ExclusionPolicyWithoutCheck},
+		},
+		Warnings: []string{"the checktypes catalogue is not available, the asset types supported by the checktypes are not checked"},
+	}
+	got, err := CreateScanPlan(program, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("plan mismatch (-want +got):\n%v", diff)
+	}
+}
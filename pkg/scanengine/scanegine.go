@@ -5,18 +5,11 @@ Copyright 2021 Adevinta
 package scanengine
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"net/url"
 	"time"
 
-	kithttp "github.com/go-kit/kit/transport/http"
-
 	"github.com/adevinta/vulcan-api/pkg/api"
 	scanengineAPI "github.com/adevinta/vulcan-scan-engine/pkg/api"
 	scanengine "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
@@ -30,8 +23,10 @@ var (
 	ErrCreatingScan               = errors.New("Error creating scan")
 	ErrGettingScan                = errors.New("Error getting scan")
 	ErrGettingScans               = errors.New("Error getting scans")
+	ErrGettingScanChecks          = errors.New("Error getting scan checks")
 	ErrAbortingScans              = errors.New("Error aborting scans")
 	ErrUnprocessableEntity        = errors.New("UnprocessableEntity")
+	ErrChecktypeExcluded          = errors.New("Checktype excluded for the asset")
 )
 
 type GenericError struct {
@@ -43,30 +38,39 @@ func (g GenericError) Error() string {
 	return g.Msg
 }
 
-type Client struct {
-	BaseURL    *url.URL
-	httpClient *http.Client
-	ctx        context.Context
-}
+// Config defines the location of the scan engine and how the client behaves
+// when the scan engine is slow or failing.
 type Config struct {
 	Url string `mapstructure:"url"`
+	// Timeout is the maximum number of seconds a request to the scan engine
+	// can last, including reading the response. Defaults to 30.
+	Timeout int `mapstructure:"timeout"`
+	// Retries is the number of times an idempotent request is retried when
+	// the scan engine can not be reached or returns a server error.
+	Retries int `mapstructure:"retries"`
+	// RetryInterval is the base number of seconds to wait before retrying a
+	// request. The wait time is doubled in every retry and randomized.
+	RetryInterval int `mapstructure:"retry_interval"`
+	// BreakerThreshold is the number of consecutive failed requests that
+	// open the circuit breaker. A value of 0 disables the circuit breaker.
+	BreakerThreshold int `mapstructure:"breaker_threshold"`
+	// BreakerCooldown is the number of seconds the circuit breaker stays
+	// open before letting a request through to check if the scan engine has
+	// recovered. Defaults to 30.
+	BreakerCooldown int `mapstructure:"breaker_cooldown"`
 }
 
-// NewClient returns a new scan engine client.
-func NewClient(ctx context.Context, httpClient *http.Client, config Config) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-	}
-	baseURL, _ := url.Parse(config.Url)
-	return &Client{
-		httpClient: httpClient,
-		BaseURL:    baseURL,
-		ctx:        ctx,
-	}
+// Client defines the operations of the scan engine used by the API.
+type Client interface {
+	Create(ctx context.Context, request scanengine.ScanRequest) (*scanengine.ScanResponse, error)
+	Get(ctx context.Context, scanID string) (*scanengine.GetScanResponse, error)
+	GetScans(ctx context.Context, externalID string) (*scanengine.GetScansResponse, error)
+	GetScanChecks(ctx context.Context, scanID string) (*scanengine.GetChecksResponse, error)
+	Abort(ctx context.Context, scanID string) (*scanengine.GetScanResponse, error)
 }
 
 // CreateScanRequest creates a scan by calling the scan engine using the information in the parameters.
-func (c *Client) CreateScanRequest(program api.Program, scheduledTime *time.Time, externalID, requestedBy, tag string) (scanengine.ScanRequest, error) {
+func CreateScanRequest(program api.Program, scheduledTime *time.Time, externalID, requestedBy, tag string) (scanengine.ScanRequest, error) {
 	scanRequest := scanengine.ScanRequest{}
 	scanRequest.Trigger = requestedBy
 	if len(program.ProgramsGroupsPolicies) < 1 {
@@ -74,21 +78,10 @@ func (c *Client) CreateScanRequest(program api.Program, scheduledTime *time.Time
 		err := fmt.Errorf("%w: %v", ErrProgramWithoutPolicyGroups, GenericError{Msg: errMssg})
 		return scanengine.ScanRequest{}, err // nolint to avoid complaining about the standard errors package usage.
 	}
+	now := time.Now()
 	targetGroups := []scanengineAPI.TargetsChecktypesGroup{}
 	for _, tg := range program.ProgramsGroupsPolicies {
-		scTargetGroup := scanengineAPI.TargetGroup{
-			Name:    tg.Group.Name,
-			Options: tg.Group.Options,
-		}
-
-		scannableAssetGroups := []*api.AssetGroup{}
-		for _, ag := range tg.Group.AssetGroup {
-			// append only if the asset group is scannable
-			if ag.Asset != nil && *ag.Asset.Scannable {
-				scannableAssetGroups = append(scannableAssetGroups, ag)
-			}
-		}
-		tg.Group.AssetGroup = scannableAssetGroups
+		tg.Group.AssetGroup, _ = ScannableAssets(tg.Group.AssetGroup)
 
 		// Get the targets for the group. If no assets are defined in the group
 		// we just skip the the entire target group as no checks are going to be
@@ -96,20 +89,46 @@ func (c *Client) CreateScanRequest(program api.Program, scheduledTime *time.Time
 		if len(tg.Group.AssetGroup) < 1 {
 			continue
 		}
+		checktypes := checktypesFromChecktypesSettings(tg.Policy.ChecktypeSettings)
 		targets, err := targetsFromAssetGroups(tg.Group.AssetGroup)
 		if err != nil {
 			return scanengine.ScanRequest{}, err
 		}
-		scTargetGroup.Targets = targets
-
-		// Get the checktypes from the policy.
-		scCheckTypesGroup := scanengineAPI.ChecktypesGroup{Name: tg.Policy.Name}
-		scCheckTypesGroup.Checktypes = checktypesFromChecktypesSettings(tg.Policy.ChecktypeSettings)
 
-		targetGroups = append(targetGroups, scanengineAPI.TargetsChecktypesGroup{
-			ChecktypesGroup: scCheckTypesGroup,
-			TargetGroup:     scTargetGroup,
-		})
+		// The assets with checktype settings that modify the checktypes of
+		// the policy are scanned in their own target group, so the rest of
+		// the assets of the group are not affected by them.
+		defaultTargets := []scanengineAPI.Target{}
+		customTargetGroups := []scanengineAPI.TargetsChecktypesGroup{}
+		for i, ag := range tg.Group.AssetGroup {
+			assetChecktypes, custom := applyAssetChecktypeSettings(checktypes, ag.Asset.ChecktypeSettings, now)
+			if !custom {
+				defaultTargets = append(defaultTargets, targets[i])
+				continue
+			}
+			if len(assetChecktypes) < 1 {
+				continue
+			}
+			customTargetGroups = append(customTargetGroups, scanengineAPI.TargetsChecktypesGroup{
+				ChecktypesGroup: scanengineAPI.ChecktypesGroup{Name: tg.Policy.Name, Checktypes: assetChecktypes},
+				TargetGroup: scanengineAPI.TargetGroup{
+					Name:    tg.Group.Name,
+					Options: tg.Group.Options,
+					Targets: []scanengineAPI.Target{targets[i]},
+				},
+			})
+		}
+		if len(defaultTargets) > 0 {
+			targetGroups = append(targetGroups, scanengineAPI.TargetsChecktypesGroup{
+				ChecktypesGroup: scanengineAPI.ChecktypesGroup{Name: tg.Policy.Name, Checktypes: checktypes},
+				TargetGroup: scanengineAPI.TargetGroup{
+					Name:    tg.Group.Name,
+					Options: tg.Group.Options,
+					Targets: defaultTargets,
+				},
+			})
+		}
+		targetGroups = append(targetGroups, customTargetGroups...)
 	}
 	scanRequest.ScheduledTime = scheduledTime
 	scanRequest.TargetGroups = targetGroups
@@ -118,6 +137,58 @@ func (c *Client) CreateScanRequest(program api.Program, scheduledTime *time.Time
 	return scanRequest, nil
 }
 
+// CreateSingleCheckScanRequest builds a scan request that runs only the given
+// checktype, with the given options, against the given asset. The checktype
+// settings of the asset are honoured: ErrChecktypeExcluded is returned if
+// the checktype is excluded and its options are replaced if overridden.
+func CreateSingleCheckScanRequest(asset api.Asset, checktype, options string, scheduledTime *time.Time, externalID, requestedBy, tag string) (scanengine.ScanRequest, error) {
+	targets, err := targetsFromAssetGroups([]*api.AssetGroup{{AssetID: asset.ID, Asset: &asset}})
+	if err != nil {
+		return scanengine.ScanRequest{}, err
+	}
+	now := time.Now()
+	if scheduledTime != nil {
+		now = *scheduledTime
+	}
+	checktypes := []scanengineAPI.Checktype{{Name: checktype, Options: options}}
+	checktypes, _ = applyAssetChecktypeSettings(checktypes, asset.ChecktypeSettings, now)
+	if len(checktypes) == 0 {
+		return scanengine.ScanRequest{}, ErrChecktypeExcluded
+	}
+	targetGroup := scanengineAPI.TargetsChecktypesGroup{
+		TargetGroup: scanengineAPI.TargetGroup{
+			Name:    checktype,
+			Targets: targets,
+		},
+		ChecktypesGroup: scanengineAPI.ChecktypesGroup{
+			Name:       checktype,
+			Checktypes: checktypes,
+		},
+	}
+	return scanengine.ScanRequest{
+		ExternalID:    externalID,
+		ScheduledTime: scheduledTime,
+		TargetGroups:  []scanengineAPI.TargetsChecktypesGroup{targetGroup},
+		Trigger:       requestedBy,
+		Tag:           tag,
+	}, nil
+}
+
+// ScannableAssets splits the given assets of a group in the ones that can be
+// scanned and the ones that can not.
+func ScannableAssets(assetGroups []*api.AssetGroup) (scannable, notScannable []*api.AssetGroup) {
+	scannable = []*api.AssetGroup{}
+	notScannable = []*api.AssetGroup{}
+	for _, ag := range assetGroups {
+		if ag.Asset != nil && ag.Asset.Scannable != nil && *ag.Asset.Scannable {
+			scannable = append(scannable, ag)
+			continue
+		}
+		notScannable = append(notScannable, ag)
+	}
+	return scannable, notScannable
+}
+
 func checktypesFromChecktypesSettings(settings []*api.ChecktypeSetting) []scanengineAPI.Checktype {
 	checktypes := []scanengineAPI.Checktype{}
 	for _, checktypesetting := range settings {
@@ -130,6 +201,42 @@ func checktypesFromChecktypesSettings(settings []*api.ChecktypeSetting) []scanen
 	return checktypes
 }
 
+// applyAssetChecktypeSettings returns the checktypes that must be run against
+// an asset with the given checktype settings: the checktypes excluded by the
+// settings are removed and the options of the overridden ones are replaced.
+// The settings that are expired at the given time are ignored. It also
+// returns false if none of the settings modifies the given checktypes.
+func applyAssetChecktypeSettings(checktypes []scanengineAPI.Checktype, settings []*api.AssetChecktypeSetting, now time.Time) ([]scanengineAPI.Checktype, bool) {
+	active := map[string]*api.AssetChecktypeSetting{}
+	for _, s := range settings {
+		if s.IsActive(now) {
+			active[s.ChecktypeName] = s
+		}
+	}
+	if len(active) < 1 {
+		return checktypes, false
+	}
+	custom := false
+	res := []scanengineAPI.Checktype{}
+	for _, ct := range checktypes {
+		s, ok := active[ct.Name]
+		if !ok {
+			res = append(res, ct)
+			continue
+		}
+		custom = true
+		if s.IsExcluded() {
+			continue
+		}
+		ct.Options = ptrStrToStr(s.Options)
+		res = append(res, ct)
+	}
+	if !custom {
+		return checktypes, false
+	}
+	return res, true
+}
+
 func targetsFromAssetGroups(assetGroups []*api.AssetGroup) ([]scanengineAPI.Target, error) {
 	targets := []scanengineAPI.Target{}
 	for _, asset := range assetGroups {
@@ -151,184 +258,6 @@ func targetsFromAssetGroups(assetGroups []*api.AssetGroup) ([]scanengineAPI.Targ
 	return targets, nil
 }
 
-// Create creates a scan in the scan engine.
-func (c *Client) Create(request scanengine.ScanRequest) (*scanengine.ScanResponse, error) {
-	data, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.BaseURL.String()+"scans", bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	v := c.ctx.Value(kithttp.ContextKeyRequestXRequestID)
-	if v != nil {
-		xReqID, ok := v.(string)
-		if ok {
-			req.Header.Set("X-Request-Id", xReqID)
-		}
-	}
-	response, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close() // nolint
-	responseBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusCreated {
-		// Try to read the response for a possible error message
-		b, errR := ioutil.ReadAll(response.Body)
-		if errR != nil {
-			return nil, errR
-		}
-
-		var err error
-		err = GenericError{
-			Msg:  string(b),
-			Code: response.StatusCode,
-		}
-		if response.StatusCode == http.StatusUnprocessableEntity {
-			err = fmt.Errorf("%w: %v", ErrUnprocessableEntity, err)
-		}
-
-		err = fmt.Errorf("%v: %w", ErrCreatingScan, err)
-		return nil, fmt.Errorf("%w", err)
-	}
-	scanResponse := &scanengine.ScanResponse{}
-	err = json.Unmarshal(responseBytes, scanResponse)
-	if err != nil {
-		return nil, err
-	}
-	return scanResponse, nil
-}
-
-func (c *Client) Get(scanID string) (*scanengine.GetScanResponse, error) {
-	req, err := http.NewRequest("GET", c.BaseURL.String()+fmt.Sprintf("scans/%v", scanID), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	v := c.ctx.Value(kithttp.ContextKeyRequestXRequestID)
-	if v != nil {
-		xReqID, ok := v.(string)
-		if ok {
-			req.Header.Set("X-Request-Id", xReqID)
-		}
-	}
-
-	response, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close() // nolint
-	responseBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.StatusCode != http.StatusOK {
-		// Try to read the response for a possible error message
-		b, errR := ioutil.ReadAll(response.Body)
-		if errR != nil {
-			return nil, errR
-		}
-
-		errMssg := string(b)
-		errCode := response.StatusCode
-		err := fmt.Errorf("%w: %v", ErrGettingScan, GenericError{Code: errCode, Msg: errMssg})
-		return nil, err
-	}
-
-	getScanResponse := &scanengine.GetScanResponse{}
-	err = json.Unmarshal(responseBytes, getScanResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling")
-	}
-	return getScanResponse, nil
-}
-
-// GetScans returns all the scans belonging to a team.
-func (c *Client) GetScans(externalID string) (*scanengine.GetScansResponse, error) {
-	req, err := http.NewRequest("GET", c.BaseURL.String()+fmt.Sprintf("scans?external_id=%v&all=true", externalID), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	v := c.ctx.Value(kithttp.ContextKeyRequestXRequestID)
-	if v != nil {
-		xReqID, ok := v.(string)
-		if ok {
-			req.Header.Set("X-Request-Id", xReqID)
-		}
-	}
-
-	response, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close() // nolint
-	responseBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusOK {
-		// Try to read the response for a possible error message
-		b, errR := ioutil.ReadAll(response.Body)
-		if errR != nil {
-			return nil, errR
-		}
-
-		errMssg := string(b)
-		errCode := response.StatusCode
-		err := fmt.Errorf("%w: %v", ErrGettingScans, GenericError{Code: errCode, Msg: errMssg})
-		return nil, err
-	}
-	getScansResponse := &scanengine.GetScansResponse{}
-	err = json.Unmarshal(responseBytes, getScansResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling")
-	}
-	return getScansResponse, nil
-}
-
-// Abort send a signal to the scan engine to try to abort a scan.
-func (c *Client) Abort(scanID string) (*scanengine.GetScanResponse, error) {
-	req, err := http.NewRequest("PUT", c.BaseURL.String()+fmt.Sprintf("scans/%v/abort", scanID), nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("X-Request-Id", c.ctx.Value(kithttp.ContextKeyRequestXRequestID).(string))
-	response, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close() // nolint
-	responseBytes, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-	if response.StatusCode != http.StatusAccepted {
-		// Try to read the response for a possible error message
-		b, errR := ioutil.ReadAll(response.Body)
-		if errR != nil {
-			return nil, errR
-		}
-
-		errMssg := string(b)
-		errCode := response.StatusCode
-		err := fmt.Errorf("%w: %v", ErrAbortingScans, GenericError{Code: errCode, Msg: errMssg})
-		return nil, err
-	}
-	getScanResponse := &scanengine.GetScanResponse{}
-	err = json.Unmarshal(responseBytes, getScanResponse)
-	if err != nil {
-		return nil, err
-	}
-	return getScanResponse, nil
-}
-
 func ptrStrToStr(input *string) string {
 	if input == nil {
 		return ""
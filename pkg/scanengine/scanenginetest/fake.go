@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package scanenginetest provides an in memory implementation of the scan
+// engine client to be used in tests.
+package scanenginetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adevinta/vulcan-api/pkg/scanengine"
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api/endpoint"
+)
+
+// Fake implements the scanengine.Client interface storing the scans in
+// memory. The zero value is ready to use.
+type Fake struct {
+	mu sync.Mutex
+	// Scans contains the scans known by the fake indexed by ID.
+	Scans map[string]scanengineapi.GetScanResponse
+	// Checks contains the checks of the scans indexed by scan ID.
+	Checks map[string][]scanengineapi.GetCheckResponse
+	// Requests contains the scan requests received by Create.
+	Requests []scanengineapi.ScanRequest
+	// Err, if not nil, is returned by all the operations.
+	Err error
+}
+
+var _ scanengine.Client = (*Fake)(nil)
+
+// Create stores a new running scan for the given request.
+func (f *Fake) Create(ctx context.Context, request scanengineapi.ScanRequest) (*scanengineapi.ScanResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Scans == nil {
+		f.Scans = map[string]scanengineapi.GetScanResponse{}
+	}
+	f.Requests = append(f.Requests, request)
+	id := fmt.Sprintf("scan-%d", len(f.Requests))
+	now := time.Now()
+	f.Scans[id] = scanengineapi.GetScanResponse{
+		ID:            id,
+		ExternalID:    request.ExternalID,
+		Status:        "RUNNING",
+		Trigger:       request.Trigger,
+		ScheduledTime: request.ScheduledTime,
+		StartTime:     &now,
+	}
+	return &scanengineapi.ScanResponse{ScanID: id}, nil
+}
+
+// Get returns the scan with the given ID.
+func (f *Fake) Get(ctx context.Context, scanID string) (*scanengineapi.GetScanResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	scan, ok := f.Scans[scanID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", scanengine.ErrGettingScan, scanengine.GenericError{Code: 404, Msg: "scan not found"})
+	}
+	return &scan, nil
+}
+
+// GetScans returns the scans with the given external ID.
+func (f *Fake) GetScans(ctx context.Context, externalID string) (*scanengineapi.GetScansResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	resp := &scanengineapi.GetScansResponse{Scans: []scanengineapi.GetScanResponse{}}
+	for _, scan := range f.Scans {
+		if scan.ExternalID == externalID {
+			resp.Scans = append(resp.Scans, scan)
+		}
+	}
+	return resp, nil
+}
+
+// GetScanChecks returns the checks of the given scan.
+func (f *Fake) GetScanChecks(ctx context.Context, scanID string) (*scanengineapi.GetChecksResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &scanengineapi.GetChecksResponse{Checks: f.Checks[scanID]}, nil
+}
+
+// Abort sets the status of the given scan to ABORTED.
+func (f *Fake) Abort(ctx context.Context, scanID string) (*scanengineapi.GetScanResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	scan, ok := f.Scans[scanID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", scanengine.ErrAbortingScans, scanengine.GenericError{Code: 404, Msg: "scan not found"})
+	}
+	now := time.Now()
+	scan.Status = "ABORTED"
+	scan.AbortedAt = &now
+	f.Scans[scanID] = scan
+	return &scan, nil
+}
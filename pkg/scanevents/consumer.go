@@ -0,0 +1,184 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package scanevents consumes the scan events published by the scan engine
+// from an SQS queue subscribed to the scan engine SNS topic.
+package scanevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+)
+
+const (
+	maxNumberOfMessages = 10
+	defaultWaitTime     = 20
+
+	snsNotificationType = "Notification"
+)
+
+// ErrInvalidEvent is returned when a message does not contain a scan event.
+var ErrInvalidEvent = errors.New("invalid scan event")
+
+// Config defines the queue the scan events are read from.
+type Config struct {
+	QueueArn string `mapstructure:"queue_arn"`
+	Endpoint string `mapstructure:"endpoint"`
+	// WaitTime is the number of seconds to wait for messages in each
+	// request to the queue.
+	WaitTime int64 `mapstructure:"wait_time"`
+}
+
+// Handler processes the scan events.
+type Handler interface {
+	HandleScanEvent(ctx context.Context, event scanengineapi.ScanNotification) error
+}
+
+// Handlers passes the scan events to several handlers in order. The
+// processing of an event stops at the first handler that returns an error,
+// so the event is processed again by all of them.
+type Handlers []Handler
+
+// HandleScanEvent passes the event to each of the handlers.
+func (h Handlers) HandleScanEvent(ctx context.Context, event scanengineapi.ScanNotification) error {
+	for _, handler := range h {
+		if err := handler.HandleScanEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Consumer reads the scan events from an SQS queue and passes them to a
+// Handler. The messages whose processing fails are not deleted from the queue
+// so they are processed again after their visibility timeout expires.
+type Consumer struct {
+	sqs      sqsiface.SQSAPI
+	queueURL string
+	waitTime int64
+	handler  Handler
+	logger   log.Logger
+}
+
+// NewConsumer returns a Consumer that reads from the queue defined in the
+// given config.
+func NewConsumer(cfg Config, handler Handler, logger log.Logger) (*Consumer, error) {
+	queueArn, err := arn.Parse(cfg.QueueArn)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	awsCfg := aws.NewConfig()
+	if queueArn.Region != "" {
+		awsCfg = awsCfg.WithRegion(queueArn.Region)
+	}
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+	srv := sqs.New(sess, awsCfg)
+	out, err := srv.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName:              aws.String(queueArn.Resource),
+		QueueOwnerAWSAccountId: aws.String(queueArn.AccountID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	waitTime := cfg.WaitTime
+	if waitTime <= 0 {
+		waitTime = defaultWaitTime
+	}
+	return &Consumer{
+		sqs:      srv,
+		queueURL: aws.StringValue(out.QueueUrl),
+		waitTime: waitTime,
+		handler:  handler,
+		logger:   logger,
+	}, nil
+}
+
+// Run reads and processes messages until the context is cancelled.
+func (c *Consumer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		out, err := c.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(c.queueURL),
+			MaxNumberOfMessages: aws.Int64(maxNumberOfMessages),
+			WaitTimeSeconds:     aws.Int64(c.waitTime),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			_ = level.Error(c.logger).Log("ScanEvents", "error reading messages", "err", err)
+			continue
+		}
+		for _, msg := range out.Messages {
+			c.process(ctx, msg)
+		}
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, msg *sqs.Message) {
+	event, err := decodeEvent(aws.StringValue(msg.Body))
+	if err != nil {
+		// Malformed messages are deleted because processing them again
+		// would fail the same way.
+		_ = level.Error(c.logger).Log("ScanEvents", "error decoding message", "MessageID", aws.StringValue(msg.MessageId), "err", err)
+	} else if err = c.handler.HandleScanEvent(ctx, event); err != nil {
+		_ = level.Error(c.logger).Log("ScanEvents", "error processing event", "ScanID", event.ScanID, "err", err)
+		return
+	}
+	_, err = c.sqs.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.queueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		_ = level.Error(c.logger).Log("ScanEvents", "error deleting message", "MessageID", aws.StringValue(msg.MessageId), "err", err)
+	}
+}
+
+// snsEnvelope is the body of the messages delivered by SNS to SQS when the
+// raw message delivery is not enabled in the subscription.
+type snsEnvelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// decodeEvent returns the scan event contained in the body of a message,
+// unwrapping it from the SNS envelope if needed.
+func decodeEvent(body string) (scanengineapi.ScanNotification, error) {
+	event := scanengineapi.ScanNotification{}
+	envelope := snsEnvelope{}
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return event, err
+	}
+	if envelope.Type == snsNotificationType {
+		body = envelope.Message
+	}
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		return event, err
+	}
+	if event.ScanID == "" {
+		return event, ErrInvalidEvent
+	}
+	return event, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package scanevents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	scanengineapi "github.com/adevinta/vulcan-scan-engine/pkg/api"
+)
+
+const rawEvent = `{"scan_id":"scan1","program_id":"team1@periodic-full-scan","status":"FINISHED","check_count":3}`
+
+func TestDecodeEvent(t *testing.T) {
+	want := scanengineapi.ScanNotification{
+		ScanID:     "scan1",
+		ProgramID:  "team1@periodic-full-scan",
+		Status:     "FINISHED",
+		CheckCount: 3,
+	}
+	tests := []struct {
+		name    string
+		body    string
+		want    scanengineapi.ScanNotification
+		wantErr bool
+	}{
+		{
+			name: "RawMessage",
+			body: rawEvent,
+			want: want,
+		},
+		{
+			name: "SNSEnvelope",
+			body: `{"Type":"Notification","MessageId":"m1","Message":"{\"scan_id\":\"scan1\",\"program_id\":\"team1@periodic-full-scan\",\"status\":\"FINISHED\",\"check_count\":3}"}`,
+			want: want,
+		},
+		{
+			name:    "NotAScanEvent",
+			body:    `{"check_id":"check1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidJSON",
+			body:    `scan1`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeEvent(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("decodeEvent() mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
+
+type sqsMock struct {
+	sqsiface.SQSAPI
+	deleted []string
+}
+
+func (m *sqsMock) DeleteMessageWithContext(ctx aws.Context, input *sqs.DeleteMessageInput, opts ...request.Option) (*sqs.DeleteMessageOutput, error) {
+	m.deleted = append(m.deleted, aws.StringValue(input.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+type handlerFunc func(ctx context.Context, event scanengineapi.ScanNotification) error
+
+func (f handlerFunc) HandleScanEvent(ctx context.Context, event scanengineapi.ScanNotification) error {
+	return f(ctx, event)
+}
+
+func TestConsumer_Process(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		handlerErr  error
+		wantDeleted []string
+	}{
+		{
+			name:        "Processed",
+			body:        rawEvent,
+			wantDeleted: []string{"r1"},
+		},
+		{
+			name:       "HandlerErrorKeepsMessage",
+			body:       rawEvent,
+			handlerErr: errors.New("scan engine unavailable"),
+		},
+		{
+			name:        "MalformedMessageIsDeleted",
+			body:        `scan1`,
+			wantDeleted: []string{"r1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &sqsMock{}
+			c := &Consumer{
+				sqs: mock,
+				handler: handlerFunc(func(ctx context.Context, event scanengineapi.ScanNotification) error {
+					return tt.handlerErr
+				}),
+				logger: log.NewNopLogger(),
+			}
+			c.process(context.Background(), &sqs.Message{Body: aws.String(tt.body), ReceiptHandle: aws.String("r1")})
+			if diff := cmp.Diff(tt.wantDeleted, mock.deleted); diff != "" {
+				t.Errorf("deleted messages mismatch (-want +got):\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package slabreaches notifies the recipients of the teams when the findings
+// of the teams breach their SLA.
+package slabreaches
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const defaultPollInterval = 3600
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the findings that breached their SLA.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ListTeams() ([]*api.Team, error)
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	NotifySLABreaches(ctx context.Context, teamID string) (int, error)
+}
+
+// Runner periodically notifies the breaches of the SLA of the findings of
+// all the teams.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Run notifies the SLA breaches until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, r.notify)
+}
+
+// notify notifies the SLA breaches of the findings of every team. The
+// breaches of a team that fail to be notified are not recorded, so they are
+// notified in the next check.
+func (r *Runner) notify(ctx context.Context) {
+	teams, err := r.store.ListTeams()
+	if err != nil {
+		_ = level.Error(r.logger).Log("SLABreaches", "error listing teams", "err", err)
+		return
+	}
+	for _, t := range teams {
+		n, err := r.service.NotifySLABreaches(ctx, t.ID)
+		if err != nil {
+			_ = level.Error(r.logger).Log("SLABreaches", "error notifying sla breaches", "TeamID", t.ID, "err", err)
+			continue
+		}
+		if n > 0 {
+			_ = level.Info(r.logger).Log("SLABreaches", "sla breaches notified", "TeamID", t.ID, "Breaches", n)
+		}
+	}
+}
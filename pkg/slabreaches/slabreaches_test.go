@@ -0,0 +1,100 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package slabreaches
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemoryStore struct {
+	teams []*api.Team
+	err   error
+}
+
+func (s *inMemoryStore) ListTeams() ([]*api.Team, error) {
+	return s.teams, s.err
+}
+
+type inMemoryService struct {
+	// breaches contains the number of breaches of each team that were not
+	// notified yet.
+	breaches map[string]int
+	// errs contains the errors returned when notifying the breaches of the
+	// teams with the given IDs.
+	errs     map[string]error
+	notified map[string]int
+}
+
+func (s *inMemoryService) NotifySLABreaches(ctx context.Context, teamID string) (int, error) {
+	if err := s.errs[teamID]; err != nil {
+		return 0, err
+	}
+	n := s.breaches[teamID]
+	delete(s.breaches, teamID)
+	if n > 0 {
+		s.notified[teamID] += n
+	}
+	return n, nil
+}
+
+func TestRunnerNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		store        *inMemoryStore
+		breaches     map[string]int
+		serviceErrs  map[string]error
+		runs         int
+		wantNotified map[string]int
+	}{
+		{
+			name:         "NotifiesEveryTeam",
+			store:        &inMemoryStore{teams: []*api.Team{{ID: "t1"}, {ID: "t2"}, {ID: "t3"}}},
+			breaches:     map[string]int{"t1": 2, "t3": 1},
+			runs:         1,
+			wantNotified: map[string]int{"t1": 2, "t3": 1},
+		},
+		{
+			name:         "NotifiesBreachesOnce",
+			store:        &inMemoryStore{teams: []*api.Team{{ID: "t1"}}},
+			breaches:     map[string]int{"t1": 3},
+			runs:         2,
+			wantNotified: map[string]int{"t1": 3},
+		},
+		{
+			name:         "ContinuesAfterTeamError",
+			store:        &inMemoryStore{teams: []*api.Team{{ID: "t1"}, {ID: "t2"}}},
+			breaches:     map[string]int{"t1": 1, "t2": 1},
+			serviceErrs:  map[string]error{"t1": errors.New("reports unavailable")},
+			runs:         1,
+			wantNotified: map[string]int{"t2": 1},
+		},
+		{
+			name:         "StoreError",
+			store:        &inMemoryStore{err: errors.New("db unavailable")},
+			breaches:     map[string]int{"t1": 1},
+			runs:         1,
+			wantNotified: map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &inMemoryService{breaches: tt.breaches, errs: tt.serviceErrs, notified: map[string]int{}}
+			r := New(log.NewNopLogger(), tt.store, service, Config{})
+			for i := 0; i < tt.runs; i++ {
+				r.notify(context.Background())
+			}
+			if diff := cmp.Diff(tt.wantNotified, service.notified); diff != "" {
+				t.Errorf("notified mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package statssnapshots stores every day the stats of the findings of the
+// teams, so the trends of the stats can be served without querying the
+// vulnerability DB.
+package statssnapshots
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const (
+	defaultPollInterval = 3600
+
+	// staleClaim is the time after which the snapshots of a team that are
+	// not done are considered abandoned by the instance that claimed them,
+	// so they can be claimed again.
+	staleClaim = time.Hour
+)
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the teams whose stats of the previous day are not
+	// snapshotted yet.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ListTeams() ([]*api.Team, error)
+	ClaimStatsSnapshots(teamID string, date, staleBefore time.Time) (bool, error)
+	ReleaseStatsSnapshots(teamID string, date time.Time, done bool) error
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	SnapshotTeamStats(ctx context.Context, teamID, date string) error
+}
+
+// Runner snapshots once a day the stats of the previous day of all the
+// teams.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+	now     func() time.Time
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Run snapshots the stats of the teams until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, r.snapshot)
+}
+
+// snapshot stores the stats of the previous day of the teams whose
+// snapshots of that day are not claimed. The claim is released when the
+// stats fail to be snapshotted.
+func (r *Runner) snapshot(ctx context.Context) {
+	now := r.now()
+	day := now.UTC().AddDate(0, 0, -1)
+	date := day.Format(api.StatsDateFormat)
+	teams, err := r.store.ListTeams()
+	if err != nil {
+		_ = level.Error(r.logger).Log("StatsSnapshots", "error listing teams", "err", err)
+		return
+	}
+	for _, t := range teams {
+		claimed, err := r.store.ClaimStatsSnapshots(t.ID, day, now.Add(-staleClaim))
+		if err != nil {
+			_ = level.Error(r.logger).Log("StatsSnapshots", "error claiming stats snapshots", "TeamID", t.ID, "Date", date, "err", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		err = r.service.SnapshotTeamStats(ctx, t.ID, date)
+		if err != nil {
+			_ = level.Error(r.logger).Log("StatsSnapshots", "error snapshotting stats", "TeamID", t.ID, "Date", date, "err", err)
+		}
+		if err := r.store.ReleaseStatsSnapshots(t.ID, day, err == nil); err != nil {
+			_ = level.Error(r.logger).Log("StatsSnapshots", "error releasing stats snapshots", "TeamID", t.ID, "Date", date, "err", err)
+		}
+	}
+}
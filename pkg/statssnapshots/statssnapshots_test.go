@@ -0,0 +1,143 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package statssnapshots
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// claim is the claim of the snapshots of a team of a day.
+type claim struct {
+	claimedAt time.Time
+	done      bool
+}
+
+type inMemoryStore struct {
+	teams []*api.Team
+	err   error
+	// claims contains the claims by team ID and date.
+	claims map[string]claim
+}
+
+func (s *inMemoryStore) ListTeams() ([]*api.Team, error) {
+	return s.teams, s.err
+}
+
+func (s *inMemoryStore) ClaimStatsSnapshots(teamID string, date, staleBefore time.Time) (bool, error) {
+	key := teamID + "/" + date.Format(api.StatsDateFormat)
+	if c, ok := s.claims[key]; ok && (c.done || !c.claimedAt.Before(staleBefore)) {
+		return false, nil
+	}
+	s.claims[key] = claim{claimedAt: staleBefore.Add(staleClaim)}
+	return true, nil
+}
+
+func (s *inMemoryStore) ReleaseStatsSnapshots(teamID string, date time.Time, done bool) error {
+	key := teamID + "/" + date.Format(api.StatsDateFormat)
+	if !done {
+		delete(s.claims, key)
+		return nil
+	}
+	s.claims[key] = claim{claimedAt: s.claims[key].claimedAt, done: true}
+	return nil
+}
+
+type inMemoryService struct {
+	// errs contains the number of times snapshotting the stats of the
+	// teams with the given IDs fails.
+	errs map[string]int
+	// snapshots contains the dates of the snapshots of each team.
+	snapshots map[string][]string
+}
+
+func (s *inMemoryService) SnapshotTeamStats(ctx context.Context, teamID, date string) error {
+	if s.errs[teamID] > 0 {
+		s.errs[teamID]--
+		return errors.New("vulnerability db unavailable")
+	}
+	s.snapshots[teamID] = append(s.snapshots[teamID], date)
+	return nil
+}
+
+func TestRunnerSnapshot(t *testing.T) {
+	day1 := time.Date(2021, 3, 2, 1, 0, 0, 0, time.UTC)
+	day2 := time.Date(2021, 3, 3, 1, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		store         *inMemoryStore
+		serviceErrs   map[string]int
+		runs          []time.Time
+		wantSnapshots map[string][]string
+	}{
+		{
+			name:          "SnapshotsEveryTeam",
+			store:         &inMemoryStore{teams: []*api.Team{{ID: "t1"}, {ID: "t2"}}},
+			runs:          []time.Time{day1},
+			wantSnapshots: map[string][]string{"t1": {"2021-03-01"}, "t2": {"2021-03-01"}},
+		},
+		{
+			name:          "SnapshotsOncePerDay",
+			store:         &inMemoryStore{teams: []*api.Team{{ID: "t1"}}},
+			runs:          []time.Time{day1, day1.Add(time.Hour), day2},
+			wantSnapshots: map[string][]string{"t1": {"2021-03-01", "2021-03-02"}},
+		},
+		{
+			name:          "RetriesTeamError",
+			store:         &inMemoryStore{teams: []*api.Team{{ID: "t1"}, {ID: "t2"}}},
+			serviceErrs:   map[string]int{"t1": 1},
+			runs:          []time.Time{day1, day1.Add(time.Hour)},
+			wantSnapshots: map[string][]string{"t1": {"2021-03-01"}, "t2": {"2021-03-01"}},
+		},
+		{
+			name: "SkipsClaimedTeam",
+			store: &inMemoryStore{
+				teams:  []*api.Team{{ID: "t1"}, {ID: "t2"}},
+				claims: map[string]claim{"t1/2021-03-01": {claimedAt: day1}},
+			},
+			runs:          []time.Time{day1},
+			wantSnapshots: map[string][]string{"t2": {"2021-03-01"}},
+		},
+		{
+			name: "RetriesStaleClaim",
+			store: &inMemoryStore{
+				teams:  []*api.Team{{ID: "t1"}},
+				claims: map[string]claim{"t1/2021-03-01": {claimedAt: day1}},
+			},
+			runs:          []time.Time{day1, day1.Add(2 * time.Hour)},
+			wantSnapshots: map[string][]string{"t1": {"2021-03-01"}},
+		},
+		{
+			name:          "StoreError",
+			store:         &inMemoryStore{err: errors.New("db unavailable")},
+			runs:          []time.Time{day1},
+			wantSnapshots: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.store.claims == nil {
+				tt.store.claims = map[string]claim{}
+			}
+			service := &inMemoryService{errs: tt.serviceErrs, snapshots: map[string][]string{}}
+			r := New(log.NewNopLogger(), tt.store, service, Config{})
+			for _, now := range tt.runs {
+				now := now
+				r.now = func() time.Time { return now }
+				r.snapshot(context.Background())
+			}
+			if diff := cmp.Diff(tt.wantSnapshots, service.snapshots); diff != "" {
+				t.Errorf("snapshots mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package subscriptions sends the reports of the report subscriptions of the
+// teams when they are due.
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const defaultPollInterval = 60
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// checks of the subscriptions that are due.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ListDueReportSubscriptions(now time.Time) ([]*api.ReportSubscription, error)
+	ClaimReportSubscription(subscription api.ReportSubscription, nextRunAt, lastRunAt time.Time) (bool, error)
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	SendReportSubscription(ctx context.Context, teamID, subscriptionID string) error
+}
+
+// Runner periodically sends the reports of the subscriptions that are due.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+	now     func() time.Time
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Run sends the due reports until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, r.dispatch)
+}
+
+// dispatch sends the reports of the subscriptions that are due. A report is
+// not sent again if it fails, the subscription just waits for its next run.
+func (r *Runner) dispatch(ctx context.Context) {
+	now := r.now()
+	subscriptions, err := r.store.ListDueReportSubscriptions(now)
+	if err != nil {
+		_ = level.Error(r.logger).Log("ReportSubscriptions", "error listing due subscriptions", "err", err)
+		return
+	}
+	for _, s := range subscriptions {
+		schedule, err := s.Schedule()
+		if err != nil {
+			_ = level.Error(r.logger).Log("ReportSubscriptions", "invalid schedule", "SubscriptionID", s.ID, "err", err)
+			continue
+		}
+		claimed, err := r.store.ClaimReportSubscription(*s, schedule.Next(now), now)
+		if err != nil {
+			_ = level.Error(r.logger).Log("ReportSubscriptions", "error claiming subscription", "SubscriptionID", s.ID, "err", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := r.service.SendReportSubscription(ctx, s.TeamID, s.ID); err != nil {
+			_ = level.Error(r.logger).Log("ReportSubscriptions", "error sending report", "SubscriptionID", s.ID, "TeamID", s.TeamID, "err", err)
+		}
+	}
+}
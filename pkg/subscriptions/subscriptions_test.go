@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/common"
+)
+
+type inMemoryStore struct {
+	subscriptions []*api.ReportSubscription
+	// claimed contains the IDs of the subscriptions already claimed by
+	// another instance of the API.
+	claimed map[string]bool
+}
+
+func (s *inMemoryStore) ListDueReportSubscriptions(now time.Time) ([]*api.ReportSubscription, error) {
+	due := []*api.ReportSubscription{}
+	for _, sub := range s.subscriptions {
+		if !sub.IsDisabled() && sub.NextRunAt != nil && !sub.NextRunAt.After(now) {
+			copied := *sub
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (s *inMemoryStore) ClaimReportSubscription(subscription api.ReportSubscription, nextRunAt, lastRunAt time.Time) (bool, error) {
+	if s.claimed[subscription.ID] {
+		return false, nil
+	}
+	for _, sub := range s.subscriptions {
+		if sub.ID == subscription.ID {
+			sub.NextRunAt = &nextRunAt
+			sub.LastRunAt = &lastRunAt
+		}
+	}
+	return true, nil
+}
+
+type inMemoryService struct {
+	sent []string
+	err  error
+}
+
+func (s *inMemoryService) SendReportSubscription(ctx context.Context, teamID, subscriptionID string) error {
+	s.sent = append(s.sent, teamID+"/"+subscriptionID)
+	return s.err
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestRunnerDispatch(t *testing.T) {
+	now := time.Date(2021, 6, 7, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		subscriptions []*api.ReportSubscription
+		claimed       map[string]bool
+		serviceErr    error
+		wantSent      []string
+		wantNextRunAt map[string]*time.Time
+	}{
+		{
+			name: "SendsDueSubscriptions",
+			subscriptions: []*api.ReportSubscription{
+				{ID: "s1", TeamID: "t1", Cron: "0 8 * * *", NextRunAt: timePtr(now.Add(-30 * time.Minute))},
+				{ID: "s2", TeamID: "t1", Cron: "0 8 * * *", NextRunAt: timePtr(now.Add(time.Hour))},
+			},
+			wantSent: []string{"t1/s1"},
+			wantNextRunAt: map[string]*time.Time{
+				"s1": timePtr(time.Date(2021, 6, 8, 8, 0, 0, 0, time.UTC)),
+				"s2": timePtr(now.Add(time.Hour)),
+			},
+		},
+		{
+			name: "SkipsDisabledSubscriptions",
+			subscriptions: []*api.ReportSubscription{
+				{ID: "s1", TeamID: "t1", Cron: "0 8 * * *", Disabled: common.Bool(true), NextRunAt: timePtr(now.Add(-time.Minute))},
+			},
+			wantNextRunAt: map[string]*time.Time{
+				"s1": timePtr(now.Add(-time.Minute)),
+			},
+		},
+		{
+			name: "SkipsSubscriptionsClaimedByOthers",
+			subscriptions: []*api.ReportSubscription{
+				{ID: "s1", TeamID: "t1", Cron: "0 8 * * *", NextRunAt: timePtr(now.Add(-time.Minute))},
+				{ID: "s2", TeamID: "t2", Cron: "0 8 * * *", NextRunAt: timePtr(now.Add(-time.Minute))},
+			},
+			claimed:  map[string]bool{"s1": true},
+			wantSent: []string{"t2/s2"},
+			wantNextRunAt: map[string]*time.Time{
+				"s1": timePtr(now.Add(-time.Minute)),
+				"s2": timePtr(time.Date(2021, 6, 8, 8, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name: "ReschedulesWhenSendingFails",
+			subscriptions: []*api.ReportSubscription{
+				{ID: "s1", TeamID: "t1", Cron: "0 8 * * 1", NextRunAt: timePtr(now.Add(-time.Minute))},
+			},
+			serviceErr: errors.New("reports not available"),
+			wantSent:   []string{"t1/s1"},
+			wantNextRunAt: map[string]*time.Time{
+				"s1": timePtr(time.Date(2021, 6, 14, 8, 0, 0, 0, time.UTC)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inMemoryStore{subscriptions: tt.subscriptions, claimed: tt.claimed}
+			service := &inMemoryService{err: tt.serviceErr}
+			r := New(log.NewNopLogger(), store, service, Config{})
+			r.now = func() time.Time { return now }
+
+			r.dispatch(context.Background())
+
+			if diff := cmp.Diff(tt.wantSent, service.sent); diff != "" {
+				t.Errorf("sent reports mismatch (-want +got):\n%s", diff)
+			}
+			gotNextRunAt := map[string]*time.Time{}
+			for _, s := range store.subscriptions {
+				gotNextRunAt[s.ID] = s.NextRunAt
+			}
+			if diff := cmp.Diff(tt.wantNextRunAt, gotNextRunAt); diff != "" {
+				t.Errorf("next run times mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
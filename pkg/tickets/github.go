@@ -0,0 +1,221 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+const (
+	// DefaultGitHubURL is the URL of the GitHub API used when the provider
+	// of a team does not define one.
+	DefaultGitHubURL = "https://api.github.com"
+
+	githubIssuesPath = "/repos/%s/issues"
+	githubIssuePath  = "/repos/%s/issues/%s"
+
+	githubStateClosed = "closed"
+)
+
+// githubResolutions maps the reasons a GitHub issue was closed for to the
+// resolutions of the tickets.
+var githubResolutions = map[string]string{
+	"completed":   "Done",
+	"not_planned": "Won't Fix",
+}
+
+type githubIssue struct {
+	Number      int           `json:"number"`
+	Title       string        `json:"title"`
+	Body        string        `json:"body"`
+	State       string        `json:"state"`
+	StateReason string        `json:"state_reason"`
+	HTMLURL     string        `json:"html_url"`
+	Labels      []githubLabel `json:"labels"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubProvider struct {
+	baseURL    string
+	repository string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitHubProvider returns a ticket provider that creates the tickets as
+// issues of the given repository, in the form owner/repository.
+func NewGitHubProvider(httpClient *http.Client, baseURL, repository, token string) Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = DefaultGitHubURL
+	}
+	return &githubProvider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		repository: repository,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (p *githubProvider) performRequest(ctx context.Context, method, path string, query url.Values, payload interface{}, v interface{}) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return false, err
+		}
+		body = bytes.NewBuffer(data)
+	}
+	u := p.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if payload != nil {
+		req.Header.Set("Content-type", "application/json")
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close() // nolint
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case !IsHTTPStatusOk(resp.StatusCode):
+		return false, ParseHTTPErr(resp.StatusCode, string(content))
+	}
+	return true, json.Unmarshal(content, v)
+}
+
+// CreateTicket creates an issue for a finding. The issue is labeled with the
+// finding so it can be found later. GitHub answers with a not found status
+// when the repository does not exist or is not visible to the token, which is
+// returned as an error.
+func (p *githubProvider) CreateTicket(ctx context.Context, payload api.FindingTicketCreate) (*api.Ticket, error) {
+	issue := map[string]interface{}{
+		"title":  payload.Summary,
+		"body":   payload.Description,
+		"labels": append([]string{FindingLabel(payload.FindingID)}, payload.Labels...),
+	}
+	var created githubIssue
+	found, err := p.performRequest(ctx, http.MethodPost, fmt.Sprintf(githubIssuesPath, p.repository), nil, issue, &created)
+	if err != nil {
+		return nil, err
+	}
+	if !found || created.Number == 0 {
+		return nil, errors.Validation(fmt.Sprintf("the repository %s does not exist or is not accessible", p.repository))
+	}
+	return p.ticket(payload.TeamID, payload.FindingID, created), nil
+}
+
+// GetFindingTicket returns the newest issue labeled with a finding.
+func (p *githubProvider) GetFindingTicket(ctx context.Context, findingID, teamID string) (*api.Ticket, error) {
+	query := url.Values{
+		"labels":    {FindingLabel(findingID)},
+		"state":     {"all"},
+		"sort":      {"created"},
+		"direction": {"desc"},
+		"per_page":  {"1"},
+	}
+	var issues []githubIssue
+	found, err := p.performRequest(ctx, http.MethodGet, fmt.Sprintf(githubIssuesPath, p.repository), query, nil, &issues)
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(issues) == 0 {
+		return &api.Ticket{}, nil
+	}
+	return p.ticket(teamID, findingID, issues[0]), nil
+}
+
+// GetTicket returns an issue given its number. An empty ticket is returned if
+// the issue does not exist.
+func (p *githubProvider) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	var issue githubIssue
+	found, err := p.performRequest(ctx, http.MethodGet, fmt.Sprintf(githubIssuePath, p.repository, ticketID), nil, nil, &issue)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &api.Ticket{}, nil
+	}
+	return p.ticket(teamID, "", issue), nil
+}
+
+// FixTicket closes an issue as completed.
+func (p *githubProvider) FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	update := map[string]string{
+		"state":        githubStateClosed,
+		"state_reason": "completed",
+	}
+	var issue githubIssue
+	found, err := p.performRequest(ctx, http.MethodPatch, fmt.Sprintf(githubIssuePath, p.repository, ticketID), nil, update, &issue)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &api.Ticket{}, nil
+	}
+	return p.ticket(teamID, "", issue), nil
+}
+
+func (p *githubProvider) ticket(teamID, findingID string, issue githubIssue) *api.Ticket {
+	t := vulcantracker.Ticket{
+		ID:          strconv.Itoa(issue.Number),
+		Key:         fmt.Sprintf("%s#%d", p.repository, issue.Number),
+		TeamID:      teamID,
+		FindingID:   findingID,
+		Summary:     issue.Title,
+		Description: issue.Body,
+		Project:     p.repository,
+		Status:      issue.State,
+		URLTracker:  issue.HTMLURL,
+	}
+	for _, l := range issue.Labels {
+		if id, ok := findingFromLabel(l.Name); ok {
+			if t.FindingID == "" {
+				t.FindingID = id
+			}
+			continue
+		}
+		t.Labels = append(t.Labels, l.Name)
+	}
+	if issue.State == githubStateClosed {
+		t.Resolution = githubResolutions[issue.StateReason]
+		if t.Resolution == "" {
+			t.Resolution = githubResolutions["completed"]
+		}
+	}
+	return &api.Ticket{Ticket: t}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+)
+
+// githubStandIn is an in memory stand-in of the issues API of GitHub.
+type githubStandIn struct {
+	t      *testing.T
+	issues []githubIssue
+}
+
+func (g *githubStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/repos/adevinta/vulcan/issues")
+	switch {
+	case path == "" && r.Method == http.MethodPost:
+		var req struct {
+			Title  string   `json:"title"`
+			Body   string   `json:"body"`
+			Labels []string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		issue := githubIssue{
+			Number:  len(g.issues) + 1,
+			Title:   req.Title,
+			Body:    req.Body,
+			State:   "open",
+			HTMLURL: fmt.Sprintf("https://github.com/adevinta/vulcan/issues/%d", len(g.issues)+1),
+		}
+		for _, l := range req.Labels {
+			issue.Labels = append(issue.Labels, githubLabel{Name: l})
+		}
+		g.issues = append(g.issues, issue)
+		g.write(w, http.StatusCreated, issue)
+	case path == "" && r.Method == http.MethodGet:
+		issues := []githubIssue{}
+		for i := len(g.issues) - 1; i >= 0; i-- {
+			for _, l := range g.issues[i].Labels {
+				if l.Name == r.URL.Query().Get("labels") {
+					issues = append(issues, g.issues[i])
+				}
+			}
+		}
+		g.write(w, http.StatusOK, issues)
+	default:
+		n, err := strconv.Atoi(strings.TrimPrefix(path, "/"))
+		if err != nil || n < 1 || n > len(g.issues) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			var req struct {
+				State       string `json:"state"`
+				StateReason string `json:"state_reason"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			g.issues[n-1].State = req.State
+			g.issues[n-1].StateReason = req.StateReason
+		}
+		g.write(w, http.StatusOK, g.issues[n-1])
+	}
+}
+
+func (g *githubStandIn) write(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		g.t.Errorf("error writing response: %v", err)
+	}
+}
+
+func TestGitHubProvider(t *testing.T) {
+	standIn := &githubStandIn{
+		t: t,
+		issues: []githubIssue{
+			{
+				Number:      1,
+				Title:       "Rejected",
+				State:       "closed",
+				StateReason: "not_planned",
+				HTMLURL:     "https://github.com/adevinta/vulcan/issues/1",
+				Labels:      []githubLabel{{Name: FindingLabel("f0")}},
+			},
+		},
+	}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+	ctx := context.Background()
+	p := NewGitHubProvider(srv.Client(), srv.URL, "adevinta/vulcan", "token")
+
+	created, err := p.CreateTicket(ctx, api.FindingTicketCreate{
+		FindingID:   "f1",
+		TeamID:      "t1",
+		Summary:     "Outdated TLS in example.com",
+		Description: "TLS 1.0 is enabled",
+		Labels:      []string{"security"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating ticket: %v", err)
+	}
+	want := &api.Ticket{Ticket: vulcantracker.Ticket{
+		ID:          "2",
+		Key:         "adevinta/vulcan#2",
+		TeamID:      "t1",
+		FindingID:   "f1",
+		Summary:     "Outdated TLS in example.com",
+		Description: "TLS 1.0 is enabled",
+		Project:     "adevinta/vulcan",
+		Status:      "open",
+		Labels:      []string{"security"},
+		URLTracker:  "https://github.com/adevinta/vulcan/issues/2",
+	}}
+	if diff := cmp.Diff(want, created); diff != "" {
+		t.Errorf("created ticket mismatch (-want +got):\n%s", diff)
+	}
+
+	found, err := p.GetFindingTicket(ctx, "f1", "t1")
+	if err != nil {
+		t.Fatalf("unexpected error getting finding ticket: %v", err)
+	}
+	if diff := cmp.Diff(want, found); diff != "" {
+		t.Errorf("finding ticket mismatch (-want +got):\n%s", diff)
+	}
+
+	fixed, err := p.FixTicket(ctx, "t1", "2")
+	if err != nil {
+		t.Fatalf("unexpected error fixing ticket: %v", err)
+	}
+	if fixed.Ticket.Status != "closed" || fixed.Ticket.Resolution != "Done" {
+		t.Errorf("got fixed ticket with status %q and resolution %q", fixed.Ticket.Status, fixed.Ticket.Resolution)
+	}
+
+	rejected, err := p.GetTicket(ctx, "t1", "1")
+	if err != nil {
+		t.Fatalf("unexpected error getting ticket: %v", err)
+	}
+	if !api.IsTicketResolutionWontFix(rejected.Ticket.Resolution) || rejected.Ticket.FindingID != "f0" {
+		t.Errorf("got rejected ticket with resolution %q and finding %q", rejected.Ticket.Resolution, rejected.Ticket.FindingID)
+	}
+
+	missing, err := p.GetTicket(ctx, "t1", "10")
+	if err != nil {
+		t.Fatalf("unexpected error getting missing ticket: %v", err)
+	}
+	if missing.Ticket.ID != "" {
+		t.Errorf("got missing ticket %+v, want empty ticket", missing.Ticket)
+	}
+
+	noTicket, err := p.GetFindingTicket(ctx, "f2", "t1")
+	if err != nil {
+		t.Fatalf("unexpected error getting finding without ticket: %v", err)
+	}
+	if noTicket.Ticket.ID != "" {
+		t.Errorf("got finding ticket %+v, want empty ticket", noTicket.Ticket)
+	}
+
+	// GitHub answers with a not found status when the repository does not
+	// exist or is private and not visible to the token.
+	inaccessible := NewGitHubProvider(srv.Client(), srv.URL, "adevinta/private", "token")
+	if _, err := inaccessible.CreateTicket(ctx, api.FindingTicketCreate{FindingID: "f1", TeamID: "t1"}); err == nil {
+		t.Error("expected error creating a ticket in an inaccessible repository")
+	}
+
+	unauthorized := NewGitHubProvider(srv.Client(), srv.URL, "adevinta/vulcan", "invalid")
+	if _, err := unauthorized.GetTicket(ctx, "t1", "1"); err == nil {
+		t.Error("expected error using invalid credentials")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+const (
+	jiraIssuesPath      = "/rest/api/2/issue"
+	jiraIssuePath       = "/rest/api/2/issue/%s"
+	jiraTransitionsPath = "/rest/api/2/issue/%s/transitions"
+	jiraSearchPath      = "/rest/api/2/search"
+	jiraBrowsePath      = "/browse/%s"
+
+	// jiraIssueType is the type of the issues created for the findings.
+	jiraIssueType = "Bug"
+	// jiraStatusCategoryDone is the key of the category of the statuses of
+	// the resolved issues.
+	jiraStatusCategoryDone = "done"
+	jiraFields             = "summary,description,status,resolution,labels,project"
+)
+
+type jiraIssue struct {
+	ID     string          `json:"id"`
+	Key    string          `json:"key"`
+	Fields jiraIssueFields `json:"fields"`
+}
+
+type jiraIssueFields struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Status      *struct {
+		Name string `json:"name"`
+	} `json:"status"`
+	Resolution *struct {
+		Name string `json:"name"`
+	} `json:"resolution"`
+	Labels  []string `json:"labels"`
+	Project *struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type jiraTransition struct {
+	ID string `json:"id"`
+	To struct {
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"to"`
+}
+
+type jiraProvider struct {
+	baseURL    string
+	project    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewJiraProvider returns a ticket provider that creates the tickets as
+// issues of a project of a Jira compatible REST API, authenticating with the
+// given personal access token.
+func NewJiraProvider(httpClient *http.Client, baseURL, project, token string) Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &jiraProvider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		project:    project,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (p *jiraProvider) performRequest(ctx context.Context, method, path string, query url.Values, payload interface{}, v interface{}) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return false, err
+		}
+		body = bytes.NewBuffer(data)
+	}
+	u := p.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	if payload != nil {
+		req.Header.Set("Content-type", "application/json")
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close() // nolint
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case !IsHTTPStatusOk(resp.StatusCode):
+		return false, ParseHTTPErr(resp.StatusCode, string(content))
+	}
+	if v == nil || len(content) == 0 {
+		return true, nil
+	}
+	return true, json.Unmarshal(content, v)
+}
+
+// CreateTicket creates an issue for a finding in the project of the provider.
+// The issue is labeled with the finding so it can be found later. A not found
+// status creating or reading back the issue is returned as an error.
+func (p *jiraProvider) CreateTicket(ctx context.Context, payload api.FindingTicketCreate) (*api.Ticket, error) {
+	issue := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": p.project},
+			"issuetype":   map[string]string{"name": jiraIssueType},
+			"summary":     payload.Summary,
+			"description": payload.Description,
+			"labels":      append([]string{FindingLabel(payload.FindingID)}, payload.Labels...),
+		},
+	}
+	var created jiraIssue
+	found, err := p.performRequest(ctx, http.MethodPost, jiraIssuesPath, nil, issue, &created)
+	if err != nil {
+		return nil, err
+	}
+	if !found || created.Key == "" {
+		return nil, errors.Validation(fmt.Sprintf("the issue could not be created in the project %s", p.project))
+	}
+	ticket, err := p.GetTicket(ctx, payload.TeamID, created.Key)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Ticket.ID == "" {
+		return nil, errors.Validation(fmt.Sprintf("the issue %s is not accessible", created.Key))
+	}
+	return ticket, nil
+}
+
+// GetFindingTicket returns the newest issue of the project labeled with a
+// finding.
+func (p *jiraProvider) GetFindingTicket(ctx context.Context, findingID, teamID string) (*api.Ticket, error) {
+	query := url.Values{
+		"jql":        {fmt.Sprintf(`project = "%s" AND labels = "%s" ORDER BY created DESC`, p.project, FindingLabel(findingID))},
+		"maxResults": {"1"},
+		"fields":     {jiraFields},
+	}
+	var result struct {
+		Issues []jiraIssue `json:"issues"`
+	}
+	found, err := p.performRequest(ctx, http.MethodGet, jiraSearchPath, query, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(result.Issues) == 0 {
+		return &api.Ticket{}, nil
+	}
+	return p.ticket(teamID, result.Issues[0]), nil
+}
+
+// GetTicket returns an issue given its key or ID. An empty ticket is returned
+// if the issue does not exist.
+func (p *jiraProvider) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	query := url.Values{"fields": {jiraFields}}
+	var issue jiraIssue
+	found, err := p.performRequest(ctx, http.MethodGet, fmt.Sprintf(jiraIssuePath, ticketID), query, nil, &issue)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &api.Ticket{}, nil
+	}
+	return p.ticket(teamID, issue), nil
+}
+
+// FixTicket resolves an issue by applying the first of its transitions that
+// leads to a done status.
+func (p *jiraProvider) FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	var result struct {
+		Transitions []jiraTransition `json:"transitions"`
+	}
+	found, err := p.performRequest(ctx, http.MethodGet, fmt.Sprintf(jiraTransitionsPath, ticketID), nil, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &api.Ticket{}, nil
+	}
+	var transitionID string
+	for _, t := range result.Transitions {
+		if t.To.StatusCategory.Key == jiraStatusCategoryDone {
+			transitionID = t.ID
+			break
+		}
+	}
+	if transitionID == "" {
+		return nil, errors.Validation(fmt.Sprintf("no transition to a done status for the issue %s", ticketID))
+	}
+	transition := map[string]interface{}{
+		"transition": map[string]string{"id": transitionID},
+	}
+	if _, err := p.performRequest(ctx, http.MethodPost, fmt.Sprintf(jiraTransitionsPath, ticketID), nil, transition, nil); err != nil {
+		return nil, err
+	}
+	return p.GetTicket(ctx, teamID, ticketID)
+}
+
+func (p *jiraProvider) ticket(teamID string, issue jiraIssue) *api.Ticket {
+	t := vulcantracker.Ticket{
+		ID:          issue.Key,
+		Key:         issue.Key,
+		TeamID:      teamID,
+		Summary:     issue.Fields.Summary,
+		Description: issue.Fields.Description,
+		Project:     p.project,
+		TicketType:  jiraIssueType,
+		URLTracker:  p.baseURL + fmt.Sprintf(jiraBrowsePath, issue.Key),
+	}
+	if issue.Fields.Project != nil {
+		t.Project = issue.Fields.Project.Key
+	}
+	if issue.Fields.Status != nil {
+		t.Status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Resolution != nil {
+		t.Resolution = issue.Fields.Resolution.Name
+	}
+	for _, l := range issue.Fields.Labels {
+		if id, ok := findingFromLabel(l); ok {
+			if t.FindingID == "" {
+				t.FindingID = id
+			}
+			continue
+		}
+		t.Labels = append(t.Labels, l)
+	}
+	return &api.Ticket{Ticket: t}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+)
+
+// jiraStandIn is an in memory stand-in of the REST API of Jira. Its issues
+// can be resolved with the transition "31".
+type jiraStandIn struct {
+	t      *testing.T
+	issues []map[string]interface{}
+}
+
+func (j *jiraStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.URL.Path == jiraIssuesPath && r.Method == http.MethodPost:
+		var req struct {
+			Fields struct {
+				Project     struct{ Key string } `json:"project"`
+				Summary     string               `json:"summary"`
+				Description string               `json:"description"`
+				Labels      []string             `json:"labels"`
+			} `json:"fields"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Fields.Project.Key == "MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		key := fmt.Sprintf("%s-%d", req.Fields.Project.Key, len(j.issues)+1)
+		j.issues = append(j.issues, map[string]interface{}{
+			"id":  fmt.Sprint(10000 + len(j.issues)),
+			"key": key,
+			"fields": map[string]interface{}{
+				"summary":     req.Fields.Summary,
+				"description": req.Fields.Description,
+				"labels":      req.Fields.Labels,
+				"project":     map[string]string{"key": req.Fields.Project.Key},
+				"status":      map[string]string{"name": "To Do"},
+				"resolution":  nil,
+			},
+		})
+		j.write(w, http.StatusCreated, map[string]string{"key": key})
+	case r.URL.Path == jiraSearchPath:
+		issues := []map[string]interface{}{}
+		for _, issue := range j.issues {
+			labels := issue["fields"].(map[string]interface{})["labels"].([]string)
+			for _, l := range labels {
+				if strings.Contains(r.URL.Query().Get("jql"), fmt.Sprintf(`labels = "%s"`, l)) {
+					issues = append(issues, issue)
+				}
+			}
+		}
+		j.write(w, http.StatusOK, map[string]interface{}{"issues": issues})
+	case strings.HasSuffix(r.URL.Path, "/transitions"):
+		issue := j.issue(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, jiraIssuesPath+"/"), "/transitions"))
+		if issue == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			j.write(w, http.StatusOK, json.RawMessage(`{"transitions": [
+				{"id": "21", "to": {"statusCategory": {"key": "indeterminate"}}},
+				{"id": "31", "to": {"statusCategory": {"key": "done"}}}
+			]}`))
+			return
+		}
+		var req struct {
+			Transition struct{ ID string } `json:"transition"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Transition.ID != "31" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fields := issue["fields"].(map[string]interface{})
+		fields["status"] = map[string]string{"name": "Done"}
+		fields["resolution"] = map[string]string{"name": "Done"}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		issue := j.issue(strings.TrimPrefix(r.URL.Path, jiraIssuesPath+"/"))
+		if issue == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		j.write(w, http.StatusOK, issue)
+	}
+}
+
+func (j *jiraStandIn) issue(key string) map[string]interface{} {
+	for _, issue := range j.issues {
+		if issue["key"] == key {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (j *jiraStandIn) write(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		j.t.Errorf("error writing response: %v", err)
+	}
+}
+
+func TestJiraProvider(t *testing.T) {
+	standIn := &jiraStandIn{t: t}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+	ctx := context.Background()
+	p := NewJiraProvider(srv.Client(), srv.URL, "SEC", "token")
+
+	created, err := p.CreateTicket(ctx, api.FindingTicketCreate{
+		FindingID:   "f1",
+		TeamID:      "t1",
+		Summary:     "Outdated TLS in example.com",
+		Description: "TLS 1.0 is enabled",
+		Labels:      []string{"security"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating ticket: %v", err)
+	}
+	want := &api.Ticket{Ticket: vulcantracker.Ticket{
+		ID:          "SEC-1",
+		Key:         "SEC-1",
+		TeamID:      "t1",
+		FindingID:   "f1",
+		Summary:     "Outdated TLS in example.com",
+		Description: "TLS 1.0 is enabled",
+		Project:     "SEC",
+		Status:      "To Do",
+		TicketType:  jiraIssueType,
+		Labels:      []string{"security"},
+		URLTracker:  srv.URL + "/browse/SEC-1",
+	}}
+	if diff := cmp.Diff(want, created); diff != "" {
+		t.Errorf("created ticket mismatch (-want +got):\n%s", diff)
+	}
+
+	found, err := p.GetFindingTicket(ctx, "f1", "t1")
+	if err != nil {
+		t.Fatalf("unexpected error getting finding ticket: %v", err)
+	}
+	if diff := cmp.Diff(want, found); diff != "" {
+		t.Errorf("finding ticket mismatch (-want +got):\n%s", diff)
+	}
+
+	fixed, err := p.FixTicket(ctx, "t1", "SEC-1")
+	if err != nil {
+		t.Fatalf("unexpected error fixing ticket: %v", err)
+	}
+	if fixed.Ticket.Status != "Done" || fixed.Ticket.Resolution != "Done" {
+		t.Errorf("got fixed ticket with status %q and resolution %q", fixed.Ticket.Status, fixed.Ticket.Resolution)
+	}
+
+	missing, err := p.GetTicket(ctx, "t1", "SEC-10")
+	if err != nil {
+		t.Fatalf("unexpected error getting missing ticket: %v", err)
+	}
+	if missing.Ticket.ID != "" {
+		t.Errorf("got missing ticket %+v, want empty ticket", missing.Ticket)
+	}
+
+	noTicket, err := p.GetFindingTicket(ctx, "f2", "t1")
+	if err != nil {
+		t.Fatalf("unexpected error getting finding without ticket: %v", err)
+	}
+	if noTicket.Ticket.ID != "" {
+		t.Errorf("got finding ticket %+v, want empty ticket", noTicket.Ticket)
+	}
+
+	missingProject := NewJiraProvider(srv.Client(), srv.URL, "MISSING", "token")
+	if _, err := missingProject.CreateTicket(ctx, api.FindingTicketCreate{FindingID: "f1", TeamID: "t1"}); err == nil {
+		t.Error("expected error creating a ticket in a missing project")
+	}
+
+	unauthorized := NewJiraProvider(srv.Client(), srv.URL, "SEC", "invalid")
+	if _, err := unauthorized.GetTicket(ctx, "t1", "SEC-1"); err == nil {
+		t.Error("expected error using invalid credentials")
+	}
+}
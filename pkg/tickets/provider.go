@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+const (
+	// CredentialsEnvPrefix is the prefix of the environment variables that
+	// contain the credentials of the ticket providers.
+	CredentialsEnvPrefix = "TICKETS_CREDENTIALS_"
+
+	findingLabelPrefix = "vulcan:"
+)
+
+var errNoTicketProvider = errors.Validation("The team has no ticket provider configured")
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// FindingLabel returns the label that links a ticket with a finding in the
+// providers that don't store the finding of a ticket.
+func FindingLabel(findingID string) string {
+	return findingLabelPrefix + findingID
+}
+
+func findingFromLabel(label string) (string, bool) {
+	if !strings.HasPrefix(label, findingLabelPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(label, findingLabelPrefix), true
+}
+
+// ProviderStore returns the ticket provider configured for a team.
+type ProviderStore interface {
+	FindTeamTicketProvider(teamID string) (*api.TicketProvider, error)
+}
+
+// Credentials returns the credentials referenced by the given name.
+type Credentials func(ref string) (string, error)
+
+// EnvCredentials reads the credentials of the ticket providers from the
+// environment. The credentials referenced by "github-security", for instance,
+// are read from TICKETS_CREDENTIALS_GITHUB_SECURITY.
+func EnvCredentials(ref string) (string, error) {
+	name := CredentialsEnvPrefix + strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToUpper(ref), "_"), "_")
+	credentials, ok := os.LookupEnv(name)
+	if !ok || credentials == "" {
+		return "", errors.Default(fmt.Sprintf("credentials %s not found", ref))
+	}
+	return credentials, nil
+}
+
+type router struct {
+	store         ProviderStore
+	vulcanTracker Client
+	credentials   Credentials
+	httpClient    *http.Client
+}
+
+// NewRouter returns a tickets client that sends the requests of each team to
+// the ticket provider configured for it. The teams without a ticket provider
+// use the given vulcan-tracker client, that can be nil if the component is
+// not configured.
+func NewRouter(httpClient *http.Client, store ProviderStore, vulcanTracker Client, credentials Credentials) Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if credentials == nil {
+		credentials = EnvCredentials
+	}
+	return &router{
+		store:         store,
+		vulcanTracker: vulcanTracker,
+		credentials:   credentials,
+		httpClient:    httpClient,
+	}
+}
+
+// provider returns the ticket provider of a team. It returns
+// errNoTicketProvider if the team has no provider and vulcan-tracker is not
+// configured.
+func (r *router) provider(teamID string) (Client, error) {
+	p, err := r.store.FindTeamTicketProvider(teamID)
+	if err != nil && !errors.IsKind(err, errors.ErrNotFound) {
+		return nil, err
+	}
+	if p == nil || p.Type == api.TicketProviderVulcanTracker {
+		if r.vulcanTracker == nil {
+			return nil, errNoTicketProvider
+		}
+		return r.vulcanTracker, nil
+	}
+	token, err := r.credentials(p.CredentialsRef)
+	if err != nil {
+		return nil, err
+	}
+	switch p.Type {
+	case api.TicketProviderJira:
+		return NewJiraProvider(r.httpClient, p.URL, p.Project, token), nil
+	case api.TicketProviderGitHub:
+		return NewGitHubProvider(r.httpClient, p.URL, p.Project, token), nil
+	default:
+		return nil, errors.Validation(fmt.Sprintf("unknown ticket provider %s", p.Type))
+	}
+}
+
+// CreateTicket creates a ticket in the provider of the team of the ticket.
+func (r *router) CreateTicket(ctx context.Context, payload api.FindingTicketCreate) (*api.Ticket, error) {
+	p, err := r.provider(payload.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	return p.CreateTicket(ctx, payload)
+}
+
+// GetFindingTicket returns the ticket of a finding in the provider of a team.
+// An empty ticket is returned if the team has no provider.
+func (r *router) GetFindingTicket(ctx context.Context, findingID, teamID string) (*api.Ticket, error) {
+	p, err := r.provider(teamID)
+	if err == errNoTicketProvider {
+		return &api.Ticket{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.GetFindingTicket(ctx, findingID, teamID)
+}
+
+// GetTicket returns a ticket from the provider of a team.
+func (r *router) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	p, err := r.provider(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetTicket(ctx, teamID, ticketID)
+}
+
+// FixTicket resolves a ticket as fixed in the provider of a team.
+func (r *router) FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	p, err := r.provider(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return p.FixTicket(ctx, teamID, ticketID)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package tickets
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adevinta/errors"
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulcantracker "github.com/adevinta/vulcan-tracker/pkg/model"
+)
+
+type inMemoryProviderStore map[string]*api.TicketProvider
+
+func (s inMemoryProviderStore) FindTeamTicketProvider(teamID string) (*api.TicketProvider, error) {
+	p, ok := s[teamID]
+	if !ok {
+		return nil, errors.NotFound("ticket provider not found")
+	}
+	return p, nil
+}
+
+// fakeVulcanTracker returns the tickets of the vulcan-tracker component.
+type fakeVulcanTracker struct {
+	Client
+}
+
+func (f fakeVulcanTracker) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	return &api.Ticket{Ticket: vulcantracker.Ticket{ID: ticketID, TeamID: teamID, Project: "vulcan-tracker"}}, nil
+}
+
+func (f fakeVulcanTracker) GetFindingTicket(ctx context.Context, findingID, teamID string) (*api.Ticket, error) {
+	return &api.Ticket{Ticket: vulcantracker.Ticket{ID: "1", FindingID: findingID, Project: "vulcan-tracker"}}, nil
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TICKETS_CREDENTIALS_GITHUB_SECURITY", "token")
+	got, err := EnvCredentials("github-security")
+	if err != nil || got != "token" {
+		t.Errorf("got credentials %q and error %v, want credentials %q", got, err, "token")
+	}
+	if _, err := EnvCredentials("unknown"); err == nil {
+		t.Error("expected error reading unknown credentials")
+	}
+}
+
+func TestRouter(t *testing.T) {
+	standIn := &githubStandIn{
+		t:      t,
+		issues: []githubIssue{{Number: 1, State: "open"}},
+	}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+
+	store := inMemoryProviderStore{
+		"github-team":         {TeamID: "github-team", Type: api.TicketProviderGitHub, URL: srv.URL, Project: "adevinta/vulcan", CredentialsRef: "github"},
+		"tracker-team":        {TeamID: "tracker-team", Type: api.TicketProviderVulcanTracker},
+		"no-credentials-team": {TeamID: "no-credentials-team", Type: api.TicketProviderGitHub, URL: srv.URL, Project: "adevinta/vulcan", CredentialsRef: "unknown"},
+	}
+	credentials := func(ref string) (string, error) {
+		if ref != "github" {
+			return "", errors.Default("credentials not found")
+		}
+		return "token", nil
+	}
+
+	tests := []struct {
+		name          string
+		vulcanTracker Client
+		teamID        string
+		wantProject   string
+		wantErr       bool
+	}{
+		{name: "GitHubProvider", vulcanTracker: fakeVulcanTracker{}, teamID: "github-team", wantProject: "adevinta/vulcan"},
+		{name: "VulcanTrackerProvider", vulcanTracker: fakeVulcanTracker{}, teamID: "tracker-team", wantProject: "vulcan-tracker"},
+		{name: "NoProvider", vulcanTracker: fakeVulcanTracker{}, teamID: "other-team", wantProject: "vulcan-tracker"},
+		{name: "NoProviderNoVulcanTracker", teamID: "other-team", wantErr: true},
+		{name: "UnknownCredentials", vulcanTracker: fakeVulcanTracker{}, teamID: "no-credentials-team", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter(srv.Client(), store, tt.vulcanTracker, credentials)
+			got, err := r.GetTicket(context.Background(), tt.teamID, "1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && got.Ticket.Project != tt.wantProject {
+				t.Errorf("got ticket of project %q, want %q", got.Ticket.Project, tt.wantProject)
+			}
+		})
+	}
+}
+
+func TestRouterGetFindingTicketNoProvider(t *testing.T) {
+	r := NewRouter(nil, inMemoryProviderStore{}, nil, nil)
+	got, err := r.GetFindingTicket(context.Background(), "f1", "other-team")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Ticket.ID != "" {
+		t.Errorf("got ticket %+v, want empty ticket", got.Ticket)
+	}
+}
@@ -20,16 +20,21 @@ import (
 
 const (
 	ticketsPath       = "/%s/tickets"
+	ticketPath        = "/%s/tickets/%s"
+	fixTicketPath     = "/%s/tickets/%s/fix"
 	findingTicketPath = "/%s/tickets/findings/%s"
 
 	authScheme = "TEAM team=%s"
 	noAuth     = ""
 )
 
-// Client represents a vulcan tracker client.
+// Client represents a ticket provider. The vulcan-tracker client returned by
+// NewClient is one of its implementations.
 type Client interface {
 	CreateTicket(ctx context.Context, payload api.FindingTicketCreate) (*api.Ticket, error)
 	GetFindingTicket(ctx context.Context, findingID, teamID string) (*api.Ticket, error)
+	GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error)
+	FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error)
 }
 
 type client struct {
@@ -126,6 +131,32 @@ func (c *client) GetFindingTicket(ctx context.Context, findingID, teamID string)
 	return &ticketResponse, err
 }
 
+// GetTicket makes a request to vulcan tracker to get a ticket of a team.
+func (c *client) GetTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	path := fmt.Sprintf(ticketPath, teamID, ticketID)
+	resp, err := c.performRequest(ctx, http.MethodGet, path, noAuth, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ticketResponse api.Ticket
+	err = json.Unmarshal(resp, &ticketResponse)
+
+	return &ticketResponse, err
+}
+
+// FixTicket requests vulcan tracker to resolve a ticket of a team as fixed.
+func (c *client) FixTicket(ctx context.Context, teamID, ticketID string) (*api.Ticket, error) {
+	path := fmt.Sprintf(fixTicketPath, teamID, ticketID)
+	resp, err := c.performRequest(ctx, http.MethodPost, path, noAuth, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ticketResponse api.Ticket
+	err = json.Unmarshal(resp, &ticketResponse)
+
+	return &ticketResponse, err
+}
+
 // IsHTTPStatusOk determines if a status code is an OK or not.
 func IsHTTPStatusOk(status int) bool {
 	return status >= http.StatusOK && status < http.StatusMultipleChoices
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+// Package ticketsync synchronizes the tickets created in the ticket tracker
+// for the findings of the teams with the status of the findings.
+package ticketsync
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	"github.com/adevinta/vulcan-api/pkg/poller"
+)
+
+const defaultPollInterval = 900
+
+// Config defines the configuration of the Runner.
+type Config struct {
+	// PollInterval is the number of seconds between two consecutive
+	// synchronizations of the tickets.
+	PollInterval int `mapstructure:"poll_interval"`
+}
+
+// Store defines the store methods needed by the Runner.
+type Store interface {
+	ListTeams() ([]*api.Team, error)
+}
+
+// Service defines the service methods needed by the Runner.
+type Service interface {
+	SyncFindingTickets(ctx context.Context, teamID string) (int, error)
+}
+
+// Runner periodically synchronizes the tickets of the findings of the teams
+// that use the ticket tracker. Synchronizing a ticket twice has no further
+// effects.
+type Runner struct {
+	store   Store
+	service Service
+	cfg     Config
+	logger  log.Logger
+}
+
+// New returns a Runner.
+func New(logger log.Logger, store Store, service Service, cfg Config) *Runner {
+	return &Runner{
+		store:   store,
+		service: service,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Run synchronizes the tickets until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	poller.Run(ctx, r.cfg.PollInterval, defaultPollInterval, r.sync)
+}
+
+// sync synchronizes the tickets of every team that uses the ticket tracker.
+func (r *Runner) sync(ctx context.Context) {
+	teams, err := r.store.ListTeams()
+	if err != nil {
+		_ = level.Error(r.logger).Log("TicketSync", "error listing teams", "err", err)
+		return
+	}
+	for _, t := range teams {
+		if !t.UsingTracker {
+			continue
+		}
+		n, err := r.service.SyncFindingTickets(ctx, t.ID)
+		if err != nil {
+			_ = level.Error(r.logger).Log("TicketSync", "error synchronizing tickets", "TeamID", t.ID, "err", err)
+			continue
+		}
+		if n > 0 {
+			_ = level.Info(r.logger).Log("TicketSync", "tickets closed", "TeamID", t.ID, "Tickets", n)
+		}
+	}
+}
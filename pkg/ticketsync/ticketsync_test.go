@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package ticketsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+type inMemoryStore struct {
+	teams []*api.Team
+	err   error
+}
+
+func (s *inMemoryStore) ListTeams() ([]*api.Team, error) {
+	return s.teams, s.err
+}
+
+type inMemoryService struct {
+	// closed contains the number of tickets of each team that are closed in
+	// the next synchronization.
+	closed map[string]int
+	// errs contains the errors returned when synchronizing the tickets of
+	// the teams with the given IDs.
+	errs   map[string]error
+	synced []string
+}
+
+func (s *inMemoryService) SyncFindingTickets(ctx context.Context, teamID string) (int, error) {
+	if err := s.errs[teamID]; err != nil {
+		return 0, err
+	}
+	s.synced = append(s.synced, teamID)
+	n := s.closed[teamID]
+	delete(s.closed, teamID)
+	return n, nil
+}
+
+func TestRunnerSync(t *testing.T) {
+	tests := []struct {
+		name        string
+		store       *inMemoryStore
+		serviceErrs map[string]error
+		wantSynced  []string
+	}{
+		{
+			name: "SyncsTeamsUsingTracker",
+			store: &inMemoryStore{teams: []*api.Team{
+				{ID: "t1", UsingTracker: true},
+				{ID: "t2"},
+				{ID: "t3", UsingTracker: true},
+			}},
+			wantSynced: []string{"t1", "t3"},
+		},
+		{
+			name: "ContinuesAfterTeamError",
+			store: &inMemoryStore{teams: []*api.Team{
+				{ID: "t1", UsingTracker: true},
+				{ID: "t2", UsingTracker: true},
+			}},
+			serviceErrs: map[string]error{"t1": errors.New("tracker unavailable")},
+			wantSynced:  []string{"t2"},
+		},
+		{
+			name:  "StoreError",
+			store: &inMemoryStore{err: errors.New("db unavailable")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &inMemoryService{closed: map[string]int{"t1": 1}, errs: tt.serviceErrs}
+			r := New(log.NewNopLogger(), tt.store, service, Config{})
+			r.sync(context.Background())
+			if diff := cmp.Diff(tt.wantSynced, service.synced); diff != "" {
+				t.Errorf("synced mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package vulnerabilitydb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+)
+
+// Names of the read endpoints of the vulnerability DB, used to configure the
+// time their responses are cached.
+const (
+	CacheIssues               = "issues"
+	CacheFindings             = "findings"
+	CacheFinding              = "finding"
+	CacheFindingsIssues       = "findings_issues"
+	CacheFindingsByIssue      = "findings_by_issue"
+	CacheFindingsTargets      = "findings_targets"
+	CacheFindingsByTarget     = "findings_by_target"
+	CacheTargets              = "targets"
+	CacheStatsMTTR            = "stats_mttr"
+	CacheStatsExposure        = "stats_exposure"
+	CacheStatsCurrentExposure = "stats_current_exposure"
+	CacheStatsOpen            = "stats_open"
+	CacheStatsFixed           = "stats_fixed"
+	CacheStatsAssets          = "stats_assets"
+	CacheLabels               = "labels"
+)
+
+// idFilter is the param used to build the cache key of the requests of a
+// single entity.
+const idFilter = "id"
+
+// globalScope is the scope of the cached responses that are not restricted
+// to a single team. It is invalidated by every write.
+const globalScope = "global"
+
+const (
+	// minCacheBackoff and maxCacheBackoff bound the time the store is not
+	// used after it fails.
+	minCacheBackoff = time.Second
+	maxCacheBackoff = time.Minute
+)
+
+// ErrCacheNotShared is returned when the cache of several replicas of the
+// API is configured without a shared store.
+var ErrCacheNotShared = errors.New("the cache of several replicas requires a shared store")
+
+// CacheConfig defines the cache of the responses of the read endpoints of the
+// vulnerability DB.
+type CacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Size is the maximum number of responses kept by the in-memory cache.
+	Size int `mapstructure:"size"`
+	// TTL is the number of seconds the responses are cached.
+	TTL int `mapstructure:"ttl"`
+	// TTLs overrides the TTL of the endpoints. A TTL lower or equal to zero
+	// disables the cache of the endpoint.
+	TTLs map[string]int `mapstructure:"ttls"`
+	// RedisAddr is the address of a Redis compatible server where the
+	// responses are cached instead of in memory. The cache is shared, and
+	// invalidated, by all the instances of the API using the same server.
+	RedisAddr     string `mapstructure:"redis_addr"`
+	RedisPassword string `mapstructure:"redis_password"`
+	RedisDB       int    `mapstructure:"redis_db"`
+	// Replicas is the number of instances of the API. The in-memory cache
+	// of an instance is not invalidated by the writes made through the
+	// others, so several replicas require a Redis server.
+	Replicas int `mapstructure:"replicas"`
+}
+
+// ttl returns the time the responses of the given endpoint are cached.
+func (c CacheConfig) ttl(endpoint string) time.Duration {
+	ttl, ok := c.TTLs[endpoint]
+	if !ok {
+		ttl = c.TTL
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// CacheStore stores the cached responses. The values belong to a scope, a
+// team or the global one, so the values of a scope can be invalidated
+// without invalidating the rest.
+type CacheStore interface {
+	// Get returns the value stored for a key of a scope, if it exists and
+	// has not expired.
+	Get(ctx context.Context, scope, key string) ([]byte, bool, error)
+	// Set stores a value for a key of a scope during the given time.
+	Set(ctx context.Context, scope, key string, value []byte, ttl time.Duration) error
+	// Invalidate removes all the values of the given scopes.
+	Invalidate(ctx context.Context, scopes ...string) error
+}
+
+// NewCacheStore returns the store configured for the cache, a Redis
+// compatible server if the address of the server is set, or an in-memory LRU
+// otherwise. The in-memory LRU can only be used by a single replica.
+func NewCacheStore(cfg CacheConfig) (CacheStore, error) {
+	if cfg.RedisAddr != "" {
+		return NewRedisStore(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB), nil
+	}
+	if cfg.Replicas > 1 {
+		return nil, ErrCacheNotShared
+	}
+	return NewLRUStore(cfg.Size)
+}
+
+type cachedClient struct {
+	Client
+	store  CacheStore
+	cfg    CacheConfig
+	logger log.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// NewCachedClient returns a client that caches the responses of the read
+// endpoints of the given client. The cached responses of a team are
+// invalidated every time the data of the team in the vulnerability DB is
+// modified through the returned client. The errors of the cache store are
+// logged and the requests are sent to the given client. After an error, the
+// store is not read nor written during an exponential backoff.
+func NewCachedClient(c Client, store CacheStore, cfg CacheConfig, logger log.Logger) Client {
+	return &cachedClient{
+		Client: c,
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// cacheKey returns the key of the response of an endpoint for the given
+// query params. The params are sorted so the same request always has the
+// same key.
+func cacheKey(endpoint string, queryParams map[string]string) string {
+	params := make([]string, 0, len(queryParams))
+	for k, v := range queryParams {
+		params = append(params, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(params)
+	return fmt.Sprintf("%s|%s", endpoint, strings.Join(params, "&"))
+}
+
+// teamScope returns the scope of the cached responses of a team.
+func teamScope(team string) string {
+	if team == "" {
+		return globalScope
+	}
+	return "team:" + team
+}
+
+// writeScopes returns the scopes invalidated by a write of the data of the
+// given teams.
+func writeScopes(teams ...string) []string {
+	scopes := []string{globalScope}
+	for _, t := range teams {
+		if t != "" {
+			scopes = append(scopes, teamScope(t))
+		}
+	}
+	return scopes
+}
+
+// available returns false while the store is in the backoff that follows an
+// error.
+func (c *cachedClient) available() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.now().Before(c.retryAt)
+}
+
+// result updates the backoff of the store after using it.
+func (c *cachedClient) result(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.backoff = 0
+		return
+	}
+	c.backoff *= 2
+	if c.backoff < minCacheBackoff {
+		c.backoff = minCacheBackoff
+	}
+	if c.backoff > maxCacheBackoff {
+		c.backoff = maxCacheBackoff
+	}
+	c.retryAt = c.now().Add(c.backoff)
+}
+
+// get reads the cached response of an endpoint into v. It returns false if
+// the cache of the endpoint is disabled or the response is not cached.
+func (c *cachedClient) get(ctx context.Context, endpoint, scope, key string, v interface{}) bool {
+	if c.cfg.ttl(endpoint) <= 0 || !c.available() {
+		return false
+	}
+	data, ok, err := c.store.Get(ctx, scope, key)
+	c.result(err)
+	if err != nil {
+		_ = level.Error(c.logger).Log("VulnDBCache", "error reading cached response", "key", key, "err", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		_ = level.Error(c.logger).Log("VulnDBCache", "error decoding cached response", "key", key, "err", err)
+		return false
+	}
+	return true
+}
+
+// set caches the response of an endpoint.
+func (c *cachedClient) set(ctx context.Context, endpoint, scope, key string, v interface{}) {
+	ttl := c.cfg.ttl(endpoint)
+	if ttl <= 0 || !c.available() {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		_ = level.Error(c.logger).Log("VulnDBCache", "error encoding response", "key", key, "err", err)
+		return
+	}
+	err = c.store.Set(ctx, scope, key, data, ttl)
+	c.result(err)
+	if err != nil {
+		_ = level.Error(c.logger).Log("VulnDBCache", "error caching response", "key", key, "err", err)
+	}
+}
+
+// invalidate removes the cached responses of the given teams, and the global
+// ones, after the vulnerability DB was modified. It is not skipped during a
+// backoff, so the stale responses are not read when the store recovers.
+func (c *cachedClient) invalidate(ctx context.Context, teams ...string) {
+	err := c.store.Invalidate(ctx, writeScopes(teams...)...)
+	c.result(err)
+	if err != nil {
+		_ = level.Error(c.logger).Log("VulnDBCache", "error invalidating cache", "teams", strings.Join(teams, ","), "err", err)
+	}
+}
+
+func (c *cachedClient) Issues(ctx context.Context, pagination api.Pagination) (*api.IssuesList, error) {
+	queryParams := make(map[string]string)
+	parsePaginationQuery(queryParams, pagination)
+	key := cacheKey(CacheIssues, queryParams)
+
+	var issues api.IssuesList
+	if c.get(ctx, CacheIssues, globalScope, key, &issues) {
+		return &issues, nil
+	}
+	resp, err := c.Client.Issues(ctx, pagination)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheIssues, globalScope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) Findings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	return c.findingsList(ctx, CacheFindings, params, pagination, c.Client.Findings)
+}
+
+func (c *cachedClient) FindingsByIssue(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	return c.findingsList(ctx, CacheFindingsByIssue, params, pagination, c.Client.FindingsByIssue)
+}
+
+func (c *cachedClient) FindingsByTarget(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	return c.findingsList(ctx, CacheFindingsByTarget, params, pagination, c.Client.FindingsByTarget)
+}
+
+func (c *cachedClient) findingsList(ctx context.Context, endpoint string, params api.FindingsParams, pagination api.Pagination,
+	next func(context.Context, api.FindingsParams, api.Pagination) (*api.FindingsList, error)) (*api.FindingsList, error) {
+	queryParams := make(map[string]string)
+	parseFindingsQuery(queryParams, params)
+	parsePaginationQuery(queryParams, pagination)
+	key := cacheKey(endpoint, queryParams)
+	scope := teamScope(params.Team)
+
+	var findings api.FindingsList
+	if c.get(ctx, endpoint, scope, key, &findings) {
+		return &findings, nil
+	}
+	resp, err := next(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, endpoint, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	key := cacheKey(CacheFinding, map[string]string{idFilter: findingID})
+
+	var finding api.Finding
+	if c.get(ctx, CacheFinding, globalScope, key, &finding) {
+		return &finding, nil
+	}
+	resp, err := c.Client.Finding(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheFinding, globalScope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) FindingsIssues(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsIssuesList, error) {
+	queryParams := make(map[string]string)
+	parseFindingsQuery(queryParams, params)
+	parsePaginationQuery(queryParams, pagination)
+	key := cacheKey(CacheFindingsIssues, queryParams)
+	scope := teamScope(params.Team)
+
+	var findingsIssues api.FindingsIssuesList
+	if c.get(ctx, CacheFindingsIssues, scope, key, &findingsIssues) {
+		return &findingsIssues, nil
+	}
+	resp, err := c.Client.FindingsIssues(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheFindingsIssues, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) FindingsTargets(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsTargetsList, error) {
+	queryParams := make(map[string]string)
+	parseFindingsQuery(queryParams, params)
+	parsePaginationQuery(queryParams, pagination)
+	key := cacheKey(CacheFindingsTargets, queryParams)
+	scope := teamScope(params.Team)
+
+	var findingsTargets api.FindingsTargetsList
+	if c.get(ctx, CacheFindingsTargets, scope, key, &findingsTargets) {
+		return &findingsTargets, nil
+	}
+	resp, err := c.Client.FindingsTargets(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheFindingsTargets, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) Targets(ctx context.Context, params api.TargetsParams, pagination api.Pagination) (*api.TargetsList, error) {
+	queryParams := make(map[string]string)
+	parseTargetsQuery(queryParams, params)
+	parsePaginationQuery(queryParams, pagination)
+	key := cacheKey(CacheTargets, queryParams)
+	scope := teamScope(params.Team)
+
+	var targets api.TargetsList
+	if c.get(ctx, CacheTargets, scope, key, &targets) {
+		return &targets, nil
+	}
+	resp, err := c.Client.Targets(ctx, params, pagination)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheTargets, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsMTTR(ctx context.Context, params api.StatsParams) (*api.StatsMTTR, error) {
+	key, scope := statsCacheKey(CacheStatsMTTR, params)
+	var stats api.StatsMTTR
+	if c.get(ctx, CacheStatsMTTR, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsMTTR(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsMTTR, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsExposure(ctx context.Context, params api.StatsParams) (*api.StatsExposure, error) {
+	key, scope := statsCacheKey(CacheStatsExposure, params)
+	var stats api.StatsExposure
+	if c.get(ctx, CacheStatsExposure, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsExposure(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsExposure, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsCurrentExposure(ctx context.Context, params api.StatsParams) (*api.StatsCurrentExposure, error) {
+	key, scope := statsCacheKey(CacheStatsCurrentExposure, params)
+	var stats api.StatsCurrentExposure
+	if c.get(ctx, CacheStatsCurrentExposure, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsCurrentExposure(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsCurrentExposure, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+	key, scope := statsCacheKey(CacheStatsOpen, params)
+	var stats api.StatsOpen
+	if c.get(ctx, CacheStatsOpen, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsOpen(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsOpen, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsFixed(ctx context.Context, params api.StatsParams) (*api.StatsFixed, error) {
+	key, scope := statsCacheKey(CacheStatsFixed, params)
+	var stats api.StatsFixed
+	if c.get(ctx, CacheStatsFixed, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsFixed(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsFixed, scope, key, resp)
+	return resp, nil
+}
+
+func (c *cachedClient) StatsAssets(ctx context.Context, params api.StatsParams) (*api.StatsAssets, error) {
+	key, scope := statsCacheKey(CacheStatsAssets, params)
+	var stats api.StatsAssets
+	if c.get(ctx, CacheStatsAssets, scope, key, &stats) {
+		return &stats, nil
+	}
+	resp, err := c.Client.StatsAssets(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheStatsAssets, scope, key, resp)
+	return resp, nil
+}
+
+// statsCacheKey returns the key and the scope of the response of a stats
+// endpoint. The stats of several teams are in the global scope.
+func statsCacheKey(endpoint string, params api.StatsParams) (string, string) {
+	queryParams := make(map[string]string)
+	parseStatsQuery(queryParams, params)
+	team := params.Team
+	if params.Teams != "" {
+		team = ""
+		if !strings.Contains(params.Teams, ",") && (params.Team == "" || params.Team == params.Teams) {
+			team = params.Teams
+		}
+	}
+	return cacheKey(endpoint, queryParams), teamScope(team)
+}
+
+func (c *cachedClient) Labels(ctx context.Context, params api.FindingsParams) (*api.FindingsLabels, error) {
+	queryParams := make(map[string]string)
+	parseFindingsQuery(queryParams, params)
+	key := cacheKey(CacheLabels, queryParams)
+	scope := teamScope(params.Team)
+
+	var labels api.FindingsLabels
+	if c.get(ctx, CacheLabels, scope, key, &labels) {
+		return &labels, nil
+	}
+	resp, err := c.Client.Labels(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, CacheLabels, scope, key, resp)
+	return resp, nil
+}
+
+// UpdateFinding invalidates the cached responses of all the teams sharing the
+// target of the finding, as the findings belong to the targets.
+func (c *cachedClient) UpdateFinding(ctx context.Context, findingID string, payload *api.UpdateFinding, team string) (*api.Finding, error) {
+	resp, err := c.Client.UpdateFinding(ctx, findingID, payload, team)
+	teams := []string{team}
+	if resp != nil {
+		teams = append(teams, resp.Finding.Target.Teams...)
+	}
+	c.invalidate(ctx, teams...)
+	return resp, err
+}
+
+func (c *cachedClient) CreateTarget(ctx context.Context, payload api.CreateTarget) (*api.Target, error) {
+	defer c.invalidate(ctx, payload.Teams...)
+	return c.Client.CreateTarget(ctx, payload)
+}
+
+func (c *cachedClient) DeleteTargetTeam(ctx context.Context, authTeam, targetID, teamID string) error {
+	defer c.invalidate(ctx, teamID)
+	return c.Client.DeleteTargetTeam(ctx, authTeam, targetID, teamID)
+}
+
+func (c *cachedClient) DeleteTeam(ctx context.Context, authTeam, teamID string) error {
+	defer c.invalidate(ctx, teamID)
+	return c.Client.DeleteTeam(ctx, authTeam, teamID)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package vulnerabilitydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultCacheSize is the number of responses kept by the in-memory
+	// cache when the size is not configured.
+	DefaultCacheSize = 1000
+
+	redisKeyPrefix = "vulcan-api:vulndb:"
+	redisPoolSize  = 10
+	redisTimeout   = 2 * time.Second
+)
+
+type lruEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUStore is an in-memory cache store that evicts the least recently used
+// values when it is full. The keys of the values include the generation of
+// their scope, so invalidating a scope only requires to increment its
+// generation, and the values of the previous generations are evicted by
+// themselves.
+type LRUStore struct {
+	cache *lru.Cache
+	now   func() time.Time
+
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+// NewLRUStore returns an in-memory cache store with room for the given
+// number of values.
+func NewLRUStore(size int) (*LRUStore, error) {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &LRUStore{cache: cache, now: time.Now, generations: map[string]uint64{}}, nil
+}
+
+func (s *LRUStore) key(scope, key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("%s:%d:%s", scope, s.generations[scope], key)
+}
+
+func (s *LRUStore) Get(ctx context.Context, scope, key string) ([]byte, bool, error) {
+	k := s.key(scope, key)
+	v, ok := s.cache.Get(k)
+	if !ok {
+		return nil, false, nil
+	}
+	entry := v.(lruEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.cache.Remove(k)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *LRUStore) Set(ctx context.Context, scope, key string, value []byte, ttl time.Duration) error {
+	s.cache.Add(s.key(scope, key), lruEntry{value: value, expiresAt: s.now().Add(ttl)})
+	return nil
+}
+
+func (s *LRUStore) Invalidate(ctx context.Context, scopes ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, scope := range scopes {
+		s.generations[scope]++
+	}
+	return nil
+}
+
+// RedisStore is a cache store backed by a Redis compatible server. As the
+// LRUStore, the keys of the values include the generation of their scope,
+// which is stored in the server, so the values are invalidated for all the
+// instances of the API using the same server. The generation is read in the
+// same round trip as the value by a script.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// redisGet returns the value of a key of the current generation of a scope.
+// KEYS[1] is the key of the generation of the scope, ARGV[1] the prefix of
+// the key of the value and ARGV[2] the key.
+var redisGet = redis.NewScript(`
+local gen = redis.call("GET", KEYS[1]) or "0"
+return redis.call("GET", ARGV[1] .. gen .. ":" .. ARGV[2])
+`)
+
+// redisSet sets the value of a key of the current generation of a scope
+// during ARGV[4] milliseconds.
+var redisSet = redis.NewScript(`
+local gen = redis.call("GET", KEYS[1]) or "0"
+return redis.call("SET", ARGV[1] .. gen .. ":" .. ARGV[2], ARGV[3], "PX", ARGV[4])
+`)
+
+// NewRedisStore returns a cache store that uses the Redis compatible server
+// listening on the given address.
+func NewRedisStore(addr, password string, db int) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:         addr,
+			Password:     password,
+			DB:           db,
+			PoolSize:     redisPoolSize,
+			DialTimeout:  redisTimeout,
+			ReadTimeout:  redisTimeout,
+			WriteTimeout: redisTimeout,
+		}),
+	}
+}
+
+func redisGenerationKey(scope string) string {
+	return redisKeyPrefix + scope + ":generation"
+}
+
+func redisValuePrefix(scope string) string {
+	return redisKeyPrefix + scope + ":"
+}
+
+func (s *RedisStore) Get(ctx context.Context, scope, key string) ([]byte, bool, error) {
+	value, err := redisGet.Run(ctx, s.client, []string{redisGenerationKey(scope)}, redisValuePrefix(scope), key).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(value), true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, scope, key string, value []byte, ttl time.Duration) error {
+	return redisSet.Run(ctx, s.client, []string{redisGenerationKey(scope)},
+		redisValuePrefix(scope), key, value, strconv.FormatInt(ttl.Milliseconds(), 10)).Err()
+}
+
+func (s *RedisStore) Invalidate(ctx context.Context, scopes ...string) error {
+	_, err := s.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, scope := range scopes {
+			p.Incr(ctx, redisGenerationKey(scope))
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2021 Adevinta
+*/
+
+package vulnerabilitydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/adevinta/vulcan-api/pkg/api"
+	vulndb "github.com/adevinta/vulnerability-db-api/pkg/model"
+)
+
+// countingClient returns a different response in each request, so the cached
+// responses can be told apart from the fresh ones.
+type countingClient struct {
+	Client
+	requests map[string]int
+}
+
+func (c *countingClient) count(endpoint string) int {
+	if c.requests == nil {
+		c.requests = map[string]int{}
+	}
+	c.requests[endpoint]++
+	return c.requests[endpoint]
+}
+
+func (c *countingClient) Findings(ctx context.Context, params api.FindingsParams, pagination api.Pagination) (*api.FindingsList, error) {
+	n := c.count(CacheFindings)
+	return &api.FindingsList{
+		Findings: []vulndb.FindingExpanded{{Finding: vulndb.Finding{ID: fmt.Sprintf("%s-%d", params.Team, n)}}},
+	}, nil
+}
+
+func (c *countingClient) Finding(ctx context.Context, findingID string) (*api.Finding, error) {
+	n := c.count(CacheFinding)
+	finding := &api.Finding{}
+	finding.Finding.Finding = vulndb.Finding{ID: findingID, Details: strconv.Itoa(n)}
+	return finding, nil
+}
+
+func (c *countingClient) StatsOpen(ctx context.Context, params api.StatsParams) (*api.StatsOpen, error) {
+	n := c.count(CacheStatsOpen)
+	return &api.StatsOpen{OpenIssues: vulndb.StatsIssueSeverity{Critical: n}}, nil
+}
+
+func (c *countingClient) UpdateFinding(ctx context.Context, findingID string, payload *api.UpdateFinding, team string) (*api.Finding, error) {
+	c.count("update_finding")
+	finding := &api.Finding{}
+	finding.Finding.Target.Teams = []string{team}
+	return finding, nil
+}
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLRUStore(10)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	next := &countingClient{}
+	c := NewCachedClient(next, store, CacheConfig{
+		TTL:  60,
+		TTLs: map[string]int{CacheStatsOpen: 0},
+	}, log.NewNopLogger())
+
+	findings := func(team string) string {
+		list, err := c.Findings(ctx, api.FindingsParams{Team: team, Status: "OPEN"}, api.Pagination{Page: 1, Size: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return list.Findings[0].Finding.ID
+	}
+	finding := func(id string) string {
+		f, err := c.Finding(ctx, id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return f.Finding.Finding.Details
+	}
+	statsOpen := func() int {
+		stats, err := c.StatsOpen(ctx, api.StatsParams{Team: "t1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return stats.OpenIssues.Critical
+	}
+
+	got := []string{findings("t1"), findings("t1"), findings("t2"), finding("f1"), finding("f1")}
+	want := []string{"t1-1", "t1-1", "t2-2", "1", "1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cached responses mismatch (-want +got):\n%s", diff)
+	}
+
+	// The cache of the stats of open findings is disabled.
+	if got := []int{statsOpen(), statsOpen()}; !cmp.Equal(got, []int{1, 2}) {
+		t.Errorf("got open stats %v, want not cached stats", got)
+	}
+
+	// Updating a finding invalidates the responses of the teams of its
+	// target and the global ones, but not the ones of the other teams.
+	if _, err := c.UpdateFinding(ctx, "f1", &api.UpdateFinding{}, "t1"); err != nil {
+		t.Fatalf("unexpected error updating finding: %v", err)
+	}
+	got = []string{findings("t1"), findings("t2"), finding("f1")}
+	want = []string{"t1-3", "t2-2", "2"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("responses after update mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// failingStore is a cache store that always fails.
+type failingStore struct {
+	calls int
+}
+
+func (s *failingStore) Get(ctx context.Context, scope, key string) ([]byte, bool, error) {
+	s.calls++
+	return nil, false, errors.New("store not available")
+}
+
+func (s *failingStore) Set(ctx context.Context, scope, key string, value []byte, ttl time.Duration) error {
+	s.calls++
+	return errors.New("store not available")
+}
+
+func (s *failingStore) Invalidate(ctx context.Context, scopes ...string) error {
+	s.calls++
+	return errors.New("store not available")
+}
+
+func TestCachedClientBackoff(t *testing.T) {
+	ctx := context.Background()
+	store := &failingStore{}
+	c := NewCachedClient(&countingClient{}, store, CacheConfig{TTL: 60}, log.NewNopLogger()).(*cachedClient)
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	finding := func() {
+		if _, err := c.Finding(ctx, "f1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first error starts the backoff, so the response is not cached.
+	finding()
+	finding()
+	if store.calls != 1 {
+		t.Fatalf("got %d calls to the store, want 1", store.calls)
+	}
+	now = now.Add(minCacheBackoff)
+	finding()
+	if store.calls != 2 {
+		t.Fatalf("got %d calls to the store after the backoff, want 2", store.calls)
+	}
+	// The backoff doubles after each error.
+	now = now.Add(minCacheBackoff)
+	finding()
+	if store.calls != 2 {
+		t.Fatalf("got %d calls to the store during the second backoff, want 2", store.calls)
+	}
+	// Invalidations are not skipped.
+	if _, err := c.UpdateFinding(ctx, "f1", &api.UpdateFinding{}, "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.calls != 3 {
+		t.Fatalf("got %d calls to the store after invalidating, want 3", store.calls)
+	}
+}
+
+func TestNewCacheStore(t *testing.T) {
+	if _, err := NewCacheStore(CacheConfig{Replicas: 2}); err != ErrCacheNotShared {
+		t.Errorf("got error %v, want %v", err, ErrCacheNotShared)
+	}
+	if _, err := NewCacheStore(CacheConfig{Replicas: 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	store, err := NewCacheStore(CacheConfig{Replicas: 2, RedisAddr: "127.0.0.1:6379"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.(*RedisStore); !ok {
+		t.Errorf("got store %T, want *RedisStore", store)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := cacheKey(CacheFindings, map[string]string{teamFilter: "t1", statusFilter: "OPEN", pageFilter: "1"})
+	b := cacheKey(CacheFindings, map[string]string{pageFilter: "1", statusFilter: "OPEN", teamFilter: "t1"})
+	if a != b {
+		t.Errorf("got different keys %q and %q for the same params", a, b)
+	}
+	if c := cacheKey(CacheLabels, map[string]string{teamFilter: "t1", statusFilter: "OPEN", pageFilter: "1"}); c == a {
+		t.Errorf("got the same key %q for different endpoints", c)
+	}
+}
+
+func TestStatsCacheKeyScope(t *testing.T) {
+	tests := []struct {
+		params api.StatsParams
+		want   string
+	}{
+		{params: api.StatsParams{Team: "t1"}, want: teamScope("t1")},
+		{params: api.StatsParams{Teams: "t1"}, want: teamScope("t1")},
+		{params: api.StatsParams{Teams: "t1,t2"}, want: globalScope},
+		{params: api.StatsParams{Team: "t1", Teams: "t2"}, want: globalScope},
+		{params: api.StatsParams{}, want: globalScope},
+	}
+	for _, tt := range tests {
+		if _, got := statsCacheKey(CacheStatsOpen, tt.params); got != tt.want {
+			t.Errorf("got scope %q for params %+v, want %q", got, tt.params, tt.want)
+		}
+	}
+}
+
+// testCacheStore checks the behavior common to all the cache stores.
+func testCacheStore(t *testing.T, store CacheStore) {
+	ctx := context.Background()
+	t1, t2 := teamScope("t1"), teamScope("t2")
+	if _, ok, err := store.Get(ctx, t1, "a"); err != nil || ok {
+		t.Fatalf("got value %v and error %v, want no value", ok, err)
+	}
+	for _, scope := range []string{t1, t2, globalScope} {
+		if err := store.Set(ctx, scope, "a", []byte(scope+"\r\n"), time.Minute); err != nil {
+			t.Fatalf("unexpected error setting value: %v", err)
+		}
+	}
+	if v, ok, err := store.Get(ctx, t1, "a"); err != nil || !ok || string(v) != t1+"\r\n" {
+		t.Errorf("got value %q, %v and error %v, want %q", v, ok, err, t1+"\r\n")
+	}
+	if err := store.Invalidate(ctx, t1, globalScope); err != nil {
+		t.Fatalf("unexpected error invalidating: %v", err)
+	}
+	for _, scope := range []string{t1, globalScope} {
+		if _, ok, err := store.Get(ctx, scope, "a"); err != nil || ok {
+			t.Errorf("got value %v and error %v for scope %s after invalidating, want no value", ok, err, scope)
+		}
+	}
+	if v, ok, err := store.Get(ctx, t2, "a"); err != nil || !ok || string(v) != t2+"\r\n" {
+		t.Errorf("got value %q, %v and error %v for a scope not invalidated", v, ok, err)
+	}
+}
+
+func TestLRUStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLRUStore(2)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	_ = store.Set(ctx, globalScope, "a", []byte("1"), time.Minute)
+	_ = store.Set(ctx, globalScope, "b", []byte("2"), time.Hour)
+	_ = store.Set(ctx, globalScope, "c", []byte("3"), time.Hour)
+	if _, ok, _ := store.Get(ctx, globalScope, "a"); ok {
+		t.Error("least recently used value not evicted")
+	}
+	if v, ok, _ := store.Get(ctx, globalScope, "b"); !ok || string(v) != "2" {
+		t.Errorf("got value %q, %v, want %q", v, ok, "2")
+	}
+
+	_ = store.Set(ctx, globalScope, "a", []byte("1"), time.Minute)
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := store.Get(ctx, globalScope, "a"); ok {
+		t.Error("expired value returned")
+	}
+
+	store, err = NewLRUStore(10)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	testCacheStore(t, store)
+}
+
+func TestRedisStore(t *testing.T) {
+	srv := miniredis.RunT(t)
+	srv.RequireAuth("secret")
+
+	testCacheStore(t, NewRedisStore(srv.Addr(), "secret", 0))
+
+	ctx := context.Background()
+	store := NewRedisStore(srv.Addr(), "secret", 0)
+	if err := store.Set(ctx, globalScope, "b", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("unexpected error setting value: %v", err)
+	}
+	srv.FastForward(2 * time.Minute)
+	if _, ok, err := store.Get(ctx, globalScope, "b"); err != nil || ok {
+		t.Errorf("got value %v and error %v after expiring, want no value", ok, err)
+	}
+
+	invalid := NewRedisStore(srv.Addr(), "invalid", 0)
+	if _, _, err := invalid.Get(ctx, globalScope, "a"); err == nil {
+		t.Error("expected error using an invalid password")
+	}
+}
@@ -15,7 +15,39 @@ export KAFKA_USER=${KAFKA_USER:-""}
 export KAFKA_PASS=${KAFKA_PASS:-""}
 export KAFKA_BROKER=${KAFKA_BROKER:-""}
 export KAFKA_TOPICS=${KAFKA_TOPICS:-"{}"}
+export GLOBALENTITIES_RELOAD_INTERVAL=${GLOBALENTITIES_RELOAD_INTERVAL:-300}
+export GLOBALENTITIES_WATCH_CONFIG=${GLOBALENTITIES_WATCH_CONFIG:-false}
 export DNS_HOSTNAME_VALIDATION=${DNS_HOSTNAME_VALIDATION:-true}
+export SCANENGINE_TIMEOUT=${SCANENGINE_TIMEOUT:-30}
+export SCANENGINE_RETRIES=${SCANENGINE_RETRIES:-3}
+export SCANENGINE_RETRY_INTERVAL=${SCANENGINE_RETRY_INTERVAL:-1}
+export SCANENGINE_BREAKER_THRESHOLD=${SCANENGINE_BREAKER_THRESHOLD:-5}
+export SCANENGINE_BREAKER_COOLDOWN=${SCANENGINE_BREAKER_COOLDOWN:-30}
+export SCANLIMITS_MAX_RUNNING_SCANS=${SCANLIMITS_MAX_RUNNING_SCANS:-0}
+export SCANLIMITS_MAX_RUNNING_SCANS_PER_TEAM=${SCANLIMITS_MAX_RUNNING_SCANS_PER_TEAM:-0}
+export SCANLIMITS_MAX_CHECKS_IN_FLIGHT=${SCANLIMITS_MAX_CHECKS_IN_FLIGHT:-0}
+export SCANLIMITS_MAX_CHECKS_IN_FLIGHT_PER_TEAM=${SCANLIMITS_MAX_CHECKS_IN_FLIGHT_PER_TEAM:-0}
+export SCANLIMITS_POLL_INTERVAL=${SCANLIMITS_POLL_INTERVAL:-30}
+export REPORTSUBSCRIPTIONS_POLL_INTERVAL=${REPORTSUBSCRIPTIONS_POLL_INTERVAL:-60}
+export RISKACCEPTANCES_POLL_INTERVAL=${RISKACCEPTANCES_POLL_INTERVAL:-300}
+export SLABREACHES_POLL_INTERVAL=${SLABREACHES_POLL_INTERVAL:-3600}
+export VULCANTRACKER_TEAMS=${VULCANTRACKER_TEAMS:-""}
+export TICKETSYNC_POLL_INTERVAL=${TICKETSYNC_POLL_INTERVAL:-900}
+export STATSSNAPSHOTS_POLL_INTERVAL=${STATSSNAPSHOTS_POLL_INTERVAL:-3600}
+export FINDINGSEXPORTS_POLL_INTERVAL=${FINDINGSEXPORTS_POLL_INTERVAL:-10}
+export FINDINGSEXPORTS_RETENTION=${FINDINGSEXPORTS_RETENTION:-168}
+export TICKETS_PROVIDERS_ENABLED=${TICKETS_PROVIDERS_ENABLED:-false}
+export VULNERABILITYDB_CACHE_ENABLED=${VULNERABILITYDB_CACHE_ENABLED:-false}
+export VULNERABILITYDB_CACHE_SIZE=${VULNERABILITYDB_CACHE_SIZE:-1000}
+export VULNERABILITYDB_CACHE_TTL=${VULNERABILITYDB_CACHE_TTL:-60}
+export VULNERABILITYDB_CACHE_FINDING_TTL=${VULNERABILITYDB_CACHE_FINDING_TTL:-10}
+export VULNERABILITYDB_CACHE_STATS_TTL=${VULNERABILITYDB_CACHE_STATS_TTL:-300}
+export VULNERABILITYDB_CACHE_REDIS_ADDR=${VULNERABILITYDB_CACHE_REDIS_ADDR:-""}
+export VULNERABILITYDB_CACHE_REDIS_PASSWORD=${VULNERABILITYDB_CACHE_REDIS_PASSWORD:-""}
+export VULNERABILITYDB_CACHE_REDIS_DB=${VULNERABILITYDB_CACHE_REDIS_DB:-0}
+export VULNERABILITYDB_CACHE_REPLICAS=${VULNERABILITYDB_CACHE_REPLICAS:-1}
+export VULCANCORE_CATALOGUE_TTL=${VULCANCORE_CATALOGUE_TTL:-300}
+export VULCANCORE_SCHEMAS_DIR=${VULCANCORE_SCHEMAS_DIR:-""}
 
 envsubst < config.toml > run.toml
 
@@ -48,6 +80,7 @@ fi
 
 flyway -user="$PG_USER" -password="$PG_PASSWORD" \
   -url="jdbc:postgresql://$PG_HOST:$PG_PORT/$PG_NAME?sslmode=$PG_SSLMODE" \
-  -baselineOnMigrate=true -locations=filesystem:/app/sql migrate
+  -baselineOnMigrate=true -locations=filesystem:/app/sql \
+  -placeholders.vulcantracker_teams="$VULCANTRACKER_TEAMS" migrate
 
 exec ./vulcan-api -c run.toml